@@ -1,11 +1,18 @@
 package main
 
 import (
+	"Backend_golang_project/infrastructure/audit"
 	"Backend_golang_project/infrastructure/config"
 	infrastructure "Backend_golang_project/infrastructure/database"
+	"Backend_golang_project/infrastructure/eventbus"
+	"Backend_golang_project/infrastructure/exchange"
+	"Backend_golang_project/infrastructure/jobs"
+	"Backend_golang_project/infrastructure/mailer"
+	"Backend_golang_project/infrastructure/middleware/jwt"
 	"Backend_golang_project/infrastructure/router"
 	"Backend_golang_project/infrastructure/server"
 	"Backend_golang_project/internal/handlers"
+	"Backend_golang_project/internal/pkg"
 	"Backend_golang_project/internal/repositories"
 	"Backend_golang_project/internal/use_cases"
 	"context"
@@ -21,22 +28,57 @@ func All() fx.Option {
 		fx.Invoke(config.NewLogConfig),
 		fx.Invoke(func(*gin.Engine) {}),
 		fx.Provide(config.NewConfig),
+		fx.Provide(jwt.NewKeySet),
+		fx.Provide(mailer.NewMailer),
+		fx.Provide(eventbus.NewBus),
 		fx.Invoke(router.NewRegisterRouters),
 		fx.Provide(infrastructure.NewInitDatabase),
+		fx.Invoke(audit.RegisterCallbacks),
 
 		//inject repository
 		fx.Provide(repositories.NewProjectRepository),
 		fx.Provide(repositories.NewUserRepository),
+		fx.Provide(repositories.NewProjectMemberRepository),
+		fx.Provide(repositories.NewProjectTransactionRepository),
+		fx.Provide(repositories.NewRevenueScheduleRepository),
+		fx.Provide(repositories.NewProjectWorkRepository),
+		fx.Provide(repositories.NewTimesheetRepository),
+		fx.Provide(repositories.NewProjectCategoryRepository),
+		fx.Provide(repositories.NewAuditRepository),
+		fx.Provide(repositories.NewRefreshTokenRepository),
+		fx.Provide(repositories.NewRoleRepository),
+		fx.Provide(repositories.NewLoginAttemptRepository),
+		fx.Provide(repositories.NewUserTokenRepository),
 		fx.Provide(logrus.New),
 		fx.Provide(context.Background),
 		fx.Provide(repositories.NewS3Repository),
 
 		//inject service
+		fx.Provide(pkg.NewCursorSigner),
+		fx.Provide(exchange.NewRateProvider),
+		fx.Provide(use_cases.NewProjectCategoryService),
+		// validator valid_category của gin engine đọc category qua service (có cache)
+		fx.Provide(func(categories use_cases.IProjectCategoryService) pkg.CategoryCatalog { return categories }),
 		fx.Provide(use_cases.NewProjectService),
+		fx.Decorate(use_cases.NewProjectPolicy),
+		fx.Invoke(use_cases.RegisterBudgetAlerts),
+		fx.Provide(use_cases.NewRevenueRecognitionService),
+		fx.Invoke(jobs.RegisterRevenueRecognitionJob),
+		fx.Provide(use_cases.NewProjectPurgeService),
+		fx.Invoke(jobs.RegisterProjectPurgeJob),
+		fx.Provide(use_cases.NewTimesheetService),
 		fx.Provide(use_cases.NewUserService),
+		fx.Provide(use_cases.NewRoleService),
+		fx.Provide(use_cases.NewAccountService),
+		fx.Provide(use_cases.NewAuditService),
 
 		//inject controller
 		fx.Provide(handlers.NewProjectHandler),
 		fx.Provide(handlers.NewUserHandler),
+		fx.Provide(handlers.NewAdminHandler),
+		fx.Provide(handlers.NewAccountHandler),
+		fx.Provide(handlers.NewTimesheetHandler),
+		fx.Provide(handlers.NewProjectCategoryHandler),
+		fx.Provide(handlers.NewAuditHandler),
 	)
 }
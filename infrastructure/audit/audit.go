@@ -0,0 +1,328 @@
+package audit
+
+import (
+	"Backend_golang_project/internal/domain/entities"
+	"Backend_golang_project/internal/pkg"
+	"errors"
+	"fmt"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+	"reflect"
+	"time"
+)
+
+// ErrAppendOnly audit_events chỉ được thêm mới
+var ErrAppendOnly = errors.New("audit events are append-only")
+
+const (
+	auditTable = "audit_events"
+	beforeKey  = "audit:before"
+	redacted   = "[redacted]"
+)
+
+// auditedTables bảng được audit và loại entity tương ứng
+var auditedTables = map[string]string{
+	"users":    entities.AuditEntityUser,
+	"projects": entities.AuditEntityProject,
+}
+
+// redactedColumns chỉ ghi nhận là có thay đổi, không ghi giá trị vào audit log
+var redactedColumns = map[string]bool{"password": true}
+
+// ignoredColumns đổi theo mọi lần ghi nên không được tính là thay đổi
+var ignoredColumns = map[string]bool{"updated_at": true, "version": true}
+
+// RegisterCallbacks gắn audit vào callback create/update/delete của GORM để mọi đường ghi vào
+// users và projects (repository, transaction, job) đều được ghi lại. Audit event được ghi trong cùng
+// transaction với thay đổi, ghi audit lỗi thì thay đổi cũng bị rollback
+func RegisterCallbacks(db *gorm.DB) error {
+	callbacks := db.Callback()
+	if err := callbacks.Create().After("gorm:create").Before("gorm:commit_or_rollback_transaction").
+		Register("audit:after_create", afterCreate); err != nil {
+		return err
+	}
+	if err := callbacks.Update().Before("gorm:update").Register("audit:before_update", beforeWrite); err != nil {
+		return err
+	}
+	if err := callbacks.Update().After("gorm:update").Before("gorm:commit_or_rollback_transaction").
+		Register("audit:after_update", afterUpdate); err != nil {
+		return err
+	}
+	if err := callbacks.Delete().Before("gorm:delete").Register("audit:before_delete", beforeWrite); err != nil {
+		return err
+	}
+	return callbacks.Delete().After("gorm:delete").Before("gorm:commit_or_rollback_transaction").
+		Register("audit:after_delete", afterDelete)
+}
+
+// beforeWrite chụp lại các bản ghi sắp bị update/delete
+func beforeWrite(db *gorm.DB) {
+	if db.Error != nil || db.DryRun {
+		return
+	}
+	if db.Statement.Table == auditTable {
+		db.AddError(ErrAppendOnly)
+		return
+	}
+	if _, ok := auditedTables[db.Statement.Table]; !ok {
+		return
+	}
+
+	rows, err := snapshot(db)
+	if err != nil {
+		db.AddError(fmt.Errorf("audit: error reading rows before write: %w", err))
+		return
+	}
+	db.InstanceSet(beforeKey, rows)
+}
+
+func afterCreate(db *gorm.DB) {
+	if db.Error != nil || db.DryRun {
+		return
+	}
+	if _, ok := auditedTables[db.Statement.Table]; !ok {
+		return
+	}
+
+	rows, err := load(db, createdIDs(db.Statement))
+	if err != nil {
+		db.AddError(fmt.Errorf("audit: error reading created rows: %w", err))
+		return
+	}
+	events := make([]entities.AuditEvent, 0, len(rows))
+	for _, row := range rows {
+		events = append(events, newEvent(db, entities.AuditActionCreate, row, nil, values(row)))
+	}
+	write(db, events)
+}
+
+func afterUpdate(db *gorm.DB) {
+	before, ok := beforeRows(db)
+	if !ok {
+		return
+	}
+
+	ids := make([]interface{}, 0, len(before))
+	for _, row := range before {
+		ids = append(ids, row["id"])
+	}
+	after, err := load(db, ids)
+	if err != nil {
+		db.AddError(fmt.Errorf("audit: error reading updated rows: %w", err))
+		return
+	}
+	afterByID := make(map[int]map[string]interface{}, len(after))
+	for _, row := range after {
+		afterByID[rowID(row)] = row
+	}
+
+	events := make([]entities.AuditEvent, 0, len(before))
+	for _, row := range before {
+		changedBefore, changedAfter := diff(row, afterByID[rowID(row)])
+		if len(changedAfter) == 0 {
+			continue
+		}
+		events = append(events, newEvent(db, entities.AuditActionUpdate, row, changedBefore, changedAfter))
+	}
+	write(db, events)
+}
+
+func afterDelete(db *gorm.DB) {
+	before, ok := beforeRows(db)
+	if !ok || db.Statement.RowsAffected == 0 {
+		return
+	}
+
+	events := make([]entities.AuditEvent, 0, len(before))
+	for _, row := range before {
+		events = append(events, newEvent(db, entities.AuditActionDelete, row, values(row), nil))
+	}
+	write(db, events)
+}
+
+func beforeRows(db *gorm.DB) ([]map[string]interface{}, bool) {
+	if db.Error != nil || db.DryRun {
+		return nil, false
+	}
+	value, ok := db.InstanceGet(beforeKey)
+	if !ok {
+		return nil, false
+	}
+	rows, _ := value.([]map[string]interface{})
+	return rows, len(rows) > 0
+}
+
+// session truy vấn mới dùng chung connection (transaction nếu có) và context với statement đang chạy
+func session(db *gorm.DB) *gorm.DB {
+	query := db.Session(&gorm.Session{NewDB: true, SkipHooks: true})
+	if db.Statement.Model != nil {
+		query = query.Model(db.Statement.Model)
+	} else {
+		query = query.Table(db.Statement.Table)
+	}
+	if db.Statement.Unscoped {
+		query = query.Unscoped()
+	}
+	return query
+}
+
+// snapshot đọc các bản ghi khớp điều kiện WHERE và khóa chính của statement.
+// Statement không có điều kiện nào sẽ bị GORM từ chối (ErrMissingWhereClause) nên không cần chụp
+func snapshot(db *gorm.DB) ([]map[string]interface{}, error) {
+	stmt := db.Statement
+	query := session(db)
+	conditions := 0
+	if where, ok := stmt.Clauses["WHERE"].Expression.(clause.Where); ok && len(where.Exprs) > 0 {
+		query = query.Clauses(where)
+		conditions++
+	}
+	if id, ok := primaryKey(stmt, stmt.ReflectValue); ok {
+		query = query.Where(clause.Eq{Column: clause.Column{Table: stmt.Table, Name: "id"}, Value: id})
+		conditions++
+	}
+	if conditions == 0 {
+		return nil, nil
+	}
+
+	var rows []map[string]interface{}
+	err := query.Find(&rows).Error
+	return rows, err
+}
+
+func load(db *gorm.DB, ids []interface{}) ([]map[string]interface{}, error) {
+	if len(ids) == 0 {
+		return nil, nil
+	}
+	var rows []map[string]interface{}
+	err := session(db).Unscoped().
+		Where(clause.IN{Column: clause.Column{Table: db.Statement.Table, Name: "id"}, Values: ids}).
+		Find(&rows).Error
+	return rows, err
+}
+
+// createdIDs khóa chính của các bản ghi vừa tạo, Create có thể nhận một struct hoặc slice struct
+func createdIDs(stmt *gorm.Statement) []interface{} {
+	value := reflect.Indirect(stmt.ReflectValue)
+	var ids []interface{}
+	switch value.Kind() {
+	case reflect.Struct:
+		if id, ok := primaryKey(stmt, value); ok {
+			ids = append(ids, id)
+		}
+	case reflect.Slice, reflect.Array:
+		for i := 0; i < value.Len(); i++ {
+			if id, ok := primaryKey(stmt, reflect.Indirect(value.Index(i))); ok {
+				ids = append(ids, id)
+			}
+		}
+	}
+	return ids
+}
+
+func primaryKey(stmt *gorm.Statement, value reflect.Value) (interface{}, bool) {
+	if stmt.Schema == nil || stmt.Schema.PrioritizedPrimaryField == nil || value.Kind() != reflect.Struct {
+		return nil, false
+	}
+	if value.Type() != stmt.Schema.ModelType {
+		return nil, false
+	}
+	id, zero := stmt.Schema.PrioritizedPrimaryField.ValueOf(stmt.Context, value)
+	if zero {
+		return nil, false
+	}
+	return id, true
+}
+
+func newEvent(db *gorm.DB, action string, row map[string]interface{}, before, after entities.AuditValues) entities.AuditEvent {
+	ctx := db.Statement.Context
+	event := entities.AuditEvent{
+		Action:     action,
+		EntityType: auditedTables[db.Statement.Table],
+		EntityID:   rowID(row),
+		Before:     before,
+		After:      after,
+		RequestID:  pkg.RequestIDFromContext(ctx),
+		ClientIP:   pkg.ClientIPFromContext(ctx),
+	}
+	if actorID, ok := pkg.UserIDFromContext(ctx); ok {
+		event.ActorID = &actorID
+	}
+	return event
+}
+
+func write(db *gorm.DB, events []entities.AuditEvent) {
+	if len(events) == 0 {
+		return
+	}
+	err := db.Session(&gorm.Session{NewDB: true, SkipHooks: true}).Create(&events).Error
+	if err != nil {
+		db.AddError(fmt.Errorf("audit: error writing audit events: %w", err))
+	}
+}
+
+// diff các cột khác nhau giữa hai lần đọc, cột bị redact chỉ cho biết là đã thay đổi
+func diff(before, after map[string]interface{}) (entities.AuditValues, entities.AuditValues) {
+	changedBefore := entities.AuditValues{}
+	changedAfter := entities.AuditValues{}
+	for column, value := range after {
+		if ignoredColumns[column] {
+			continue
+		}
+		old, now := normalize(before[column]), normalize(value)
+		if reflect.DeepEqual(old, now) {
+			continue
+		}
+		if redactedColumns[column] {
+			old, now = redacted, redacted
+		}
+		changedBefore[column] = old
+		changedAfter[column] = now
+	}
+	return changedBefore, changedAfter
+}
+
+func values(row map[string]interface{}) entities.AuditValues {
+	result := make(entities.AuditValues, len(row))
+	for column, value := range row {
+		if redactedColumns[column] {
+			result[column] = redacted
+			continue
+		}
+		result[column] = normalize(value)
+	}
+	return result
+}
+
+// normalize đưa giá trị driver trả về về dạng so sánh và ghi JSON được như nhau giữa các lần đọc
+func normalize(value interface{}) interface{} {
+	switch v := value.(type) {
+	case []byte:
+		return string(v)
+	case time.Time:
+		return v.UTC().Format(time.RFC3339Nano)
+	case *time.Time:
+		if v == nil {
+			return nil
+		}
+		return v.UTC().Format(time.RFC3339Nano)
+	}
+	return value
+}
+
+func rowID(row map[string]interface{}) int {
+	switch id := row["id"].(type) {
+	case int64:
+		return int(id)
+	case int:
+		return id
+	case int32:
+		return int(id)
+	case uint64:
+		return int(id)
+	case []byte:
+		var parsed int
+		fmt.Sscan(string(id), &parsed)
+		return parsed
+	}
+	return 0
+}
@@ -0,0 +1,139 @@
+package test
+
+import (
+	"Backend_golang_project/infrastructure/audit"
+	"Backend_golang_project/internal/domain/entities"
+	"Backend_golang_project/internal/domain/money"
+	"Backend_golang_project/internal/pkg"
+	"Backend_golang_project/internal/repositories"
+	"context"
+	"github.com/glebarez/sqlite"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/suite"
+	"gorm.io/gorm"
+)
+
+type AuditCallbacksTestSuite struct {
+	suite.Suite
+	db  *gorm.DB
+	ctx context.Context
+}
+
+func (suite *AuditCallbacksTestSuite) SetupTest() {
+	db, err := gorm.Open(sqlite.Open(":memory:"), &gorm.Config{})
+	suite.Require().NoError(err)
+	suite.Require().NoError(entities.SetupJoinTables(db))
+	suite.Require().NoError(db.AutoMigrate(&entities.User{}, &entities.Project{}, &entities.UserProject{}, &entities.ProjectVersion{},
+		&entities.ProjectTransaction{}, &entities.AuditEvent{}))
+	suite.Require().NoError(audit.RegisterCallbacks(db))
+	suite.db = db
+
+	ctx := pkg.WithUserID(context.Background(), 7)
+	ctx = pkg.WithRequestID(ctx, "req-1")
+	suite.ctx = pkg.WithClientIP(ctx, "10.0.0.1")
+}
+
+func (suite *AuditCallbacksTestSuite) events(entityType string, entityID int) []entities.AuditEvent {
+	var events []entities.AuditEvent
+	suite.Require().NoError(suite.db.Where("entity_type = ? AND entity_id = ?", entityType, entityID).Order("id").Find(&events).Error)
+	return events
+}
+
+func (suite *AuditCallbacksTestSuite) createProject() *entities.Project {
+	project := &entities.Project{Name: "Apollo", Category: "client", Status: entities.ProjectStatusActive,
+		Budget: money.New(1000, "USD"), ProjectSpend: money.New(0, "USD"), ProjectStartedAt: time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)}
+	suite.Require().NoError(suite.db.WithContext(suite.ctx).Create(project).Error)
+	return project
+}
+
+func (suite *AuditCallbacksTestSuite) TestCreate_RecordsActorAndRequest() {
+	project := suite.createProject()
+
+	events := suite.events(entities.AuditEntityProject, project.ID)
+	suite.Require().Len(events, 1)
+	suite.Equal(entities.AuditActionCreate, events[0].Action)
+	suite.Require().NotNil(events[0].ActorID)
+	suite.Equal(7, *events[0].ActorID)
+	suite.Equal("req-1", events[0].RequestID)
+	suite.Equal("10.0.0.1", events[0].ClientIP)
+	suite.Nil(events[0].Before)
+	suite.Equal("Apollo", events[0].After["name"])
+}
+
+func (suite *AuditCallbacksTestSuite) TestUpdate_RecordsChangedColumnsOnly() {
+	project := suite.createProject()
+
+	// Save qua khóa chính và Update theo điều kiện WHERE
+	project.Name = "Apollo 2"
+	suite.Require().NoError(suite.db.WithContext(suite.ctx).Save(project).Error)
+	suite.Require().NoError(suite.db.WithContext(suite.ctx).Model(&entities.Project{}).
+		Where("id = ?", project.ID).Update("status", entities.ProjectStatusOnHold).Error)
+	// ghi lại nhưng không đổi gì thì không có event
+	suite.Require().NoError(suite.db.First(project, project.ID).Error)
+	suite.Require().NoError(suite.db.WithContext(suite.ctx).Save(project).Error)
+
+	events := suite.events(entities.AuditEntityProject, project.ID)
+	suite.Require().Len(events, 3)
+	suite.Equal(entities.AuditValues{"name": "Apollo"}, events[1].Before)
+	suite.Equal(entities.AuditValues{"name": "Apollo 2"}, events[1].After)
+	suite.Equal(entities.AuditValues{"status": entities.ProjectStatusActive}, events[2].Before)
+	suite.Equal(entities.AuditValues{"status": entities.ProjectStatusOnHold}, events[2].After)
+}
+
+func (suite *AuditCallbacksTestSuite) TestLedgerPostingIsAudited() {
+	project := suite.createProject()
+	ledger := repositories.NewProjectTransactionRepository(suite.db)
+
+	_, err := ledger.Post(suite.ctx, &entities.ProjectTransaction{ProjectID: project.ID, Type: entities.TransactionTypeSpend,
+		Target: entities.TransactionTypeSpend, Amount: money.New(300, "USD"), EffectiveDate: time.Now()})
+	suite.Require().NoError(err)
+
+	// postEntry cập nhật tổng spend rồi tính lại variance, mỗi câu UPDATE là một event
+	events := suite.events(entities.AuditEntityProject, project.ID)
+	suite.Require().Len(events, 3)
+	suite.EqualValues(0, events[1].Before["project_spend_amount"])
+	suite.EqualValues(300, events[1].After["project_spend_amount"])
+	suite.EqualValues(700, events[2].After["project_variance_amount"])
+}
+
+func (suite *AuditCallbacksTestSuite) TestUserPasswordIsRedactedAndSoftDeleteRecorded() {
+	user := &entities.User{Email: "ada@example.com", Username: "ada", Password: "hash-1", Role: entities.RoleMember}
+	suite.Require().NoError(suite.db.WithContext(suite.ctx).Create(user).Error)
+	users := repositories.NewUserRepository(suite.db)
+
+	suite.Require().NoError(users.UpdatePassword(suite.ctx, user.ID, "hash-2"))
+	suite.Require().NoError(users.DeleteUser(suite.ctx, user.ID))
+
+	events := suite.events(entities.AuditEntityUser, user.ID)
+	suite.Require().Len(events, 3)
+	suite.Equal("[redacted]", events[0].After["password"])
+	suite.Equal(entities.AuditValues{"password": "[redacted]"}, events[1].After)
+	suite.Equal(entities.AuditActionDelete, events[2].Action)
+	suite.Equal("ada@example.com", events[2].Before["email"])
+	suite.Nil(events[2].After)
+}
+
+func (suite *AuditCallbacksTestSuite) TestAuditEventsAreAppendOnly() {
+	suite.createProject()
+
+	err := suite.db.Model(&entities.AuditEvent{}).Where("1 = 1").Update("action", "tampered").Error
+	suite.ErrorIs(err, audit.ErrAppendOnly)
+	err = suite.db.Where("1 = 1").Delete(&entities.AuditEvent{}).Error
+	suite.ErrorIs(err, audit.ErrAppendOnly)
+}
+
+func (suite *AuditCallbacksTestSuite) TestFailedWriteIsNotAudited() {
+	project := suite.createProject()
+
+	tx := suite.db.WithContext(suite.ctx).Begin()
+	suite.Require().NoError(tx.Model(&entities.Project{}).Where("id = ?", project.ID).Update("name", "Rolled back").Error)
+	suite.Require().NoError(tx.Rollback().Error)
+
+	suite.Len(suite.events(entities.AuditEntityProject, project.ID), 1)
+}
+
+func TestAuditCallbacksTestSuite(t *testing.T) {
+	suite.Run(t, new(AuditCallbacksTestSuite))
+}
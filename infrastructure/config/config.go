@@ -1,11 +1,18 @@
 package config
 
 type Config struct {
-	HttpConfig server    `mapstructure:"server"`
-	DB         database  `mapstructure:"database"`
-	LogLevel   logConfig `mapstructure:"log"`
-	JwtConfig  jwtConfig `mapstructure:"auth"`
-	S3Config   s3Config  `mapstructure:"s3"`
+	HttpConfig server         `mapstructure:"server"`
+	DB         database       `mapstructure:"database"`
+	LogLevel   logConfig      `mapstructure:"log"`
+	JwtConfig  jwtConfig      `mapstructure:"auth"`
+	S3Config   s3Config       `mapstructure:"s3"`
+	Mail       mailConfig     `mapstructure:"mail"`
+	Password   PasswordConfig `mapstructure:"password"`
+	Pagination pagination     `mapstructure:"pagination"`
+	Exchange   exchange       `mapstructure:"exchange"`
+	Budget     budget         `mapstructure:"budget"`
+	Revenue    revenue        `mapstructure:"revenue"`
+	Trash      trash          `mapstructure:"trash"`
 }
 type database struct {
 	Username     string `mapstructure:"username"`
@@ -14,18 +21,52 @@ type database struct {
 	Port         string `mapstructure:"port"`
 	DatabaseName string `mapstructure:"databaseName"`
 }
+// server TrustedProxies là các IP/CIDR của reverse proxy được tin header X-Forwarded-For/X-Real-IP,
+// để trống thì IP của client luôn là địa chỉ kết nối trực tiếp
 type server struct {
-	Host string `mapstructure:"host"`
-	Port string `mapstructure:"port"`
+	Host           string   `mapstructure:"host"`
+	Port           string   `mapstructure:"port"`
+	TrustedProxies []string `mapstructure:"trustedProxies"`
 }
 type logConfig struct {
 	Level string `mapstructure:"level"`
 }
 
 type jwtConfig struct {
-	SecretKey       string `mapstructure:"jwtSecretKey"`
-	AccessTokenExp  int    `mapstructure:"expAT"`
-	RefreshTokenExp int    `mapstructure:"expRT"`
+	SecretKey       string        `mapstructure:"jwtSecretKey"`
+	AccessTokenExp  int           `mapstructure:"expAT"`
+	RefreshTokenExp int           `mapstructure:"expRT"`
+	RefreshTokenKey string        `mapstructure:"refreshTokenKey"`
+	Issuer          string        `mapstructure:"issuer"`
+	Audience        []string      `mapstructure:"audience"`
+	Signing         signingConfig `mapstructure:"signing"`
+	LoginThrottle   LoginThrottle `mapstructure:"loginThrottle"`
+}
+
+// LoginThrottle giới hạn số lần đăng nhập sai. Sau MaxFailures lần sai liên tiếp (trong FailureWindowMinutes)
+// khóa bị khóa BaseLockoutSeconds giây, mỗi lần sai tiếp theo thời gian khóa nhân đôi nhưng không quá MaxLockoutSeconds
+type LoginThrottle struct {
+	MaxFailuresPerEmail  int `mapstructure:"maxFailuresPerEmail"`
+	MaxFailuresPerIP     int `mapstructure:"maxFailuresPerIP"`
+	FailureWindowMinutes int `mapstructure:"failureWindowMinutes"`
+	BaseLockoutSeconds   int `mapstructure:"baseLockoutSeconds"`
+	MaxLockoutSeconds    int `mapstructure:"maxLockoutSeconds"`
+}
+
+// signingConfig chọn thuật toán ký token. Với HS256 (mặc định) token được ký bằng jwtSecretKey,
+// với RS256/EdDSA token được ký bằng khóa activeKid, các khóa còn lại chỉ dùng để xác thực khi xoay vòng khóa
+type signingConfig struct {
+	Algorithm string             `mapstructure:"algorithm"`
+	ActiveKid string             `mapstructure:"activeKid"`
+	Keys      []SigningKeyConfig `mapstructure:"keys"`
+}
+
+// SigningKeyConfig một khóa trong auth.signing.keys, khóa chỉ có publicKeyFile thì chỉ dùng để xác thực
+type SigningKeyConfig struct {
+	Kid            string `mapstructure:"kid"`
+	Algorithm      string `mapstructure:"algorithm"`
+	PrivateKeyFile string `mapstructure:"privateKeyFile"`
+	PublicKeyFile  string `mapstructure:"publicKeyFile"`
 }
 
 type s3Config struct {
@@ -35,3 +76,66 @@ type s3Config struct {
 	AwsId    string `mapstructure:"AWS_ACCESS_KEY_ID"`
 	AwsKey   string `mapstructure:"AWS_SECRET_ACCESS_KEY"`
 }
+
+// mailConfig driver là smtp, file (ghi mỗi thư thành một file .eml trong directory) hoặc memory
+type mailConfig struct {
+	Driver     string     `mapstructure:"driver"`
+	From       string     `mapstructure:"from"`
+	Directory  string     `mapstructure:"directory"`
+	AppBaseURL string     `mapstructure:"appBaseURL"`
+	SMTP       smtpConfig `mapstructure:"smtp"`
+	// thời hạn của link đặt lại mật khẩu và link xác thực email
+	ResetTokenTTLMinutes      int `mapstructure:"resetTokenTTLMinutes"`
+	VerificationTokenTTLHours int `mapstructure:"verificationTokenTTLHours"`
+}
+
+type smtpConfig struct {
+	Host     string `mapstructure:"host"`
+	Port     int    `mapstructure:"port"`
+	Username string `mapstructure:"username"`
+	Password string `mapstructure:"password"`
+}
+
+// PasswordConfig thuật toán băm mật khẩu mới (bcrypt hoặc argon2id), hash cũ khác cấu hình được băm lại khi đăng nhập thành công
+type PasswordConfig struct {
+	Algorithm  string       `mapstructure:"algorithm"`
+	BcryptCost int          `mapstructure:"bcryptCost"`
+	Argon2     Argon2Config `mapstructure:"argon2"`
+}
+
+type Argon2Config struct {
+	MemoryKiB   uint32 `mapstructure:"memoryKiB"`
+	Iterations  uint32 `mapstructure:"iterations"`
+	Parallelism uint8  `mapstructure:"parallelism"`
+}
+
+// pagination CursorKey là khóa HMAC ký cursor phân trang, để trống thì dẫn xuất từ jwtSecretKey
+type pagination struct {
+	CursorKey string `mapstructure:"cursorKey"`
+}
+
+// exchange driver static dùng Base/Rates trong config, driver file đọc File (JSON cùng định dạng {"base", "rates"}).
+// Rates: 1 đơn vị của mã tiền = giá trị (chuỗi thập phân) đơn vị Base
+type exchange struct {
+	Driver string            `mapstructure:"driver"`
+	Base   string            `mapstructure:"base"`
+	File   string            `mapstructure:"file"`
+	Rates  map[string]string `mapstructure:"rates"`
+}
+
+// budget AlertThreshold phần trăm budget đã chi thì phát event project.over_budget, 0 thì dùng 100
+type budget struct {
+	AlertThreshold int `mapstructure:"alertThreshold"`
+}
+
+// revenue RecognitionIntervalMinutes chu kỳ chạy job ghi nhận doanh thu, 0 thì không chạy job
+type revenue struct {
+	RecognitionIntervalMinutes int `mapstructure:"recognitionIntervalMinutes"`
+}
+
+// trash project đã xóa mềm quá RetentionDays ngày bị xóa hẳn bởi job chạy mỗi PurgeIntervalMinutes phút,
+// một trong hai bằng 0 thì không chạy job
+type trash struct {
+	RetentionDays        int `mapstructure:"retentionDays"`
+	PurgeIntervalMinutes int `mapstructure:"purgeIntervalMinutes"`
+}
@@ -2,6 +2,7 @@ package infrastructure
 
 import (
 	"Backend_golang_project/infrastructure/config"
+	"Backend_golang_project/internal/domain/entities"
 	"fmt"
 	"github.com/sirupsen/logrus"
 	"gorm.io/driver/mysql"
@@ -24,9 +25,11 @@ func NewInitDatabase(config *config.Config) (*gorm.DB, error) {
 	db := mysql.Open(url)
 	gormDB, err := gorm.Open(db, &gorm.Config{
 		Logger: logger.Default.LogMode(logger.Info),
+		// chuyển lỗi của driver (ví dụ MySQL 1062) thành gorm.ErrDuplicatedKey để repository không phụ thuộc driver
+		TranslateError: true,
 	})
 	if err != nil {
-		log.Fatal(fmt.Sprintf("Can not connect database with err %v"), err)
+		log.Fatal(fmt.Sprintf("Can not connect database with err %v", err))
 		return nil, err
 	}
 
@@ -34,5 +37,9 @@ func NewInitDatabase(config *config.Config) (*gorm.DB, error) {
 	if err != nil {
 		logrus.Fatal("failed to migrate database:", err)
 	}
+
+	if err := entities.SetupJoinTables(gormDB); err != nil {
+		logrus.Fatal("failed to set up join tables:", err)
+	}
 	return gormDB, nil
 }
@@ -0,0 +1,64 @@
+package eventbus
+
+import (
+	"context"
+	"fmt"
+	"sync"
+
+	log "github.com/sirupsen/logrus"
+)
+
+// Event một domain event, EventName dùng để định tuyến tới các subscriber
+type Event interface {
+	EventName() string
+}
+
+// Handler xử lý một event, lỗi chỉ được ghi log, không trả ngược về nơi publish
+type Handler func(ctx context.Context, event Event) error
+
+// Bus phát domain event tới các subscriber đã đăng ký theo tên event
+type Bus interface {
+	Publish(ctx context.Context, event Event)
+	Subscribe(name string, handler Handler)
+}
+
+// InMemoryBus gọi các handler tuần tự ngay trong Publish, theo thứ tự đăng ký.
+// Nghiệp vụ đã được lưu trước khi publish nên một subscriber lỗi hoặc panic không làm hỏng request
+type InMemoryBus struct {
+	mu       sync.RWMutex
+	handlers map[string][]Handler
+}
+
+func NewBus() Bus {
+	return &InMemoryBus{handlers: map[string][]Handler{}}
+}
+
+func (b *InMemoryBus) Subscribe(name string, handler Handler) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	b.handlers[name] = append(b.handlers[name], handler)
+}
+
+func (b *InMemoryBus) Publish(ctx context.Context, event Event) {
+	b.mu.RLock()
+	handlers := b.handlers[event.EventName()]
+	b.mu.RUnlock()
+
+	for _, handler := range handlers {
+		if err := dispatch(ctx, handler, event); err != nil {
+			log.WithFields(log.Fields{
+				"event": event.EventName(),
+				"error": err,
+			}).Error("Event subscriber failed")
+		}
+	}
+}
+
+func dispatch(ctx context.Context, handler Handler, event Event) (err error) {
+	defer func() {
+		if recovered := recover(); recovered != nil {
+			err = fmt.Errorf("subscriber panicked: %v", recovered)
+		}
+	}()
+	return handler(ctx, event)
+}
@@ -0,0 +1,56 @@
+package test
+
+import (
+	"Backend_golang_project/infrastructure/eventbus"
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+type testEvent struct {
+	name string
+}
+
+func (e testEvent) EventName() string {
+	return e.name
+}
+
+func TestInMemoryBus_DispatchesByName(t *testing.T) {
+	bus := eventbus.NewBus()
+	var received []string
+	bus.Subscribe("project.created", func(_ context.Context, event eventbus.Event) error {
+		received = append(received, "first:"+event.EventName())
+		return nil
+	})
+	bus.Subscribe("project.created", func(_ context.Context, event eventbus.Event) error {
+		received = append(received, "second:"+event.EventName())
+		return nil
+	})
+
+	bus.Publish(context.Background(), testEvent{name: "project.created"})
+	bus.Publish(context.Background(), testEvent{name: "project.deleted"})
+
+	assert.Equal(t, []string{"first:project.created", "second:project.created"}, received)
+}
+
+func TestInMemoryBus_FailingSubscriberDoesNotStopOthers(t *testing.T) {
+	bus := eventbus.NewBus()
+	delivered := false
+	bus.Subscribe("project.created", func(context.Context, eventbus.Event) error {
+		panic("boom")
+	})
+	bus.Subscribe("project.created", func(context.Context, eventbus.Event) error {
+		return errors.New("mail server down")
+	})
+	bus.Subscribe("project.created", func(context.Context, eventbus.Event) error {
+		delivered = true
+		return nil
+	})
+
+	assert.NotPanics(t, func() {
+		bus.Publish(context.Background(), testEvent{name: "project.created"})
+	})
+	assert.True(t, delivered)
+}
@@ -0,0 +1,70 @@
+package exchange
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"math/big"
+	"os"
+	"sync"
+	"time"
+)
+
+// FileRateProvider đọc tỷ giá từ file JSON {"base": "VND", "rates": {"USD": "25400"}},
+// file được đọc lại khi thời gian sửa đổi thay đổi nên có thể cập nhật tỷ giá mà không restart
+type FileRateProvider struct {
+	path    string
+	mu      sync.RWMutex
+	modTime time.Time
+	table   *rateTable
+}
+
+type rateFile struct {
+	Base  string            `json:"base"`
+	Rates map[string]string `json:"rates"`
+}
+
+func NewFileRateProvider(path string) *FileRateProvider {
+	return &FileRateProvider{path: path}
+}
+
+func (p *FileRateProvider) Rate(_ context.Context, from, to string) (*big.Rat, error) {
+	table, err := p.load()
+	if err != nil {
+		return nil, err
+	}
+	return table.rate(from, to)
+}
+
+func (p *FileRateProvider) load() (*rateTable, error) {
+	info, err := os.Stat(p.path)
+	if err != nil {
+		return nil, fmt.Errorf("cannot read exchange rate file: %w", err)
+	}
+
+	p.mu.RLock()
+	if p.table != nil && info.ModTime().Equal(p.modTime) {
+		table := p.table
+		p.mu.RUnlock()
+		return table, nil
+	}
+	p.mu.RUnlock()
+
+	data, err := os.ReadFile(p.path)
+	if err != nil {
+		return nil, fmt.Errorf("cannot read exchange rate file: %w", err)
+	}
+	var file rateFile
+	if err := json.Unmarshal(data, &file); err != nil {
+		return nil, fmt.Errorf("invalid exchange rate file %s: %w", p.path, err)
+	}
+	table, err := parseRateTable(file.Base, file.Rates)
+	if err != nil {
+		return nil, err
+	}
+
+	p.mu.Lock()
+	p.table, p.modTime = table, info.ModTime()
+	p.mu.Unlock()
+	return table, nil
+}
@@ -0,0 +1,69 @@
+package exchange
+
+import (
+	"Backend_golang_project/infrastructure/config"
+	"context"
+	"errors"
+	"fmt"
+	"math/big"
+	"strings"
+)
+
+// ErrRateNotFound không có tỷ giá cho cặp tiền tệ được yêu cầu
+var ErrRateNotFound = errors.New("exchange rate not found")
+
+// RateProvider cung cấp tỷ giá, chọn implementation theo exchange.driver trong config.
+// Rate trả về số đơn vị to cho một đơn vị (major unit) của from
+type RateProvider interface {
+	Rate(ctx context.Context, from, to string) (*big.Rat, error)
+}
+
+// NewRateProvider constructor, mặc định dùng bảng tỷ giá tĩnh trong config
+func NewRateProvider(config *config.Config) (RateProvider, error) {
+	switch strings.ToLower(config.Exchange.Driver) {
+	case "", "static":
+		return NewStaticRateProvider(config.Exchange.Base, config.Exchange.Rates)
+	case "file":
+		return NewFileRateProvider(config.Exchange.File), nil
+	default:
+		return nil, fmt.Errorf("unsupported exchange driver %q", config.Exchange.Driver)
+	}
+}
+
+// rateTable tỷ giá của mỗi mã tiền so với base: 1 đơn vị mã tiền = rates[mã] đơn vị base
+type rateTable struct {
+	base  string
+	rates map[string]*big.Rat
+}
+
+// parseRateTable rates có dạng {"USD": "25400"}, giá trị là chuỗi để giữ nguyên độ chính xác.
+// Key được chuyển thành chữ hoa vì viper trả về key của map dạng chữ thường
+func parseRateTable(base string, rates map[string]string) (*rateTable, error) {
+	base = strings.ToUpper(base)
+	if base == "" {
+		return nil, errors.New("exchange base currency is required")
+	}
+	table := &rateTable{base: base, rates: map[string]*big.Rat{base: big.NewRat(1, 1)}}
+	for currency, value := range rates {
+		rate, ok := new(big.Rat).SetString(strings.TrimSpace(value))
+		if !ok || rate.Sign() <= 0 {
+			return nil, fmt.Errorf("invalid exchange rate %q for %s", value, currency)
+		}
+		table.rates[strings.ToUpper(currency)] = rate
+	}
+	return table, nil
+}
+
+// rate tỷ giá chéo qua base: from → base → to
+func (t *rateTable) rate(from, to string) (*big.Rat, error) {
+	from, to = strings.ToUpper(from), strings.ToUpper(to)
+	fromRate, ok := t.rates[from]
+	if !ok {
+		return nil, fmt.Errorf("%w: %s/%s", ErrRateNotFound, from, t.base)
+	}
+	toRate, ok := t.rates[to]
+	if !ok {
+		return nil, fmt.Errorf("%w: %s/%s", ErrRateNotFound, to, t.base)
+	}
+	return new(big.Rat).Quo(fromRate, toRate), nil
+}
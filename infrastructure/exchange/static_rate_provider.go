@@ -0,0 +1,23 @@
+package exchange
+
+import (
+	"context"
+	"math/big"
+)
+
+// StaticRateProvider bảng tỷ giá cố định, lấy từ exchange.rates trong config
+type StaticRateProvider struct {
+	table *rateTable
+}
+
+func NewStaticRateProvider(base string, rates map[string]string) (*StaticRateProvider, error) {
+	table, err := parseRateTable(base, rates)
+	if err != nil {
+		return nil, err
+	}
+	return &StaticRateProvider{table: table}, nil
+}
+
+func (p *StaticRateProvider) Rate(_ context.Context, from, to string) (*big.Rat, error) {
+	return p.table.rate(from, to)
+}
@@ -0,0 +1,63 @@
+package test
+
+import (
+	"Backend_golang_project/infrastructure/config"
+	"Backend_golang_project/infrastructure/exchange"
+	"context"
+	"math/big"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestStaticRateProvider_CrossRate(t *testing.T) {
+	cfg := &config.Config{}
+	cfg.Exchange.Base = "VND"
+	// viper trả về key của map dạng chữ thường
+	cfg.Exchange.Rates = map[string]string{"usd": "25000", "eur": "27500"}
+	provider, err := exchange.NewRateProvider(cfg)
+	require.NoError(t, err)
+	ctx := context.Background()
+
+	rate, err := provider.Rate(ctx, "USD", "VND")
+	require.NoError(t, err)
+	assert.Equal(t, big.NewRat(25000, 1), rate)
+
+	rate, err = provider.Rate(ctx, "EUR", "USD")
+	require.NoError(t, err)
+	assert.Equal(t, big.NewRat(11, 10), rate)
+
+	_, err = provider.Rate(ctx, "GBP", "VND")
+	assert.ErrorIs(t, err, exchange.ErrRateNotFound)
+}
+
+func TestStaticRateProvider_InvalidRate(t *testing.T) {
+	_, err := exchange.NewStaticRateProvider("VND", map[string]string{"USD": "abc"})
+	assert.Error(t, err)
+
+	_, err = exchange.NewStaticRateProvider("VND", map[string]string{"USD": "-1"})
+	assert.Error(t, err)
+}
+
+func TestFileRateProvider_ReloadsOnChange(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "rates.json")
+	require.NoError(t, os.WriteFile(path, []byte(`{"base": "VND", "rates": {"USD": "25000"}}`), 0o600))
+	provider := exchange.NewFileRateProvider(path)
+	ctx := context.Background()
+
+	rate, err := provider.Rate(ctx, "USD", "VND")
+	require.NoError(t, err)
+	assert.Equal(t, big.NewRat(25000, 1), rate)
+
+	require.NoError(t, os.WriteFile(path, []byte(`{"base": "VND", "rates": {"USD": "26000"}}`), 0o600))
+	later := time.Now().Add(time.Second)
+	require.NoError(t, os.Chtimes(path, later, later))
+
+	rate, err = provider.Rate(ctx, "USD", "VND")
+	require.NoError(t, err)
+	assert.Equal(t, big.NewRat(26000, 1), rate)
+}
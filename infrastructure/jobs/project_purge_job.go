@@ -0,0 +1,64 @@
+package jobs
+
+import (
+	"Backend_golang_project/infrastructure/config"
+	"Backend_golang_project/internal/use_cases"
+	"context"
+	"time"
+
+	log "github.com/sirupsen/logrus"
+	"go.uber.org/fx"
+)
+
+// RegisterProjectPurgeJob dọn thùng rác project theo chu kỳ trash.purgeIntervalMinutes, xóa hẳn các project
+// đã bị xóa quá trash.retentionDays ngày
+func RegisterProjectPurgeJob(lc fx.Lifecycle, config *config.Config, service use_cases.IProjectPurgeService) {
+	interval := time.Duration(config.Trash.PurgeIntervalMinutes) * time.Minute
+	if interval <= 0 || config.Trash.RetentionDays <= 0 {
+		log.Info("Project purge job is disabled")
+		return
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	done := make(chan struct{})
+	lc.Append(fx.Hook{
+		OnStart: func(context.Context) error {
+			log.WithFields(log.Fields{
+				"interval":       interval,
+				"retention_days": config.Trash.RetentionDays,
+			}).Info("Starting project purge job")
+			go func() {
+				defer close(done)
+				ticker := time.NewTicker(interval)
+				defer ticker.Stop()
+				for {
+					select {
+					case <-ctx.Done():
+						return
+					case <-ticker.C:
+						runProjectPurge(ctx, service)
+					}
+				}
+			}()
+			return nil
+		},
+		OnStop: func(stopCtx context.Context) error {
+			log.Info("Stopping project purge job")
+			cancel()
+			select {
+			case <-done:
+			case <-stopCtx.Done():
+			}
+			return nil
+		},
+	})
+}
+
+func runProjectPurge(ctx context.Context, service use_cases.IProjectPurgeService) {
+	purged, err := service.PurgeExpired(ctx, time.Now())
+	if err != nil {
+		log.WithField("purged", purged).Error("Project purge job failed: ", err)
+		return
+	}
+	log.WithField("purged", purged).Info("Project purge job finished")
+}
@@ -0,0 +1,61 @@
+package jobs
+
+import (
+	"Backend_golang_project/infrastructure/config"
+	"Backend_golang_project/internal/use_cases"
+	"context"
+	"time"
+
+	log "github.com/sirupsen/logrus"
+	"go.uber.org/fx"
+)
+
+// RegisterRevenueRecognitionJob chạy ghi nhận doanh thu cho mọi project theo chu kỳ revenue.recognitionIntervalMinutes.
+// Mỗi lần chạy ghi nhận tới hết ngày hiện tại, chạy lại trong cùng ngày không ghi trùng vì repository chỉ ghi phần chênh lệch
+func RegisterRevenueRecognitionJob(lc fx.Lifecycle, config *config.Config, service use_cases.IRevenueRecognitionService) {
+	interval := time.Duration(config.Revenue.RecognitionIntervalMinutes) * time.Minute
+	if interval <= 0 {
+		log.Info("Revenue recognition job is disabled")
+		return
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	done := make(chan struct{})
+	lc.Append(fx.Hook{
+		OnStart: func(context.Context) error {
+			log.WithField("interval", interval).Info("Starting revenue recognition job")
+			go func() {
+				defer close(done)
+				ticker := time.NewTicker(interval)
+				defer ticker.Stop()
+				for {
+					select {
+					case <-ctx.Done():
+						return
+					case <-ticker.C:
+						runRevenueRecognition(ctx, service)
+					}
+				}
+			}()
+			return nil
+		},
+		OnStop: func(stopCtx context.Context) error {
+			log.Info("Stopping revenue recognition job")
+			cancel()
+			select {
+			case <-done:
+			case <-stopCtx.Done():
+			}
+			return nil
+		},
+	})
+}
+
+func runRevenueRecognition(ctx context.Context, service use_cases.IRevenueRecognitionService) {
+	posted, err := service.RecogniseAll(ctx, time.Now())
+	if err != nil {
+		log.Error("Revenue recognition job failed: ", err)
+		return
+	}
+	log.WithField("entries", posted).Info("Revenue recognition job finished")
+}
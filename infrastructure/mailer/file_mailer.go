@@ -0,0 +1,30 @@
+package mailer
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+)
+
+// FileMailer ghi mỗi email thành một file .eml, dùng khi chạy local để mở link trong thư bằng tay
+type FileMailer struct {
+	from      string
+	directory string
+}
+
+func (m *FileMailer) Send(_ context.Context, message Message) error {
+	if err := os.MkdirAll(m.directory, 0o755); err != nil {
+		return err
+	}
+	now := time.Now()
+	recipient := strings.NewReplacer("@", "_at_", "/", "_").Replace(message.To)
+	name := fmt.Sprintf("%s-%s.eml", now.Format("20060102T150405.000000000"), recipient)
+	return os.WriteFile(filepath.Join(m.directory, name), format(m.from, message, now), 0o600)
+}
+
+func NewFileMailer(from string, directory string) *FileMailer {
+	return &FileMailer{from: from, directory: directory}
+}
@@ -0,0 +1,54 @@
+package mailer
+
+import (
+	"Backend_golang_project/infrastructure/config"
+	"context"
+	"fmt"
+	"strings"
+	"time"
+)
+
+// Message một email dạng text thuần
+type Message struct {
+	To      string
+	Subject string
+	Body    string
+}
+
+// Mailer gửi email ra ngoài, chọn implementation theo mail.driver trong config
+type Mailer interface {
+	Send(ctx context.Context, message Message) error
+}
+
+// NewMailer constructor, mặc định dùng file để chạy local không cần SMTP server
+func NewMailer(config *config.Config) (Mailer, error) {
+	switch strings.ToLower(config.Mail.Driver) {
+	case "smtp":
+		return NewSMTPMailer(config.Mail.From, config.Mail.SMTP.Host, config.Mail.SMTP.Port,
+			config.Mail.SMTP.Username, config.Mail.SMTP.Password), nil
+	case "memory":
+		return NewMemoryMailer(), nil
+	case "", "file":
+		directory := config.Mail.Directory
+		if directory == "" {
+			directory = "tmp/mail"
+		}
+		return NewFileMailer(config.Mail.From, directory), nil
+	default:
+		return nil, fmt.Errorf("unsupported mail driver %q", config.Mail.Driver)
+	}
+}
+
+// format ghi message theo định dạng RFC 5322 tối giản
+func format(from string, message Message, now time.Time) []byte {
+	var builder strings.Builder
+	builder.WriteString("From: " + from + "\r\n")
+	builder.WriteString("To: " + message.To + "\r\n")
+	builder.WriteString("Subject: " + message.Subject + "\r\n")
+	builder.WriteString("Date: " + now.Format(time.RFC1123Z) + "\r\n")
+	builder.WriteString("MIME-Version: 1.0\r\n")
+	builder.WriteString("Content-Type: text/plain; charset=UTF-8\r\n")
+	builder.WriteString("\r\n")
+	builder.WriteString(message.Body)
+	return []byte(builder.String())
+}
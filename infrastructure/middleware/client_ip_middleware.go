@@ -0,0 +1,15 @@
+package middleware
+
+import (
+	"Backend_golang_project/internal/pkg"
+	"github.com/gin-gonic/gin"
+)
+
+// ClientIPMiddleware gắn IP của client vào context của request để tầng service đọc qua pkg.ClientIPFromContext.
+// Header X-Forwarded-For chỉ được dùng khi request đi qua proxy nằm trong danh sách server.trustedProxies
+func ClientIPMiddleware() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		c.Request = c.Request.WithContext(pkg.WithClientIP(c.Request.Context(), c.ClientIP()))
+		c.Next()
+	}
+}
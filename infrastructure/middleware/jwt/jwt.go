@@ -6,11 +6,14 @@ import (
 	"crypto/cipher"
 	"crypto/rand"
 	"crypto/sha1"
+	"crypto/sha256"
 	"encoding/base64"
 	"errors"
 	"fmt"
 	"github.com/golang-jwt/jwt/v5"
+	"github.com/google/uuid"
 	"io"
+	"strconv"
 	"time"
 )
 
@@ -62,49 +65,68 @@ khi mà client login server sẽ xác thực thông tin dựa trên nhưng gì c
     + khi rt hết hạn thì yêu cầu đăng nhập lại
 */
 
+const (
+	TokenUseAccess  = "access"
+	TokenUseRefresh = "refresh"
+)
+
+var ErrTokenUseMismatch = errors.New("token type is not accepted here")
+
 type JWTClaims struct {
-	ID int `json:"id"`
+	ID       int      `json:"id"`
+	TokenUse string   `json:"token_use"`
+	Roles    []string `json:"roles,omitempty"`
 	jwt.RegisteredClaims
 }
 
 type RefreshTokenClaims struct {
-	ID int `json:"id"`
+	ID       int    `json:"id"`
+	TokenUse string `json:"token_use"`
 	jwt.RegisteredClaims
 }
 
-// GenerateJwtToken tạo cặp token mới
-func GenerateJwtToken(config *config.Config, ID int) (string, string, error) {
-	atExpTime := time.Now().Add(time.Duration(config.JwtConfig.AccessTokenExp) * time.Minute)
-	rtExpTime := time.Now().Add(time.Duration(config.JwtConfig.RefreshTokenExp) * time.Minute)
+// TokenSubject thông tin của user được ghi vào token
+type TokenSubject struct {
+	ID    int
+	Roles []string
+}
+
+// RefreshTokenExpiry thời điểm hết hạn của refresh token được phát hành tại thời điểm now
+func RefreshTokenExpiry(config *config.Config, now time.Time) time.Time {
+	return now.Add(time.Duration(config.JwtConfig.RefreshTokenExp) * time.Minute)
+}
+
+// GenerateJwtToken tạo cặp token mới, refreshJTI được ghi vào claim jti của refresh token
+// để token store có thể tra cứu, rotate và thu hồi
+func GenerateJwtToken(config *config.Config, keySet *KeySet, subject TokenSubject, refreshJTI string) (string, string, error) {
+	now := time.Now()
+	atExpTime := now.Add(time.Duration(config.JwtConfig.AccessTokenExp) * time.Minute)
+	rtExpTime := RefreshTokenExpiry(config, now)
 
 	accessClaims := &JWTClaims{
-		ID: ID,
-		RegisteredClaims: jwt.RegisteredClaims{
-			ExpiresAt: jwt.NewNumericDate(atExpTime),
-		},
+		ID:               subject.ID,
+		TokenUse:         TokenUseAccess,
+		Roles:            subject.Roles,
+		RegisteredClaims: registeredClaims(config, subject.ID, uuid.NewString(), now, atExpTime),
 	}
 
 	refreshClaims := &RefreshTokenClaims{
-		ID: ID,
-		RegisteredClaims: jwt.RegisteredClaims{
-			ExpiresAt: jwt.NewNumericDate(rtExpTime),
-		},
+		ID:               subject.ID,
+		TokenUse:         TokenUseRefresh,
+		RegisteredClaims: registeredClaims(config, subject.ID, refreshJTI, now, rtExpTime),
 	}
 
-	accessToken := jwt.NewWithClaims(jwt.SigningMethodHS256, accessClaims)
-	refreshToken := jwt.NewWithClaims(jwt.SigningMethodHS256, refreshClaims)
-
-	accessTokenString, err := accessToken.SignedString([]byte(config.JwtConfig.SecretKey))
+	accessTokenString, err := keySet.Sign(accessClaims)
 	if err != nil {
 		return "", "", err
 	}
 
-	refreshTokenString, err := refreshToken.SignedString([]byte(config.JwtConfig.SecretKey))
+	refreshTokenString, err := keySet.Sign(refreshClaims)
 	if err != nil {
 		return "", "", err
 	}
 
-	encryptedRefreshToken, err := encryptRefreshToken(refreshTokenString, config.JwtConfig.SecretKey)
+	encryptedRefreshToken, err := encryptRefreshToken(refreshTokenString, refreshEncryptionKey(config))
 	if err != nil {
 		return "", "", err
 	}
@@ -112,14 +134,45 @@ func GenerateJwtToken(config *config.Config, ID int) (string, string, error) {
 	return accessTokenString, encryptedRefreshToken, nil
 }
 
-// ClaimToken xác thực token
-func ClaimToken(tokenString string, config *config.Config) (*JWTClaims, error) {
+func registeredClaims(config *config.Config, ID int, jti string, now time.Time, expiresAt time.Time) jwt.RegisteredClaims {
+	claims := jwt.RegisteredClaims{
+		Issuer:    config.JwtConfig.Issuer,
+		Subject:   strconv.Itoa(ID),
+		ExpiresAt: jwt.NewNumericDate(expiresAt),
+		NotBefore: jwt.NewNumericDate(now),
+		IssuedAt:  jwt.NewNumericDate(now),
+		ID:        jti,
+	}
+	if len(config.JwtConfig.Audience) > 0 {
+		claims.Audience = config.JwtConfig.Audience
+	}
+	return claims
+}
+
+// parserOptions kiểm tra chữ ký, thời hạn, issuer và audience theo cấu hình auth
+func parserOptions(config *config.Config, keySet *KeySet) []jwt.ParserOption {
+	options := []jwt.ParserOption{
+		jwt.WithValidMethods(keySet.ValidMethods()),
+		jwt.WithExpirationRequired(),
+		jwt.WithIssuedAt(),
+	}
+	if config.JwtConfig.Issuer != "" {
+		options = append(options, jwt.WithIssuer(config.JwtConfig.Issuer))
+	}
+	// audience đầu tiên là của chính service này, các audience còn lại dành cho service khác xác thực qua JWKS
+	if len(config.JwtConfig.Audience) > 0 {
+		options = append(options, jwt.WithAudience(config.JwtConfig.Audience[0]))
+	}
+	return options
+}
+
+// ClaimToken xác thực access token
+func ClaimToken(tokenString string, config *config.Config, keySet *KeySet) (*JWTClaims, error) {
 	token, err := jwt.ParseWithClaims(
 		tokenString,
 		&JWTClaims{},
-		func(token *jwt.Token) (interface{}, error) {
-			return []byte(config.JwtConfig.SecretKey), nil
-		},
+		keySet.Keyfunc,
+		parserOptions(config, keySet)...,
 	)
 
 	if err != nil {
@@ -130,6 +183,12 @@ func ClaimToken(tokenString string, config *config.Config) (*JWTClaims, error) {
 	if !ok || !token.Valid {
 		return nil, errors.New("invalid token")
 	}
+	if claims.TokenUse != TokenUseAccess {
+		return nil, ErrTokenUseMismatch
+	}
+	if claims.Subject != strconv.Itoa(claims.ID) {
+		return nil, errors.New("token subject does not match user id")
+	}
 	return claims, nil
 }
 
@@ -139,10 +198,14 @@ func handleTokenError(err error) error {
 		return errors.New("malformed token")
 	case errors.Is(err, jwt.ErrTokenExpired):
 		return errors.New("token is expired")
-	case errors.Is(err, jwt.ErrTokenNotValidYet):
+	case errors.Is(err, jwt.ErrTokenNotValidYet), errors.Is(err, jwt.ErrTokenUsedBeforeIssued):
 		return errors.New("token not active yet")
 	case errors.Is(err, jwt.ErrTokenSignatureInvalid):
 		return errors.New("invalid token signature")
+	case errors.Is(err, jwt.ErrTokenInvalidIssuer):
+		return errors.New("token issuer is not accepted")
+	case errors.Is(err, jwt.ErrTokenInvalidAudience):
+		return errors.New("token audience is not accepted")
 	default:
 		return fmt.Errorf("couldn't handle this token: %w", err)
 	}
@@ -150,8 +213,8 @@ func handleTokenError(err error) error {
 
 // ClaimRefreshToken em nghĩ có thể tái sử dụng đoạn mã bên trên theo một cách nào đó,
 // vì ở hàm này chỉ đơn giản là copy bên trên và thêm phần giải mã
-func ClaimRefreshToken(encryptedToken string, config *config.Config) (*RefreshTokenClaims, error) {
-	refreshToken, err := decryptRefreshToken(encryptedToken, config.JwtConfig.SecretKey)
+func ClaimRefreshToken(encryptedToken string, config *config.Config, keySet *KeySet) (*RefreshTokenClaims, error) {
+	refreshToken, err := decryptRefreshToken(encryptedToken, refreshEncryptionKey(config))
 	if err != nil {
 		return nil, err
 	}
@@ -159,31 +222,43 @@ func ClaimRefreshToken(encryptedToken string, config *config.Config) (*RefreshTo
 	token, err := jwt.ParseWithClaims(
 		refreshToken,
 		&RefreshTokenClaims{},
-		func(token *jwt.Token) (interface{}, error) {
-			return []byte(config.JwtConfig.SecretKey), nil
-		},
+		keySet.Keyfunc,
+		parserOptions(config, keySet)...,
 	)
 	if err != nil {
-		return nil, err
+		return nil, handleTokenError(err)
 	}
 
 	claims, ok := token.Claims.(*RefreshTokenClaims)
 	if !ok || !token.Valid {
 		return nil, errors.New("invalid token")
 	}
+	if claims.TokenUse != TokenUseRefresh {
+		return nil, ErrTokenUseMismatch
+	}
 	return claims, nil
 }
 
-// giải mã Refresh token từ thuật toán AES-GCM
-func decryptRefreshToken(encryptedToken string, secretKey string) (string, error) {
+// refreshEncryptionKey khóa AES dùng để mã hóa refresh token.
+// Nếu có auth.refreshTokenKey thì dùng SHA-256 của nó (AES-256), độc lập với khóa ký token;
+// nếu không thì giữ cách cũ: 16 byte đầu SHA1 của jwtSecretKey để các refresh token đã phát hành vẫn giải mã được
+func refreshEncryptionKey(config *config.Config) []byte {
+	if config.JwtConfig.RefreshTokenKey != "" {
+		sum := sha256.Sum256([]byte(config.JwtConfig.RefreshTokenKey))
+		return sum[:]
+	}
+
 	// cách tạo salt em đọc được ở trên diễn đàn nào đó, nó là chuỗi ngẫy nhiên được băm
 	// từ secret key bằng SHA1
 	sha1Hasher := sha1.New()
-	io.WriteString(sha1Hasher, secretKey)
-	salt := string(sha1Hasher.Sum(nil))[0:16]
+	io.WriteString(sha1Hasher, config.JwtConfig.SecretKey)
+	return sha1Hasher.Sum(nil)[0:16]
+}
 
+// giải mã Refresh token từ thuật toán AES-GCM
+func decryptRefreshToken(encryptedToken string, key []byte) (string, error) {
 	// mã hóa salt
-	block, err := aes.NewCipher([]byte(salt))
+	block, err := aes.NewCipher(key)
 	if err != nil {
 		return "", err
 	}
@@ -201,6 +276,9 @@ func decryptRefreshToken(encryptedToken string, secretKey string) (string, error
 
 	// tách nonce và bản mã
 	nonceSize := gcm.NonceSize()
+	if len(data) < nonceSize {
+		return "", errors.New("malformed refresh token")
+	}
 	nonce, ciphertext := data[:nonceSize], data[nonceSize:]
 
 	//giải mã
@@ -213,12 +291,8 @@ func decryptRefreshToken(encryptedToken string, secretKey string) (string, error
 }
 
 // ngược lại với hàm giải mã
-func encryptRefreshToken(refreshToken string, secretKey string) (string, error) {
-	sha1Hasher := sha1.New()
-	io.WriteString(sha1Hasher, secretKey)
-	salt := string(sha1Hasher.Sum(nil))[0:16]
-
-	block, err := aes.NewCipher([]byte(salt))
+func encryptRefreshToken(refreshToken string, key []byte) (string, error) {
+	block, err := aes.NewCipher(key)
 	if err != nil {
 		return "", err
 	}
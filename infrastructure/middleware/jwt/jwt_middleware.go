@@ -2,12 +2,14 @@ package jwt
 
 import (
 	"Backend_golang_project/infrastructure/config"
+	"Backend_golang_project/internal/pkg"
 	"github.com/gin-gonic/gin"
 	"net/http"
 	"strings"
 )
 
-func AuthMiddleware(config *config.Config) gin.HandlerFunc {
+// AuthMiddleware chỉ chấp nhận access token có issuer, audience và token_use hợp lệ
+func AuthMiddleware(config *config.Config, keySet *KeySet) gin.HandlerFunc {
 	return func(c *gin.Context) {
 		authHeader := c.GetHeader("Authorization")
 		if authHeader == "" {
@@ -24,7 +26,7 @@ func AuthMiddleware(config *config.Config) gin.HandlerFunc {
 		}
 
 		tokenString := bearerToken[1]
-		claims, err := ClaimToken(tokenString, config)
+		claims, err := ClaimToken(tokenString, config, keySet)
 		if err != nil {
 			c.JSON(http.StatusUnauthorized, gin.H{"error": err.Error()})
 			c.Abort()
@@ -32,6 +34,17 @@ func AuthMiddleware(config *config.Config) gin.HandlerFunc {
 		}
 
 		c.Set("ID", claims.ID)
+		c.Set("roles", claims.Roles)
+		ctx := pkg.WithUserID(c.Request.Context(), claims.ID)
+		c.Request = c.Request.WithContext(pkg.WithRoles(ctx, claims.Roles))
 		c.Next()
 	}
 }
+
+// JWKSHandler công bố public key của KeySet tại /.well-known/jwks.json
+func JWKSHandler(keySet *KeySet) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		c.Header("Cache-Control", "public, max-age=300")
+		c.JSON(http.StatusOK, keySet.JWKS())
+	}
+}
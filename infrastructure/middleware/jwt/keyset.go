@@ -0,0 +1,224 @@
+package jwt
+
+import (
+	"Backend_golang_project/infrastructure/config"
+	"crypto/ed25519"
+	"crypto/rsa"
+	"encoding/base64"
+	"errors"
+	"fmt"
+	"github.com/golang-jwt/jwt/v5"
+	"math/big"
+	"os"
+	"sort"
+)
+
+const (
+	AlgorithmHS256 = "HS256"
+	AlgorithmRS256 = "RS256"
+	AlgorithmEdDSA = "EdDSA"
+)
+
+// SigningKey là một khóa trong KeySet, signKey có thể nil nếu khóa chỉ còn dùng để xác thực
+type SigningKey struct {
+	Kid       string
+	Method    jwt.SigningMethod
+	signKey   interface{}
+	verifyKey interface{}
+}
+
+// KeySet giữ khóa đang dùng để ký cùng các khóa cũ vẫn còn được chấp nhận khi xác thực,
+// nhờ đó có thể xoay vòng khóa mà không làm mất hiệu lực các token đã phát hành
+type KeySet struct {
+	active *SigningKey
+	keys   map[string]*SigningKey
+}
+
+// JWK là public key theo định dạng RFC 7517
+type JWK struct {
+	Kty string `json:"kty"`
+	Kid string `json:"kid"`
+	Use string `json:"use"`
+	Alg string `json:"alg"`
+	N   string `json:"n,omitempty"`
+	E   string `json:"e,omitempty"`
+	Crv string `json:"crv,omitempty"`
+	X   string `json:"x,omitempty"`
+}
+
+type JWKS struct {
+	Keys []JWK `json:"keys"`
+}
+
+// NewKeySet đọc cấu hình auth.signing, mặc định dùng HS256 với auth.jwtSecretKey
+func NewKeySet(config *config.Config) (*KeySet, error) {
+	signing := config.JwtConfig.Signing
+	algorithm := signing.Algorithm
+	if algorithm == "" {
+		algorithm = AlgorithmHS256
+	}
+
+	if algorithm == AlgorithmHS256 {
+		if config.JwtConfig.SecretKey == "" {
+			return nil, errors.New("auth.jwtSecretKey is required for HS256 signing")
+		}
+		key := &SigningKey{
+			Method:    jwt.SigningMethodHS256,
+			signKey:   []byte(config.JwtConfig.SecretKey),
+			verifyKey: []byte(config.JwtConfig.SecretKey),
+		}
+		return &KeySet{active: key, keys: map[string]*SigningKey{"": key}}, nil
+	}
+
+	keySet := &KeySet{keys: make(map[string]*SigningKey)}
+	for _, keyConfig := range signing.Keys {
+		key, err := loadSigningKey(keyConfig)
+		if err != nil {
+			return nil, fmt.Errorf("cannot load signing key %q: %w", keyConfig.Kid, err)
+		}
+		if _, exists := keySet.keys[key.Kid]; exists {
+			return nil, fmt.Errorf("duplicate signing key id %q", key.Kid)
+		}
+		keySet.keys[key.Kid] = key
+	}
+
+	active, ok := keySet.keys[signing.ActiveKid]
+	if !ok {
+		return nil, fmt.Errorf("active signing key %q is not configured", signing.ActiveKid)
+	}
+	if active.Method.Alg() != algorithm {
+		return nil, fmt.Errorf("active signing key %q uses %s, expected %s", active.Kid, active.Method.Alg(), algorithm)
+	}
+	if active.signKey == nil {
+		return nil, fmt.Errorf("active signing key %q has no private key", active.Kid)
+	}
+	keySet.active = active
+	return keySet, nil
+}
+
+func loadSigningKey(keyConfig config.SigningKeyConfig) (*SigningKey, error) {
+	if keyConfig.Kid == "" {
+		return nil, errors.New("kid is required")
+	}
+	key := &SigningKey{Kid: keyConfig.Kid}
+
+	var privatePEM, publicPEM []byte
+	var err error
+	if keyConfig.PrivateKeyFile != "" {
+		if privatePEM, err = os.ReadFile(keyConfig.PrivateKeyFile); err != nil {
+			return nil, err
+		}
+	}
+	if keyConfig.PublicKeyFile != "" {
+		if publicPEM, err = os.ReadFile(keyConfig.PublicKeyFile); err != nil {
+			return nil, err
+		}
+	}
+	if privatePEM == nil && publicPEM == nil {
+		return nil, errors.New("privateKeyFile or publicKeyFile is required")
+	}
+
+	switch keyConfig.Algorithm {
+	case AlgorithmRS256:
+		key.Method = jwt.SigningMethodRS256
+		if privatePEM != nil {
+			privateKey, err := jwt.ParseRSAPrivateKeyFromPEM(privatePEM)
+			if err != nil {
+				return nil, err
+			}
+			key.signKey = privateKey
+			key.verifyKey = &privateKey.PublicKey
+		} else {
+			if key.verifyKey, err = jwt.ParseRSAPublicKeyFromPEM(publicPEM); err != nil {
+				return nil, err
+			}
+		}
+	case AlgorithmEdDSA:
+		key.Method = jwt.SigningMethodEdDSA
+		if privatePEM != nil {
+			privateKey, err := jwt.ParseEdPrivateKeyFromPEM(privatePEM)
+			if err != nil {
+				return nil, err
+			}
+			key.signKey = privateKey
+			key.verifyKey = privateKey.(ed25519.PrivateKey).Public()
+		} else {
+			if key.verifyKey, err = jwt.ParseEdPublicKeyFromPEM(publicPEM); err != nil {
+				return nil, err
+			}
+		}
+	default:
+		return nil, fmt.Errorf("unsupported algorithm %q", keyConfig.Algorithm)
+	}
+	return key, nil
+}
+
+// Sign ký claims bằng khóa đang active và gắn kid vào header
+func (k *KeySet) Sign(claims jwt.Claims) (string, error) {
+	token := jwt.NewWithClaims(k.active.Method, claims)
+	if k.active.Kid != "" {
+		token.Header["kid"] = k.active.Kid
+	}
+	return token.SignedString(k.active.signKey)
+}
+
+// Keyfunc tìm khóa xác thực theo kid trong header, thuật toán của token phải khớp với khóa
+func (k *KeySet) Keyfunc(token *jwt.Token) (interface{}, error) {
+	kid, _ := token.Header["kid"].(string)
+	key, ok := k.keys[kid]
+	if !ok {
+		return nil, fmt.Errorf("unknown signing key %q", kid)
+	}
+	if token.Method.Alg() != key.Method.Alg() {
+		return nil, fmt.Errorf("unexpected signing method %s", token.Method.Alg())
+	}
+	return key.verifyKey, nil
+}
+
+// ValidMethods danh sách thuật toán được chấp nhận khi parse token
+func (k *KeySet) ValidMethods() []string {
+	seen := make(map[string]bool)
+	var methods []string
+	for _, key := range k.keys {
+		if alg := key.Method.Alg(); !seen[alg] {
+			seen[alg] = true
+			methods = append(methods, alg)
+		}
+	}
+	return methods
+}
+
+// JWKS trả về các public key để service khác xác thực access token, khóa HS256 không bao giờ được công bố
+func (k *KeySet) JWKS() JWKS {
+	kids := make([]string, 0, len(k.keys))
+	for kid := range k.keys {
+		kids = append(kids, kid)
+	}
+	sort.Strings(kids)
+
+	jwks := JWKS{Keys: []JWK{}}
+	for _, kid := range kids {
+		key := k.keys[kid]
+		switch publicKey := key.verifyKey.(type) {
+		case *rsa.PublicKey:
+			jwks.Keys = append(jwks.Keys, JWK{
+				Kty: "RSA",
+				Kid: key.Kid,
+				Use: "sig",
+				Alg: key.Method.Alg(),
+				N:   base64.RawURLEncoding.EncodeToString(publicKey.N.Bytes()),
+				E:   base64.RawURLEncoding.EncodeToString(big.NewInt(int64(publicKey.E)).Bytes()),
+			})
+		case ed25519.PublicKey:
+			jwks.Keys = append(jwks.Keys, JWK{
+				Kty: "OKP",
+				Kid: key.Kid,
+				Use: "sig",
+				Alg: key.Method.Alg(),
+				Crv: "Ed25519",
+				X:   base64.RawURLEncoding.EncodeToString(publicKey),
+			})
+		}
+	}
+	return jwks
+}
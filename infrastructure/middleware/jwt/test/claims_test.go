@@ -0,0 +1,74 @@
+package test
+
+import (
+	"Backend_golang_project/infrastructure/middleware/jwt"
+	"strconv"
+	"testing"
+	"time"
+
+	jwtlib "github.com/golang-jwt/jwt/v5"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestClaimToken_CarriesStandardClaims(t *testing.T) {
+	cfg := baseConfig()
+	keySet, err := jwt.NewKeySet(cfg)
+	require.NoError(t, err)
+
+	accessToken, _, err := jwt.GenerateJwtToken(cfg, keySet, jwt.TokenSubject{ID: 9, Roles: []string{"admin"}}, "jti")
+	require.NoError(t, err)
+
+	claims, err := jwt.ClaimToken(accessToken, cfg, keySet)
+	require.NoError(t, err)
+	assert.Equal(t, "golang-web", claims.Issuer)
+	assert.Equal(t, jwtlib.ClaimStrings{"golang-web-api"}, claims.Audience)
+	assert.Equal(t, strconv.Itoa(9), claims.Subject)
+	assert.Equal(t, jwt.TokenUseAccess, claims.TokenUse)
+	assert.Equal(t, []string{"admin"}, claims.Roles)
+	assert.NotEmpty(t, claims.RegisteredClaims.ID)
+	assert.NotNil(t, claims.IssuedAt)
+	assert.NotNil(t, claims.NotBefore)
+}
+
+func TestClaimToken_RejectsWrongIssuerAndAudience(t *testing.T) {
+	cfg := baseConfig()
+	keySet, err := jwt.NewKeySet(cfg)
+	require.NoError(t, err)
+	accessToken, _, err := jwt.GenerateJwtToken(cfg, keySet, jwt.TokenSubject{ID: 1}, "jti")
+	require.NoError(t, err)
+
+	otherIssuer := baseConfig()
+	otherIssuer.JwtConfig.Issuer = "someone-else"
+	_, err = jwt.ClaimToken(accessToken, otherIssuer, keySet)
+	assert.Error(t, err)
+
+	otherAudience := baseConfig()
+	otherAudience.JwtConfig.Audience = []string{"reporting-service"}
+	_, err = jwt.ClaimToken(accessToken, otherAudience, keySet)
+	assert.Error(t, err)
+}
+
+func TestClaimToken_RejectsRefreshTokenAsAccessToken(t *testing.T) {
+	cfg := baseConfig()
+	keySet, err := jwt.NewKeySet(cfg)
+	require.NoError(t, err)
+
+	now := time.Now()
+	refreshClaims := &jwt.RefreshTokenClaims{
+		ID:       1,
+		TokenUse: jwt.TokenUseRefresh,
+		RegisteredClaims: jwtlib.RegisteredClaims{
+			Issuer:    cfg.JwtConfig.Issuer,
+			Audience:  cfg.JwtConfig.Audience,
+			Subject:   "1",
+			IssuedAt:  jwtlib.NewNumericDate(now),
+			ExpiresAt: jwtlib.NewNumericDate(now.Add(time.Hour)),
+		},
+	}
+	signed, err := keySet.Sign(refreshClaims)
+	require.NoError(t, err)
+
+	_, err = jwt.ClaimToken(signed, cfg, keySet)
+	assert.ErrorIs(t, err, jwt.ErrTokenUseMismatch)
+}
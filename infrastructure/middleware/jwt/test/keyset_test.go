@@ -0,0 +1,181 @@
+package test
+
+import (
+	"Backend_golang_project/infrastructure/config"
+	"Backend_golang_project/infrastructure/middleware/jwt"
+	"crypto/ed25519"
+	"crypto/rand"
+	"crypto/rsa"
+	"crypto/x509"
+	"encoding/pem"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func writePEM(t *testing.T, dir string, name string, blockType string, der []byte) string {
+	path := filepath.Join(dir, name)
+	err := os.WriteFile(path, pem.EncodeToMemory(&pem.Block{Type: blockType, Bytes: der}), 0o600)
+	require.NoError(t, err)
+	return path
+}
+
+func writeRSAKey(t *testing.T, dir string, name string) (string, string) {
+	privateKey, err := rsa.GenerateKey(rand.Reader, 2048)
+	require.NoError(t, err)
+	publicDER, err := x509.MarshalPKIXPublicKey(&privateKey.PublicKey)
+	require.NoError(t, err)
+
+	return writePEM(t, dir, name+".pem", "RSA PRIVATE KEY", x509.MarshalPKCS1PrivateKey(privateKey)),
+		writePEM(t, dir, name+".pub.pem", "PUBLIC KEY", publicDER)
+}
+
+func writeEd25519Key(t *testing.T, dir string, name string) string {
+	_, privateKey, err := ed25519.GenerateKey(rand.Reader)
+	require.NoError(t, err)
+	privateDER, err := x509.MarshalPKCS8PrivateKey(privateKey)
+	require.NoError(t, err)
+	return writePEM(t, dir, name+".pem", "PRIVATE KEY", privateDER)
+}
+
+func baseConfig() *config.Config {
+	cfg := &config.Config{}
+	cfg.JwtConfig.SecretKey = "test-secret-key"
+	cfg.JwtConfig.Issuer = "golang-web"
+	cfg.JwtConfig.Audience = []string{"golang-web-api"}
+	cfg.JwtConfig.AccessTokenExp = 10
+	cfg.JwtConfig.RefreshTokenExp = 60
+	return cfg
+}
+
+func TestKeySet_HS256Default(t *testing.T) {
+	cfg := baseConfig()
+	keySet, err := jwt.NewKeySet(cfg)
+	require.NoError(t, err)
+
+	accessToken, _, err := jwt.GenerateJwtToken(cfg, keySet, jwt.TokenSubject{ID: 42}, "jti")
+	require.NoError(t, err)
+
+	claims, err := jwt.ClaimToken(accessToken, cfg, keySet)
+	require.NoError(t, err)
+	assert.Equal(t, 42, claims.ID)
+	assert.Empty(t, keySet.JWKS().Keys, "shared secrets must never be published")
+}
+
+func TestKeySet_RS256SignsWithKidAndPublishesJWKS(t *testing.T) {
+	dir := t.TempDir()
+	privateFile, _ := writeRSAKey(t, dir, "rsa-1")
+
+	cfg := baseConfig()
+	cfg.JwtConfig.Signing.Algorithm = jwt.AlgorithmRS256
+	cfg.JwtConfig.Signing.ActiveKid = "rsa-1"
+	cfg.JwtConfig.Signing.Keys = []config.SigningKeyConfig{
+		{Kid: "rsa-1", Algorithm: jwt.AlgorithmRS256, PrivateKeyFile: privateFile},
+	}
+
+	keySet, err := jwt.NewKeySet(cfg)
+	require.NoError(t, err)
+
+	accessToken, refreshToken, err := jwt.GenerateJwtToken(cfg, keySet, jwt.TokenSubject{ID: 7}, "jti-7")
+	require.NoError(t, err)
+
+	claims, err := jwt.ClaimToken(accessToken, cfg, keySet)
+	require.NoError(t, err)
+	assert.Equal(t, 7, claims.ID)
+
+	refreshClaims, err := jwt.ClaimRefreshToken(refreshToken, cfg, keySet)
+	require.NoError(t, err)
+	assert.Equal(t, "jti-7", refreshClaims.RegisteredClaims.ID)
+
+	jwks := keySet.JWKS()
+	require.Len(t, jwks.Keys, 1)
+	assert.Equal(t, "RSA", jwks.Keys[0].Kty)
+	assert.Equal(t, "rsa-1", jwks.Keys[0].Kid)
+	assert.Equal(t, "AQAB", jwks.Keys[0].E)
+}
+
+func TestKeySet_RotationKeepsOldTokensValid(t *testing.T) {
+	dir := t.TempDir()
+	oldPrivate, oldPublic := writeRSAKey(t, dir, "rsa-old")
+	newPrivate := writeEd25519Key(t, dir, "ed-new")
+
+	// phát hành token bằng khóa cũ
+	before := baseConfig()
+	before.JwtConfig.Signing.Algorithm = jwt.AlgorithmRS256
+	before.JwtConfig.Signing.ActiveKid = "rsa-old"
+	before.JwtConfig.Signing.Keys = []config.SigningKeyConfig{
+		{Kid: "rsa-old", Algorithm: jwt.AlgorithmRS256, PrivateKeyFile: oldPrivate},
+	}
+	oldKeySet, err := jwt.NewKeySet(before)
+	require.NoError(t, err)
+	oldToken, _, err := jwt.GenerateJwtToken(before, oldKeySet, jwt.TokenSubject{ID: 1}, "jti-old")
+	require.NoError(t, err)
+
+	// chuyển sang EdDSA, khóa RSA cũ chỉ còn public key
+	after := baseConfig()
+	after.JwtConfig.Signing.Algorithm = jwt.AlgorithmEdDSA
+	after.JwtConfig.Signing.ActiveKid = "ed-new"
+	after.JwtConfig.Signing.Keys = []config.SigningKeyConfig{
+		{Kid: "ed-new", Algorithm: jwt.AlgorithmEdDSA, PrivateKeyFile: newPrivate},
+		{Kid: "rsa-old", Algorithm: jwt.AlgorithmRS256, PublicKeyFile: oldPublic},
+	}
+	keySet, err := jwt.NewKeySet(after)
+	require.NoError(t, err)
+
+	claims, err := jwt.ClaimToken(oldToken, after, keySet)
+	require.NoError(t, err)
+	assert.Equal(t, 1, claims.ID)
+
+	newToken, _, err := jwt.GenerateJwtToken(after, keySet, jwt.TokenSubject{ID: 2}, "jti-new")
+	require.NoError(t, err)
+	claims, err = jwt.ClaimToken(newToken, after, keySet)
+	require.NoError(t, err)
+	assert.Equal(t, 2, claims.ID)
+
+	jwks := keySet.JWKS()
+	require.Len(t, jwks.Keys, 2)
+	assert.Equal(t, "OKP", jwks.Keys[0].Kty)
+	assert.Equal(t, "Ed25519", jwks.Keys[0].Crv)
+	assert.Equal(t, "RSA", jwks.Keys[1].Kty)
+}
+
+func TestKeySet_RejectsHS256TokensAfterSwitch(t *testing.T) {
+	dir := t.TempDir()
+	privateFile := writeEd25519Key(t, dir, "ed-1")
+
+	cfg := baseConfig()
+	cfg.JwtConfig.Signing.Algorithm = jwt.AlgorithmEdDSA
+	cfg.JwtConfig.Signing.ActiveKid = "ed-1"
+	cfg.JwtConfig.Signing.Keys = []config.SigningKeyConfig{
+		{Kid: "ed-1", Algorithm: jwt.AlgorithmEdDSA, PrivateKeyFile: privateFile},
+	}
+	keySet, err := jwt.NewKeySet(cfg)
+	require.NoError(t, err)
+
+	// token HS256 ký bằng jwtSecretKey không còn được chấp nhận
+	hsKeySet, err := jwt.NewKeySet(baseConfig())
+	require.NoError(t, err)
+	hsToken, _, err := jwt.GenerateJwtToken(baseConfig(), hsKeySet, jwt.TokenSubject{ID: 1}, "jti")
+	require.NoError(t, err)
+
+	_, err = jwt.ClaimToken(hsToken, cfg, keySet)
+	assert.Error(t, err)
+}
+
+func TestKeySet_ActiveKeyMustHavePrivateKey(t *testing.T) {
+	dir := t.TempDir()
+	_, publicFile := writeRSAKey(t, dir, "rsa-1")
+
+	cfg := baseConfig()
+	cfg.JwtConfig.Signing.Algorithm = jwt.AlgorithmRS256
+	cfg.JwtConfig.Signing.ActiveKid = "rsa-1"
+	cfg.JwtConfig.Signing.Keys = []config.SigningKeyConfig{
+		{Kid: "rsa-1", Algorithm: jwt.AlgorithmRS256, PublicKeyFile: publicFile},
+	}
+
+	_, err := jwt.NewKeySet(cfg)
+	assert.Error(t, err)
+}
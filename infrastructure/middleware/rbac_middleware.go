@@ -0,0 +1,70 @@
+package middleware
+
+import (
+	"Backend_golang_project/internal/pkg"
+	"context"
+	"github.com/gin-gonic/gin"
+	log "github.com/sirupsen/logrus"
+	"net/http"
+	"strconv"
+)
+
+// PermissionResolver trả lời câu hỏi các role có được cấp permission hay không (được implement bởi RoleService)
+type PermissionResolver interface {
+	HasPermission(ctx context.Context, roles []string, permission string) (bool, error)
+}
+
+// RequirePermission phải đặt sau jwt.AuthMiddleware, nó đọc claim roles mà AuthMiddleware gắn vào context
+func RequirePermission(resolver PermissionResolver, permission string) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		if !checkPermission(c, resolver, permission) {
+			return
+		}
+		c.Next()
+	}
+}
+
+// RequireSelfOrPermission cho phép request đi tiếp nếu path param idParam trùng với ID của người gọi,
+// ngược lại người gọi phải có permission, ví dụ GET /users/:id/projects
+func RequireSelfOrPermission(resolver PermissionResolver, idParam string, permission string) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		callerID, ok := pkg.UserIDFromContext(c.Request.Context())
+		if ok && c.Param(idParam) == strconv.Itoa(callerID) {
+			c.Next()
+			return
+		}
+		if !checkPermission(c, resolver, permission) {
+			return
+		}
+		c.Next()
+	}
+}
+
+func checkPermission(c *gin.Context, resolver PermissionResolver, permission string) bool {
+	ctx := c.Request.Context()
+	if _, ok := pkg.UserIDFromContext(ctx); !ok {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "Authorization header is required"})
+		c.Abort()
+		return false
+	}
+
+	granted, err := resolver.HasPermission(ctx, pkg.RolesFromContext(ctx), permission)
+	if err != nil {
+		log.Error("Cannot resolve permission with err: ", err)
+		pkg.AbortErrorHandleCustomMessage(c, http.StatusInternalServerError, err.Error())
+		c.Abort()
+		return false
+	}
+	if !granted {
+		callerID, _ := pkg.UserIDFromContext(ctx)
+		log.WithFields(log.Fields{
+			"user_id":    callerID,
+			"permission": permission,
+			"path":       c.FullPath(),
+		}).Warn("Denied request without permission")
+		pkg.AbortErrorHandler(c, pkg.Forbidden)
+		c.Abort()
+		return false
+	}
+	return true
+}
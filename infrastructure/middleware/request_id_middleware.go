@@ -0,0 +1,23 @@
+package middleware
+
+import (
+	"Backend_golang_project/internal/pkg"
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+)
+
+const RequestIDHeader = "X-Request-ID"
+
+// RequestIDMiddleware dùng X-Request-ID của client (ví dụ do gateway gắn) hoặc tạo mới,
+// trả lại trong response header và gắn vào context để audit log ghi kèm
+func RequestIDMiddleware() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		requestID := c.GetHeader(RequestIDHeader)
+		if requestID == "" || len(requestID) > 64 {
+			requestID = uuid.NewString()
+		}
+		c.Header(RequestIDHeader, requestID)
+		c.Request = c.Request.WithContext(pkg.WithRequestID(c.Request.Context(), requestID))
+		c.Next()
+	}
+}
@@ -0,0 +1,40 @@
+package test
+
+import (
+	"Backend_golang_project/infrastructure/middleware"
+	"Backend_golang_project/internal/pkg"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func clientIPFor(t *testing.T, trustedProxies []string, remoteAddr string, forwardedFor string) string {
+	gin.SetMode(gin.TestMode)
+	engine := gin.New()
+	require.NoError(t, engine.SetTrustedProxies(trustedProxies))
+	engine.Use(middleware.ClientIPMiddleware())
+	var seen string
+	engine.GET("/", func(c *gin.Context) {
+		seen = pkg.ClientIPFromContext(c.Request.Context())
+	})
+
+	req := httptest.NewRequest(http.MethodGet, "/", nil)
+	req.RemoteAddr = remoteAddr
+	req.Header.Set("X-Forwarded-For", forwardedFor)
+	engine.ServeHTTP(httptest.NewRecorder(), req)
+	return seen
+}
+
+func TestClientIPMiddleware_IgnoresForwardedForWithoutTrustedProxy(t *testing.T) {
+	assert.Equal(t, "203.0.113.7", clientIPFor(t, nil, "203.0.113.7:5123", "198.51.100.1"))
+}
+
+func TestClientIPMiddleware_TrustedProxy(t *testing.T) {
+	assert.Equal(t, "198.51.100.1", clientIPFor(t, []string{"10.0.0.0/8"}, "10.0.0.2:5123", "198.51.100.1"))
+	// request không đi qua proxy được tin thì header bị bỏ qua
+	assert.Equal(t, "203.0.113.7", clientIPFor(t, []string{"10.0.0.0/8"}, "203.0.113.7:5123", "198.51.100.1"))
+}
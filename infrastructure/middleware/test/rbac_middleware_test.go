@@ -0,0 +1,62 @@
+package test
+
+import (
+	"Backend_golang_project/infrastructure/middleware"
+	"Backend_golang_project/internal/pkg"
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+)
+
+type staticResolver map[string][]string
+
+func (r staticResolver) HasPermission(_ context.Context, roles []string, permission string) (bool, error) {
+	for _, role := range roles {
+		for _, granted := range r[role] {
+			if granted == permission {
+				return true, nil
+			}
+		}
+	}
+	return false, nil
+}
+
+// fakeAuth thay cho jwt.AuthMiddleware, gắn user ID và roles vào context của request
+func fakeAuth(id int, roles ...string) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		ctx := pkg.WithUserID(c.Request.Context(), id)
+		c.Request = c.Request.WithContext(pkg.WithRoles(ctx, roles))
+		c.Next()
+	}
+}
+
+func serve(handlers ...gin.HandlerFunc) *httptest.ResponseRecorder {
+	gin.SetMode(gin.TestMode)
+	engine := gin.New()
+	engine.GET("/users/:id/projects", append(handlers, func(c *gin.Context) { c.Status(http.StatusOK) })...)
+
+	recorder := httptest.NewRecorder()
+	engine.ServeHTTP(recorder, httptest.NewRequest(http.MethodGet, "/users/2/projects", nil))
+	return recorder
+}
+
+var resolver = staticResolver{
+	"admin":  {"user:read", "user:export"},
+	"member": {"project:read"},
+}
+
+func TestRequirePermission(t *testing.T) {
+	assert.Equal(t, http.StatusOK, serve(fakeAuth(1, "admin"), middleware.RequirePermission(resolver, "user:export")).Code)
+	assert.Equal(t, http.StatusForbidden, serve(fakeAuth(3, "member"), middleware.RequirePermission(resolver, "user:export")).Code)
+	assert.Equal(t, http.StatusUnauthorized, serve(middleware.RequirePermission(resolver, "user:export")).Code)
+}
+
+func TestRequireSelfOrPermission(t *testing.T) {
+	assert.Equal(t, http.StatusOK, serve(fakeAuth(2, "member"), middleware.RequireSelfOrPermission(resolver, "id", "user:read")).Code)
+	assert.Equal(t, http.StatusOK, serve(fakeAuth(1, "admin"), middleware.RequireSelfOrPermission(resolver, "id", "user:read")).Code)
+	assert.Equal(t, http.StatusForbidden, serve(fakeAuth(3, "member"), middleware.RequireSelfOrPermission(resolver, "id", "user:read")).Code)
+}
@@ -0,0 +1,35 @@
+package test
+
+import (
+	"Backend_golang_project/infrastructure/middleware"
+	"Backend_golang_project/internal/pkg"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestRequestIDMiddleware(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	engine := gin.New()
+	engine.Use(middleware.RequestIDMiddleware())
+	var seen string
+	engine.GET("/", func(c *gin.Context) {
+		seen = pkg.RequestIDFromContext(c.Request.Context())
+	})
+
+	req := httptest.NewRequest(http.MethodGet, "/", nil)
+	req.Header.Set(middleware.RequestIDHeader, "gateway-42")
+	recorder := httptest.NewRecorder()
+	engine.ServeHTTP(recorder, req)
+	assert.Equal(t, "gateway-42", seen)
+	assert.Equal(t, "gateway-42", recorder.Header().Get(middleware.RequestIDHeader))
+
+	recorder = httptest.NewRecorder()
+	engine.ServeHTTP(recorder, httptest.NewRequest(http.MethodGet, "/", nil))
+	assert.NotEmpty(t, seen)
+	assert.NotEqual(t, "gateway-42", seen)
+	assert.Equal(t, seen, recorder.Header().Get(middleware.RequestIDHeader))
+}
@@ -4,41 +4,136 @@ import (
 	"Backend_golang_project/infrastructure/config"
 	"Backend_golang_project/infrastructure/middleware"
 	"Backend_golang_project/infrastructure/middleware/jwt"
+	"Backend_golang_project/internal/domain/entities"
 	"Backend_golang_project/internal/handlers"
+	"Backend_golang_project/internal/use_cases"
 	"github.com/gin-gonic/gin"
 	"go.uber.org/fx"
 )
 
 type RegisterRoutersIn struct {
 	fx.In
-	Engine         *gin.Engine
-	ProjectHandler *handlers.ProjectHandler
-	UserHandler    *handlers.UserHandler
-	Config         *config.Config
+	Engine           *gin.Engine
+	ProjectHandler   *handlers.ProjectHandler
+	UserHandler      *handlers.UserHandler
+	AdminHandler     *handlers.AdminHandler
+	AccountHandler   *handlers.AccountHandler
+	TimesheetHandler *handlers.TimesheetHandler
+	CategoryHandler  *handlers.ProjectCategoryHandler
+	AuditHandler     *handlers.AuditHandler
+	RoleService      use_cases.IRoleService
+	Config           *config.Config
+	KeySet           *jwt.KeySet
 }
 
 func NewRegisterRouters(p RegisterRoutersIn) {
 	r := p.Engine
+	auth := jwt.AuthMiddleware(p.Config, p.KeySet)
+	can := func(permission string) gin.HandlerFunc {
+		return middleware.RequirePermission(p.RoleService, permission)
+	}
+	r.GET("/.well-known/jwks.json", jwt.JWKSHandler(p.KeySet))
+
 	v1 := r.Group("/golang-web/api/")
-	v1.Use(middleware.LoggingMiddleware(), middleware.GinRecovery(true))
+	v1.Use(middleware.RequestIDMiddleware(), middleware.LoggingMiddleware(), middleware.GinRecovery(true), middleware.ClientIPMiddleware())
 	{
 		v1.POST("/refresh", p.UserHandler.RefreshToken)
-		v1.GET("/streaming", p.UserHandler.StreamingData)
+		v1.POST("/logout", auth, p.UserHandler.Logout)
+		v1.POST("/logout-all", auth, p.UserHandler.LogoutAll)
+		v1.GET("/streaming", auth, can(entities.PermissionUserExport), p.UserHandler.StreamingData)
+
+		// permission ở đây chỉ chặn theo role toàn hệ thống,
+		// quyền trên từng project vẫn do ProjectPolicy kiểm tra theo membership
 		projectGroup := v1.Group("projects")
-		projectGroup.Use(jwt.AuthMiddleware(p.Config))
+		projectGroup.Use(auth)
+		{
+			projectGroup.POST("/create", can(entities.PermissionProjectCreate), p.ProjectHandler.Create)
+			projectGroup.GET("/:id", can(entities.PermissionProjectRead), p.ProjectHandler.GetById)
+			projectGroup.DELETE("/:id", can(entities.PermissionProjectDelete), p.ProjectHandler.Delete)
+			projectGroup.PUT("/:id", can(entities.PermissionProjectUpdate), p.ProjectHandler.Update)
+			projectGroup.GET("/all", can(entities.PermissionProjectRead), p.ProjectHandler.GetProjects)
+			projectGroup.GET("/trash", can(entities.PermissionProjectDelete), p.ProjectHandler.GetTrash)
+			projectGroup.GET("/slug/:slug", can(entities.PermissionProjectRead), p.ProjectHandler.GetBySlug)
+			projectGroup.POST("/:id/restore", can(entities.PermissionProjectDelete), p.ProjectHandler.Restore)
+
+			projectGroup.GET("/:id/members", can(entities.PermissionProjectRead), p.ProjectHandler.ListMembers)
+			projectGroup.POST("/:id/members", can(entities.PermissionProjectUpdate), p.ProjectHandler.AddMember)
+			projectGroup.DELETE("/:id/members/:user_id", can(entities.PermissionProjectUpdate), p.ProjectHandler.RemoveMember)
+			projectGroup.GET("/:id/transitions", can(entities.PermissionProjectRead), p.ProjectHandler.GetStatusHistory)
+			projectGroup.POST("/:id/transitions", can(entities.PermissionProjectUpdate), p.ProjectHandler.Transition)
+			projectGroup.GET("/:id/versions", can(entities.PermissionProjectRead), p.ProjectHandler.ListVersions)
+			projectGroup.POST("/:id/versions/:version/restore", can(entities.PermissionProjectUpdate), p.ProjectHandler.RestoreVersion)
+			projectGroup.GET("/:id/transactions", can(entities.PermissionProjectRead), p.ProjectHandler.ListTransactions)
+			projectGroup.POST("/:id/transactions", can(entities.PermissionProjectUpdate), p.ProjectHandler.PostTransaction)
+			projectGroup.GET("/:id/revenue-schedule", can(entities.PermissionProjectRead), p.ProjectHandler.GetRevenueSchedule)
+			projectGroup.PUT("/:id/revenue-schedule", can(entities.PermissionProjectUpdate), p.ProjectHandler.SetRevenueSchedule)
+			projectGroup.POST("/:id/revenue-schedule/preview", can(entities.PermissionProjectRead), p.ProjectHandler.PreviewRevenueSchedule)
+			projectGroup.POST("/:id/revenue-schedule/recognise", can(entities.PermissionProjectUpdate), p.ProjectHandler.RecogniseRevenue)
+			projectGroup.GET("/:id/milestones", can(entities.PermissionProjectRead), p.ProjectHandler.ListMilestones)
+			projectGroup.POST("/:id/milestones", can(entities.PermissionProjectUpdate), p.ProjectHandler.CreateMilestone)
+			projectGroup.GET("/:id/milestones/:milestone_id", can(entities.PermissionProjectRead), p.ProjectHandler.GetMilestone)
+			projectGroup.PUT("/:id/milestones/:milestone_id", can(entities.PermissionProjectUpdate), p.ProjectHandler.UpdateMilestone)
+			projectGroup.DELETE("/:id/milestones/:milestone_id", can(entities.PermissionProjectUpdate), p.ProjectHandler.DeleteMilestone)
+			projectGroup.GET("/:id/tasks", can(entities.PermissionProjectRead), p.ProjectHandler.ListTasks)
+			projectGroup.POST("/:id/tasks", can(entities.PermissionProjectUpdate), p.ProjectHandler.CreateTask)
+			projectGroup.GET("/:id/tasks/:task_id", can(entities.PermissionProjectRead), p.ProjectHandler.GetTask)
+			projectGroup.PUT("/:id/tasks/:task_id", can(entities.PermissionProjectUpdate), p.ProjectHandler.UpdateTask)
+			projectGroup.DELETE("/:id/tasks/:task_id", can(entities.PermissionProjectUpdate), p.ProjectHandler.DeleteTask)
+			projectGroup.GET("/:id/timesheets", can(entities.PermissionProjectRead), p.TimesheetHandler.ListProjectTimesheets)
+			projectGroup.GET("/:id/timesheets/summary", can(entities.PermissionProjectRead), p.TimesheetHandler.ProjectSummary)
+		}
+
+		// giờ công của chính người gọi, quyền duyệt do TimesheetService kiểm tra theo vai trò trong project
+		timesheetGroup := v1.Group("timesheets")
+		timesheetGroup.Use(auth)
 		{
-			projectGroup.POST("/create", p.ProjectHandler.Create)
-			projectGroup.GET("/:id", p.ProjectHandler.GetById)
-			projectGroup.DELETE("/:name", p.ProjectHandler.Delete)
-			projectGroup.PUT("/:id", p.ProjectHandler.Update)
-			projectGroup.GET("/all", p.ProjectHandler.GetProjects)
+			timesheetGroup.GET("", can(entities.PermissionProjectRead), p.TimesheetHandler.ListMyTimesheets)
+			timesheetGroup.POST("/entries", can(entities.PermissionProjectUpdate), p.TimesheetHandler.LogTime)
+			timesheetGroup.PUT("/entries/:id", can(entities.PermissionProjectUpdate), p.TimesheetHandler.UpdateEntry)
+			timesheetGroup.DELETE("/entries/:id", can(entities.PermissionProjectUpdate), p.TimesheetHandler.DeleteEntry)
+			timesheetGroup.POST("/:id/submit", can(entities.PermissionProjectUpdate), p.TimesheetHandler.Submit)
+			timesheetGroup.POST("/:id/approve", can(entities.PermissionProjectUpdate), p.TimesheetHandler.Approve)
+			timesheetGroup.POST("/:id/reject", can(entities.PermissionProjectUpdate), p.TimesheetHandler.Reject)
+		}
+
+		// danh sách category cho form tạo/sửa project, thêm/sửa/xóa nằm trong nhóm admin
+		categoryGroup := v1.Group("categories")
+		categoryGroup.Use(auth)
+		{
+			categoryGroup.GET("", can(entities.PermissionProjectRead), p.CategoryHandler.List)
+			categoryGroup.GET("/:id", can(entities.PermissionProjectRead), p.CategoryHandler.Get)
 		}
 
 		userGroup := v1.Group("users")
 		{
+			userGroup.GET("", auth, can(entities.PermissionUserRead), p.UserHandler.ListUsers)
 			userGroup.POST("/create", p.UserHandler.CreateNewUser)
 			userGroup.POST("/login", p.UserHandler.LoginUser)
-			userGroup.GET("/:id/projects", p.UserHandler.GetUserById)
+			userGroup.POST("/password/forgot", p.AccountHandler.ForgotPassword)
+			userGroup.POST("/password/reset", p.AccountHandler.ResetPassword)
+			userGroup.POST("/email/verify", p.AccountHandler.VerifyEmail)
+			userGroup.GET("/me", auth, p.UserHandler.GetProfile)
+			userGroup.PATCH("/me", auth, p.UserHandler.UpdateProfile)
+			userGroup.DELETE("/me", auth, p.UserHandler.DeleteAccount)
+			userGroup.POST("/me/password", auth, p.UserHandler.ChangePassword)
+			userGroup.GET("/:id/projects", auth,
+				middleware.RequireSelfOrPermission(p.RoleService, "id", entities.PermissionUserRead),
+				p.UserHandler.GetUserById)
+			userGroup.GET("/:id/timesheets/summary", auth,
+				middleware.RequireSelfOrPermission(p.RoleService, "id", entities.PermissionUserRead),
+				p.TimesheetHandler.UserSummary)
+		}
+
+		adminGroup := v1.Group("admin")
+		adminGroup.Use(auth)
+		{
+			adminGroup.GET("/roles", can(entities.PermissionRoleAssign), p.AdminHandler.ListRoles)
+			adminGroup.PUT("/users/:id/role", can(entities.PermissionRoleAssign), p.AdminHandler.AssignRole)
+			adminGroup.PUT("/users/:id/hourly-rate", can(entities.PermissionUserManageRates), p.TimesheetHandler.SetHourlyRate)
+			adminGroup.POST("/categories", can(entities.PermissionCategoryManage), p.CategoryHandler.Create)
+			adminGroup.PUT("/categories/:id", can(entities.PermissionCategoryManage), p.CategoryHandler.Update)
+			adminGroup.DELETE("/categories/:id", can(entities.PermissionCategoryManage), p.CategoryHandler.Delete)
+			adminGroup.GET("/audit-events", can(entities.PermissionAuditRead), p.AuditHandler.ListEvents)
 		}
 
 	}
@@ -12,17 +12,24 @@ import (
 	"go.uber.org/fx"
 )
 
-func registerCustomValidators() {
+func registerCustomValidators(categories pkg.CategoryCatalog) {
 	if v, ok := binding.Validator.Engine().(*validator.Validate); ok {
 		v.RegisterValidation("password_strength", pkg.PasswordValidator)
-		v.RegisterValidation("valid_category", pkg.CategoryValidator)
+		v.RegisterValidation("valid_category", pkg.NewCategoryValidator(categories))
 		v.RegisterValidation("future_date", pkg.ProjectStartDateValidator)
+		v.RegisterValidation("slug", pkg.SlugValidator)
 	}
 }
 
-func NewGinEngine(lc fx.Lifecycle, config *config.Config) *gin.Engine {
+func NewGinEngine(lc fx.Lifecycle, config *config.Config, categories pkg.CategoryCatalog) (*gin.Engine, error) {
 	engine := gin.New()
-	registerCustomValidators()
+	// cho phép service đọc các giá trị AuthMiddleware gắn vào request context qua *gin.Context
+	engine.ContextWithFallback = true
+	// mặc định gin tin mọi proxy, khi đó client tự gửi X-Forwarded-For là đổi được IP dùng cho giới hạn đăng nhập và audit log
+	if err := engine.SetTrustedProxies(config.HttpConfig.TrustedProxies); err != nil {
+		return nil, fmt.Errorf("invalid trusted proxies: %w", err)
+	}
+	registerCustomValidators(categories)
 	lc.Append(fx.Hook{
 		OnStart: func(ctx context.Context) error {
 			log.Info("Starting HTTP server")
@@ -40,5 +47,5 @@ func NewGinEngine(lc fx.Lifecycle, config *config.Config) *gin.Engine {
 			return nil
 		},
 	})
-	return engine
+	return engine, nil
 }
@@ -0,0 +1,16 @@
+package request
+
+import "Backend_golang_project/internal/domain/entities"
+
+type AddProjectMemberRequest struct {
+	UserID int    `json:"user_id" binding:"required,gt=0"`
+	Role   string `json:"role" binding:"required,oneof=owner manager contributor viewer"`
+}
+
+func (req *AddProjectMemberRequest) ToUserProjectEntity(projectID int) *entities.UserProject {
+	return &entities.UserProject{
+		ProjectID: projectID,
+		UserID:    req.UserID,
+		Role:      req.Role,
+	}
+}
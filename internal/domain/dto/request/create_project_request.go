@@ -2,27 +2,41 @@ package request
 
 import (
 	"Backend_golang_project/internal/domain/entities"
+	"Backend_golang_project/internal/domain/money"
 	"time"
 )
 
+// CreateProjectRequest budget/project_spend tính theo đơn vị nhỏ nhất của currency (cent với USD),
+// currency bỏ trống thì là VND. project_variance do server tính từ budget và spend.
+// slug tùy chọn, phải chưa được dùng trong các project do người gọi tạo
 type CreateProjectRequest struct {
 	Name             string     `json:"name" binding:"required,lte=255"`
 	Category         string     `json:"category" binding:"required,valid_category"`
-	ProjectSpend     int        `json:"project_spend"`
-	ProjectVariance  int        `json:"project_variance"`
+	Currency         string     `json:"currency" binding:"omitempty,len=3"`
+	Budget           int64      `json:"budget" binding:"gte=0"`
+	ProjectSpend     int64      `json:"project_spend"`
 	ProjectStartedAt time.Time  `json:"project_started_at" binding:"required,future_date"`
 	ProjectEndedAt   *time.Time `json:"project_ended_at"`
+	Slug             *string    `json:"slug,omitempty" binding:"omitempty,slug"`
 }
 
 func (req *CreateProjectRequest) ToProjectEntity() *entities.Project {
+	currency := req.Currency
+	if currency == "" {
+		currency = money.DefaultCurrency
+	}
 	project := &entities.Project{
 		Name:              req.Name,
 		Category:          req.Category,
-		ProjectSpend:      req.ProjectSpend,
-		ProjectVariance:   req.ProjectVariance,
+		Status:            entities.ProjectStatusDraft,
+		Budget:            money.New(req.Budget, currency),
+		ProjectSpend:      money.New(req.ProjectSpend, currency),
 		ProjectStartedAt:  req.ProjectStartedAt,
 		ProjectEndedAt:    req.ProjectEndedAt,
-		RevenueRecognised: 0,
+		RevenueRecognised: money.New(0, currency),
+	}
+	if req.Slug != nil && *req.Slug != "" {
+		project.Slug = req.Slug
 	}
 	return project
 }
@@ -0,0 +1,15 @@
+package request
+
+import "time"
+
+// GetProjectRequest query string của GET /projects/:id, asOf theo RFC 3339 (ví dụ ?asOf=2024-06-30T23:59:59Z)
+// để đọc trạng thái của project tại thời điểm đó thay vì trạng thái hiện tại
+type GetProjectRequest struct {
+	AsOf *time.Time `form:"asOf" time_format:"2006-01-02T15:04:05Z07:00"`
+}
+
+// GetProjectBySlugRequest query string của GET /projects/slug/:slug, owner là ID người tạo project,
+// bỏ trống thì tìm trong các project do người gọi tạo
+type GetProjectBySlugRequest struct {
+	Owner *int `form:"owner" binding:"omitempty,gt=0"`
+}
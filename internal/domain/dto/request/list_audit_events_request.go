@@ -0,0 +1,15 @@
+package request
+
+import "time"
+
+// ListAuditEventsRequest query string của GET /admin/audit-events, thời gian theo RFC 3339,
+// ví dụ ?entity_type=project&entity_id=5&from=2024-09-01T00:00:00Z, phân trang xem PageQuery
+type ListAuditEventsRequest struct {
+	PageQuery
+	EntityType string     `form:"entity_type" binding:"omitempty,oneof=user project"`
+	EntityID   int        `form:"entity_id" binding:"omitempty,gt=0"`
+	ActorID    int        `form:"actor_id" binding:"omitempty,gt=0"`
+	Action     string     `form:"action" binding:"omitempty,oneof=create update delete"`
+	From       *time.Time `form:"from" time_format:"2006-01-02T15:04:05Z07:00"`
+	To         *time.Time `form:"to" time_format:"2006-01-02T15:04:05Z07:00"`
+}
@@ -0,0 +1,23 @@
+package request
+
+import "time"
+
+// ListProjectsRequest query string của GET /projects/all, thời gian theo RFC 3339,
+// ví dụ ?category=client&started_from=2024-01-01T00:00:00Z&sort=-project_spend,name, phân trang xem PageQuery.
+// currency=USD quy đổi các khoản tiền trong kết quả sang USD, spend_min/variance_* vẫn so với số tiền gốc
+type ListProjectsRequest struct {
+	PageQuery
+	Category    string     `form:"category" binding:"omitempty,valid_category"`
+	Status      string     `form:"status" binding:"omitempty,oneof=draft active on_hold completed archived"`
+	Name        string     `form:"name" binding:"max=255"`
+	StartedFrom *time.Time `form:"started_from" time_format:"2006-01-02T15:04:05Z07:00"`
+	StartedTo   *time.Time `form:"started_to" time_format:"2006-01-02T15:04:05Z07:00"`
+	EndedFrom   *time.Time `form:"ended_from" time_format:"2006-01-02T15:04:05Z07:00"`
+	EndedTo     *time.Time `form:"ended_to" time_format:"2006-01-02T15:04:05Z07:00"`
+	SpendMin    *int       `form:"spend_min"`
+	SpendMax    *int       `form:"spend_max"`
+	VarianceMin *int       `form:"variance_min"`
+	VarianceMax *int       `form:"variance_max"`
+	Sort        string     `form:"sort" binding:"max=255"`
+	Currency    string     `form:"currency" binding:"omitempty,len=3"`
+}
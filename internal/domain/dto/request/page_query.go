@@ -0,0 +1,26 @@
+package request
+
+import "Backend_golang_project/internal/pkg"
+
+// PageQuery tham số phân trang dùng chung cho các API danh sách: ?page=2&pageSize=20 (offset)
+// hoặc ?cursor=<next_cursor|prev_cursor>&pageSize=20 (keyset, page bị bỏ qua).
+// include_total mặc định true với offset, false với cursor để không phải COUNT(*) ở mỗi trang
+type PageQuery struct {
+	Page         int    `form:"page,default=1" binding:"min=1"`
+	PageSize     int    `form:"pageSize,default=10" binding:"min=1,max=100"`
+	Cursor       string `form:"cursor" binding:"max=2048"`
+	IncludeTotal *bool  `form:"include_total"`
+}
+
+func (q PageQuery) ToPageRequest() pkg.PageRequest {
+	includeTotal := q.Cursor == ""
+	if q.IncludeTotal != nil {
+		includeTotal = *q.IncludeTotal
+	}
+	return pkg.PageRequest{
+		Page:         q.Page,
+		PageSize:     q.PageSize,
+		Cursor:       q.Cursor,
+		IncludeTotal: includeTotal,
+	}
+}
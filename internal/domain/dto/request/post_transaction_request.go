@@ -0,0 +1,35 @@
+package request
+
+import (
+	"Backend_golang_project/internal/domain/entities"
+	"Backend_golang_project/internal/domain/money"
+	"strings"
+	"time"
+)
+
+// PostTransactionRequest body của POST /projects/:id/transactions.
+// target chỉ cần với type adjustment, amount (đơn vị nhỏ nhất của currency) của adjustment được phép âm,
+// currency phải trùng với đơn vị tiền của project
+type PostTransactionRequest struct {
+	Type          string    `json:"type" binding:"required,oneof=spend revenue adjustment"`
+	Target        string    `json:"target" binding:"omitempty,oneof=spend revenue"`
+	Amount        int64     `json:"amount" binding:"required"`
+	Currency      string    `json:"currency" binding:"required,len=3"`
+	EffectiveDate time.Time `json:"effective_date" binding:"required"`
+	Description   string    `json:"description" binding:"max=500"`
+}
+
+func (req *PostTransactionRequest) ToProjectTransactionEntity(projectID int) *entities.ProjectTransaction {
+	target := req.Target
+	if target == "" {
+		target = entities.TransactionTarget(req.Type)
+	}
+	return &entities.ProjectTransaction{
+		ProjectID:     projectID,
+		Type:          req.Type,
+		Target:        target,
+		Amount:        money.New(req.Amount, req.Currency),
+		EffectiveDate: req.EffectiveDate,
+		Description:   strings.TrimSpace(req.Description),
+	}
+}
@@ -0,0 +1,32 @@
+package request
+
+import (
+	"Backend_golang_project/internal/domain/entities"
+	"strings"
+)
+
+// ProjectCategoryRequest body của PUT /admin/categories/:id, default_revenue_method chỉ dùng được với category billable
+type ProjectCategoryRequest struct {
+	Description          string `json:"description" binding:"max=255"`
+	Billable             bool   `json:"billable"`
+	DefaultRevenueMethod string `json:"default_revenue_method" binding:"omitempty,oneof=straight_line milestone percent_complete"`
+}
+
+// CreateProjectCategoryRequest body của POST /admin/categories, name là giá trị projects.category dùng và không đổi được
+type CreateProjectCategoryRequest struct {
+	Name string `json:"name" binding:"required,max=50"`
+	ProjectCategoryRequest
+}
+
+// ApplyTo ghi các thuộc tính trong request vào category mới hoặc đang có
+func (req *ProjectCategoryRequest) ApplyTo(category *entities.ProjectCategory) {
+	category.Description = strings.TrimSpace(req.Description)
+	category.Billable = req.Billable
+	category.DefaultRevenueMethod = req.DefaultRevenueMethod
+}
+
+func (req *CreateProjectCategoryRequest) ToProjectCategoryEntity() *entities.ProjectCategory {
+	category := &entities.ProjectCategory{Name: strings.ToLower(strings.TrimSpace(req.Name))}
+	req.ApplyTo(category)
+	return category
+}
@@ -0,0 +1,7 @@
+package request
+
+// ProjectTransitionRequest body của POST /projects/:id/transitions, status là trạng thái đích
+type ProjectTransitionRequest struct {
+	Status string `json:"status" binding:"required,oneof=draft active on_hold completed archived"`
+	Reason string `json:"reason" binding:"required,max=500"`
+}
@@ -0,0 +1,61 @@
+package request
+
+import (
+	"Backend_golang_project/internal/domain/entities"
+	"strings"
+	"time"
+)
+
+// WorkItemRequest các trường chung của milestone và task, bỏ trống status thì là todo.
+// assignee_id phải là thành viên của project
+type WorkItemRequest struct {
+	Description   string     `json:"description" binding:"max=2000"`
+	AssigneeID    *int       `json:"assignee_id" binding:"omitempty,gt=0"`
+	DueDate       *time.Time `json:"due_date"`
+	Status        string     `json:"status" binding:"omitempty,oneof=todo in_progress done cancelled"`
+	EstimateHours float64    `json:"estimate_hours" binding:"gte=0,lte=100000"`
+}
+
+// MilestoneRequest body của POST /projects/:id/milestones và PUT /projects/:id/milestones/:milestone_id
+type MilestoneRequest struct {
+	Name string `json:"name" binding:"required,max=255"`
+	WorkItemRequest
+}
+
+// TaskRequest body của POST /projects/:id/tasks và PUT /projects/:id/tasks/:task_id,
+// milestone_id phải là milestone của cùng project
+type TaskRequest struct {
+	Title       string `json:"title" binding:"required,max=255"`
+	MilestoneID *int   `json:"milestone_id" binding:"omitempty,gt=0"`
+	WorkItemRequest
+}
+
+// ApplyTo ghi các trường của request vào work item, trạng thái được đổi qua SetStatus để giữ đúng CompletedAt
+func (req *WorkItemRequest) ApplyTo(item *entities.WorkItem, now time.Time) {
+	item.Description = strings.TrimSpace(req.Description)
+	item.AssigneeID = req.AssigneeID
+	item.DueDate = nil
+	if req.DueDate != nil {
+		dueDate := entities.Day(*req.DueDate)
+		item.DueDate = &dueDate
+	}
+	item.EstimateHours = req.EstimateHours
+	status := req.Status
+	if status == "" {
+		status = entities.WorkStatusTodo
+	}
+	item.SetStatus(status, now)
+}
+
+// ApplyTo ghi request vào milestone mới hoặc milestone đang có
+func (req *MilestoneRequest) ApplyTo(milestone *entities.Milestone, now time.Time) {
+	milestone.Name = strings.TrimSpace(req.Name)
+	req.WorkItemRequest.ApplyTo(&milestone.WorkItem, now)
+}
+
+// ApplyTo ghi request vào task mới hoặc task đang có
+func (req *TaskRequest) ApplyTo(task *entities.Task, now time.Time) {
+	task.Title = strings.TrimSpace(req.Title)
+	task.MilestoneID = req.MilestoneID
+	req.WorkItemRequest.ApplyTo(&task.WorkItem, now)
+}
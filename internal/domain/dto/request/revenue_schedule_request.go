@@ -0,0 +1,49 @@
+package request
+
+import (
+	"Backend_golang_project/internal/domain/entities"
+	"Backend_golang_project/internal/domain/money"
+	"strings"
+	"time"
+)
+
+// RevenueScheduleRequest body của PUT /projects/:id/revenue-schedule và POST .../preview.
+// contract_value và amount của milestone theo đơn vị nhỏ nhất của đơn vị tiền project,
+// percent_complete chỉ dùng với percent_complete, milestones chỉ dùng với milestone.
+// Bỏ trống method thì dùng default_revenue_method của category
+type RevenueScheduleRequest struct {
+	Method          string                    `json:"method" binding:"omitempty,oneof=straight_line milestone percent_complete"`
+	ContractValue   int64                     `json:"contract_value" binding:"required,gt=0"`
+	PercentComplete int                       `json:"percent_complete" binding:"gte=0,lte=100"`
+	Milestones      []RevenueMilestoneRequest `json:"milestones" binding:"dive"`
+}
+
+type RevenueMilestoneRequest struct {
+	Name        string     `json:"name" binding:"required,max=255"`
+	Amount      int64      `json:"amount" binding:"required,gt=0"`
+	DueDate     time.Time  `json:"due_date" binding:"required"`
+	CompletedAt *time.Time `json:"completed_at"`
+}
+
+func (req *RevenueScheduleRequest) ToRevenueScheduleEntity(project *entities.Project) *entities.RevenueSchedule {
+	schedule := &entities.RevenueSchedule{
+		ProjectID:       project.ID,
+		Method:          req.Method,
+		ContractValue:   money.New(req.ContractValue, project.Currency()),
+		PercentComplete: req.PercentComplete,
+	}
+	for _, milestone := range req.Milestones {
+		schedule.Milestones = append(schedule.Milestones, entities.RevenueMilestone{
+			Name:        strings.TrimSpace(milestone.Name),
+			Amount:      milestone.Amount,
+			DueDate:     entities.Day(milestone.DueDate),
+			CompletedAt: milestone.CompletedAt,
+		})
+	}
+	return schedule
+}
+
+// RecogniseRevenueRequest body của POST /projects/:id/revenue-schedule/recognise, bỏ trống as_of thì là hôm nay
+type RecogniseRevenueRequest struct {
+	AsOf *time.Time `json:"as_of"`
+}
@@ -0,0 +1,60 @@
+package request
+
+import (
+	"Backend_golang_project/internal/domain/entities"
+	"strings"
+	"time"
+)
+
+// HourlyRateRequest body của PUT /admin/users/:id/hourly-rate, amount theo đơn vị nhỏ nhất của currency
+type HourlyRateRequest struct {
+	Amount   int64  `json:"amount" binding:"gte=0"`
+	Currency string `json:"currency" binding:"required,len=3"`
+}
+
+// TimesheetEntryRequest body của PUT /timesheets/entries/:id, dòng giờ công không chuyển được sang project khác
+type TimesheetEntryRequest struct {
+	WorkDate    time.Time `json:"work_date" binding:"required"`
+	Hours       float64   `json:"hours" binding:"required,gt=0,lte=24"`
+	TaskID      *int      `json:"task_id" binding:"omitempty,gt=0"`
+	Description string    `json:"description" binding:"max=500"`
+}
+
+// LogTimeRequest body của POST /timesheets/entries
+type LogTimeRequest struct {
+	ProjectID int `json:"project_id" binding:"required,gt=0"`
+	TimesheetEntryRequest
+}
+
+// ApplyTo ghi request vào dòng giờ công mới hoặc đang có, WorkDate được cắt về đầu ngày (UTC)
+func (req *TimesheetEntryRequest) ApplyTo(entry *entities.TimesheetEntry) {
+	entry.WorkDate = entities.Day(req.WorkDate)
+	entry.Hours = req.Hours
+	entry.TaskID = req.TaskID
+	entry.Description = strings.TrimSpace(req.Description)
+}
+
+func (req *LogTimeRequest) ToTimesheetEntryEntity(userID int) *entities.TimesheetEntry {
+	entry := &entities.TimesheetEntry{UserID: userID, ProjectID: req.ProjectID}
+	req.ApplyTo(entry)
+	return entry
+}
+
+// ListTimesheetsRequest query string của GET /timesheets và GET /projects/:id/timesheets, week là một ngày bất kỳ trong tuần
+type ListTimesheetsRequest struct {
+	Week   *time.Time `form:"week" time_format:"2006-01-02"`
+	Status string     `form:"status" binding:"omitempty,oneof=draft submitted approved rejected"`
+}
+
+// RejectTimesheetRequest body của POST /timesheets/:id/reject, note là lý do gửi lại cho người nộp
+type RejectTimesheetRequest struct {
+	Note string `json:"note" binding:"required,max=500"`
+}
+
+// TimesheetSummaryRequest query string của các endpoint tổng hợp giờ công.
+// group: user hoặc week với project, project hoặc week với user; bỏ trống thì lấy giá trị đầu tiên
+type TimesheetSummaryRequest struct {
+	From  *time.Time `form:"from" time_format:"2006-01-02"`
+	To    *time.Time `form:"to" time_format:"2006-01-02"`
+	Group string     `form:"group" binding:"omitempty,oneof=user project week"`
+}
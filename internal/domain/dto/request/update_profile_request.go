@@ -0,0 +1,17 @@
+package request
+
+// UpdateProfileRequest các trường bỏ trống (null) được giữ nguyên
+type UpdateProfileRequest struct {
+	Username *string `json:"username" binding:"omitempty,min=1,max=255"`
+	Email    *string `json:"email" binding:"omitempty,email"`
+}
+
+type ChangePasswordRequest struct {
+	CurrentPassword string `json:"current_password" binding:"required"`
+	NewPassword     string `json:"new_password" binding:"required,min=8,max=20,password_strength"`
+}
+
+// DeleteAccountRequest yêu cầu nhập lại mật khẩu để tránh xóa nhầm tài khoản bằng access token bị lộ
+type DeleteAccountRequest struct {
+	Password string `json:"password" binding:"required"`
+}
@@ -2,12 +2,17 @@ package request
 
 import "time"
 
+// UpdateProjectRequest spend/revenue không sửa trực tiếp được, chúng được ghi qua sổ cái POST /projects/:id/transactions.
+// budget theo đơn vị tiền của project (đơn vị tiền không đổi được sau khi tạo), bỏ trống thì giữ nguyên;
+// project_variance do server tính lại từ budget; bỏ trống category thì giữ nguyên.
+// slug bỏ trống (không gửi) thì giữ nguyên, gửi chuỗi rỗng thì bỏ slug của project
 type UpdateProjectRequest struct {
-	Name              string     `json:"name"`
-	Category          string     `json:"category"`
-	ProjectSpend      int        `json:"project_spend"`
-	ProjectVariance   int        `json:"project_variance"`
-	RevenueRecognised int        `json:"revenue_recognised"`
-	ProjectStartedAt  time.Time  `json:"project_started_at"`
-	ProjectEndedAt    *time.Time `json:"project_ended_at,omitempty"`
+	Name             string     `json:"name"`
+	Category         string     `json:"category" binding:"omitempty,valid_category"`
+	Budget           *int64     `json:"budget,omitempty" binding:"omitempty,gte=0"`
+	ProjectStartedAt time.Time  `json:"project_started_at"`
+	ProjectEndedAt   *time.Time `json:"project_ended_at,omitempty"`
+	Slug             *string    `json:"slug,omitempty" binding:"omitempty,slug"`
+	// ExpectedVersion lấy từ header If-Match, nil khi header không được gửi (hoặc là *)
+	ExpectedVersion *int `json:"-"`
 }
@@ -0,0 +1,26 @@
+package response
+
+import (
+	"Backend_golang_project/internal/domain/entities"
+	"time"
+)
+
+type UserProfileResponse struct {
+	ID              int        `json:"id"`
+	Email           string     `json:"email"`
+	Username        string     `json:"username"`
+	Role            string     `json:"role"`
+	EmailVerifiedAt *time.Time `json:"email_verified_at"`
+	CreatedAt       time.Time  `json:"created_at"`
+}
+
+func ToUserProfileResponse(user *entities.User) *UserProfileResponse {
+	return &UserProfileResponse{
+		ID:              user.ID,
+		Email:           user.Email,
+		Username:        user.Username,
+		Role:            user.Role,
+		EmailVerifiedAt: user.EmailVerifiedAt,
+		CreatedAt:       user.CreatedAt,
+	}
+}
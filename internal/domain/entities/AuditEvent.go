@@ -0,0 +1,60 @@
+package entities
+
+import (
+	"database/sql/driver"
+	"encoding/json"
+	"fmt"
+	"time"
+)
+
+// Hành động được ghi vào audit_events
+const (
+	AuditActionCreate = "create"
+	AuditActionUpdate = "update"
+	AuditActionDelete = "delete"
+)
+
+// Loại entity được audit, mỗi loại ứng với một bảng
+const (
+	AuditEntityUser    = "user"
+	AuditEntityProject = "project"
+)
+
+// AuditEvent một lần ghi vào bảng users hoặc projects, chỉ được thêm mới và không bao giờ sửa hay xóa.
+// Before/After chỉ gồm các cột thay đổi (create: After là toàn bộ bản ghi, delete: Before là toàn bộ bản ghi),
+// ActorID nil khi thay đổi không đến từ request của user (ví dụ job chạy nền)
+type AuditEvent struct {
+	ID         int         `gorm:"primaryKey;autoIncrement" json:"id"`
+	ActorID    *int        `gorm:"index" json:"actor_id"`
+	Action     string      `gorm:"size:10;not null" json:"action"`
+	EntityType string      `gorm:"size:20;not null;index:idx_audit_events_entity,priority:1" json:"entity_type"`
+	EntityID   int         `gorm:"not null;index:idx_audit_events_entity,priority:2" json:"entity_id"`
+	Before     AuditValues `gorm:"type:json" json:"before"`
+	After      AuditValues `gorm:"type:json" json:"after"`
+	RequestID  string      `gorm:"size:64;not null;default:''" json:"request_id"`
+	ClientIP   string      `gorm:"size:45;not null;default:''" json:"client_ip"`
+	CreatedAt  time.Time   `gorm:"autoCreateTime;index" json:"created_at"`
+}
+
+// AuditValues giá trị các cột theo tên cột, lưu dưới dạng JSON
+type AuditValues map[string]interface{}
+
+func (v AuditValues) Value() (driver.Value, error) {
+	if v == nil {
+		return nil, nil
+	}
+	return json.Marshal(v)
+}
+
+func (v *AuditValues) Scan(value interface{}) error {
+	switch raw := value.(type) {
+	case nil:
+		*v = nil
+		return nil
+	case []byte:
+		return json.Unmarshal(raw, v)
+	case string:
+		return json.Unmarshal([]byte(raw), v)
+	}
+	return fmt.Errorf("cannot scan %T into AuditValues", value)
+}
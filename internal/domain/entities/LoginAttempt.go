@@ -0,0 +1,21 @@
+package entities
+
+import "time"
+
+// LoginAttempt đếm số lần đăng nhập sai liên tiếp theo một khóa, ví dụ "email:a@b.com" hoặc "ip:10.0.0.1"
+type LoginAttempt struct {
+	ID           int       `gorm:"primaryKey;autoIncrement"`
+	Key          string    `gorm:"column:attempt_key;size:320;uniqueIndex;not null"`
+	Failures     int       `gorm:"not null;default:0"`
+	LastFailedAt time.Time `gorm:"not null"`
+	LockedUntil  *time.Time
+	UpdatedAt    time.Time `gorm:"autoUpdateTime"`
+}
+
+func (LoginAttempt) TableName() string {
+	return "login_attempts"
+}
+
+func (a *LoginAttempt) IsLocked(now time.Time) bool {
+	return a.LockedUntil != nil && now.Before(*a.LockedUntil)
+}
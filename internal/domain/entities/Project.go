@@ -1,19 +1,61 @@
 package entities
 
-import "time"
+import (
+	"Backend_golang_project/internal/domain/money"
+	"gorm.io/gorm"
+	"time"
+)
 
 type Project struct {
-	ID                int        `gorm:"primaryKey,autoIncrement"`
-	Name              string     `gorm:"notnull"`
-	Category          string     `gorm:"notnull"`
-	ProjectSpend      int        `gorm:"notnull, default=0"`
-	ProjectVariance   int        `gorm:"notnull, default=0"`
-	RevenueRecognised int        `gorm:"notnull, default=0"`
-	ProjectStartedAt  time.Time  `gorm:"notnull"`
-	ProjectEndedAt    *time.Time `gorm:"type:datetime"`
-	CreatedAt         time.Time  `gorm:"autoCreateTime"`
-	UpdatedAt         time.Time  `gorm:"autoUpdateTime"`
-	DeletedAt         *time.Time `gorm:"index"`
+	ID                int         `gorm:"primaryKey,autoIncrement"`
+	Name              string      `gorm:"notnull"`
+	Category          string      `gorm:"notnull"`
+	Status            string      `gorm:"size:20;not null;default:draft;index"`
+	Budget            money.Money `gorm:"embedded;embeddedPrefix:budget_"`
+	ProjectSpend      money.Money `gorm:"embedded;embeddedPrefix:project_spend_"`
+	ProjectVariance   money.Money `gorm:"embedded;embeddedPrefix:project_variance_"`
+	RevenueRecognised money.Money `gorm:"embedded;embeddedPrefix:revenue_recognised_"`
+	ProjectStartedAt  time.Time   `gorm:"notnull"`
+	ProjectEndedAt    *time.Time  `gorm:"type:datetime"`
+	// OwnerID người tạo project, không đổi khi quyền owner được trao cho người khác; nil với project tạo trước khi có cột này.
+	// Slug (tùy chọn) là khóa dễ đọc của project, duy nhất trong các project của cùng OwnerID;
+	// project trong thùng rác vẫn giữ slug cho tới khi bị xóa hẳn
+	OwnerID *int    `gorm:"uniqueIndex:idx_projects_owner_slug"`
+	Slug    *string `gorm:"size:64;uniqueIndex:idx_projects_owner_slug"`
+	// Version tăng sau mỗi lần ghi vào project (trùng với số version mới nhất trong project_versions),
+	// được trả về qua ETag và dùng để kiểm tra If-Match khi cập nhật
+	Version   int       `gorm:"not null;default:0"`
+	CreatedAt time.Time `gorm:"autoCreateTime"`
+	UpdatedAt time.Time `gorm:"autoUpdateTime"`
+	// DeletedAt xóa mềm, GORM tự loại project đã xóa khỏi mọi truy vấn; project nằm trong thùng rác
+	// tới khi được khôi phục hoặc bị job dọn thùng rác xóa hẳn
+	DeletedAt gorm.DeletedAt `gorm:"index"`
 
-	Users []User `gorm:"many2many:user_projects;"`
+	Users        []User               `gorm:"many2many:user_projects;"`
+	Members      []UserProject        `gorm:"foreignKey:ProjectID" json:"-"`
+	Transactions []ProjectTransaction `gorm:"foreignKey:ProjectID" json:"-"`
+
+	// Progress được tổng hợp từ project_milestones và project_tasks khi đọc project, không lưu trong bảng projects
+	Progress *ProjectProgress `gorm:"-"`
+}
+
+// Currency đơn vị tiền của project, mọi khoản tiền và bút toán của project dùng chung đơn vị này
+func (p *Project) Currency() string {
+	if p.ProjectSpend.Currency == "" {
+		return money.DefaultCurrency
+	}
+	return p.ProjectSpend.Currency
+}
+
+// ComputeVariance variance = budget - spend, âm nghĩa là đã chi vượt ngân sách
+func (p *Project) ComputeVariance() {
+	p.ProjectVariance = money.New(p.Budget.Amount-p.ProjectSpend.Amount, p.Currency())
+}
+
+// OverBudget spend đã đạt threshold phần trăm của budget, project không có budget thì không bao giờ vượt
+func (p *Project) OverBudget(threshold int) bool {
+	if p.Budget.Amount <= 0 {
+		return false
+	}
+	return p.ProjectSpend.Amount*100 >= p.Budget.Amount*int64(threshold)
 }
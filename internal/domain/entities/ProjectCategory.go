@@ -0,0 +1,20 @@
+package entities
+
+import "time"
+
+// ProjectCategory loại project do admin quản lý, projects.category tham chiếu tới project_categories.name.
+// Name không đổi được sau khi tạo; DefaultRevenueMethod là phương pháp ghi nhận doanh thu dùng khi
+// lịch doanh thu không chỉ định method, để trống nghĩa là không có mặc định
+type ProjectCategory struct {
+	ID                   int       `gorm:"primaryKey;autoIncrement" json:"id"`
+	Name                 string    `gorm:"size:50;not null;uniqueIndex" json:"name"`
+	Description          string    `gorm:"size:255;not null;default:''" json:"description"`
+	Billable             bool      `gorm:"not null;default:false" json:"billable"`
+	DefaultRevenueMethod string    `gorm:"size:20;not null;default:''" json:"default_revenue_method"`
+	CreatedAt            time.Time `gorm:"autoCreateTime" json:"created_at"`
+	UpdatedAt            time.Time `gorm:"autoUpdateTime" json:"updated_at"`
+}
+
+func (ProjectCategory) TableName() string {
+	return "project_categories"
+}
@@ -0,0 +1,57 @@
+package entities
+
+import "time"
+
+// Vòng đời của project: draft → active ⇄ on_hold → completed → archived.
+// Project đã completed/archived thì không được sửa các trường tài chính nữa
+const (
+	ProjectStatusDraft     = "draft"
+	ProjectStatusActive    = "active"
+	ProjectStatusOnHold    = "on_hold"
+	ProjectStatusCompleted = "completed"
+	ProjectStatusArchived  = "archived"
+)
+
+// projectTransitions trạng thái đích hợp lệ theo từng trạng thái hiện tại, archived là trạng thái cuối
+var projectTransitions = map[string][]string{
+	ProjectStatusDraft:     {ProjectStatusActive, ProjectStatusArchived},
+	ProjectStatusActive:    {ProjectStatusOnHold, ProjectStatusCompleted},
+	ProjectStatusOnHold:    {ProjectStatusActive, ProjectStatusCompleted},
+	ProjectStatusCompleted: {ProjectStatusArchived},
+	ProjectStatusArchived:  {},
+}
+
+func IsValidProjectStatus(status string) bool {
+	_, ok := projectTransitions[status]
+	return ok
+}
+
+// CanTransitionTo cho biết project có được chuyển từ trạng thái hiện tại sang status hay không
+func (p *Project) CanTransitionTo(status string) bool {
+	for _, next := range projectTransitions[p.Status] {
+		if next == status {
+			return true
+		}
+	}
+	return false
+}
+
+// FinancialsLocked project đã kết thúc thì số liệu tài chính được chốt lại
+func (p *Project) FinancialsLocked() bool {
+	return p.Status == ProjectStatusCompleted || p.Status == ProjectStatusArchived
+}
+
+// ProjectStatusHistory một lần chuyển trạng thái của project, ChangedBy nil khi do hệ thống thực hiện
+type ProjectStatusHistory struct {
+	ID         int       `gorm:"primaryKey;autoIncrement" json:"id"`
+	ProjectID  int       `gorm:"not null;index" json:"project_id"`
+	FromStatus string    `gorm:"size:20;not null" json:"from_status"`
+	ToStatus   string    `gorm:"size:20;not null" json:"to_status"`
+	Reason     string    `gorm:"size:500;not null" json:"reason"`
+	ChangedBy  *int      `json:"changed_by"`
+	CreatedAt  time.Time `gorm:"autoCreateTime" json:"created_at"`
+}
+
+func (ProjectStatusHistory) TableName() string {
+	return "project_status_history"
+}
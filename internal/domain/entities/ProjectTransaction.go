@@ -0,0 +1,45 @@
+package entities
+
+import (
+	"Backend_golang_project/internal/domain/money"
+	"time"
+)
+
+// Loại bút toán trong sổ cái của project. spend và revenue luôn dương,
+// adjustment dùng để điều chỉnh (có thể âm) một trong hai khoản theo Target
+const (
+	TransactionTypeSpend      = "spend"
+	TransactionTypeRevenue    = "revenue"
+	TransactionTypeAdjustment = "adjustment"
+)
+
+// ProjectTransaction một bút toán trong sổ cái project_transactions, chỉ được thêm mới, không sửa/xóa.
+// Amount phải cùng đơn vị tiền với project (cột amount/currency).
+// Target là khoản bị ảnh hưởng (spend hoặc revenue), với spend/revenue thì Target trùng với Type.
+// CreatedBy nil với các bút toán do hệ thống tạo (ví dụ số dư đầu kỳ khi migrate)
+type ProjectTransaction struct {
+	ID            int         `gorm:"primaryKey;autoIncrement" json:"id"`
+	ProjectID     int         `gorm:"not null;index" json:"project_id"`
+	Type          string      `gorm:"size:20;not null" json:"type"`
+	Target        string      `gorm:"size:20;not null" json:"target"`
+	Amount        money.Money `gorm:"embedded" json:"amount"`
+	EffectiveDate time.Time   `gorm:"not null" json:"effective_date"`
+	Description   string      `gorm:"size:500" json:"description"`
+	CreatedBy     *int        `json:"created_by"`
+	CreatedAt     time.Time   `gorm:"autoCreateTime" json:"created_at"`
+}
+
+func (ProjectTransaction) TableName() string {
+	return "project_transactions"
+}
+
+// TransactionTarget khoản mà một loại bút toán ghi vào, chuỗi rỗng nếu loại không hợp lệ
+// hoặc là adjustment (adjustment phải chỉ rõ target)
+func TransactionTarget(transactionType string) string {
+	switch transactionType {
+	case TransactionTypeSpend, TransactionTypeRevenue:
+		return transactionType
+	default:
+		return ""
+	}
+}
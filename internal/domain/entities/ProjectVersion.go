@@ -0,0 +1,77 @@
+package entities
+
+import (
+	"Backend_golang_project/internal/domain/money"
+	"time"
+)
+
+const (
+	ProjectChangeCreate  = "create"
+	ProjectChangeUpdate  = "update"
+	ProjectChangeStatus  = "status"
+	ProjectChangeLedger  = "ledger"
+	ProjectChangeRestore = "restore"
+)
+
+// ProjectVersion ảnh chụp toàn bộ dòng projects sau một lần ghi, Version tăng dần theo từng project.
+// Các cột tiền được lưu riêng (không gộp vào JSON) để báo cáo quý truy vấn trực tiếp lịch sử budget/spend/revenue;
+// ChangedBy nil khi thay đổi do hệ thống thực hiện, RestoredFrom là version được khôi phục khi ChangeType = restore
+type ProjectVersion struct {
+	ID                int         `gorm:"primaryKey;autoIncrement" json:"id"`
+	ProjectID         int         `gorm:"not null;uniqueIndex:idx_project_versions_project_version,priority:1" json:"project_id"`
+	Version           int         `gorm:"not null;uniqueIndex:idx_project_versions_project_version,priority:2" json:"version"`
+	ChangeType        string      `gorm:"size:20;not null" json:"change_type"`
+	RestoredFrom      *int        `json:"restored_from,omitempty"`
+	ChangedBy         *int        `json:"changed_by"`
+	Name              string      `gorm:"not null" json:"name"`
+	Category          string      `gorm:"size:50;not null" json:"category"`
+	Status            string      `gorm:"size:20;not null" json:"status"`
+	Budget            money.Money `gorm:"embedded;embeddedPrefix:budget_" json:"budget"`
+	ProjectSpend      money.Money `gorm:"embedded;embeddedPrefix:project_spend_" json:"project_spend"`
+	ProjectVariance   money.Money `gorm:"embedded;embeddedPrefix:project_variance_" json:"project_variance"`
+	RevenueRecognised money.Money `gorm:"embedded;embeddedPrefix:revenue_recognised_" json:"revenue_recognised"`
+	ProjectStartedAt  time.Time   `gorm:"not null" json:"project_started_at"`
+	ProjectEndedAt    *time.Time  `gorm:"type:datetime" json:"project_ended_at"`
+	CreatedAt         time.Time   `gorm:"autoCreateTime;index" json:"created_at"`
+}
+
+func (ProjectVersion) TableName() string {
+	return "project_versions"
+}
+
+// NewProjectVersion chụp trạng thái hiện tại của p
+func NewProjectVersion(p *Project, version int, changeType string, changedBy *int) *ProjectVersion {
+	return &ProjectVersion{
+		ProjectID:         p.ID,
+		Version:           version,
+		ChangeType:        changeType,
+		ChangedBy:         changedBy,
+		Name:              p.Name,
+		Category:          p.Category,
+		Status:            p.Status,
+		Budget:            p.Budget,
+		ProjectSpend:      p.ProjectSpend,
+		ProjectVariance:   p.ProjectVariance,
+		RevenueRecognised: p.RevenueRecognised,
+		ProjectStartedAt:  p.ProjectStartedAt,
+		ProjectEndedAt:    p.ProjectEndedAt,
+	}
+}
+
+// Project dựng lại project tại thời điểm của version, UpdatedAt là lúc version được ghi
+func (v *ProjectVersion) Project() *Project {
+	return &Project{
+		ID:                v.ProjectID,
+		Name:              v.Name,
+		Category:          v.Category,
+		Status:            v.Status,
+		Budget:            v.Budget,
+		ProjectSpend:      v.ProjectSpend,
+		ProjectVariance:   v.ProjectVariance,
+		RevenueRecognised: v.RevenueRecognised,
+		ProjectStartedAt:  v.ProjectStartedAt,
+		ProjectEndedAt:    v.ProjectEndedAt,
+		Version:           v.Version,
+		UpdatedAt:         v.CreatedAt,
+	}
+}
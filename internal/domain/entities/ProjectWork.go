@@ -0,0 +1,120 @@
+package entities
+
+import "time"
+
+// Trạng thái của milestone và task
+const (
+	WorkStatusTodo       = "todo"
+	WorkStatusInProgress = "in_progress"
+	WorkStatusDone       = "done"
+	// WorkStatusCancelled không được tính vào tiến độ của project
+	WorkStatusCancelled = "cancelled"
+)
+
+// WorkItem các trường chung của milestone và task. AssigneeID phải là thành viên của project trong user_projects,
+// EstimateHours là số giờ ước lượng để tính tiến độ theo trọng số
+type WorkItem struct {
+	Description   string     `gorm:"type:text" json:"description"`
+	AssigneeID    *int       `gorm:"index" json:"assignee_id"`
+	DueDate       *time.Time `gorm:"type:date" json:"due_date"`
+	Status        string     `gorm:"size:20;not null;default:todo;index" json:"status"`
+	EstimateHours float64    `gorm:"type:decimal(8,2);not null;default:0" json:"estimate_hours"`
+	CompletedAt   *time.Time `json:"completed_at"`
+	CreatedBy     *int       `json:"created_by"`
+	CreatedAt     time.Time  `gorm:"autoCreateTime" json:"created_at"`
+	UpdatedAt     time.Time  `gorm:"autoUpdateTime" json:"updated_at"`
+}
+
+// SetStatus đổi trạng thái và cập nhật CompletedAt: ghi lại thời điểm khi chuyển sang done,
+// giữ nguyên nếu đã done từ trước, xóa đi khi rời khỏi done
+func (w *WorkItem) SetStatus(status string, now time.Time) {
+	switch {
+	case status != WorkStatusDone:
+		w.CompletedAt = nil
+	case w.Status != WorkStatusDone || w.CompletedAt == nil:
+		w.CompletedAt = &now
+	}
+	w.Status = status
+}
+
+// Milestone mốc tiến độ của project, khác với RevenueMilestone là mốc ghi nhận doanh thu
+type Milestone struct {
+	ID        int    `gorm:"primaryKey;autoIncrement" json:"id"`
+	ProjectID int    `gorm:"not null;index" json:"project_id"`
+	Name      string `gorm:"size:255;not null" json:"name"`
+	WorkItem  `gorm:"embedded"`
+}
+
+func (Milestone) TableName() string {
+	return "project_milestones"
+}
+
+// Task công việc của project, có thể thuộc một milestone của cùng project
+type Task struct {
+	ID          int    `gorm:"primaryKey;autoIncrement" json:"id"`
+	ProjectID   int    `gorm:"not null;index" json:"project_id"`
+	MilestoneID *int   `gorm:"index" json:"milestone_id"`
+	Title       string `gorm:"size:255;not null" json:"title"`
+	WorkItem    `gorm:"embedded"`
+}
+
+func (Task) TableName() string {
+	return "project_tasks"
+}
+
+func IsValidWorkStatus(status string) bool {
+	switch status {
+	case WorkStatusTodo, WorkStatusInProgress, WorkStatusDone, WorkStatusCancelled:
+		return true
+	}
+	return false
+}
+
+// WorkCount số lượng và tổng giờ ước lượng của các milestone hoặc task không bị hủy
+type WorkCount struct {
+	Total         int64   `json:"total"`
+	Done          int64   `json:"done"`
+	EstimateHours float64 `json:"estimate_hours"`
+	DoneHours     float64 `json:"done_hours"`
+}
+
+// Add cộng một nhóm milestone/task cùng trạng thái vào bộ đếm
+func (c *WorkCount) Add(status string, count int64, hours float64) {
+	if status == WorkStatusCancelled {
+		return
+	}
+	c.Total += count
+	c.EstimateHours += hours
+	if status == WorkStatusDone {
+		c.Done += count
+		c.DoneHours += hours
+	}
+}
+
+// Percent phần trăm hoàn thành theo giờ ước lượng, nếu chưa có ước lượng nào thì theo số lượng
+func (c WorkCount) Percent() int {
+	if c.EstimateHours > 0 {
+		return int(c.DoneHours * 100 / c.EstimateHours)
+	}
+	if c.Total > 0 {
+		return int(c.Done * 100 / c.Total)
+	}
+	return 0
+}
+
+// ProjectProgress tiến độ của project tổng hợp từ milestone và task.
+// Percent tính theo task, project chưa có task nào thì tính theo milestone
+type ProjectProgress struct {
+	Milestones WorkCount `json:"milestones"`
+	Tasks      WorkCount `json:"tasks"`
+	Percent    int       `json:"percent"`
+}
+
+// ComputePercent tính lại Percent sau khi đã cộng đủ Milestones và Tasks
+func (p *ProjectProgress) ComputePercent() {
+	if p.Tasks.Total > 0 {
+		p.Percent = p.Tasks.Percent()
+		return
+	}
+	p.Percent = p.Milestones.Percent()
+}
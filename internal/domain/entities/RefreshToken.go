@@ -0,0 +1,20 @@
+package entities
+
+import "time"
+
+// RefreshToken lưu lại mỗi refresh token đã phát hành, khóa theo claim jti.
+// Các token sinh ra từ cùng một lần đăng nhập dùng chung FamilyID để có thể thu hồi cả chuỗi
+type RefreshToken struct {
+	ID         int       `gorm:"primaryKey;autoIncrement"`
+	JTI        string    `gorm:"column:jti;size:64;uniqueIndex;not null"`
+	FamilyID   string    `gorm:"size:64;index;not null"`
+	UserID     int       `gorm:"not null;index"`
+	ExpiresAt  time.Time `gorm:"not null"`
+	RevokedAt  *time.Time
+	ReplacedBy string    `gorm:"size:64"`
+	CreatedAt  time.Time `gorm:"autoCreateTime"`
+}
+
+func (t *RefreshToken) IsRevoked() bool {
+	return t.RevokedAt != nil
+}
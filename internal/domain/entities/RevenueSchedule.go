@@ -0,0 +1,113 @@
+package entities
+
+import (
+	"Backend_golang_project/internal/domain/money"
+	"math/big"
+	"time"
+)
+
+// ProjectCategoryClient category billable có sẵn, non-billable và system không ghi nhận doanh thu.
+// Các category khác do admin tạo trong project_categories
+const ProjectCategoryClient = "client"
+
+// Phương pháp ghi nhận doanh thu của RevenueSchedule
+const (
+	// RecognitionStraightLine chia đều ContractValue theo từng ngày từ ngày bắt đầu tới ngày kết thúc project
+	RecognitionStraightLine = "straight_line"
+	// RecognitionMilestone ghi nhận Amount của từng milestone khi milestone hoàn thành
+	RecognitionMilestone = "milestone"
+	// RecognitionPercentComplete ghi nhận PercentComplete phần trăm của ContractValue
+	RecognitionPercentComplete = "percent_complete"
+)
+
+// RevenueSchedule lịch ghi nhận doanh thu của một project client, mỗi project có tối đa một lịch.
+// RecognisedAmount là tổng đã ghi vào sổ cái bởi lịch này, lần chạy sau chỉ ghi phần chênh lệch
+// nên chạy lại nhiều lần cho cùng một ngày không ghi trùng
+type RevenueSchedule struct {
+	ID                int                `gorm:"primaryKey;autoIncrement" json:"id"`
+	ProjectID         int                `gorm:"not null;uniqueIndex" json:"project_id"`
+	Method            string             `gorm:"size:20;not null" json:"method"`
+	ContractValue     money.Money        `gorm:"embedded;embeddedPrefix:contract_value_" json:"contract_value"`
+	PercentComplete   int                `gorm:"not null;default:0" json:"percent_complete"`
+	RecognisedAmount  int64              `gorm:"not null;default:0" json:"recognised_amount"`
+	RecognisedThrough *time.Time         `gorm:"type:date" json:"recognised_through"`
+	CreatedBy         *int               `json:"created_by"`
+	CreatedAt         time.Time          `gorm:"autoCreateTime" json:"created_at"`
+	UpdatedAt         time.Time          `gorm:"autoUpdateTime" json:"updated_at"`
+	Milestones        []RevenueMilestone `gorm:"foreignKey:ScheduleID" json:"milestones,omitempty"`
+}
+
+func (RevenueSchedule) TableName() string {
+	return "project_revenue_schedules"
+}
+
+// RevenueMilestone một mốc của lịch milestone, Amount theo đơn vị tiền của project
+type RevenueMilestone struct {
+	ID          int        `gorm:"primaryKey;autoIncrement" json:"id"`
+	ScheduleID  int        `gorm:"not null;index" json:"-"`
+	Name        string     `gorm:"size:255;not null" json:"name"`
+	Amount      int64      `gorm:"not null" json:"amount"`
+	DueDate     time.Time  `gorm:"type:date;not null" json:"due_date"`
+	CompletedAt *time.Time `json:"completed_at"`
+}
+
+func (RevenueMilestone) TableName() string {
+	return "project_revenue_milestones"
+}
+
+func IsValidRecognitionMethod(method string) bool {
+	switch method {
+	case RecognitionStraightLine, RecognitionMilestone, RecognitionPercentComplete:
+		return true
+	}
+	return false
+}
+
+// RecognisedAsOf tổng doanh thu phải được ghi nhận tính tới hết ngày asOf (theo UTC)
+func (s *RevenueSchedule) RecognisedAsOf(project *Project, asOf time.Time) int64 {
+	asOfDay := Day(asOf)
+	startDay := Day(project.ProjectStartedAt)
+	if asOfDay.Before(startDay) {
+		return 0
+	}
+
+	switch s.Method {
+	case RecognitionStraightLine:
+		if project.ProjectEndedAt == nil {
+			return 0
+		}
+		totalDays := daysBetween(startDay, Day(*project.ProjectEndedAt)) + 1
+		elapsedDays := daysBetween(startDay, asOfDay) + 1
+		if elapsedDays >= totalDays {
+			return s.ContractValue.Amount
+		}
+		return fraction(s.ContractValue.Amount, elapsedDays, totalDays)
+	case RecognitionMilestone:
+		var recognised int64
+		for _, milestone := range s.Milestones {
+			if milestone.CompletedAt != nil && !Day(*milestone.CompletedAt).After(asOfDay) {
+				recognised += milestone.Amount
+			}
+		}
+		return recognised
+	case RecognitionPercentComplete:
+		return fraction(s.ContractValue.Amount, int64(s.PercentComplete), 100)
+	}
+	return 0
+}
+
+// Day ngày (UTC) chứa t, doanh thu được ghi nhận theo ngày
+func Day(t time.Time) time.Time {
+	year, month, day := t.UTC().Date()
+	return time.Date(year, month, day, 0, 0, 0, 0, time.UTC)
+}
+
+func daysBetween(from, to time.Time) int64 {
+	return int64(to.Sub(from).Hours() / 24)
+}
+
+// fraction amount * numerator / denominator làm tròn xuống, tính bằng big.Int để không tràn int64
+func fraction(amount, numerator, denominator int64) int64 {
+	value := new(big.Int).Mul(big.NewInt(amount), big.NewInt(numerator))
+	return value.Quo(value, big.NewInt(denominator)).Int64()
+}
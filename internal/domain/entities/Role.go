@@ -0,0 +1,34 @@
+package entities
+
+const (
+	RoleAdmin   = UserRoleAdmin
+	RoleMember  = UserRoleMember
+	RoleAuditor = "auditor"
+)
+
+const (
+	PermissionProjectCreate    = "project:create"
+	PermissionProjectRead      = "project:read"
+	PermissionProjectUpdate    = "project:update"
+	PermissionProjectDelete    = "project:delete"
+	PermissionProjectReadAll   = "project:read_all"
+	PermissionProjectManageAll = "project:manage_all"
+	PermissionUserRead         = "user:read"
+	PermissionUserExport       = "user:export"
+	PermissionRoleAssign       = "role:assign"
+	PermissionUserManageRates  = "user:manage_rates"
+	PermissionCategoryManage   = "category:manage"
+	PermissionAuditRead        = "audit:read"
+)
+
+// Role là vai trò toàn hệ thống của user (admin, member, auditor), users.role tham chiếu tới roles.name
+type Role struct {
+	Name        string       `gorm:"primaryKey;size:32" json:"name"`
+	Description string       `gorm:"size:255" json:"description"`
+	Permissions []Permission `gorm:"many2many:role_permissions;joinForeignKey:role_name;joinReferences:permission_name" json:"permissions"`
+}
+
+type Permission struct {
+	Name        string `gorm:"primaryKey;size:64" json:"name"`
+	Description string `gorm:"size:255" json:"description"`
+}
@@ -0,0 +1,105 @@
+package entities
+
+import (
+	"Backend_golang_project/internal/domain/money"
+	"math"
+	"time"
+)
+
+// Trạng thái của timesheet tuần: draft → submitted → approved, hoặc submitted → rejected → submitted.
+// Chỉ timesheet draft/rejected mới được thêm, sửa, xóa dòng giờ công
+const (
+	TimesheetStatusDraft     = "draft"
+	TimesheetStatusSubmitted = "submitted"
+	TimesheetStatusApproved  = "approved"
+	TimesheetStatusRejected  = "rejected"
+)
+
+// Timesheet giờ công của một user trên một project trong một tuần (WeekStart là thứ Hai, UTC).
+// Khi được duyệt, HourlyRate của user được quy đổi sang đơn vị tiền của project và chốt lại cùng Cost,
+// Cost được ghi vào sổ cái thành bút toán spend TransactionID
+type Timesheet struct {
+	ID            int              `gorm:"primaryKey;autoIncrement" json:"id"`
+	UserID        int              `gorm:"not null;uniqueIndex:idx_timesheets_user_project_week" json:"user_id"`
+	ProjectID     int              `gorm:"not null;uniqueIndex:idx_timesheets_user_project_week;index" json:"project_id"`
+	WeekStart     time.Time        `gorm:"type:date;not null;uniqueIndex:idx_timesheets_user_project_week" json:"week_start"`
+	Status        string           `gorm:"size:20;not null;default:draft;index" json:"status"`
+	SubmittedAt   *time.Time       `json:"submitted_at"`
+	ReviewedBy    *int             `json:"reviewed_by"`
+	ReviewedAt    *time.Time       `json:"reviewed_at"`
+	ReviewNote    string           `gorm:"size:500;not null;default:''" json:"review_note"`
+	HourlyRate    money.Money      `gorm:"embedded;embeddedPrefix:hourly_rate_" json:"hourly_rate"`
+	Cost          money.Money      `gorm:"embedded;embeddedPrefix:cost_" json:"cost"`
+	TransactionID *int             `json:"transaction_id"`
+	CreatedAt     time.Time        `gorm:"autoCreateTime" json:"created_at"`
+	UpdatedAt     time.Time        `gorm:"autoUpdateTime" json:"updated_at"`
+	Entries       []TimesheetEntry `gorm:"foreignKey:TimesheetID" json:"entries,omitempty"`
+}
+
+func (Timesheet) TableName() string {
+	return "timesheets"
+}
+
+// Editable timesheet còn cho phép thay đổi dòng giờ công và nộp lại
+func (t *Timesheet) Editable() bool {
+	return t.Status == TimesheetStatusDraft || t.Status == TimesheetStatusRejected
+}
+
+// TotalHours tổng giờ của các dòng đã được nạp cùng timesheet
+func (t *Timesheet) TotalHours() float64 {
+	var hours float64
+	for _, entry := range t.Entries {
+		hours += entry.Hours
+	}
+	return hours
+}
+
+// TimesheetEntry số giờ một user làm cho project trong một ngày, TaskID tùy chọn là task của cùng project.
+// Cost (theo đơn vị nhỏ nhất của đơn vị tiền project) chỉ có giá trị sau khi timesheet được duyệt
+type TimesheetEntry struct {
+	ID          int       `gorm:"primaryKey;autoIncrement" json:"id"`
+	TimesheetID int       `gorm:"not null;index" json:"timesheet_id"`
+	UserID      int       `gorm:"not null;index" json:"user_id"`
+	ProjectID   int       `gorm:"not null;index" json:"project_id"`
+	TaskID      *int      `json:"task_id"`
+	WorkDate    time.Time `gorm:"type:date;not null" json:"work_date"`
+	Hours       float64   `gorm:"type:decimal(5,2);not null" json:"hours"`
+	Description string    `gorm:"size:500;not null;default:''" json:"description"`
+	Cost        int64     `gorm:"not null;default:0" json:"cost"`
+	CreatedAt   time.Time `gorm:"autoCreateTime" json:"created_at"`
+	UpdatedAt   time.Time `gorm:"autoUpdateTime" json:"updated_at"`
+}
+
+func (TimesheetEntry) TableName() string {
+	return "timesheet_entries"
+}
+
+// WeekStart thứ Hai (UTC) của tuần chứa t
+func WeekStart(t time.Time) time.Time {
+	day := Day(t)
+	offset := (int(day.Weekday()) + 6) % 7
+	return day.AddDate(0, 0, -offset)
+}
+
+// EntryCost chi phí của hours giờ với đơn giá rate, giờ được làm tròn tới 0.01 và chi phí làm tròn tới đơn vị nhỏ nhất
+func EntryCost(hours float64, rate int64) int64 {
+	hundredths := int64(math.Round(hours * 100))
+	return (hundredths*rate + 50) / 100
+}
+
+// AcceptsTime project đang chạy (active hoặc tạm dừng) mới nhận giờ công,
+// project draft chưa bắt đầu còn project completed/archived đã chốt số liệu tài chính
+func (p *Project) AcceptsTime() bool {
+	return p.Status == ProjectStatusActive || p.Status == ProjectStatusOnHold
+}
+
+// TimesheetSummary tổng giờ công của một project, tách theo user và/hoặc tuần tùy cách gom nhóm.
+// ApprovedCost theo đơn vị tiền của project, chỉ tính các timesheet đã duyệt
+type TimesheetSummary struct {
+	ProjectID     int         `json:"project_id"`
+	UserID        *int        `json:"user_id,omitempty"`
+	WeekStart     *time.Time  `json:"week_start,omitempty"`
+	Hours         float64     `json:"hours"`
+	ApprovedHours float64     `json:"approved_hours"`
+	ApprovedCost  money.Money `json:"approved_cost"`
+}
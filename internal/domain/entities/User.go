@@ -1,14 +1,31 @@
 package entities
 
-import "time"
+import (
+	"Backend_golang_project/internal/domain/money"
+	"gorm.io/gorm"
+	"time"
+)
+
+const (
+	UserRoleAdmin  = "admin"
+	UserRoleMember = "member"
+)
 
 type User struct {
-	ID        int    `gorm:"primaryKey;autoIncrement"`
-	Email     string `gorm:"unique;not null"`
-	Password  string `gorm:"not null"`
-	Username  string `gorm:"size:255;not null"`
-	CreatedAt time.Time
-	UpdatedAt time.Time
+	ID       int    `gorm:"primaryKey;autoIncrement"`
+	Email    string `gorm:"unique;not null"`
+	Password string `gorm:"not null" json:"-"`
+	Username string `gorm:"size:255;not null"`
+	Role     string `gorm:"size:32;not null;default:member"`
+	// EmailVerifiedAt nil nghĩa là user chưa bấm link xác thực email, chưa được đăng nhập
+	EmailVerifiedAt *time.Time
+	CreatedAt       time.Time
+	UpdatedAt       time.Time
+	// DeletedAt xóa mềm khi user tự xóa tài khoản, GORM tự loại các user đã xóa khỏi mọi truy vấn
+	DeletedAt gorm.DeletedAt `gorm:"index" json:"-"`
+
+	// HourlyRate đơn giá một giờ công dùng để tính chi phí khi timesheet được duyệt, chỉ admin xem và sửa
+	HourlyRate money.Money `gorm:"embedded;embeddedPrefix:hourly_rate_" json:"-"`
 
 	Projects []Project `gorm:"many2many:user_projects;"`
 }
@@ -0,0 +1,52 @@
+package entities
+
+import (
+	"gorm.io/gorm"
+	"time"
+)
+
+const (
+	ProjectRoleOwner       = "owner"
+	ProjectRoleManager     = "manager"
+	ProjectRoleContributor = "contributor"
+	ProjectRoleViewer      = "viewer"
+)
+
+// UserProject là một dòng trong bảng nối user_projects, mang theo vai trò của user trong project
+type UserProject struct {
+	ID        int        `gorm:"primaryKey;autoIncrement"`
+	ProjectID int        `gorm:"not null;index;uniqueIndex:uni_user_projects_project_user"`
+	UserID    int        `gorm:"not null;uniqueIndex:uni_user_projects_project_user"`
+	Role      string     `gorm:"size:32;not null;default:contributor"`
+	CreatedAt time.Time  `gorm:"autoCreateTime"`
+	UpdatedAt time.Time  `gorm:"autoUpdateTime"`
+	DeletedAt *time.Time `gorm:"index"`
+
+	User *User `gorm:"foreignKey:UserID" json:",omitempty"`
+}
+
+func (UserProject) TableName() string {
+	return "user_projects"
+}
+
+// CanManageMembers cho biết vai trò này có được thêm/xóa thành viên của project hay không
+func (m *UserProject) CanManageMembers() bool {
+	return m.Role == ProjectRoleOwner || m.Role == ProjectRoleManager
+}
+
+func IsValidProjectRole(role string) bool {
+	switch role {
+	case ProjectRoleOwner, ProjectRoleManager, ProjectRoleContributor, ProjectRoleViewer:
+		return true
+	}
+	return false
+}
+
+// SetupJoinTables báo cho GORM biết bảng nối user_projects được ánh xạ bởi UserProject,
+// để quan hệ many2many giữa User và Project đọc/ghi cả các cột role, deleted_at
+func SetupJoinTables(db *gorm.DB) error {
+	if err := db.SetupJoinTable(&User{}, "Projects", &UserProject{}); err != nil {
+		return err
+	}
+	return db.SetupJoinTable(&Project{}, "Users", &UserProject{})
+}
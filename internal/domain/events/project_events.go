@@ -0,0 +1,24 @@
+package events
+
+import (
+	"Backend_golang_project/internal/domain/money"
+	"time"
+)
+
+const ProjectOverBudgetEvent = "project.over_budget"
+
+// ProjectOverBudget phát ra một lần khi spend của project vượt ngưỡng Threshold (% của budget),
+// không phát lại cho tới khi spend giảm xuống dưới ngưỡng hoặc budget được nâng lên
+type ProjectOverBudget struct {
+	ProjectID  int
+	Name       string
+	Budget     money.Money
+	Spend      money.Money
+	Variance   money.Money
+	Threshold  int
+	OccurredAt time.Time
+}
+
+func (ProjectOverBudget) EventName() string {
+	return ProjectOverBudgetEvent
+}
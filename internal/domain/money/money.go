@@ -0,0 +1,121 @@
+package money
+
+import (
+	"errors"
+	"fmt"
+	"math/big"
+	"strings"
+)
+
+var (
+	ErrUnsupportedCurrency = errors.New("unsupported currency")
+	ErrCurrencyMismatch    = errors.New("currency mismatch")
+)
+
+// DefaultCurrency đơn vị tiền của các project tạo trước khi có đa tiền tệ
+const DefaultCurrency = "VND"
+
+// exponents số chữ số thập phân của đơn vị nhỏ nhất (minor unit) theo ISO 4217
+var exponents = map[string]int{
+	"VND": 0,
+	"USD": 2,
+	"EUR": 2,
+	"GBP": 2,
+	"JPY": 0,
+	"SGD": 2,
+}
+
+// Money số tiền theo đơn vị nhỏ nhất của Currency (cent với USD/EUR, đồng với VND).
+// Khi nhúng vào entity dùng gorm:"embedded;embeddedPrefix:<tên>_" để có hai cột <tên>_amount và <tên>_currency
+type Money struct {
+	Amount   int64  `gorm:"not null;default:0" json:"amount"`
+	Currency string `gorm:"size:3;not null;default:VND" json:"currency"`
+}
+
+func New(amount int64, currency string) Money {
+	return Money{Amount: amount, Currency: strings.ToUpper(currency)}
+}
+
+// IsSupported cho biết currency có nằm trong danh sách mã ISO 4217 được hỗ trợ hay không
+func IsSupported(currency string) bool {
+	_, ok := exponents[strings.ToUpper(currency)]
+	return ok
+}
+
+func Exponent(currency string) (int, error) {
+	exponent, ok := exponents[strings.ToUpper(currency)]
+	if !ok {
+		return 0, fmt.Errorf("%w: %q", ErrUnsupportedCurrency, currency)
+	}
+	return exponent, nil
+}
+
+func (m Money) Add(other Money) (Money, error) {
+	if m.Currency != other.Currency {
+		return m, fmt.Errorf("%w: %s + %s", ErrCurrencyMismatch, m.Currency, other.Currency)
+	}
+	return Money{Amount: m.Amount + other.Amount, Currency: m.Currency}, nil
+}
+
+// Convert đổi sang currency to với rate là số đơn vị to cho một đơn vị (major unit) của m.Currency,
+// kết quả được làm tròn half away from zero về đơn vị nhỏ nhất của to
+func (m Money) Convert(to string, rate *big.Rat) (Money, error) {
+	to = strings.ToUpper(to)
+	if m.Currency == to {
+		return m, nil
+	}
+	fromExp, err := Exponent(m.Currency)
+	if err != nil {
+		return m, err
+	}
+	toExp, err := Exponent(to)
+	if err != nil {
+		return m, err
+	}
+
+	value := new(big.Rat).SetInt64(m.Amount)
+	value.Mul(value, rate)
+	value.Mul(value, pow10(toExp-fromExp))
+	return Money{Amount: round(value), Currency: to}, nil
+}
+
+// Decimal số tiền theo đơn vị chính, ví dụ "1500.25" với 150025 USD, dùng cho export
+func (m Money) Decimal() string {
+	exponent, err := Exponent(m.Currency)
+	if err != nil || exponent == 0 {
+		return fmt.Sprintf("%d", m.Amount)
+	}
+	return new(big.Rat).SetFrac64(m.Amount, pow10Int(exponent)).FloatString(exponent)
+}
+
+func (m Money) String() string {
+	return m.Decimal() + " " + m.Currency
+}
+
+func pow10(exponent int) *big.Rat {
+	if exponent < 0 {
+		return new(big.Rat).SetFrac64(1, pow10Int(-exponent))
+	}
+	return new(big.Rat).SetInt64(pow10Int(exponent))
+}
+
+func pow10Int(exponent int) int64 {
+	result := int64(1)
+	for i := 0; i < exponent; i++ {
+		result *= 10
+	}
+	return result
+}
+
+func round(value *big.Rat) int64 {
+	quotient, remainder := new(big.Int).QuoRem(value.Num(), value.Denom(), new(big.Int))
+	// |remainder| * 2 >= denom thì làm tròn ra xa số 0
+	if new(big.Int).Mul(new(big.Int).Abs(remainder), big.NewInt(2)).Cmp(value.Denom()) >= 0 {
+		if value.Sign() < 0 {
+			quotient.Sub(quotient, big.NewInt(1))
+		} else {
+			quotient.Add(quotient, big.NewInt(1))
+		}
+	}
+	return quotient.Int64()
+}
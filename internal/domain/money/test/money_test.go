@@ -0,0 +1,52 @@
+package test
+
+import (
+	"Backend_golang_project/internal/domain/money"
+	"math/big"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestMoney_Convert(t *testing.T) {
+	// 1 USD = 25400 VND
+	usdToVnd := big.NewRat(25400, 1)
+	vndToUsd := big.NewRat(1, 25400)
+
+	converted, err := money.New(1999, "usd").Convert("VND", usdToVnd)
+	require.NoError(t, err)
+	assert.Equal(t, money.New(507746, "VND"), converted)
+
+	// 1 270 000 VND = 50 USD = 5000 cent
+	converted, err = money.New(1270000, "VND").Convert("USD", vndToUsd)
+	require.NoError(t, err)
+	assert.Equal(t, money.New(5000, "USD"), converted)
+
+	// 127 VND = 0.5 cent, làm tròn ra xa số 0 ở cả hai chiều
+	converted, err = money.New(127, "VND").Convert("USD", vndToUsd)
+	require.NoError(t, err)
+	assert.Equal(t, int64(1), converted.Amount)
+	converted, err = money.New(-127, "VND").Convert("USD", vndToUsd)
+	require.NoError(t, err)
+	assert.Equal(t, int64(-1), converted.Amount)
+
+	_, err = money.New(100, "VND").Convert("XYZ", usdToVnd)
+	assert.ErrorIs(t, err, money.ErrUnsupportedCurrency)
+}
+
+func TestMoney_Add(t *testing.T) {
+	sum, err := money.New(150, "EUR").Add(money.New(-50, "EUR"))
+	require.NoError(t, err)
+	assert.Equal(t, money.New(100, "EUR"), sum)
+
+	_, err = money.New(150, "EUR").Add(money.New(50, "USD"))
+	assert.ErrorIs(t, err, money.ErrCurrencyMismatch)
+}
+
+func TestMoney_Decimal(t *testing.T) {
+	assert.Equal(t, "1500.25", money.New(150025, "USD").Decimal())
+	assert.Equal(t, "-0.05", money.New(-5, "EUR").Decimal())
+	assert.Equal(t, "2500000", money.New(2500000, "VND").Decimal())
+	assert.Equal(t, "1500.25 USD", money.New(150025, "USD").String())
+}
@@ -0,0 +1,71 @@
+package handlers
+
+import (
+	dto "Backend_golang_project/internal/domain/dto/request"
+	"Backend_golang_project/internal/pkg"
+	"Backend_golang_project/internal/use_cases"
+	"errors"
+	"github.com/gin-gonic/gin"
+	"net/http"
+)
+
+type AccountHandler struct {
+	accountService use_cases.IAccountService
+}
+
+func NewAccountHandler(accountService use_cases.IAccountService) *AccountHandler {
+	return &AccountHandler{
+		accountService: accountService,
+	}
+}
+
+// ForgotPassword luôn trả về 200 dù email có tồn tại hay không
+func (h *AccountHandler) ForgotPassword(ctx *gin.Context) {
+	var request dto.ForgotPasswordRequest
+	if err := ctx.ShouldBindJSON(&request); err != nil {
+		pkg.AbortErrorHandleCustomMessage(ctx, pkg.CannotBindJson, err.Error())
+		return
+	}
+
+	if err := h.accountService.ForgotPassword(ctx, request); err != nil {
+		pkg.AbortErrorHandleCustomMessage(ctx, http.StatusInternalServerError, err.Error())
+		return
+	}
+	pkg.SuccessfulHandle(ctx, gin.H{"message": "If the email is registered, a reset link has been sent"})
+}
+
+func (h *AccountHandler) ResetPassword(ctx *gin.Context) {
+	var request dto.ResetPasswordRequest
+	if err := ctx.ShouldBindJSON(&request); err != nil {
+		pkg.AbortErrorHandleCustomMessage(ctx, pkg.CannotBindJson, err.Error())
+		return
+	}
+
+	if err := h.accountService.ResetPassword(ctx, request); err != nil {
+		handleAccountError(ctx, err)
+		return
+	}
+	pkg.SuccessfulHandle(ctx, gin.H{"message": "Password has been reset, please log in again"})
+}
+
+func (h *AccountHandler) VerifyEmail(ctx *gin.Context) {
+	var request dto.VerifyEmailRequest
+	if err := ctx.ShouldBindJSON(&request); err != nil {
+		pkg.AbortErrorHandleCustomMessage(ctx, pkg.CannotBindJson, err.Error())
+		return
+	}
+
+	if err := h.accountService.VerifyEmail(ctx, request); err != nil {
+		handleAccountError(ctx, err)
+		return
+	}
+	pkg.SuccessfulHandle(ctx, gin.H{"message": "Email verified"})
+}
+
+func handleAccountError(ctx *gin.Context, err error) {
+	if errors.Is(err, use_cases.ErrInvalidUserToken) {
+		pkg.AbortErrorHandler(ctx, pkg.InvalidUserToken)
+		return
+	}
+	pkg.AbortErrorHandleCustomMessage(ctx, http.StatusInternalServerError, err.Error())
+}
@@ -0,0 +1,61 @@
+package handlers
+
+import (
+	dto "Backend_golang_project/internal/domain/dto/request"
+	"Backend_golang_project/internal/pkg"
+	"Backend_golang_project/internal/use_cases"
+	"errors"
+	"github.com/gin-gonic/gin"
+	"gorm.io/gorm"
+	"net/http"
+	"strconv"
+)
+
+type AdminHandler struct {
+	roleService use_cases.IRoleService
+}
+
+func NewAdminHandler(roleService use_cases.IRoleService) *AdminHandler {
+	return &AdminHandler{
+		roleService: roleService,
+	}
+}
+
+func (h *AdminHandler) ListRoles(ctx *gin.Context) {
+	roles, err := h.roleService.ListRoles(ctx)
+	if err != nil {
+		pkg.AbortErrorHandleCustomMessage(ctx, http.StatusInternalServerError, err.Error())
+		return
+	}
+	pkg.SuccessfulHandle(ctx, roles)
+}
+
+func (h *AdminHandler) AssignRole(ctx *gin.Context) {
+	userID, err := strconv.Atoi(ctx.Param("id"))
+	if err != nil {
+		pkg.AbortErrorHandleCustomMessage(ctx, pkg.CannotBindJson, "Invalid user id")
+		return
+	}
+
+	var request dto.AssignRoleRequest
+	if err := ctx.ShouldBindJSON(&request); err != nil {
+		pkg.AbortErrorHandleCustomMessage(ctx, pkg.CannotBindJson, err.Error())
+		return
+	}
+
+	if err := h.roleService.AssignRole(ctx, userID, request.Role); err != nil {
+		switch {
+		case errors.Is(err, gorm.ErrRecordNotFound):
+			pkg.AbortErrorHandleCustomMessage(ctx, pkg.RecordNotFound, "User not found")
+		case errors.Is(err, use_cases.ErrInvalidRole):
+			pkg.AbortErrorHandler(ctx, pkg.InvalidRole)
+		case errors.Is(err, use_cases.ErrLastAdmin):
+			pkg.AbortErrorHandler(ctx, pkg.LastAdmin)
+		default:
+			pkg.AbortErrorHandleCustomMessage(ctx, http.StatusInternalServerError, err.Error())
+		}
+		return
+	}
+
+	pkg.SuccessfulHandle(ctx, gin.H{"user_id": userID, "role": request.Role})
+}
@@ -0,0 +1,51 @@
+package handlers
+
+import (
+	"Backend_golang_project/internal/domain/dto/request"
+	"Backend_golang_project/internal/pkg"
+	"Backend_golang_project/internal/use_cases"
+	"errors"
+	"github.com/gin-gonic/gin"
+	"net/http"
+)
+
+type AuditHandler struct {
+	service use_cases.IAuditService
+}
+
+func NewAuditHandler(service use_cases.IAuditService) *AuditHandler {
+	return &AuditHandler{
+		service: service,
+	}
+}
+
+func (h *AuditHandler) ListEvents(ctx *gin.Context) {
+	var req request.ListAuditEventsRequest
+	if err := ctx.ShouldBindQuery(&req); err != nil {
+		pkg.AbortErrorHandleCustomMessage(ctx, pkg.InvalidAuditFilter, err.Error())
+		return
+	}
+
+	filter := use_cases.AuditFilter{
+		EntityType: req.EntityType,
+		EntityID:   req.EntityID,
+		ActorID:    req.ActorID,
+		Action:     req.Action,
+		From:       req.From,
+		To:         req.To,
+	}
+	events, err := h.service.ListEvents(ctx, filter, req.ToPageRequest())
+	if err != nil {
+		switch {
+		case errors.Is(err, use_cases.ErrInvalidAuditFilter):
+			pkg.AbortErrorHandleCustomMessage(ctx, pkg.InvalidAuditFilter, err.Error())
+		case errors.Is(err, use_cases.ErrInvalidCursor):
+			pkg.AbortErrorHandler(ctx, pkg.InvalidCursor)
+		default:
+			pkg.AbortErrorHandleCustomMessage(ctx, http.StatusInternalServerError, "Failed to list audit events")
+		}
+		return
+	}
+
+	pkg.SuccessfulHandle(ctx, events)
+}
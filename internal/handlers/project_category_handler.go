@@ -0,0 +1,111 @@
+package handlers
+
+import (
+	"Backend_golang_project/internal/domain/dto/request"
+	"Backend_golang_project/internal/pkg"
+	"Backend_golang_project/internal/use_cases"
+	"errors"
+	"github.com/gin-gonic/gin"
+	"gorm.io/gorm"
+	"net/http"
+	"strconv"
+)
+
+type ProjectCategoryHandler struct {
+	service use_cases.IProjectCategoryService
+}
+
+func NewProjectCategoryHandler(service use_cases.IProjectCategoryService) *ProjectCategoryHandler {
+	return &ProjectCategoryHandler{
+		service: service,
+	}
+}
+
+func (h *ProjectCategoryHandler) List(ctx *gin.Context) {
+	categories, err := h.service.List(ctx)
+	if err != nil {
+		handleCategoryError(ctx, err, "Failed to retrieve project categories")
+		return
+	}
+	pkg.SuccessfulHandle(ctx, categories)
+}
+
+func (h *ProjectCategoryHandler) Get(ctx *gin.Context) {
+	id, err := strconv.Atoi(ctx.Param("id"))
+	if err != nil {
+		pkg.AbortErrorHandleCustomMessage(ctx, pkg.CannotBindJson, "Invalid category ID")
+		return
+	}
+
+	category, err := h.service.Get(ctx, id)
+	if err != nil {
+		handleCategoryError(ctx, err, "Failed to retrieve project category")
+		return
+	}
+	pkg.SuccessfulHandle(ctx, category)
+}
+
+func (h *ProjectCategoryHandler) Create(ctx *gin.Context) {
+	var req request.CreateProjectCategoryRequest
+	if err := ctx.ShouldBindJSON(&req); err != nil {
+		pkg.AbortErrorHandleCustomMessage(ctx, pkg.CannotBindJson, err.Error())
+		return
+	}
+
+	category, err := h.service.Create(ctx, req)
+	if err != nil {
+		handleCategoryError(ctx, err, "Failed to create project category")
+		return
+	}
+	pkg.SuccessfulHandle(ctx, category)
+}
+
+func (h *ProjectCategoryHandler) Update(ctx *gin.Context) {
+	id, err := strconv.Atoi(ctx.Param("id"))
+	if err != nil {
+		pkg.AbortErrorHandleCustomMessage(ctx, pkg.CannotBindJson, "Invalid category ID")
+		return
+	}
+
+	var req request.ProjectCategoryRequest
+	if err := ctx.ShouldBindJSON(&req); err != nil {
+		pkg.AbortErrorHandleCustomMessage(ctx, pkg.CannotBindJson, err.Error())
+		return
+	}
+
+	category, err := h.service.Update(ctx, id, req)
+	if err != nil {
+		handleCategoryError(ctx, err, "Failed to update project category")
+		return
+	}
+	pkg.SuccessfulHandle(ctx, category)
+}
+
+func (h *ProjectCategoryHandler) Delete(ctx *gin.Context) {
+	id, err := strconv.Atoi(ctx.Param("id"))
+	if err != nil {
+		pkg.AbortErrorHandleCustomMessage(ctx, pkg.CannotBindJson, "Invalid category ID")
+		return
+	}
+
+	if err := h.service.Delete(ctx, id); err != nil {
+		handleCategoryError(ctx, err, "Failed to delete project category")
+		return
+	}
+	pkg.SuccessfulHandle(ctx, gin.H{"message": "Project category deleted successfully", "id": id})
+}
+
+func handleCategoryError(ctx *gin.Context, err error, message string) {
+	switch {
+	case errors.Is(err, gorm.ErrRecordNotFound):
+		pkg.AbortErrorHandleCustomMessage(ctx, pkg.RecordNotFound, err.Error())
+	case errors.Is(err, use_cases.ErrInvalidCategory):
+		pkg.AbortErrorHandleCustomMessage(ctx, pkg.InvalidCategory, err.Error())
+	case errors.Is(err, use_cases.ErrCategoryInUse):
+		pkg.AbortErrorHandleCustomMessage(ctx, pkg.CategoryInUse, err.Error())
+	case errors.Is(err, use_cases.ErrCategoryAlreadyExists):
+		pkg.AbortErrorHandler(ctx, pkg.CategoryAlreadyExists)
+	default:
+		pkg.AbortErrorHandleCustomMessage(ctx, http.StatusInternalServerError, message)
+	}
+}
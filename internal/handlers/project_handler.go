@@ -2,7 +2,10 @@ package handlers
 
 import (
 	"Backend_golang_project/internal/domain/dto/request"
+	"Backend_golang_project/internal/domain/entities"
+	"Backend_golang_project/internal/domain/money"
 	"Backend_golang_project/internal/pkg"
+	"Backend_golang_project/internal/repositories"
 	"Backend_golang_project/internal/use_cases"
 	"encoding/json"
 	"errors"
@@ -12,6 +15,7 @@ import (
 	"io"
 	"net/http"
 	"strconv"
+	"strings"
 )
 
 type ProjectHandler struct {
@@ -51,7 +55,7 @@ func (h *ProjectHandler) Create(ctx *gin.Context) {
 
 	newProject, err := h.service.Create(ctx, projectRequest)
 	if err != nil {
-		pkg.AbortErrorHandleCustomMessage(ctx, http.StatusInternalServerError, "Failed to create project")
+		handleProjectError(ctx, err, "Failed to create project")
 		return
 	}
 
@@ -65,32 +69,99 @@ func (h *ProjectHandler) GetById(ctx *gin.Context) {
 		return
 	}
 
-	project, err := h.service.GetById(ctx, id)
+	var req request.GetProjectRequest
+	if err := ctx.ShouldBindQuery(&req); err != nil {
+		pkg.AbortErrorHandleCustomMessage(ctx, pkg.CannotBindJson, err.Error())
+		return
+	}
+
+	var project *entities.Project
+	if req.AsOf != nil {
+		project, err = h.service.GetByIdAsOf(ctx, id, *req.AsOf)
+	} else {
+		project, err = h.service.GetById(ctx, id)
+	}
 	if err != nil {
-		if errors.Is(err, gorm.ErrRecordNotFound) {
-			pkg.AbortErrorHandler(ctx, pkg.RecordNotFound)
-		} else {
-			pkg.AbortErrorHandleCustomMessage(ctx, http.StatusInternalServerError, "Failed to retrieve project")
-		}
+		handleProjectError(ctx, err, "Failed to retrieve project")
+		return
+	}
+	// project dựng lại từ version cũ không phải là trạng thái hiện tại nên không dùng để gửi If-Match
+	if req.AsOf == nil {
+		ctx.Header("ETag", projectETag(project))
+	}
+	pkg.SuccessfulHandle(ctx, project)
+}
+
+// GetBySlug tìm project theo slug trong các project do user owner tạo, bỏ trống owner thì là người gọi
+func (h *ProjectHandler) GetBySlug(ctx *gin.Context) {
+	var req request.GetProjectBySlugRequest
+	if err := ctx.ShouldBindQuery(&req); err != nil {
+		pkg.AbortErrorHandleCustomMessage(ctx, pkg.CannotBindJson, err.Error())
+		return
+	}
+	ownerID, ok := pkg.UserIDFromContext(ctx)
+	if !ok {
+		pkg.AbortErrorHandler(ctx, pkg.Forbidden)
+		return
+	}
+	if req.Owner != nil {
+		ownerID = *req.Owner
+	}
+
+	project, err := h.service.GetBySlug(ctx, ownerID, ctx.Param("slug"))
+	if err != nil {
+		handleProjectError(ctx, err, "Failed to retrieve project")
 		return
 	}
 	pkg.SuccessfulHandle(ctx, project)
 }
 
 func (h *ProjectHandler) Delete(ctx *gin.Context) {
-	name := ctx.Param("name")
+	id, err := strconv.Atoi(ctx.Param("id"))
+	if err != nil {
+		pkg.AbortErrorHandleCustomMessage(ctx, pkg.CannotBindJson, "Invalid project ID")
+		return
+	}
 
-	err := h.service.Delete(ctx, name)
+	if err := h.service.Delete(ctx, id); err != nil {
+		handleProjectError(ctx, err, "Failed to delete project")
+		return
+	}
+
+	pkg.SuccessfulHandle(ctx, gin.H{"message": "Project deleted successfully", "id": id})
+}
+
+// GetTrash project đã xóa mà người gọi được khôi phục, bị xóa gần nhất trước
+func (h *ProjectHandler) GetTrash(ctx *gin.Context) {
+	var query request.PageQuery
+	if err := ctx.ShouldBindQuery(&query); err != nil {
+		pkg.AbortErrorHandleCustomMessage(ctx, pkg.CannotBindJson, err.Error())
+		return
+	}
+
+	projects, err := h.service.GetTrash(ctx, query.ToPageRequest())
 	if err != nil {
-		if errors.Is(err, gorm.ErrRecordNotFound) {
-			pkg.AbortErrorHandler(ctx, pkg.RecordNotFound)
-		} else {
-			pkg.AbortErrorHandleCustomMessage(ctx, http.StatusInternalServerError, "Failed to delete project")
-		}
+		handleProjectError(ctx, err, "Failed to retrieve deleted projects")
+		return
+	}
+
+	pkg.SuccessfulHandle(ctx, projects)
+}
+
+func (h *ProjectHandler) Restore(ctx *gin.Context) {
+	id, err := strconv.Atoi(ctx.Param("id"))
+	if err != nil {
+		pkg.AbortErrorHandleCustomMessage(ctx, pkg.CannotBindJson, "Invalid project ID")
 		return
 	}
 
-	pkg.SuccessfulHandle(ctx, gin.H{"message": "Project deleted successfully", "name": name})
+	project, err := h.service.Restore(ctx, id)
+	if err != nil {
+		handleProjectError(ctx, err, "Failed to restore project")
+		return
+	}
+
+	pkg.SuccessfulHandle(ctx, project)
 }
 
 func (h *ProjectHandler) Update(c *gin.Context) {
@@ -106,30 +177,612 @@ func (h *ProjectHandler) Update(c *gin.Context) {
 		pkg.AbortErrorHandleCustomMessage(c, pkg.CannotBindJson, err.Error())
 		return
 	}
+	req.ExpectedVersion, err = ifMatchVersion(c.GetHeader("If-Match"))
+	if err != nil {
+		pkg.AbortErrorHandleCustomMessage(c, pkg.CannotBindJson, err.Error())
+		return
+	}
 
 	updatedProject, err := h.service.Update(c.Request.Context(), id, req)
-	if err != nil {
-		switch {
-		case errors.Is(err, gorm.ErrRecordNotFound):
-			pkg.AbortErrorHandler(c, pkg.RecordNotFound)
-		default:
-			pkg.AbortErrorHandleCustomMessage(c, http.StatusInternalServerError, "Failed to update project")
+	if errors.Is(err, repositories.ErrProjectVersionConflict) {
+		// trả về trạng thái hiện tại để client gộp thay đổi rồi gửi lại với ETag mới
+		current, getErr := h.service.GetById(c.Request.Context(), id)
+		if getErr != nil {
+			handleProjectError(c, getErr, "Failed to retrieve project")
+			return
 		}
+		c.Header("ETag", projectETag(current))
+		pkg.AbortErrorWithData(c, pkg.ProjectVersionMismatch, current)
+		return
+	}
+	if err != nil {
+		handleProjectError(c, err, "Failed to update project")
 		return
 	}
 
+	c.Header("ETag", projectETag(updatedProject))
 	pkg.SuccessfulHandle(c, updatedProject)
 }
 
+// projectETag ETag của project là version hiện tại của nó, ví dụ "3"
+func projectETag(project *entities.Project) string {
+	return strconv.Quote(strconv.Itoa(project.Version))
+}
+
+// ifMatchVersion đọc version từ header If-Match theo định dạng của projectETag,
+// header trống hoặc * thì không kiểm tra version
+func ifMatchVersion(header string) (*int, error) {
+	header = strings.TrimSpace(header)
+	if header == "" || header == "*" {
+		return nil, nil
+	}
+	invalid := errors.New("If-Match must be a single ETag returned by GET /projects/:id")
+	unquoted, err := strconv.Unquote(header)
+	if err != nil {
+		return nil, invalid
+	}
+	version, err := strconv.Atoi(unquoted)
+	if err != nil || version < 0 {
+		return nil, invalid
+	}
+	return &version, nil
+}
+
 func (h *ProjectHandler) GetProjects(ctx *gin.Context) {
-	page, _ := strconv.Atoi(ctx.DefaultQuery("page", "1"))
-	pageSize, _ := strconv.Atoi(ctx.DefaultQuery("pageSize", "10"))
+	var req request.ListProjectsRequest
+	if err := ctx.ShouldBindQuery(&req); err != nil {
+		pkg.AbortErrorHandleCustomMessage(ctx, pkg.InvalidProjectFilter, err.Error())
+		return
+	}
 
-	pagination, err := h.service.GetProjectList(ctx, page, pageSize)
+	filter := use_cases.ProjectFilter{
+		Category:     req.Category,
+		Status:       req.Status,
+		NameContains: req.Name,
+		StartedFrom:  req.StartedFrom,
+		StartedTo:    req.StartedTo,
+		EndedFrom:    req.EndedFrom,
+		EndedTo:      req.EndedTo,
+		SpendMin:     req.SpendMin,
+		SpendMax:     req.SpendMax,
+		VarianceMin:  req.VarianceMin,
+		VarianceMax:  req.VarianceMax,
+		Sort:         req.Sort,
+		Currency:     strings.ToUpper(req.Currency),
+	}
+
+	pagination, err := h.service.GetProjectList(ctx, filter, req.ToPageRequest())
 	if err != nil {
-		pkg.AbortErrorHandleCustomMessage(ctx, http.StatusInternalServerError, "Failed to retrieve projects")
+		handleProjectError(ctx, err, "Failed to retrieve projects")
 		return
 	}
 
 	pkg.SuccessfulHandle(ctx, pagination)
 }
+
+func (h *ProjectHandler) ListMembers(ctx *gin.Context) {
+	projectID, err := strconv.Atoi(ctx.Param("id"))
+	if err != nil {
+		pkg.AbortErrorHandleCustomMessage(ctx, pkg.CannotBindJson, "Invalid project ID")
+		return
+	}
+
+	members, err := h.service.ListMembers(ctx, projectID)
+	if err != nil {
+		handleProjectError(ctx, err, "Failed to retrieve project members")
+		return
+	}
+
+	pkg.SuccessfulHandle(ctx, members)
+}
+
+func (h *ProjectHandler) AddMember(ctx *gin.Context) {
+	projectID, err := strconv.Atoi(ctx.Param("id"))
+	if err != nil {
+		pkg.AbortErrorHandleCustomMessage(ctx, pkg.CannotBindJson, "Invalid project ID")
+		return
+	}
+
+	var req request.AddProjectMemberRequest
+	if err := ctx.ShouldBindJSON(&req); err != nil {
+		pkg.AbortErrorHandleCustomMessage(ctx, pkg.CannotBindJson, err.Error())
+		return
+	}
+
+	member, err := h.service.AddMember(ctx, projectID, req)
+	if err != nil {
+		handleProjectError(ctx, err, "Failed to add project member")
+		return
+	}
+
+	pkg.SuccessfulHandle(ctx, member)
+}
+
+func (h *ProjectHandler) RemoveMember(ctx *gin.Context) {
+	projectID, err := strconv.Atoi(ctx.Param("id"))
+	if err != nil {
+		pkg.AbortErrorHandleCustomMessage(ctx, pkg.CannotBindJson, "Invalid project ID")
+		return
+	}
+	userID, err := strconv.Atoi(ctx.Param("user_id"))
+	if err != nil {
+		pkg.AbortErrorHandleCustomMessage(ctx, pkg.CannotBindJson, "Invalid user ID")
+		return
+	}
+
+	if err := h.service.RemoveMember(ctx, projectID, userID); err != nil {
+		handleProjectError(ctx, err, "Failed to remove project member")
+		return
+	}
+
+	pkg.SuccessfulHandle(ctx, gin.H{"message": "Project member removed successfully", "user_id": userID})
+}
+
+func (h *ProjectHandler) Transition(ctx *gin.Context) {
+	projectID, err := strconv.Atoi(ctx.Param("id"))
+	if err != nil {
+		pkg.AbortErrorHandleCustomMessage(ctx, pkg.CannotBindJson, "Invalid project ID")
+		return
+	}
+
+	var req request.ProjectTransitionRequest
+	if err := ctx.ShouldBindJSON(&req); err != nil {
+		pkg.AbortErrorHandleCustomMessage(ctx, pkg.CannotBindJson, err.Error())
+		return
+	}
+
+	project, err := h.service.Transition(ctx, projectID, req)
+	if err != nil {
+		handleProjectError(ctx, err, "Failed to change project status")
+		return
+	}
+
+	pkg.SuccessfulHandle(ctx, project)
+}
+
+func (h *ProjectHandler) GetStatusHistory(ctx *gin.Context) {
+	projectID, err := strconv.Atoi(ctx.Param("id"))
+	if err != nil {
+		pkg.AbortErrorHandleCustomMessage(ctx, pkg.CannotBindJson, "Invalid project ID")
+		return
+	}
+
+	history, err := h.service.GetStatusHistory(ctx, projectID)
+	if err != nil {
+		handleProjectError(ctx, err, "Failed to retrieve project status history")
+		return
+	}
+
+	pkg.SuccessfulHandle(ctx, history)
+}
+
+func (h *ProjectHandler) ListVersions(ctx *gin.Context) {
+	projectID, err := strconv.Atoi(ctx.Param("id"))
+	if err != nil {
+		pkg.AbortErrorHandleCustomMessage(ctx, pkg.CannotBindJson, "Invalid project ID")
+		return
+	}
+
+	var query request.PageQuery
+	if err := ctx.ShouldBindQuery(&query); err != nil {
+		pkg.AbortErrorHandleCustomMessage(ctx, pkg.CannotBindJson, err.Error())
+		return
+	}
+
+	versions, err := h.service.ListVersions(ctx, projectID, query.ToPageRequest())
+	if err != nil {
+		handleProjectError(ctx, err, "Failed to retrieve project versions")
+		return
+	}
+
+	pkg.SuccessfulHandle(ctx, versions)
+}
+
+func (h *ProjectHandler) RestoreVersion(ctx *gin.Context) {
+	projectID, err := strconv.Atoi(ctx.Param("id"))
+	if err != nil {
+		pkg.AbortErrorHandleCustomMessage(ctx, pkg.CannotBindJson, "Invalid project ID")
+		return
+	}
+	version, err := strconv.Atoi(ctx.Param("version"))
+	if err != nil {
+		pkg.AbortErrorHandleCustomMessage(ctx, pkg.CannotBindJson, "Invalid version")
+		return
+	}
+
+	project, err := h.service.RestoreVersion(ctx, projectID, version)
+	if err != nil {
+		handleProjectError(ctx, err, "Failed to restore project version")
+		return
+	}
+
+	pkg.SuccessfulHandle(ctx, project)
+}
+
+func (h *ProjectHandler) PostTransaction(ctx *gin.Context) {
+	projectID, err := strconv.Atoi(ctx.Param("id"))
+	if err != nil {
+		pkg.AbortErrorHandleCustomMessage(ctx, pkg.CannotBindJson, "Invalid project ID")
+		return
+	}
+
+	var req request.PostTransactionRequest
+	if err := ctx.ShouldBindJSON(&req); err != nil {
+		pkg.AbortErrorHandleCustomMessage(ctx, pkg.CannotBindJson, err.Error())
+		return
+	}
+
+	entry, err := h.service.PostTransaction(ctx, projectID, req)
+	if err != nil {
+		handleProjectError(ctx, err, "Failed to post project transaction")
+		return
+	}
+
+	pkg.SuccessfulHandle(ctx, entry)
+}
+
+func (h *ProjectHandler) ListTransactions(ctx *gin.Context) {
+	projectID, err := strconv.Atoi(ctx.Param("id"))
+	if err != nil {
+		pkg.AbortErrorHandleCustomMessage(ctx, pkg.CannotBindJson, "Invalid project ID")
+		return
+	}
+
+	var query request.PageQuery
+	if err := ctx.ShouldBindQuery(&query); err != nil {
+		pkg.AbortErrorHandleCustomMessage(ctx, pkg.CannotBindJson, err.Error())
+		return
+	}
+
+	transactions, err := h.service.ListTransactions(ctx, projectID, query.ToPageRequest())
+	if err != nil {
+		handleProjectError(ctx, err, "Failed to retrieve project transactions")
+		return
+	}
+
+	pkg.SuccessfulHandle(ctx, transactions)
+}
+
+func (h *ProjectHandler) SetRevenueSchedule(ctx *gin.Context) {
+	projectID, err := strconv.Atoi(ctx.Param("id"))
+	if err != nil {
+		pkg.AbortErrorHandleCustomMessage(ctx, pkg.CannotBindJson, "Invalid project ID")
+		return
+	}
+
+	var req request.RevenueScheduleRequest
+	if err := ctx.ShouldBindJSON(&req); err != nil {
+		pkg.AbortErrorHandleCustomMessage(ctx, pkg.CannotBindJson, err.Error())
+		return
+	}
+
+	schedule, err := h.service.SetRevenueSchedule(ctx, projectID, req)
+	if err != nil {
+		handleProjectError(ctx, err, "Failed to save revenue schedule")
+		return
+	}
+
+	pkg.SuccessfulHandle(ctx, schedule)
+}
+
+func (h *ProjectHandler) GetRevenueSchedule(ctx *gin.Context) {
+	projectID, err := strconv.Atoi(ctx.Param("id"))
+	if err != nil {
+		pkg.AbortErrorHandleCustomMessage(ctx, pkg.CannotBindJson, "Invalid project ID")
+		return
+	}
+
+	schedule, err := h.service.GetRevenueSchedule(ctx, projectID)
+	if err != nil {
+		handleProjectError(ctx, err, "Failed to retrieve revenue schedule")
+		return
+	}
+
+	pkg.SuccessfulHandle(ctx, schedule)
+}
+
+func (h *ProjectHandler) PreviewRevenueSchedule(ctx *gin.Context) {
+	projectID, err := strconv.Atoi(ctx.Param("id"))
+	if err != nil {
+		pkg.AbortErrorHandleCustomMessage(ctx, pkg.CannotBindJson, "Invalid project ID")
+		return
+	}
+
+	var req request.RevenueScheduleRequest
+	if err := ctx.ShouldBindJSON(&req); err != nil {
+		pkg.AbortErrorHandleCustomMessage(ctx, pkg.CannotBindJson, err.Error())
+		return
+	}
+
+	lines, err := h.service.PreviewRevenueSchedule(ctx, projectID, req)
+	if err != nil {
+		handleProjectError(ctx, err, "Failed to preview revenue schedule")
+		return
+	}
+
+	pkg.SuccessfulHandle(ctx, lines)
+}
+
+// RecogniseRevenue body có thể bỏ trống, khi đó ghi nhận tới hết hôm nay
+func (h *ProjectHandler) RecogniseRevenue(ctx *gin.Context) {
+	projectID, err := strconv.Atoi(ctx.Param("id"))
+	if err != nil {
+		pkg.AbortErrorHandleCustomMessage(ctx, pkg.CannotBindJson, "Invalid project ID")
+		return
+	}
+
+	var req request.RecogniseRevenueRequest
+	if err := ctx.ShouldBindJSON(&req); err != nil && !errors.Is(err, io.EOF) {
+		pkg.AbortErrorHandleCustomMessage(ctx, pkg.CannotBindJson, err.Error())
+		return
+	}
+
+	entry, err := h.service.RecogniseRevenue(ctx, projectID, req)
+	if err != nil {
+		handleProjectError(ctx, err, "Failed to recognise revenue")
+		return
+	}
+
+	pkg.SuccessfulHandle(ctx, entry)
+}
+
+func (h *ProjectHandler) ListMilestones(ctx *gin.Context) {
+	projectID, err := strconv.Atoi(ctx.Param("id"))
+	if err != nil {
+		pkg.AbortErrorHandleCustomMessage(ctx, pkg.CannotBindJson, "Invalid project ID")
+		return
+	}
+
+	milestones, err := h.service.ListMilestones(ctx, projectID)
+	if err != nil {
+		handleProjectError(ctx, err, "Failed to retrieve project milestones")
+		return
+	}
+
+	pkg.SuccessfulHandle(ctx, milestones)
+}
+
+func (h *ProjectHandler) GetMilestone(ctx *gin.Context) {
+	projectID, err := strconv.Atoi(ctx.Param("id"))
+	if err != nil {
+		pkg.AbortErrorHandleCustomMessage(ctx, pkg.CannotBindJson, "Invalid project ID")
+		return
+	}
+	milestoneID, err := strconv.Atoi(ctx.Param("milestone_id"))
+	if err != nil {
+		pkg.AbortErrorHandleCustomMessage(ctx, pkg.CannotBindJson, "Invalid milestone ID")
+		return
+	}
+
+	milestone, err := h.service.GetMilestone(ctx, projectID, milestoneID)
+	if err != nil {
+		handleProjectError(ctx, err, "Failed to retrieve milestone")
+		return
+	}
+
+	pkg.SuccessfulHandle(ctx, milestone)
+}
+
+func (h *ProjectHandler) CreateMilestone(ctx *gin.Context) {
+	projectID, err := strconv.Atoi(ctx.Param("id"))
+	if err != nil {
+		pkg.AbortErrorHandleCustomMessage(ctx, pkg.CannotBindJson, "Invalid project ID")
+		return
+	}
+
+	var req request.MilestoneRequest
+	if err := ctx.ShouldBindJSON(&req); err != nil {
+		pkg.AbortErrorHandleCustomMessage(ctx, pkg.CannotBindJson, err.Error())
+		return
+	}
+
+	milestone, err := h.service.CreateMilestone(ctx, projectID, req)
+	if err != nil {
+		handleProjectError(ctx, err, "Failed to create milestone")
+		return
+	}
+
+	pkg.SuccessfulHandle(ctx, milestone)
+}
+
+func (h *ProjectHandler) UpdateMilestone(ctx *gin.Context) {
+	projectID, err := strconv.Atoi(ctx.Param("id"))
+	if err != nil {
+		pkg.AbortErrorHandleCustomMessage(ctx, pkg.CannotBindJson, "Invalid project ID")
+		return
+	}
+	milestoneID, err := strconv.Atoi(ctx.Param("milestone_id"))
+	if err != nil {
+		pkg.AbortErrorHandleCustomMessage(ctx, pkg.CannotBindJson, "Invalid milestone ID")
+		return
+	}
+
+	var req request.MilestoneRequest
+	if err := ctx.ShouldBindJSON(&req); err != nil {
+		pkg.AbortErrorHandleCustomMessage(ctx, pkg.CannotBindJson, err.Error())
+		return
+	}
+
+	milestone, err := h.service.UpdateMilestone(ctx, projectID, milestoneID, req)
+	if err != nil {
+		handleProjectError(ctx, err, "Failed to update milestone")
+		return
+	}
+
+	pkg.SuccessfulHandle(ctx, milestone)
+}
+
+func (h *ProjectHandler) DeleteMilestone(ctx *gin.Context) {
+	projectID, err := strconv.Atoi(ctx.Param("id"))
+	if err != nil {
+		pkg.AbortErrorHandleCustomMessage(ctx, pkg.CannotBindJson, "Invalid project ID")
+		return
+	}
+	milestoneID, err := strconv.Atoi(ctx.Param("milestone_id"))
+	if err != nil {
+		pkg.AbortErrorHandleCustomMessage(ctx, pkg.CannotBindJson, "Invalid milestone ID")
+		return
+	}
+
+	if err := h.service.DeleteMilestone(ctx, projectID, milestoneID); err != nil {
+		handleProjectError(ctx, err, "Failed to delete milestone")
+		return
+	}
+
+	pkg.SuccessfulHandle(ctx, gin.H{"message": "Milestone deleted successfully", "milestone_id": milestoneID})
+}
+
+func (h *ProjectHandler) ListTasks(ctx *gin.Context) {
+	projectID, err := strconv.Atoi(ctx.Param("id"))
+	if err != nil {
+		pkg.AbortErrorHandleCustomMessage(ctx, pkg.CannotBindJson, "Invalid project ID")
+		return
+	}
+
+	tasks, err := h.service.ListTasks(ctx, projectID)
+	if err != nil {
+		handleProjectError(ctx, err, "Failed to retrieve project tasks")
+		return
+	}
+
+	pkg.SuccessfulHandle(ctx, tasks)
+}
+
+func (h *ProjectHandler) GetTask(ctx *gin.Context) {
+	projectID, err := strconv.Atoi(ctx.Param("id"))
+	if err != nil {
+		pkg.AbortErrorHandleCustomMessage(ctx, pkg.CannotBindJson, "Invalid project ID")
+		return
+	}
+	taskID, err := strconv.Atoi(ctx.Param("task_id"))
+	if err != nil {
+		pkg.AbortErrorHandleCustomMessage(ctx, pkg.CannotBindJson, "Invalid task ID")
+		return
+	}
+
+	task, err := h.service.GetTask(ctx, projectID, taskID)
+	if err != nil {
+		handleProjectError(ctx, err, "Failed to retrieve task")
+		return
+	}
+
+	pkg.SuccessfulHandle(ctx, task)
+}
+
+func (h *ProjectHandler) CreateTask(ctx *gin.Context) {
+	projectID, err := strconv.Atoi(ctx.Param("id"))
+	if err != nil {
+		pkg.AbortErrorHandleCustomMessage(ctx, pkg.CannotBindJson, "Invalid project ID")
+		return
+	}
+
+	var req request.TaskRequest
+	if err := ctx.ShouldBindJSON(&req); err != nil {
+		pkg.AbortErrorHandleCustomMessage(ctx, pkg.CannotBindJson, err.Error())
+		return
+	}
+
+	task, err := h.service.CreateTask(ctx, projectID, req)
+	if err != nil {
+		handleProjectError(ctx, err, "Failed to create task")
+		return
+	}
+
+	pkg.SuccessfulHandle(ctx, task)
+}
+
+func (h *ProjectHandler) UpdateTask(ctx *gin.Context) {
+	projectID, err := strconv.Atoi(ctx.Param("id"))
+	if err != nil {
+		pkg.AbortErrorHandleCustomMessage(ctx, pkg.CannotBindJson, "Invalid project ID")
+		return
+	}
+	taskID, err := strconv.Atoi(ctx.Param("task_id"))
+	if err != nil {
+		pkg.AbortErrorHandleCustomMessage(ctx, pkg.CannotBindJson, "Invalid task ID")
+		return
+	}
+
+	var req request.TaskRequest
+	if err := ctx.ShouldBindJSON(&req); err != nil {
+		pkg.AbortErrorHandleCustomMessage(ctx, pkg.CannotBindJson, err.Error())
+		return
+	}
+
+	task, err := h.service.UpdateTask(ctx, projectID, taskID, req)
+	if err != nil {
+		handleProjectError(ctx, err, "Failed to update task")
+		return
+	}
+
+	pkg.SuccessfulHandle(ctx, task)
+}
+
+func (h *ProjectHandler) DeleteTask(ctx *gin.Context) {
+	projectID, err := strconv.Atoi(ctx.Param("id"))
+	if err != nil {
+		pkg.AbortErrorHandleCustomMessage(ctx, pkg.CannotBindJson, "Invalid project ID")
+		return
+	}
+	taskID, err := strconv.Atoi(ctx.Param("task_id"))
+	if err != nil {
+		pkg.AbortErrorHandleCustomMessage(ctx, pkg.CannotBindJson, "Invalid task ID")
+		return
+	}
+
+	if err := h.service.DeleteTask(ctx, projectID, taskID); err != nil {
+		handleProjectError(ctx, err, "Failed to delete task")
+		return
+	}
+
+	pkg.SuccessfulHandle(ctx, gin.H{"message": "Task deleted successfully", "task_id": taskID})
+}
+
+func handleProjectError(ctx *gin.Context, err error, message string) {
+	switch {
+	case errors.Is(err, gorm.ErrRecordNotFound):
+		pkg.AbortErrorHandleCustomMessage(ctx, pkg.RecordNotFound, err.Error())
+	case errors.Is(err, use_cases.ErrProjectAccessDenied):
+		pkg.AbortErrorHandler(ctx, pkg.ProjectAccessDenied)
+	case errors.Is(err, use_cases.ErrForbidden):
+		pkg.AbortErrorHandler(ctx, pkg.Forbidden)
+	case errors.Is(err, use_cases.ErrInvalidProjectFilter):
+		pkg.AbortErrorHandleCustomMessage(ctx, pkg.InvalidProjectFilter, err.Error())
+	case errors.Is(err, use_cases.ErrInvalidCursor):
+		pkg.AbortErrorHandler(ctx, pkg.InvalidCursor)
+	case errors.Is(err, use_cases.ErrInvalidProjectRole):
+		pkg.AbortErrorHandler(ctx, pkg.InvalidProjectRole)
+	case errors.Is(err, use_cases.ErrLastProjectOwner):
+		pkg.AbortErrorHandler(ctx, pkg.LastProjectOwner)
+	case errors.Is(err, repositories.ErrMemberAlreadyExists):
+		pkg.AbortErrorHandler(ctx, pkg.MemberAlreadyExists)
+	case errors.Is(err, use_cases.ErrInvalidTransition):
+		pkg.AbortErrorHandleCustomMessage(ctx, pkg.InvalidTransition, err.Error())
+	case errors.Is(err, use_cases.ErrFinancialsLocked):
+		pkg.AbortErrorHandler(ctx, pkg.FinancialsLocked)
+	case errors.Is(err, use_cases.ErrInvalidTransaction):
+		pkg.AbortErrorHandleCustomMessage(ctx, pkg.InvalidTransaction, err.Error())
+	case errors.Is(err, use_cases.ErrInvalidRevenueSchedule):
+		pkg.AbortErrorHandleCustomMessage(ctx, pkg.InvalidRevenueSchedule, err.Error())
+	case errors.Is(err, use_cases.ErrInvalidWorkItem):
+		pkg.AbortErrorHandleCustomMessage(ctx, pkg.InvalidWorkItem, err.Error())
+	case errors.Is(err, use_cases.ErrInvalidTimesheet):
+		pkg.AbortErrorHandleCustomMessage(ctx, pkg.InvalidTimesheet, err.Error())
+	case errors.Is(err, use_cases.ErrTimesheetLocked):
+		pkg.AbortErrorHandleCustomMessage(ctx, pkg.TimesheetLocked, err.Error())
+	case errors.Is(err, use_cases.ErrInvalidCategory):
+		pkg.AbortErrorHandleCustomMessage(ctx, pkg.InvalidCategory, err.Error())
+	case errors.Is(err, repositories.ErrProjectNotDeleted):
+		pkg.AbortErrorHandler(ctx, pkg.ProjectNotDeleted)
+	case errors.Is(err, repositories.ErrProjectHasPendingTimesheets):
+		pkg.AbortErrorHandleCustomMessage(ctx, pkg.ProjectHasPendingWork, err.Error())
+	case errors.Is(err, repositories.ErrSlugAlreadyExists):
+		pkg.AbortErrorHandler(ctx, pkg.SlugAlreadyExists)
+	case errors.Is(err, use_cases.ErrProjectNotBillable):
+		pkg.AbortErrorHandler(ctx, pkg.ProjectNotBillable)
+	case errors.Is(err, money.ErrUnsupportedCurrency), errors.Is(err, use_cases.ErrCurrencyConversion):
+		pkg.AbortErrorHandleCustomMessage(ctx, pkg.UnsupportedCurrency, err.Error())
+	default:
+		pkg.AbortErrorHandleCustomMessage(ctx, http.StatusInternalServerError, message)
+	}
+}
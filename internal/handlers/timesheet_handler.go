@@ -0,0 +1,230 @@
+package handlers
+
+import (
+	"Backend_golang_project/internal/domain/dto/request"
+	"Backend_golang_project/internal/pkg"
+	"Backend_golang_project/internal/use_cases"
+	"github.com/gin-gonic/gin"
+	"strconv"
+)
+
+type TimesheetHandler struct {
+	service use_cases.ITimesheetService
+}
+
+func NewTimesheetHandler(service use_cases.ITimesheetService) *TimesheetHandler {
+	return &TimesheetHandler{
+		service: service,
+	}
+}
+
+func (h *TimesheetHandler) SetHourlyRate(ctx *gin.Context) {
+	userID, err := strconv.Atoi(ctx.Param("id"))
+	if err != nil {
+		pkg.AbortErrorHandleCustomMessage(ctx, pkg.CannotBindJson, "Invalid user id")
+		return
+	}
+
+	var req request.HourlyRateRequest
+	if err := ctx.ShouldBindJSON(&req); err != nil {
+		pkg.AbortErrorHandleCustomMessage(ctx, pkg.CannotBindJson, err.Error())
+		return
+	}
+
+	rate, err := h.service.SetHourlyRate(ctx, userID, req)
+	if err != nil {
+		handleProjectError(ctx, err, "Failed to update hourly rate")
+		return
+	}
+
+	pkg.SuccessfulHandle(ctx, gin.H{"user_id": userID, "hourly_rate": rate})
+}
+
+func (h *TimesheetHandler) LogTime(ctx *gin.Context) {
+	var req request.LogTimeRequest
+	if err := ctx.ShouldBindJSON(&req); err != nil {
+		pkg.AbortErrorHandleCustomMessage(ctx, pkg.CannotBindJson, err.Error())
+		return
+	}
+
+	entry, err := h.service.LogTime(ctx, req)
+	if err != nil {
+		handleProjectError(ctx, err, "Failed to log time")
+		return
+	}
+
+	pkg.SuccessfulHandle(ctx, entry)
+}
+
+func (h *TimesheetHandler) UpdateEntry(ctx *gin.Context) {
+	entryID, err := strconv.Atoi(ctx.Param("id"))
+	if err != nil {
+		pkg.AbortErrorHandleCustomMessage(ctx, pkg.CannotBindJson, "Invalid timesheet entry ID")
+		return
+	}
+
+	var req request.TimesheetEntryRequest
+	if err := ctx.ShouldBindJSON(&req); err != nil {
+		pkg.AbortErrorHandleCustomMessage(ctx, pkg.CannotBindJson, err.Error())
+		return
+	}
+
+	entry, err := h.service.UpdateEntry(ctx, entryID, req)
+	if err != nil {
+		handleProjectError(ctx, err, "Failed to update timesheet entry")
+		return
+	}
+
+	pkg.SuccessfulHandle(ctx, entry)
+}
+
+func (h *TimesheetHandler) DeleteEntry(ctx *gin.Context) {
+	entryID, err := strconv.Atoi(ctx.Param("id"))
+	if err != nil {
+		pkg.AbortErrorHandleCustomMessage(ctx, pkg.CannotBindJson, "Invalid timesheet entry ID")
+		return
+	}
+
+	if err := h.service.DeleteEntry(ctx, entryID); err != nil {
+		handleProjectError(ctx, err, "Failed to delete timesheet entry")
+		return
+	}
+
+	pkg.SuccessfulHandle(ctx, gin.H{"message": "Timesheet entry deleted successfully", "id": entryID})
+}
+
+func (h *TimesheetHandler) ListMyTimesheets(ctx *gin.Context) {
+	var req request.ListTimesheetsRequest
+	if err := ctx.ShouldBindQuery(&req); err != nil {
+		pkg.AbortErrorHandleCustomMessage(ctx, pkg.CannotBindJson, err.Error())
+		return
+	}
+
+	timesheets, err := h.service.ListMyTimesheets(ctx, req)
+	if err != nil {
+		handleProjectError(ctx, err, "Failed to retrieve timesheets")
+		return
+	}
+
+	pkg.SuccessfulHandle(ctx, timesheets)
+}
+
+func (h *TimesheetHandler) Submit(ctx *gin.Context) {
+	timesheetID, err := strconv.Atoi(ctx.Param("id"))
+	if err != nil {
+		pkg.AbortErrorHandleCustomMessage(ctx, pkg.CannotBindJson, "Invalid timesheet ID")
+		return
+	}
+
+	timesheet, err := h.service.Submit(ctx, timesheetID)
+	if err != nil {
+		handleProjectError(ctx, err, "Failed to submit timesheet")
+		return
+	}
+
+	pkg.SuccessfulHandle(ctx, timesheet)
+}
+
+func (h *TimesheetHandler) Approve(ctx *gin.Context) {
+	timesheetID, err := strconv.Atoi(ctx.Param("id"))
+	if err != nil {
+		pkg.AbortErrorHandleCustomMessage(ctx, pkg.CannotBindJson, "Invalid timesheet ID")
+		return
+	}
+
+	timesheet, err := h.service.Approve(ctx, timesheetID)
+	if err != nil {
+		handleProjectError(ctx, err, "Failed to approve timesheet")
+		return
+	}
+
+	pkg.SuccessfulHandle(ctx, timesheet)
+}
+
+func (h *TimesheetHandler) Reject(ctx *gin.Context) {
+	timesheetID, err := strconv.Atoi(ctx.Param("id"))
+	if err != nil {
+		pkg.AbortErrorHandleCustomMessage(ctx, pkg.CannotBindJson, "Invalid timesheet ID")
+		return
+	}
+
+	var req request.RejectTimesheetRequest
+	if err := ctx.ShouldBindJSON(&req); err != nil {
+		pkg.AbortErrorHandleCustomMessage(ctx, pkg.CannotBindJson, err.Error())
+		return
+	}
+
+	timesheet, err := h.service.Reject(ctx, timesheetID, req)
+	if err != nil {
+		handleProjectError(ctx, err, "Failed to reject timesheet")
+		return
+	}
+
+	pkg.SuccessfulHandle(ctx, timesheet)
+}
+
+func (h *TimesheetHandler) ListProjectTimesheets(ctx *gin.Context) {
+	projectID, err := strconv.Atoi(ctx.Param("id"))
+	if err != nil {
+		pkg.AbortErrorHandleCustomMessage(ctx, pkg.CannotBindJson, "Invalid project ID")
+		return
+	}
+
+	var req request.ListTimesheetsRequest
+	if err := ctx.ShouldBindQuery(&req); err != nil {
+		pkg.AbortErrorHandleCustomMessage(ctx, pkg.CannotBindJson, err.Error())
+		return
+	}
+
+	timesheets, err := h.service.ListProjectTimesheets(ctx, projectID, req)
+	if err != nil {
+		handleProjectError(ctx, err, "Failed to retrieve project timesheets")
+		return
+	}
+
+	pkg.SuccessfulHandle(ctx, timesheets)
+}
+
+func (h *TimesheetHandler) ProjectSummary(ctx *gin.Context) {
+	projectID, err := strconv.Atoi(ctx.Param("id"))
+	if err != nil {
+		pkg.AbortErrorHandleCustomMessage(ctx, pkg.CannotBindJson, "Invalid project ID")
+		return
+	}
+
+	var req request.TimesheetSummaryRequest
+	if err := ctx.ShouldBindQuery(&req); err != nil {
+		pkg.AbortErrorHandleCustomMessage(ctx, pkg.CannotBindJson, err.Error())
+		return
+	}
+
+	summary, err := h.service.ProjectSummary(ctx, projectID, req)
+	if err != nil {
+		handleProjectError(ctx, err, "Failed to summarise project timesheets")
+		return
+	}
+
+	pkg.SuccessfulHandle(ctx, summary)
+}
+
+func (h *TimesheetHandler) UserSummary(ctx *gin.Context) {
+	userID, err := strconv.Atoi(ctx.Param("id"))
+	if err != nil {
+		pkg.AbortErrorHandleCustomMessage(ctx, pkg.CannotBindJson, "Invalid user id")
+		return
+	}
+
+	var req request.TimesheetSummaryRequest
+	if err := ctx.ShouldBindQuery(&req); err != nil {
+		pkg.AbortErrorHandleCustomMessage(ctx, pkg.CannotBindJson, err.Error())
+		return
+	}
+
+	summary, err := h.service.UserSummary(ctx, userID, req)
+	if err != nil {
+		handleProjectError(ctx, err, "Failed to summarise user timesheets")
+		return
+	}
+
+	pkg.SuccessfulHandle(ctx, summary)
+}
@@ -3,7 +3,9 @@ package handlers
 import (
 	dto "Backend_golang_project/internal/domain/dto/request"
 	"Backend_golang_project/internal/domain/dto/response"
+	"Backend_golang_project/internal/domain/entities"
 	"Backend_golang_project/internal/pkg"
+	"Backend_golang_project/internal/repositories"
 	"Backend_golang_project/internal/use_cases"
 	"encoding/json"
 	"errors"
@@ -11,6 +13,7 @@ import (
 	"github.com/gin-gonic/gin"
 	log "github.com/sirupsen/logrus"
 	"io"
+	"math"
 	"net/http"
 	"strconv"
 )
@@ -46,6 +49,28 @@ func (h *UserHandler) GetUserById(ctx *gin.Context) {
 	})
 }
 
+func (h *UserHandler) ListUsers(ctx *gin.Context) {
+	var query dto.PageQuery
+	if err := ctx.ShouldBindQuery(&query); err != nil {
+		pkg.AbortErrorHandleCustomMessage(ctx, pkg.CannotBindJson, err.Error())
+		return
+	}
+
+	users, err := h.userService.ListUsers(ctx, query.ToPageRequest())
+	if err != nil {
+		if errors.Is(err, use_cases.ErrInvalidCursor) {
+			pkg.AbortErrorHandler(ctx, pkg.InvalidCursor)
+			return
+		}
+		pkg.AbortErrorHandleCustomMessage(ctx, http.StatusInternalServerError, "Failed to list users")
+		return
+	}
+
+	pkg.SuccessfulHandle(ctx, pkg.MapPagination(users, func(user entities.User) *response.UserProfileResponse {
+		return response.ToUserProfileResponse(&user)
+	}))
+}
+
 // CreateNewUser vẫn còn bug khi tạo response chứa json project thì chưa validate được các trường dữ liệu của
 // các trường dữ liệu của project
 func (h *UserHandler) CreateNewUser(ctx *gin.Context) {
@@ -59,6 +84,10 @@ func (h *UserHandler) CreateNewUser(ctx *gin.Context) {
 	// 500
 	newUser, err := h.userService.Create(ctx, &request)
 	if err != nil {
+		if errors.Is(err, repositories.ErrEmailAlreadyExists) {
+			pkg.AbortErrorHandler(ctx, pkg.EmailAlreadyExists)
+			return
+		}
 		pkg.AbortErrorHandleCustomMessage(ctx, pkg.CannotCreateNewUser, err.Error())
 		return
 	}
@@ -95,9 +124,8 @@ func (h *UserHandler) LoginUser(ctx *gin.Context) {
 	}
 
 	accessToken, refreshToken, err := h.userService.Login(ctx, request)
-
-	if err != nil || len(accessToken) == 0 || len(refreshToken) == 0 {
-		pkg.AbortErrorHandleCustomMessage(ctx, pkg.InvalidLogin, err.Error())
+	if err != nil {
+		handleLoginError(ctx, err)
 		return
 	}
 	pkg.SuccessfulHandle(ctx, response.ToLoginResponse(accessToken, refreshToken))
@@ -110,13 +138,138 @@ func (h *UserHandler) RefreshToken(c *gin.Context) {
 		return
 	}
 
-	newAccessToken, err := h.userService.RefreshToken(&req)
+	accessToken, refreshToken, err := h.userService.RefreshToken(c, &req)
 	if err != nil {
-		pkg.AbortErrorHandleCustomMessage(c, 500, err.Error())
+		handleTokenError(c, err)
 		return
 	}
 
-	pkg.SuccessfulHandle(c, newAccessToken)
+	pkg.SuccessfulHandle(c, response.ToLoginResponse(accessToken, refreshToken))
+}
+
+func (h *UserHandler) Logout(c *gin.Context) {
+	var req dto.RefreshTokenRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		pkg.AbortErrorHandleCustomMessage(c, pkg.CannotBindJson, err.Error())
+		return
+	}
+
+	if err := h.userService.Logout(c, &req); err != nil {
+		handleTokenError(c, err)
+		return
+	}
+
+	pkg.SuccessfulHandle(c, gin.H{"message": "Logged out successfully"})
+}
+
+func (h *UserHandler) LogoutAll(c *gin.Context) {
+	if err := h.userService.LogoutAll(c); err != nil {
+		handleTokenError(c, err)
+		return
+	}
+
+	pkg.SuccessfulHandle(c, gin.H{"message": "Logged out from all sessions"})
+}
+
+func (h *UserHandler) GetProfile(ctx *gin.Context) {
+	user, err := h.userService.GetProfile(ctx)
+	if err != nil {
+		handleProfileError(ctx, err)
+		return
+	}
+	pkg.SuccessfulHandle(ctx, response.ToUserProfileResponse(user))
+}
+
+func (h *UserHandler) UpdateProfile(ctx *gin.Context) {
+	var request dto.UpdateProfileRequest
+	if err := ctx.ShouldBindJSON(&request); err != nil {
+		pkg.AbortErrorHandleCustomMessage(ctx, pkg.CannotBindJson, err.Error())
+		return
+	}
+
+	user, err := h.userService.UpdateProfile(ctx, request)
+	if err != nil {
+		handleProfileError(ctx, err)
+		return
+	}
+	pkg.SuccessfulHandle(ctx, response.ToUserProfileResponse(user))
+}
+
+func (h *UserHandler) ChangePassword(ctx *gin.Context) {
+	var request dto.ChangePasswordRequest
+	if err := ctx.ShouldBindJSON(&request); err != nil {
+		pkg.AbortErrorHandleCustomMessage(ctx, pkg.CannotBindJson, err.Error())
+		return
+	}
+
+	if err := h.userService.ChangePassword(ctx, request); err != nil {
+		handleProfileError(ctx, err)
+		return
+	}
+	pkg.SuccessfulHandle(ctx, gin.H{"message": "Password changed, please log in again"})
+}
+
+func (h *UserHandler) DeleteAccount(ctx *gin.Context) {
+	var request dto.DeleteAccountRequest
+	if err := ctx.ShouldBindJSON(&request); err != nil {
+		pkg.AbortErrorHandleCustomMessage(ctx, pkg.CannotBindJson, err.Error())
+		return
+	}
+
+	if err := h.userService.DeleteAccount(ctx, request); err != nil {
+		handleProfileError(ctx, err)
+		return
+	}
+	pkg.SuccessfulHandle(ctx, gin.H{"message": "Account deleted"})
+}
+
+func handleProfileError(c *gin.Context, err error) {
+	switch {
+	case errors.Is(err, repositories.ErrEmailAlreadyExists):
+		pkg.AbortErrorHandler(c, pkg.EmailAlreadyExists)
+	case errors.Is(err, use_cases.ErrInvalidCredentials):
+		pkg.AbortErrorHandleCustomMessage(c, pkg.InvalidLogin, "Current password is incorrect")
+	case errors.Is(err, use_cases.ErrForbidden):
+		pkg.AbortErrorHandler(c, pkg.Forbidden)
+	case errors.Is(err, use_cases.ErrLastAdmin):
+		pkg.AbortErrorHandler(c, pkg.LastAdmin)
+	case errors.Is(err, use_cases.ErrUserOwnsProjects):
+		pkg.AbortErrorHandler(c, pkg.UserOwnsProjects)
+	default:
+		pkg.AbortErrorHandleCustomMessage(c, http.StatusInternalServerError, err.Error())
+	}
+}
+
+func handleLoginError(c *gin.Context, err error) {
+	var lockedErr *use_cases.LockedError
+	switch {
+	case errors.As(err, &lockedErr):
+		c.Header("Retry-After", strconv.Itoa(int(math.Ceil(lockedErr.RetryAfter.Seconds()))))
+		if lockedErr.ByIP {
+			pkg.AbortErrorHandleCustomMessage(c, pkg.TooManyLoginAttempt, lockedErr.Error())
+			return
+		}
+		pkg.AbortErrorHandleCustomMessage(c, pkg.AccountLocked, lockedErr.Error())
+	case errors.Is(err, use_cases.ErrInvalidCredentials):
+		pkg.AbortErrorHandleCustomMessage(c, pkg.InvalidLogin, err.Error())
+	case errors.Is(err, use_cases.ErrEmailNotVerified):
+		pkg.AbortErrorHandler(c, pkg.EmailNotVerified)
+	default:
+		pkg.AbortErrorHandleCustomMessage(c, http.StatusInternalServerError, err.Error())
+	}
+}
+
+func handleTokenError(c *gin.Context, err error) {
+	switch {
+	case errors.Is(err, use_cases.ErrInvalidRefreshToken):
+		pkg.AbortErrorHandler(c, pkg.InvalidRefreshToken)
+	case errors.Is(err, use_cases.ErrRefreshTokenReused):
+		pkg.AbortErrorHandler(c, pkg.RefreshTokenReused)
+	case errors.Is(err, use_cases.ErrForbidden):
+		pkg.AbortErrorHandler(c, pkg.Forbidden)
+	default:
+		pkg.AbortErrorHandleCustomMessage(c, http.StatusInternalServerError, err.Error())
+	}
 }
 
 func (h *UserHandler) StreamingData(ctx *gin.Context) {
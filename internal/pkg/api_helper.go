@@ -31,6 +31,16 @@ func AbortErrorResponseHandle(c *gin.Context, errorResponse *ErrorResponse) {
 		"data":    nil,
 	})
 }
+
+// AbortErrorWithData như AbortErrorHandler nhưng trả kèm data, ví dụ bản ghi hiện tại khi điều kiện If-Match không khớp
+func AbortErrorWithData(c *gin.Context, code int, data interface{}) {
+	errorResponse := GetErrorResponse(code)
+	c.JSON(errorResponse.HTTPCode, gin.H{
+		"code":    errorResponse.ServiceCode,
+		"message": errorResponse.Message,
+		"data":    data,
+	})
+}
 func SuccessfulHandle(c *gin.Context, data interface{}) {
 	c.JSON(http.StatusOK, gin.H{
 		"code":    http.StatusOK,
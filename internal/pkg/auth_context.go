@@ -0,0 +1,75 @@
+package pkg
+
+import "context"
+
+type contextKey string
+
+const (
+	userIDKey contextKey = "ID"
+	rolesKey  contextKey = "roles"
+)
+
+// WithUserID gắn ID của user đã xác thực vào context để các tầng service đọc lại
+func WithUserID(ctx context.Context, id int) context.Context {
+	return context.WithValue(ctx, userIDKey, id)
+}
+
+// UserIDFromContext trả về ID của user đã xác thực, ok = false nếu request chưa qua AuthMiddleware
+func UserIDFromContext(ctx context.Context) (int, bool) {
+	id, ok := ctx.Value(userIDKey).(int)
+	return id, ok
+}
+
+// WithRoles gắn các role lấy từ claim roles của access token
+func WithRoles(ctx context.Context, roles []string) context.Context {
+	return context.WithValue(ctx, rolesKey, roles)
+}
+
+func RolesFromContext(ctx context.Context) []string {
+	roles, _ := ctx.Value(rolesKey).([]string)
+	return roles
+}
+
+const clientIPKey contextKey = "client_ip"
+
+// WithClientIP gắn IP của client (gin.Context.ClientIP) để service có thể giới hạn theo IP
+func WithClientIP(ctx context.Context, ip string) context.Context {
+	return context.WithValue(ctx, clientIPKey, ip)
+}
+
+func ClientIPFromContext(ctx context.Context) string {
+	ip, _ := ctx.Value(clientIPKey).(string)
+	return ip
+}
+
+const requestIDKey contextKey = "request_id"
+
+// WithRequestID gắn request ID (header X-Request-ID) để log và audit của cùng một request nối được với nhau
+func WithRequestID(ctx context.Context, id string) context.Context {
+	return context.WithValue(ctx, requestIDKey, id)
+}
+
+func RequestIDFromContext(ctx context.Context) string {
+	id, _ := ctx.Value(requestIDKey).(string)
+	return id
+}
+
+// Detach tạo context mới từ context.Background chỉ mang theo user ID, role, IP và request ID của ctx,
+// dùng cho goroutine chạy tiếp sau khi handler trả về: gin tái sử dụng *gin.Context cho request khác
+// nên không được giữ ctx của request (kể cả qua context.WithoutCancel)
+func Detach(ctx context.Context) context.Context {
+	detached := context.Background()
+	if id, ok := UserIDFromContext(ctx); ok {
+		detached = WithUserID(detached, id)
+	}
+	if roles := RolesFromContext(ctx); roles != nil {
+		detached = WithRoles(detached, roles)
+	}
+	if ip := ClientIPFromContext(ctx); ip != "" {
+		detached = WithClientIP(detached, ip)
+	}
+	if requestID := RequestIDFromContext(ctx); requestID != "" {
+		detached = WithRequestID(detached, requestID)
+	}
+	return detached
+}
@@ -0,0 +1,67 @@
+package pkg
+
+import (
+	"Backend_golang_project/infrastructure/config"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"strings"
+)
+
+// ErrInvalidCursor cursor bị sửa, ký bằng khóa khác hoặc không đúng định dạng
+var ErrInvalidCursor = errors.New("invalid cursor")
+
+// CursorSigner mã hóa cursor phân trang thành chuỗi "payload.signature" (base64url, HMAC-SHA256)
+// để client không tự sửa được giá trị cột dùng trong điều kiện keyset
+type CursorSigner struct {
+	key []byte
+}
+
+// NewCursorSigner dùng pagination.cursorKey, để trống thì dẫn xuất từ jwtSecretKey
+func NewCursorSigner(config *config.Config) *CursorSigner {
+	secret := config.Pagination.CursorKey
+	if secret == "" {
+		secret = "cursor:" + config.JwtConfig.SecretKey
+	}
+	sum := sha256.Sum256([]byte(secret))
+	return &CursorSigner{key: sum[:]}
+}
+
+func (s *CursorSigner) Encode(payload interface{}) (string, error) {
+	data, err := json.Marshal(payload)
+	if err != nil {
+		return "", err
+	}
+	encoded := base64.RawURLEncoding.EncodeToString(data)
+	return encoded + "." + base64.RawURLEncoding.EncodeToString(s.sign(encoded)), nil
+}
+
+// Decode kiểm tra chữ ký rồi giải mã payload vào out, mọi lỗi đều bọc ErrInvalidCursor
+func (s *CursorSigner) Decode(token string, out interface{}) error {
+	encoded, signature, found := strings.Cut(token, ".")
+	if !found {
+		return ErrInvalidCursor
+	}
+	mac, err := base64.RawURLEncoding.DecodeString(signature)
+	if err != nil || !hmac.Equal(mac, s.sign(encoded)) {
+		return ErrInvalidCursor
+	}
+	data, err := base64.RawURLEncoding.DecodeString(encoded)
+	if err != nil {
+		return ErrInvalidCursor
+	}
+	decoder := json.NewDecoder(strings.NewReader(string(data)))
+	decoder.UseNumber()
+	if err := decoder.Decode(out); err != nil {
+		return errors.Join(ErrInvalidCursor, err)
+	}
+	return nil
+}
+
+func (s *CursorSigner) sign(encoded string) []byte {
+	mac := hmac.New(sha256.New, s.key)
+	mac.Write([]byte(encoded))
+	return mac.Sum(nil)
+}
@@ -14,23 +14,27 @@ func PasswordValidator(fl validator.FieldLevel) bool {
 	return hasUppercase && hasLowercase && hasNumber
 }
 
-func CategoryValidator(fl validator.FieldLevel) bool {
-	category := fl.Field().String()
-	const (
-		CLIENT      = "client"
-		NonBillable = "non-billable"
-		SYSTEM      = "system"
-	)
-	validCategories := []string{CLIENT, NonBillable, SYSTEM}
-	for _, validCategory := range validCategories {
-		if category == validCategory {
-			return true
-		}
+// CategoryCatalog nguồn các category hợp lệ cho validator valid_category, được cache từ bảng project_categories
+type CategoryCatalog interface {
+	IsValidCategory(name string) bool
+}
+
+// NewCategoryValidator tạo validator valid_category đọc từ catalog thay vì danh sách cố định
+func NewCategoryValidator(catalog CategoryCatalog) validator.Func {
+	return func(fl validator.FieldLevel) bool {
+		return catalog.IsValidCategory(fl.Field().String())
 	}
-	return false
 }
 
 func ProjectStartDateValidator(fl validator.FieldLevel) bool {
 	startDate := fl.Field().Interface().(time.Time)
 	return startDate.After(time.Now())
 }
+
+var slugPattern = regexp.MustCompile(`^[a-z0-9]+(-[a-z0-9]+)*$`)
+
+// SlugValidator slug gồm chữ thường, số và dấu gạch ngang giữa các cụm, tối đa 64 ký tự
+func SlugValidator(fl validator.FieldLevel) bool {
+	slug := fl.Field().String()
+	return len(slug) <= 64 && slugPattern.MatchString(slug)
+}
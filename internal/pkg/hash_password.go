@@ -1,12 +1,137 @@
 package pkg
 
-import "golang.org/x/crypto/bcrypt"
+import (
+	"crypto/rand"
+	"crypto/subtle"
+	"encoding/base64"
+	"errors"
+	"fmt"
+	"golang.org/x/crypto/argon2"
+	"golang.org/x/crypto/bcrypt"
+	"strings"
+)
 
-func HashPassword(password string) (string, error) {
-	bytes, err := bcrypt.GenerateFromPassword([]byte(password), 14)
+const (
+	PasswordAlgorithmBcrypt   = "bcrypt"
+	PasswordAlgorithmArgon2id = "argon2id"
+
+	argon2idPrefix = "$argon2id$"
+)
+
+var errMalformedArgon2Hash = errors.New("malformed argon2id hash")
+
+// Argon2Params tham số argon2id, cũng là các tham số được ghi trong hash định dạng PHC
+type Argon2Params struct {
+	MemoryKiB   uint32
+	Iterations  uint32
+	Parallelism uint8
+}
+
+// PasswordHasher băm mật khẩu theo thuật toán được cấu hình, thuật toán của hash đã lưu được nhận ra theo tiền tố:
+// "$2a$"/"$2b$"/"$2y$" là bcrypt, "$argon2id$" là argon2id (định dạng PHC)
+type PasswordHasher struct {
+	algorithm  string
+	bcryptCost int
+	argon2     Argon2Params
+}
+
+// NewPasswordHasher các giá trị bỏ trống lấy mặc định: bcrypt cost 10, argon2id m=64MiB t=3 p=2
+func NewPasswordHasher(algorithm string, bcryptCost int, argon2Params Argon2Params) *PasswordHasher {
+	hasher := &PasswordHasher{
+		algorithm:  strings.ToLower(algorithm),
+		bcryptCost: bcryptCost,
+		argon2:     argon2Params,
+	}
+	if hasher.algorithm != PasswordAlgorithmArgon2id {
+		hasher.algorithm = PasswordAlgorithmBcrypt
+	}
+	if hasher.bcryptCost < bcrypt.MinCost || hasher.bcryptCost > bcrypt.MaxCost {
+		hasher.bcryptCost = bcrypt.DefaultCost
+	}
+	if hasher.argon2.MemoryKiB == 0 {
+		hasher.argon2.MemoryKiB = 64 * 1024
+	}
+	if hasher.argon2.Iterations == 0 {
+		hasher.argon2.Iterations = 3
+	}
+	if hasher.argon2.Parallelism == 0 {
+		hasher.argon2.Parallelism = 2
+	}
+	return hasher
+}
+
+func (h *PasswordHasher) Hash(password string) (string, error) {
+	if h.algorithm == PasswordAlgorithmArgon2id {
+		return h.hashArgon2id(password)
+	}
+	bytes, err := bcrypt.GenerateFromPassword([]byte(password), h.bcryptCost)
 	return string(bytes), err
 }
-func CheckPasswordHash(password, hash string) bool {
-	err := bcrypt.CompareHashAndPassword([]byte(hash), []byte(password))
-	return err == nil
+
+// Verify kiểm tra được cả hash bcrypt lẫn argon2id bất kể thuật toán đang cấu hình
+func (h *PasswordHasher) Verify(password, hash string) bool {
+	if strings.HasPrefix(hash, argon2idPrefix) {
+		params, salt, key, err := decodeArgon2id(hash)
+		if err != nil {
+			return false
+		}
+		actual := argon2.IDKey([]byte(password), salt, params.Iterations, params.MemoryKiB, params.Parallelism, uint32(len(key)))
+		return subtle.ConstantTimeCompare(actual, key) == 1
+	}
+	return bcrypt.CompareHashAndPassword([]byte(hash), []byte(password)) == nil
+}
+
+// NeedsRehash true nếu hash được tạo bằng thuật toán hoặc tham số khác với cấu hình hiện tại
+func (h *PasswordHasher) NeedsRehash(hash string) bool {
+	if strings.HasPrefix(hash, argon2idPrefix) {
+		if h.algorithm != PasswordAlgorithmArgon2id {
+			return true
+		}
+		params, _, _, err := decodeArgon2id(hash)
+		return err != nil || params != h.argon2
+	}
+
+	if h.algorithm != PasswordAlgorithmBcrypt {
+		return true
+	}
+	cost, err := bcrypt.Cost([]byte(hash))
+	return err != nil || cost != h.bcryptCost
+}
+
+func (h *PasswordHasher) hashArgon2id(password string) (string, error) {
+	salt := make([]byte, 16)
+	if _, err := rand.Read(salt); err != nil {
+		return "", err
+	}
+	key := argon2.IDKey([]byte(password), salt, h.argon2.Iterations, h.argon2.MemoryKiB, h.argon2.Parallelism, 32)
+	return fmt.Sprintf("%sv=%d$m=%d,t=%d,p=%d$%s$%s",
+		argon2idPrefix, argon2.Version, h.argon2.MemoryKiB, h.argon2.Iterations, h.argon2.Parallelism,
+		base64.RawStdEncoding.EncodeToString(salt), base64.RawStdEncoding.EncodeToString(key)), nil
+}
+
+// decodeArgon2id tách "$argon2id$v=19$m=65536,t=3,p=2$<salt>$<key>"
+func decodeArgon2id(hash string) (Argon2Params, []byte, []byte, error) {
+	var params Argon2Params
+	parts := strings.Split(hash, "$")
+	if len(parts) != 6 {
+		return params, nil, nil, errMalformedArgon2Hash
+	}
+
+	var version int
+	if _, err := fmt.Sscanf(parts[2], "v=%d", &version); err != nil || version != argon2.Version {
+		return params, nil, nil, errMalformedArgon2Hash
+	}
+	if _, err := fmt.Sscanf(parts[3], "m=%d,t=%d,p=%d", &params.MemoryKiB, &params.Iterations, &params.Parallelism); err != nil {
+		return params, nil, nil, errMalformedArgon2Hash
+	}
+
+	salt, err := base64.RawStdEncoding.DecodeString(parts[4])
+	if err != nil {
+		return params, nil, nil, errMalformedArgon2Hash
+	}
+	key, err := base64.RawStdEncoding.DecodeString(parts[5])
+	if err != nil || len(key) == 0 {
+		return params, nil, nil, errMalformedArgon2Hash
+	}
+	return params, salt, key, nil
 }
@@ -4,4 +4,5 @@ const (
 	ERROR_PARSER_JSON = "Can not parse json"
 	RECORD_NOT_FOUND  = "Record not found"
 	INVALIDED_LOGIN   = "Invalid login"
+	FORBIDDEN         = "You do not have permission to perform this action"
 )
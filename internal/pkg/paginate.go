@@ -1,10 +1,42 @@
 package pkg
 
-import "Backend_golang_project/internal/domain/entities"
+// Pagination một trang kết quả. Phân trang theo offset thì có CurrentPage, phân trang theo cursor thì dùng
+// NextCursor/PrevCursor; TotalPages/TotalRecords chỉ có khi client yêu cầu (include_total) vì COUNT(*) tốn kém trên bảng lớn
+type Pagination[T any] struct {
+	Items        []T    `json:"items"`
+	PageSize     int    `json:"page_size"`
+	CurrentPage  int    `json:"current_page,omitempty"`
+	TotalPages   *int   `json:"total_pages,omitempty"`
+	TotalRecords *int   `json:"total_records,omitempty"`
+	HasNext      bool   `json:"has_next"`
+	HasPrev      bool   `json:"has_prev"`
+	NextCursor   string `json:"next_cursor,omitempty"`
+	PrevCursor   string `json:"prev_cursor,omitempty"`
+}
+
+// PageRequest tham số phân trang từ client, có Cursor thì bỏ qua Page
+type PageRequest struct {
+	Page         int
+	PageSize     int
+	Cursor       string
+	IncludeTotal bool
+}
 
-type Pagination struct {
-	Projects     []entities.Project `json:"projects"`
-	TotalPages   int                `json:"total_pages"`
-	TotalRecords int                `json:"total_records"`
-	CurrentPage  int                `json:"current_page"`
+// MapPagination chuyển item của một trang sang kiểu khác (ví dụ entity sang response), giữ nguyên thông tin phân trang
+func MapPagination[T any, U any](page *Pagination[T], convert func(T) U) *Pagination[U] {
+	items := make([]U, 0, len(page.Items))
+	for _, item := range page.Items {
+		items = append(items, convert(item))
+	}
+	return &Pagination[U]{
+		Items:        items,
+		PageSize:     page.PageSize,
+		CurrentPage:  page.CurrentPage,
+		TotalPages:   page.TotalPages,
+		TotalRecords: page.TotalRecords,
+		HasNext:      page.HasNext,
+		HasPrev:      page.HasPrev,
+		NextCursor:   page.NextCursor,
+		PrevCursor:   page.PrevCursor,
+	}
 }
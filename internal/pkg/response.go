@@ -14,10 +14,44 @@ type ErrorResponse struct {
 }
 
 const (
-	CannotBindJson      = 40000001
-	CannotCreateNewUser = 40000002
-	InvalidLogin        = 40000003
-	RecordNotFound      = 40400000
+	CannotBindJson         = 40000001
+	CannotCreateNewUser    = 40000002
+	InvalidLogin           = 40000003
+	InvalidProjectRole     = 40000004
+	InvalidRole            = 40000005
+	InvalidUserToken       = 40000006
+	InvalidProjectFilter   = 40000007
+	InvalidCursor          = 40000008
+	InvalidTransaction     = 40000009
+	UnsupportedCurrency    = 40000010
+	InvalidRevenueSchedule = 40000011
+	InvalidWorkItem        = 40000012
+	InvalidTimesheet       = 40000013
+	InvalidCategory        = 40000014
+	InvalidAuditFilter     = 40000015
+	InvalidRefreshToken    = 40100001
+	RefreshTokenReused     = 40100002
+	Forbidden              = 40300000
+	ProjectAccessDenied    = 40300001
+	EmailNotVerified       = 40300002
+	RecordNotFound         = 40400000
+	MemberAlreadyExists    = 40900000
+	LastProjectOwner       = 40900001
+	LastAdmin              = 40900002
+	EmailAlreadyExists     = 40900003
+	InvalidTransition      = 40900004
+	FinancialsLocked       = 40900005
+	ProjectNotBillable     = 40900006
+	TimesheetLocked        = 40900007
+	CategoryInUse          = 40900008
+	CategoryAlreadyExists  = 40900009
+	ProjectNotDeleted      = 40900010
+	ProjectHasPendingWork  = 40900011
+	SlugAlreadyExists      = 40900012
+	UserOwnsProjects       = 40900013
+	ProjectVersionMismatch = 41200000
+	AccountLocked          = 42300000
+	TooManyLoginAttempt    = 42900000
 )
 
 var errResponseMap = map[int]ErrorResponse{
@@ -41,6 +75,176 @@ var errResponseMap = map[int]ErrorResponse{
 		ServiceCode: InvalidLogin,
 		Message:     INVALIDED_LOGIN,
 	},
+	InvalidProjectRole: {
+		HTTPCode:    http.StatusBadRequest,
+		ServiceCode: InvalidProjectRole,
+		Message:     "Role must be one of owner, manager, contributor, viewer",
+	},
+	InvalidRole: {
+		HTTPCode:    http.StatusBadRequest,
+		ServiceCode: InvalidRole,
+		Message:     "Role does not exist",
+	},
+	InvalidUserToken: {
+		HTTPCode:    http.StatusBadRequest,
+		ServiceCode: InvalidUserToken,
+		Message:     "Token is invalid, expired or already used",
+	},
+	InvalidProjectFilter: {
+		HTTPCode:    http.StatusBadRequest,
+		ServiceCode: InvalidProjectFilter,
+		Message:     "Invalid project filter",
+	},
+	InvalidCursor: {
+		HTTPCode:    http.StatusBadRequest,
+		ServiceCode: InvalidCursor,
+		Message:     "Page cursor is invalid or does not match the current sort order",
+	},
+	InvalidTransaction: {
+		HTTPCode:    http.StatusBadRequest,
+		ServiceCode: InvalidTransaction,
+		Message:     "Invalid project transaction",
+	},
+	UnsupportedCurrency: {
+		HTTPCode:    http.StatusBadRequest,
+		ServiceCode: UnsupportedCurrency,
+		Message:     "Currency is not supported or has no exchange rate",
+	},
+	InvalidRevenueSchedule: {
+		HTTPCode:    http.StatusBadRequest,
+		ServiceCode: InvalidRevenueSchedule,
+		Message:     "Invalid revenue schedule",
+	},
+	InvalidWorkItem: {
+		HTTPCode:    http.StatusBadRequest,
+		ServiceCode: InvalidWorkItem,
+		Message:     "Invalid milestone or task",
+	},
+	InvalidTimesheet: {
+		HTTPCode:    http.StatusBadRequest,
+		ServiceCode: InvalidTimesheet,
+		Message:     "Invalid timesheet entry",
+	},
+	InvalidCategory: {
+		HTTPCode:    http.StatusBadRequest,
+		ServiceCode: InvalidCategory,
+		Message:     "Invalid project category",
+	},
+	InvalidAuditFilter: {
+		HTTPCode:    http.StatusBadRequest,
+		ServiceCode: InvalidAuditFilter,
+		Message:     "Invalid audit log filter",
+	},
+	InvalidRefreshToken: {
+		HTTPCode:    http.StatusUnauthorized,
+		ServiceCode: InvalidRefreshToken,
+		Message:     "Refresh token is invalid, expired or revoked",
+	},
+	RefreshTokenReused: {
+		HTTPCode:    http.StatusUnauthorized,
+		ServiceCode: RefreshTokenReused,
+		Message:     "Refresh token was already used, please log in again",
+	},
+	Forbidden: {
+		HTTPCode:    http.StatusForbidden,
+		ServiceCode: Forbidden,
+		Message:     FORBIDDEN,
+	},
+	ProjectAccessDenied: {
+		HTTPCode:    http.StatusForbidden,
+		ServiceCode: ProjectAccessDenied,
+		Message:     "You do not have access to this project",
+	},
+	EmailNotVerified: {
+		HTTPCode:    http.StatusForbidden,
+		ServiceCode: EmailNotVerified,
+		Message:     "Email address is not verified, a verification link has been sent",
+	},
+	MemberAlreadyExists: {
+		HTTPCode:    http.StatusConflict,
+		ServiceCode: MemberAlreadyExists,
+		Message:     "User is already a member of this project",
+	},
+	LastProjectOwner: {
+		HTTPCode:    http.StatusConflict,
+		ServiceCode: LastProjectOwner,
+		Message:     "A project must keep at least one owner",
+	},
+	LastAdmin: {
+		HTTPCode:    http.StatusConflict,
+		ServiceCode: LastAdmin,
+		Message:     "The system must keep at least one admin",
+	},
+	EmailAlreadyExists: {
+		HTTPCode:    http.StatusConflict,
+		ServiceCode: EmailAlreadyExists,
+		Message:     "Email is already registered",
+	},
+	InvalidTransition: {
+		HTTPCode:    http.StatusConflict,
+		ServiceCode: InvalidTransition,
+		Message:     "Project status transition is not allowed",
+	},
+	FinancialsLocked: {
+		HTTPCode:    http.StatusConflict,
+		ServiceCode: FinancialsLocked,
+		Message:     "Financial fields of a completed or archived project cannot be changed",
+	},
+	ProjectNotBillable: {
+		HTTPCode:    http.StatusConflict,
+		ServiceCode: ProjectNotBillable,
+		Message:     "Revenue recognition is only available for projects in a billable category",
+	},
+	TimesheetLocked: {
+		HTTPCode:    http.StatusConflict,
+		ServiceCode: TimesheetLocked,
+		Message:     "Timesheet cannot be changed in its current status",
+	},
+	CategoryInUse: {
+		HTTPCode:    http.StatusConflict,
+		ServiceCode: CategoryInUse,
+		Message:     "Project category is used by existing projects",
+	},
+	CategoryAlreadyExists: {
+		HTTPCode:    http.StatusConflict,
+		ServiceCode: CategoryAlreadyExists,
+		Message:     "Project category already exists",
+	},
+	ProjectNotDeleted: {
+		HTTPCode:    http.StatusConflict,
+		ServiceCode: ProjectNotDeleted,
+		Message:     "Project is not in the trash",
+	},
+	ProjectHasPendingWork: {
+		HTTPCode:    http.StatusConflict,
+		ServiceCode: ProjectHasPendingWork,
+		Message:     "Project has submitted timesheets waiting for approval",
+	},
+	SlugAlreadyExists: {
+		HTTPCode:    http.StatusConflict,
+		ServiceCode: SlugAlreadyExists,
+		Message:     "Slug is already used by another project of the same owner",
+	},
+	UserOwnsProjects: {
+		HTTPCode:    http.StatusConflict,
+		ServiceCode: UserOwnsProjects,
+		Message:     "Transfer ownership of the projects you own before deleting your account",
+	},
+	ProjectVersionMismatch: {
+		HTTPCode:    http.StatusPreconditionFailed,
+		ServiceCode: ProjectVersionMismatch,
+		Message:     "Project has been modified since it was read, data contains the current version",
+	},
+	AccountLocked: {
+		HTTPCode:    http.StatusLocked,
+		ServiceCode: AccountLocked,
+		Message:     "Account is temporarily locked after too many failed login attempts",
+	},
+	TooManyLoginAttempt: {
+		HTTPCode:    http.StatusTooManyRequests,
+		ServiceCode: TooManyLoginAttempt,
+		Message:     "Too many failed login attempts from this address",
+	},
 }
 
 func GetErrorResponse(code int) ErrorResponse {
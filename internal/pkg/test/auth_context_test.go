@@ -0,0 +1,24 @@
+package test
+
+import (
+	"Backend_golang_project/internal/pkg"
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestDetach_KeepsValuesWithoutParent(t *testing.T) {
+	parent, cancel := context.WithCancel(context.Background())
+	parent = pkg.WithRequestID(pkg.WithClientIP(pkg.WithUserID(parent, 7), "10.0.0.1"), "req-1")
+	cancel()
+
+	detached := pkg.Detach(parent)
+
+	assert.NoError(t, detached.Err())
+	id, ok := pkg.UserIDFromContext(detached)
+	assert.True(t, ok)
+	assert.Equal(t, 7, id)
+	assert.Equal(t, "10.0.0.1", pkg.ClientIPFromContext(detached))
+	assert.Equal(t, "req-1", pkg.RequestIDFromContext(detached))
+}
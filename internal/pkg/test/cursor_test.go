@@ -0,0 +1,53 @@
+package test
+
+import (
+	"Backend_golang_project/infrastructure/config"
+	"Backend_golang_project/internal/pkg"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type testCursor struct {
+	ID   int    `json:"id"`
+	Name string `json:"name"`
+}
+
+func newCursorSigner(key string) *pkg.CursorSigner {
+	cfg := &config.Config{}
+	cfg.JwtConfig.SecretKey = "test-secret-key"
+	cfg.Pagination.CursorKey = key
+	return pkg.NewCursorSigner(cfg)
+}
+
+func TestCursorSigner_RoundTrip(t *testing.T) {
+	signer := newCursorSigner("")
+
+	token, err := signer.Encode(testCursor{ID: 42, Name: "Apollo"})
+	require.NoError(t, err)
+
+	var decoded testCursor
+	require.NoError(t, signer.Decode(token, &decoded))
+	assert.Equal(t, testCursor{ID: 42, Name: "Apollo"}, decoded)
+}
+
+func TestCursorSigner_RejectsTamperedOrForeignCursor(t *testing.T) {
+	signer := newCursorSigner("")
+	token, err := signer.Encode(testCursor{ID: 42})
+	require.NoError(t, err)
+
+	forged, err := newCursorSigner("other-key").Encode(testCursor{ID: 1})
+	require.NoError(t, err)
+
+	for name, candidate := range map[string]string{
+		"foreign key":  forged,
+		"no signature": "eyJpZCI6MX0",
+		// {"id":1} đi kèm chữ ký của cursor gốc
+		"tampered body": "eyJpZCI6MX0" + token[strings.Index(token, "."):],
+	} {
+		var decoded testCursor
+		assert.ErrorIs(t, signer.Decode(candidate, &decoded), pkg.ErrInvalidCursor, name)
+	}
+}
@@ -0,0 +1,63 @@
+package test
+
+import (
+	"Backend_golang_project/internal/pkg"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// tham số argon2id nhỏ để test chạy nhanh
+var testArgon2 = pkg.Argon2Params{MemoryKiB: 1024, Iterations: 1, Parallelism: 1}
+
+func TestPasswordHasher_Bcrypt(t *testing.T) {
+	hasher := pkg.NewPasswordHasher("bcrypt", 4, pkg.Argon2Params{})
+
+	hash, err := hasher.Hash("Passw0rd1")
+	require.NoError(t, err)
+
+	assert.True(t, strings.HasPrefix(hash, "$2a$04$"))
+	assert.True(t, hasher.Verify("Passw0rd1", hash))
+	assert.False(t, hasher.Verify("wrong", hash))
+	assert.False(t, hasher.NeedsRehash(hash))
+}
+
+func TestPasswordHasher_Argon2id(t *testing.T) {
+	hasher := pkg.NewPasswordHasher("argon2id", 0, testArgon2)
+
+	hash, err := hasher.Hash("Passw0rd1")
+	require.NoError(t, err)
+
+	assert.True(t, strings.HasPrefix(hash, "$argon2id$v=19$m=1024,t=1,p=1$"))
+	assert.True(t, hasher.Verify("Passw0rd1", hash))
+	assert.False(t, hasher.Verify("wrong", hash))
+	assert.False(t, hasher.NeedsRehash(hash))
+}
+
+func TestPasswordHasher_NeedsRehash(t *testing.T) {
+	oldBcrypt, err := pkg.NewPasswordHasher("", 5, pkg.Argon2Params{}).Hash("Passw0rd1")
+	require.NoError(t, err)
+	oldArgon, err := pkg.NewPasswordHasher("argon2id", 0, testArgon2).Hash("Passw0rd1")
+	require.NoError(t, err)
+
+	bcryptHasher := pkg.NewPasswordHasher("", 4, pkg.Argon2Params{})
+	assert.True(t, bcryptHasher.NeedsRehash(oldBcrypt), "cost changed")
+	assert.True(t, bcryptHasher.NeedsRehash(oldArgon), "algorithm changed")
+	// hash cũ vẫn xác thực được dù thuật toán đang cấu hình khác
+	assert.True(t, bcryptHasher.Verify("Passw0rd1", oldArgon))
+
+	stronger := testArgon2
+	stronger.Iterations = 2
+	argonHasher := pkg.NewPasswordHasher("argon2id", 0, stronger)
+	assert.True(t, argonHasher.NeedsRehash(oldArgon), "parameters changed")
+	assert.True(t, argonHasher.NeedsRehash(oldBcrypt), "algorithm changed")
+}
+
+func TestPasswordHasher_MalformedArgon2Hash(t *testing.T) {
+	hasher := pkg.NewPasswordHasher("argon2id", 0, testArgon2)
+
+	assert.False(t, hasher.Verify("Passw0rd1", "$argon2id$v=19$m=1024$broken"))
+	assert.True(t, hasher.NeedsRehash("$argon2id$v=19$m=1024$broken"))
+}
@@ -0,0 +1,63 @@
+package repositories
+
+import (
+	"Backend_golang_project/internal/domain/entities"
+	"Backend_golang_project/internal/pkg"
+	"context"
+	"fmt"
+	"gorm.io/gorm"
+	"time"
+)
+
+// AuditQuery điều kiện lọc audit log, giá trị rỗng/nil thì không lọc theo trường đó
+type AuditQuery struct {
+	EntityType string
+	EntityID   int
+	ActorID    int
+	Action     string
+	From       *time.Time
+	To         *time.Time
+}
+
+// IAuditRepository chỉ đọc, audit event được ghi bởi callback của GORM (infrastructure/audit)
+type IAuditRepository interface {
+	List(ctx context.Context, query AuditQuery, spec PageSpec) (*pkg.Pagination[entities.AuditEvent], error)
+}
+
+type AuditRepository struct {
+	base
+}
+
+// List audit event mới nhất trước, Keyset (nếu có) chỉ gồm giá trị id
+func (r AuditRepository) List(ctx context.Context, query AuditQuery, spec PageSpec) (*pkg.Pagination[entities.AuditEvent], error) {
+	db := r.db.WithContext(ctx).Model(&entities.AuditEvent{})
+	if query.EntityType != "" {
+		db = db.Where("entity_type = ?", query.EntityType)
+	}
+	if query.EntityID > 0 {
+		db = db.Where("entity_id = ?", query.EntityID)
+	}
+	if query.ActorID > 0 {
+		db = db.Where("actor_id = ?", query.ActorID)
+	}
+	if query.Action != "" {
+		db = db.Where("action = ?", query.Action)
+	}
+	if query.From != nil {
+		db = db.Where("created_at >= ?", *query.From)
+	}
+	if query.To != nil {
+		db = db.Where("created_at < ?", *query.To)
+	}
+
+	events, err := paginate[entities.AuditEvent](db, "audit_events", []SortColumn{{Column: "id", Desc: true}}, spec)
+	if err != nil {
+		return nil, fmt.Errorf("error listing audit events: %w", err)
+	}
+	return events, nil
+}
+
+// NewAuditRepository constructor
+func NewAuditRepository(db *gorm.DB) IAuditRepository {
+	return &AuditRepository{base: base{db: db}}
+}
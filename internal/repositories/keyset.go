@@ -0,0 +1,139 @@
+package repositories
+
+import (
+	"Backend_golang_project/internal/pkg"
+	"errors"
+
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+// ErrKeysetMismatch số giá trị trong Keyset không khớp với số cột sắp xếp (kể cả cột id cuối cùng)
+var ErrKeysetMismatch = errors.New("keyset values do not match sort columns")
+
+// PageSpec Keyset nil thì phân trang theo Page (offset), ngược lại lấy các bản ghi liền sau/liền trước Keyset
+type PageSpec struct {
+	Page         int
+	PageSize     int
+	Keyset       *Keyset
+	IncludeTotal bool
+}
+
+// Keyset giá trị các cột sắp xếp của bản ghi biên, theo thứ tự SortColumns(...) trả về.
+// Backward lấy trang đứng trước bản ghi đó (prev_cursor)
+type Keyset struct {
+	Values   []interface{}
+	Backward bool
+}
+
+// SortColumns thêm id vào cuối để thứ tự luôn ổn định, đây cũng là thứ tự các giá trị trong Keyset
+func SortColumns(sort []SortColumn) []SortColumn {
+	columns := make([]SortColumn, 0, len(sort)+1)
+	for _, column := range sort {
+		if column.Column == "id" {
+			return append(columns, column)
+		}
+		columns = append(columns, column)
+	}
+	return append(columns, SortColumn{Column: "id"})
+}
+
+// paginate lấy PageSize+1 bản ghi để biết còn trang sau hay không, thay vì COUNT(*) mỗi lần gọi.
+// Điều kiện keyset (a, b, id) > (va, vb, vid) được viết dạng OR/AND vì các cột có thể khác chiều sắp xếp
+func paginate[T any](db *gorm.DB, table string, sort []SortColumn, spec PageSpec) (*pkg.Pagination[T], error) {
+	columns := SortColumns(sort)
+	result := &pkg.Pagination[T]{PageSize: spec.PageSize}
+
+	if spec.IncludeTotal {
+		var totalRecords int64
+		if err := db.Session(&gorm.Session{}).Count(&totalRecords).Error; err != nil {
+			return nil, err
+		}
+		total := int(totalRecords)
+		totalPages := (total + spec.PageSize - 1) / spec.PageSize
+		result.TotalRecords = &total
+		result.TotalPages = &totalPages
+	}
+
+	query := db.Session(&gorm.Session{})
+	backward := false
+	if spec.Keyset != nil {
+		if len(spec.Keyset.Values) != len(columns) {
+			return nil, ErrKeysetMismatch
+		}
+		backward = spec.Keyset.Backward
+		query = query.Where(keysetCondition(table, columns, spec.Keyset.Values, backward))
+	} else {
+		result.CurrentPage = spec.Page
+		query = query.Offset((spec.Page - 1) * spec.PageSize)
+	}
+
+	orderBy := make([]clause.OrderByColumn, 0, len(columns))
+	for _, column := range columns {
+		orderBy = append(orderBy, clause.OrderByColumn{
+			Column: clause.Column{Table: table, Name: column.Column},
+			Desc:   column.Desc != backward,
+		})
+	}
+
+	var items []T
+	if err := query.Order(clause.OrderBy{Columns: orderBy}).Limit(spec.PageSize + 1).Find(&items).Error; err != nil {
+		return nil, err
+	}
+
+	more := len(items) > spec.PageSize
+	if more {
+		items = items[:spec.PageSize]
+	}
+	if backward {
+		// trang trước được đọc theo chiều ngược lại, đảo lại cho đúng thứ tự hiển thị
+		for i, j := 0, len(items)-1; i < j; i, j = i+1, j-1 {
+			items[i], items[j] = items[j], items[i]
+		}
+		result.HasPrev = more
+		// over-fetch theo chiều ngược chỉ cho biết còn trang trước, trang sau là các bản ghi từ bản ghi biên trở đi;
+		// bản ghi biên có thể đã bị xóa kể từ khi cursor được phát hành nên phải kiểm tra lại
+		hasNext, err := hasRecordsFrom(db, table, columns, spec.Keyset.Values)
+		if err != nil {
+			return nil, err
+		}
+		result.HasNext = hasNext
+	} else {
+		result.HasNext = more
+		result.HasPrev = spec.Keyset != nil || spec.Page > 1
+	}
+	result.Items = items
+	return result, nil
+}
+
+// hasRecordsFrom còn bản ghi nào đứng tại hoặc sau values theo thứ tự hiển thị hay không,
+// tức là phủ định của điều kiện "đứng trước values" mà trang lùi dùng
+func hasRecordsFrom(db *gorm.DB, table string, columns []SortColumn, values []interface{}) (bool, error) {
+	var ids []int
+	err := db.Session(&gorm.Session{}).
+		Where(clause.Not(keysetCondition(table, columns, values, true))).
+		Limit(1).
+		Pluck(table+".id", &ids).Error
+	if err != nil {
+		return false, err
+	}
+	return len(ids) > 0, nil
+}
+
+func keysetCondition(table string, columns []SortColumn, values []interface{}, backward bool) clause.Expression {
+	branches := make([]clause.Expression, 0, len(columns))
+	for i, column := range columns {
+		exprs := make([]clause.Expression, 0, i+1)
+		for j := 0; j < i; j++ {
+			exprs = append(exprs, clause.Eq{Column: clause.Column{Table: table, Name: columns[j].Column}, Value: values[j]})
+		}
+		target := clause.Column{Table: table, Name: column.Column}
+		if column.Desc != backward {
+			exprs = append(exprs, clause.Lt{Column: target, Value: values[i]})
+		} else {
+			exprs = append(exprs, clause.Gt{Column: target, Value: values[i]})
+		}
+		branches = append(branches, clause.And(exprs...))
+	}
+	return clause.Or(branches...)
+}
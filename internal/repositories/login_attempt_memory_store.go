@@ -0,0 +1,66 @@
+package repositories
+
+import (
+	"Backend_golang_project/internal/domain/entities"
+	"context"
+	"sync"
+	"time"
+)
+
+// InMemoryLoginAttemptStore giữ bộ đếm đăng nhập sai trong bộ nhớ,
+// chỉ đúng khi chạy một instance vì mỗi instance có bộ đếm riêng
+type InMemoryLoginAttemptStore struct {
+	mu       sync.Mutex
+	attempts map[string]entities.LoginAttempt
+}
+
+func (s *InMemoryLoginAttemptStore) Get(_ context.Context, key string) (*entities.LoginAttempt, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	attempt, ok := s.attempts[key]
+	if !ok {
+		return nil, nil
+	}
+	return &attempt, nil
+}
+
+func (s *InMemoryLoginAttemptStore) RegisterFailure(_ context.Context, key string, now time.Time, window time.Duration) (*entities.LoginAttempt, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	attempt, ok := s.attempts[key]
+	if !ok {
+		attempt = entities.LoginAttempt{Key: key}
+	} else if now.Sub(attempt.LastFailedAt) > window && !attempt.IsLocked(now) {
+		attempt.Failures = 0
+	}
+	attempt.Failures++
+	attempt.LastFailedAt = now
+	attempt.UpdatedAt = now
+	s.attempts[key] = attempt
+	return &attempt, nil
+}
+
+func (s *InMemoryLoginAttemptStore) Lock(_ context.Context, key string, until time.Time) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if attempt, ok := s.attempts[key]; ok {
+		attempt.LockedUntil = &until
+		s.attempts[key] = attempt
+	}
+	return nil
+}
+
+func (s *InMemoryLoginAttemptStore) Reset(_ context.Context, key string) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	delete(s.attempts, key)
+	return nil
+}
+
+func NewInMemoryLoginAttemptStore() *InMemoryLoginAttemptStore {
+	return &InMemoryLoginAttemptStore{attempts: make(map[string]entities.LoginAttempt)}
+}
@@ -0,0 +1,81 @@
+package repositories
+
+import (
+	"Backend_golang_project/internal/domain/entities"
+	"context"
+	"errors"
+	"fmt"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+	"time"
+)
+
+// ILoginAttemptStore lưu bộ đếm đăng nhập sai, có bản MySQL (LoginAttemptRepository)
+// và bản bộ nhớ (InMemoryLoginAttemptStore) cho test hoặc khi chạy một instance
+type ILoginAttemptStore interface {
+	// Get trả về nil, nil nếu khóa chưa từng đăng nhập sai
+	Get(ctx context.Context, key string) (*entities.LoginAttempt, error)
+	// RegisterFailure tăng bộ đếm của khóa, bộ đếm được đếm lại từ đầu nếu lần sai trước đã cũ hơn window
+	RegisterFailure(ctx context.Context, key string, now time.Time, window time.Duration) (*entities.LoginAttempt, error)
+	Lock(ctx context.Context, key string, until time.Time) error
+	Reset(ctx context.Context, key string) error
+}
+
+type LoginAttemptRepository struct {
+	base
+}
+
+func (r LoginAttemptRepository) Get(ctx context.Context, key string) (*entities.LoginAttempt, error) {
+	var attempt entities.LoginAttempt
+	if err := r.db.WithContext(ctx).Where("attempt_key = ?", key).First(&attempt).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, nil
+		}
+		return nil, fmt.Errorf("error retrieving login attempt: %w", err)
+	}
+	return &attempt, nil
+}
+
+// RegisterFailure khóa dòng bằng SELECT ... FOR UPDATE để các request song song không ghi đè bộ đếm của nhau
+func (r LoginAttemptRepository) RegisterFailure(ctx context.Context, key string, now time.Time, window time.Duration) (*entities.LoginAttempt, error) {
+	var attempt entities.LoginAttempt
+	err := r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		err := tx.Clauses(clause.Locking{Strength: "UPDATE"}).Where("attempt_key = ?", key).First(&attempt).Error
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			attempt = entities.LoginAttempt{Key: key, Failures: 1, LastFailedAt: now}
+			return tx.Create(&attempt).Error
+		}
+		if err != nil {
+			return err
+		}
+
+		if now.Sub(attempt.LastFailedAt) > window && !attempt.IsLocked(now) {
+			attempt.Failures = 0
+		}
+		attempt.Failures++
+		attempt.LastFailedAt = now
+		return tx.Model(&attempt).Updates(map[string]interface{}{
+			"failures":       attempt.Failures,
+			"last_failed_at": attempt.LastFailedAt,
+		}).Error
+	})
+	if err != nil {
+		return nil, fmt.Errorf("error registering login failure: %w", err)
+	}
+	return &attempt, nil
+}
+
+func (r LoginAttemptRepository) Lock(ctx context.Context, key string, until time.Time) error {
+	return r.db.WithContext(ctx).Model(&entities.LoginAttempt{}).
+		Where("attempt_key = ?", key).
+		Update("locked_until", until).Error
+}
+
+func (r LoginAttemptRepository) Reset(ctx context.Context, key string) error {
+	return r.db.WithContext(ctx).Where("attempt_key = ?", key).Delete(&entities.LoginAttempt{}).Error
+}
+
+// NewLoginAttemptRepository constructor
+func NewLoginAttemptRepository(db *gorm.DB) ILoginAttemptStore {
+	return &LoginAttemptRepository{base: base{db: db}}
+}
@@ -0,0 +1,123 @@
+package repositories
+
+import (
+	"Backend_golang_project/internal/domain/entities"
+	"context"
+	"errors"
+	"fmt"
+	"gorm.io/gorm"
+)
+
+var (
+	// ErrCategoryAlreadyExists vi phạm unique index của project_categories.name
+	ErrCategoryAlreadyExists = errors.New("project category already exists")
+	// ErrCategoryInUse còn project tham chiếu tới category (khóa ngoại fk_projects_category)
+	ErrCategoryInUse = errors.New("project category is used by existing projects")
+)
+
+type IProjectCategoryRepository interface {
+	List(ctx context.Context) ([]entities.ProjectCategory, error)
+	Get(ctx context.Context, id int) (*entities.ProjectCategory, error)
+	GetByName(ctx context.Context, name string) (*entities.ProjectCategory, error)
+	Create(ctx context.Context, category *entities.ProjectCategory) (*entities.ProjectCategory, error)
+	Update(ctx context.Context, category *entities.ProjectCategory) (*entities.ProjectCategory, error)
+	Delete(ctx context.Context, id int) error
+}
+
+type ProjectCategoryRepository struct {
+	base
+}
+
+func (r ProjectCategoryRepository) List(ctx context.Context) ([]entities.ProjectCategory, error) {
+	var categories []entities.ProjectCategory
+	if err := r.db.WithContext(ctx).Order("name").Find(&categories).Error; err != nil {
+		return nil, fmt.Errorf("error listing project categories: %w", err)
+	}
+	return categories, nil
+}
+
+func (r ProjectCategoryRepository) Get(ctx context.Context, id int) (*entities.ProjectCategory, error) {
+	var category entities.ProjectCategory
+	if err := r.db.WithContext(ctx).First(&category, id).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, fmt.Errorf("project category %d not found: %w", id, err)
+		}
+		return nil, fmt.Errorf("error retrieving project category: %w", err)
+	}
+	return &category, nil
+}
+
+func (r ProjectCategoryRepository) GetByName(ctx context.Context, name string) (*entities.ProjectCategory, error) {
+	return categoryByName(r.db.WithContext(ctx), name)
+}
+
+func (r ProjectCategoryRepository) Create(ctx context.Context, category *entities.ProjectCategory) (*entities.ProjectCategory, error) {
+	if err := r.db.WithContext(ctx).Create(category).Error; err != nil {
+		if errors.Is(err, gorm.ErrDuplicatedKey) {
+			return nil, fmt.Errorf("%w: %s", ErrCategoryAlreadyExists, category.Name)
+		}
+		return nil, fmt.Errorf("error creating project category: %w", err)
+	}
+	return category, nil
+}
+
+// Update chỉ ghi các thuộc tính của category, name là khóa được projects tham chiếu nên không đổi
+func (r ProjectCategoryRepository) Update(ctx context.Context, category *entities.ProjectCategory) (*entities.ProjectCategory, error) {
+	err := r.db.WithContext(ctx).Model(category).
+		Select("description", "billable", "default_revenue_method", "updated_at").
+		Updates(category).Error
+	if err != nil {
+		return nil, fmt.Errorf("error updating project category: %w", err)
+	}
+	return category, nil
+}
+
+// Delete không xóa category còn project sử dụng, kể cả project đã bị xóa mềm
+func (r ProjectCategoryRepository) Delete(ctx context.Context, id int) error {
+	tx := r.StartTransaction().WithContext(ctx)
+
+	var category entities.ProjectCategory
+	if err := tx.First(&category, id).Error; err != nil {
+		r.RollBackTransaction(tx)
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return fmt.Errorf("project category %d not found: %w", id, err)
+		}
+		return fmt.Errorf("error retrieving project category: %w", err)
+	}
+
+	var projects int64
+	if err := tx.Model(&entities.Project{}).Where("category = ?", category.Name).Count(&projects).Error; err != nil {
+		r.RollBackTransaction(tx)
+		return fmt.Errorf("error counting projects of category: %w", err)
+	}
+	if projects > 0 {
+		r.RollBackTransaction(tx)
+		return fmt.Errorf("%w: %d projects in %s", ErrCategoryInUse, projects, category.Name)
+	}
+
+	if err := tx.Delete(&category).Error; err != nil {
+		r.RollBackTransaction(tx)
+		if errors.Is(err, gorm.ErrForeignKeyViolated) {
+			return fmt.Errorf("%w: %s", ErrCategoryInUse, category.Name)
+		}
+		return fmt.Errorf("error deleting project category: %w", err)
+	}
+	return r.CommitTransaction(tx)
+}
+
+// categoryByName dùng chung cho repository này và các repository cần thuộc tính category trong transaction
+func categoryByName(db *gorm.DB, name string) (*entities.ProjectCategory, error) {
+	var category entities.ProjectCategory
+	if err := db.Where("name = ?", name).First(&category).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, fmt.Errorf("project category %s not found: %w", name, err)
+		}
+		return nil, fmt.Errorf("error retrieving project category: %w", err)
+	}
+	return &category, nil
+}
+
+// NewProjectCategoryRepository constructor
+func NewProjectCategoryRepository(db *gorm.DB) IProjectCategoryRepository {
+	return &ProjectCategoryRepository{base: base{db: db}}
+}
@@ -0,0 +1,104 @@
+package repositories
+
+import (
+	"Backend_golang_project/internal/domain/entities"
+	"context"
+	"errors"
+	"fmt"
+	log "github.com/sirupsen/logrus"
+	"gorm.io/gorm"
+)
+
+var ErrMemberAlreadyExists = errors.New("user is already a member of this project")
+
+type IProjectMemberRepository interface {
+	AddMember(ctx context.Context, member *entities.UserProject) (*entities.UserProject, error)
+	RemoveMember(ctx context.Context, projectID int, userID int) error
+	GetMember(ctx context.Context, projectID int, userID int) (*entities.UserProject, error)
+	ListMembers(ctx context.Context, projectID int) ([]entities.UserProject, error)
+	CountByRole(ctx context.Context, projectID int, role string) (int64, error)
+}
+
+type ProjectMemberRepository struct {
+	base
+}
+
+// AddMember inserts a new row into user_projects.
+//
+// The user must exist and must not already be a member of the project,
+// otherwise gorm.ErrRecordNotFound (wrapped) or ErrMemberAlreadyExists is returned.
+// Membership is checked by the unique index uni_user_projects_project_user rather than a prior count,
+// so concurrent adds of the same user also get ErrMemberAlreadyExists.
+func (r ProjectMemberRepository) AddMember(ctx context.Context, member *entities.UserProject) (*entities.UserProject, error) {
+	if err := r.db.WithContext(ctx).Select("id").First(&entities.User{}, member.UserID).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, fmt.Errorf("user %d not found: %w", member.UserID, err)
+		}
+		return nil, fmt.Errorf("error checking user: %w", err)
+	}
+
+	if err := r.db.WithContext(ctx).Create(member).Error; err != nil {
+		if errors.Is(err, gorm.ErrDuplicatedKey) {
+			return nil, ErrMemberAlreadyExists
+		}
+		log.Error("Cannot add project member with err: ", err)
+		return nil, err
+	}
+	return member, nil
+}
+
+// RemoveMember deletes the membership row of a user in a project.
+// It returns a wrapped gorm.ErrRecordNotFound if the user is not a member.
+func (r ProjectMemberRepository) RemoveMember(ctx context.Context, projectID int, userID int) error {
+	result := r.db.WithContext(ctx).
+		Where("project_id = ? AND user_id = ?", projectID, userID).
+		Delete(&entities.UserProject{})
+	if result.Error != nil {
+		return fmt.Errorf("error removing project member: %w", result.Error)
+	}
+	if result.RowsAffected == 0 {
+		return fmt.Errorf("user %d is not a member of project %d: %w", userID, projectID, gorm.ErrRecordNotFound)
+	}
+	return nil
+}
+
+func (r ProjectMemberRepository) GetMember(ctx context.Context, projectID int, userID int) (*entities.UserProject, error) {
+	var member entities.UserProject
+	err := r.db.WithContext(ctx).
+		Where("project_id = ? AND user_id = ?", projectID, userID).
+		First(&member).Error
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, fmt.Errorf("user %d is not a member of project %d: %w", userID, projectID, err)
+		}
+		return nil, fmt.Errorf("error retrieving project member: %w", err)
+	}
+	return &member, nil
+}
+
+// ListMembers returns every member of the project together with the basic user information.
+func (r ProjectMemberRepository) ListMembers(ctx context.Context, projectID int) ([]entities.UserProject, error) {
+	var members []entities.UserProject
+	err := r.db.WithContext(ctx).
+		Preload("User").
+		Where("project_id = ?", projectID).
+		Order("id").
+		Find(&members).Error
+	if err != nil {
+		return nil, fmt.Errorf("error listing project members: %w", err)
+	}
+	return members, nil
+}
+
+func (r ProjectMemberRepository) CountByRole(ctx context.Context, projectID int, role string) (int64, error) {
+	var count int64
+	err := r.db.WithContext(ctx).Model(&entities.UserProject{}).
+		Where("project_id = ? AND role = ?", projectID, role).
+		Count(&count).Error
+	return count, err
+}
+
+// NewProjectMemberRepository constructor
+func NewProjectMemberRepository(db *gorm.DB) IProjectMemberRepository {
+	return &ProjectMemberRepository{base: base{db: db}}
+}
@@ -0,0 +1,71 @@
+package repositories
+
+import (
+	"strings"
+	"time"
+
+	"gorm.io/gorm"
+)
+
+// ProjectQuery điều kiện lọc và sắp xếp đã được tầng use case kiểm tra (xem use_cases.ProjectFilter),
+// các trường nil/rỗng thì bỏ qua. Khoảng spend/variance so sánh theo đơn vị nhỏ nhất của tiền tệ từng project
+type ProjectQuery struct {
+	Category     string
+	Status       string
+	NameContains string
+	StartedFrom  *time.Time
+	StartedTo    *time.Time
+	EndedFrom    *time.Time
+	EndedTo      *time.Time
+	SpendMin     *int
+	SpendMax     *int
+	VarianceMin  *int
+	VarianceMax  *int
+	Sort         []SortColumn
+}
+
+// SortColumn Column phải là tên cột nằm trong whitelist của use case, nó vẫn được quote bởi GORM khi build SQL
+type SortColumn struct {
+	Column string
+	Desc   bool
+}
+
+// likeEscaper escape ký tự đặc biệt của LIKE bằng '!' (dùng chung được cho MySQL và SQLite)
+var likeEscaper = strings.NewReplacer("!", "!!", "%", "!%", "_", "!_")
+
+func (q ProjectQuery) apply(db *gorm.DB) *gorm.DB {
+	if q.Category != "" {
+		db = db.Where("projects.category = ?", q.Category)
+	}
+	if q.Status != "" {
+		db = db.Where("projects.status = ?", q.Status)
+	}
+	if q.NameContains != "" {
+		db = db.Where("projects.name LIKE ? ESCAPE '!'", "%"+likeEscaper.Replace(q.NameContains)+"%")
+	}
+	if q.StartedFrom != nil {
+		db = db.Where("projects.project_started_at >= ?", *q.StartedFrom)
+	}
+	if q.StartedTo != nil {
+		db = db.Where("projects.project_started_at <= ?", *q.StartedTo)
+	}
+	if q.EndedFrom != nil {
+		db = db.Where("projects.project_ended_at >= ?", *q.EndedFrom)
+	}
+	if q.EndedTo != nil {
+		db = db.Where("projects.project_ended_at <= ?", *q.EndedTo)
+	}
+	if q.SpendMin != nil {
+		db = db.Where("projects.project_spend_amount >= ?", *q.SpendMin)
+	}
+	if q.SpendMax != nil {
+		db = db.Where("projects.project_spend_amount <= ?", *q.SpendMax)
+	}
+	if q.VarianceMin != nil {
+		db = db.Where("projects.project_variance_amount >= ?", *q.VarianceMin)
+	}
+	if q.VarianceMax != nil {
+		db = db.Where("projects.project_variance_amount <= ?", *q.VarianceMax)
+	}
+	return db
+}
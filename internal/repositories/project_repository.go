@@ -8,61 +8,68 @@ import (
 	"fmt"
 	log "github.com/sirupsen/logrus"
 	"gorm.io/gorm"
+	"time"
 )
 
 type IProjectRepository interface {
 	Create(ctx context.Context, pj *entities.Project) (*entities.Project, error)
 	Update(ctx context.Context, pj *entities.Project) (*entities.Project, error)
-	Delete(ctx context.Context, name string) bool
+	Delete(ctx context.Context, id int) error
 	GetById(ctx context.Context, id int) (*entities.Project, error)
-	GetList(ctx context.Context, page int, pageSize int) (*pkg.Pagination, error)
+	GetList(ctx context.Context, query ProjectQuery, spec PageSpec) (*pkg.Pagination[entities.Project], error)
+	GetListByMember(ctx context.Context, userID int, query ProjectQuery, spec PageSpec) (*pkg.Pagination[entities.Project], error)
+	GetBySlug(ctx context.Context, ownerID int, slug string) (*entities.Project, error)
+	ChangeStatus(ctx context.Context, pj *entities.Project, fromStatus string, history *entities.ProjectStatusHistory) error
+	GetStatusHistory(ctx context.Context, projectID int) ([]entities.ProjectStatusHistory, error)
+	ListVersions(ctx context.Context, projectID int, spec PageSpec) (*pkg.Pagination[entities.ProjectVersion], error)
+	GetVersion(ctx context.Context, projectID int, version int) (*entities.ProjectVersion, error)
+	GetVersionAt(ctx context.Context, projectID int, asOf time.Time) (*entities.ProjectVersion, error)
+	RestoreVersion(ctx context.Context, projectID int, version int) (*entities.Project, error)
+	GetTrash(ctx context.Context, spec PageSpec) (*pkg.Pagination[entities.Project], error)
+	GetTrashByOwner(ctx context.Context, userID int, spec PageSpec) (*pkg.Pagination[entities.Project], error)
+	Restore(ctx context.Context, id int) (*entities.Project, error)
+	PurgeDeleted(ctx context.Context, before time.Time, limit int) (int, error)
 }
 
+var (
+	// ErrProjectStatusChanged trạng thái của project đã bị thay đổi bởi một request khác kể từ lúc đọc
+	ErrProjectStatusChanged = errors.New("project status was changed concurrently")
+	// ErrProjectHasPendingTimesheets project còn timesheet đã nộp đang chờ duyệt nên chưa xóa được
+	ErrProjectHasPendingTimesheets = errors.New("project has submitted timesheets waiting for approval")
+	// ErrProjectVersionConflict project đã được ghi bởi một request khác kể từ version mà người gọi đã đọc
+	ErrProjectVersionConflict = errors.New("project was modified concurrently")
+	// ErrSlugAlreadyExists vi phạm unique index idx_projects_owner_slug
+	ErrSlugAlreadyExists = errors.New("slug is already used by another project of the same owner")
+)
+
 type ProjectRepository struct {
 	base
 }
 
-// GetList retrieves a list of projects from the database based on pagination parameters.
+// GetList retrieves a page of projects matching the query.
 //
-// The function accepts a context, a page number, and a page size as parameters.
+// The function accepts a context, a ProjectQuery and a PageSpec as parameters.
 // It uses the provided context to ensure that the operation is performed within the specified deadline.
 //
 // The function performs the following steps:
-// 1. Counts the total number of projects in the database.
-// 2. Calculates the total number of pages based on the total records and page size.
-// 3. Retrieves the list of projects for the specified page, using the provided page number and page size.
+// 1. Applies the query filters and orders the rows by query.Sort and then by id.
+// 2. If spec.Keyset is set, seeks past the boundary row instead of using OFFSET.
+// 3. Counts the matching projects only when spec.IncludeTotal is true.
 //
-// The function returns a Pagination struct containing the list of projects, total pages, total records, and current page.
+// The function returns a Pagination struct containing the projects and the has_next/has_prev flags;
+// cursors are filled in by the use case layer.
 // If an error occurs during the process, the function returns nil and the error.
-func (p ProjectRepository) GetList(ctx context.Context, page int, pageSize int) (*pkg.Pagination, error) {
-	var projectList []entities.Project
-	var totalRecords int64
-
-	// Count total records
-	if err := p.db.WithContext(ctx).Model(&entities.Project{}).Count(&totalRecords).Error; err != nil {
-		return nil, err
-	}
-
-	// Calculate total pages
-	totalPages := int(totalRecords) / pageSize
-	if int(totalRecords)%pageSize != 0 {
-		totalPages++
-	}
-
-	// Retrieve data for the current page
-	offset := (page - 1) * pageSize
-	if err := p.db.WithContext(ctx).Limit(pageSize).Offset(offset).Find(&projectList).Error; err != nil {
-		return nil, err
-	}
-
-	pagination := &pkg.Pagination{
-		Projects:     projectList,
-		TotalPages:   totalPages,
-		TotalRecords: int(totalRecords),
-		CurrentPage:  page,
-	}
+func (p ProjectRepository) GetList(ctx context.Context, query ProjectQuery, spec PageSpec) (*pkg.Pagination[entities.Project], error) {
+	db := query.apply(p.db.WithContext(ctx).Model(&entities.Project{}))
+	return paginate[entities.Project](db, "projects", query.Sort, spec)
+}
 
-	return pagination, nil
+// GetListByMember works like GetList but only returns projects the user is a member of.
+func (p ProjectRepository) GetListByMember(ctx context.Context, userID int, query ProjectQuery, spec PageSpec) (*pkg.Pagination[entities.Project], error) {
+	db := p.db.WithContext(ctx).Model(&entities.Project{}).
+		Joins("JOIN user_projects ON user_projects.project_id = projects.id").
+		Where("user_projects.user_id = ?", userID)
+	return paginate[entities.Project](query.apply(db), "projects", query.Sort, spec)
 }
 
 // Create creates a new project in the database.
@@ -75,9 +82,25 @@ func (p ProjectRepository) GetList(ctx context.Context, page int, pageSize int)
 // It creates a new record in the database using the provided Project entity.
 // If an error occurs during the creation process, the function logs the error and returns nil, along with the error.
 // Otherwise, it returns the created Project entity and nil.
+// The first version of the project is recorded in the same transaction.
+// A slug already used by another project of the same owner returns ErrSlugAlreadyExists.
 func (p ProjectRepository) Create(ctx context.Context, pj *entities.Project) (*entities.Project, error) {
-	if err := p.db.WithContext(ctx).Create(pj).Error; err != nil {
+	tx := p.StartTransaction().WithContext(ctx)
+	if err := tx.Create(pj).Error; err != nil {
+		p.RollBackTransaction(tx)
 		log.Error("Cannot create project with err: ", err)
+		if errors.Is(err, gorm.ErrDuplicatedKey) {
+			return nil, ErrSlugAlreadyExists
+		}
+		return nil, err
+	}
+	version, err := recordVersion(tx, pj.ID, entities.ProjectChangeCreate, nil)
+	if err != nil {
+		p.RollBackTransaction(tx)
+		return nil, err
+	}
+	pj.Version = version.Version
+	if err := p.CommitTransaction(tx); err != nil {
 		return nil, err
 	}
 	return pj, nil
@@ -90,12 +113,16 @@ func (p ProjectRepository) Create(ctx context.Context, pj *entities.Project) (*e
 //
 // 2. Updates the project in the database using the provided Project entity.
 //   - If an error occurs during the update, it returns nil and the error.
-//   - If no rows are affected (i.e., the project was not updated), it returns nil and an error indicating that the update might have failed.
+//   - The update only applies while the row still has pj.Version; if another write has bumped the version since pj
+//     was read, nothing is written and it returns nil and ErrProjectVersionConflict.
+//   - If the slug is already used by another project of the same owner, it returns nil and ErrSlugAlreadyExists.
 //
 // 3. Fetches the updated project from the database.
 //   - If an error occurs during the fetch, it returns nil and the error.
 //
-// 4. Returns the updated Project entity and nil.
+// 4. Records the new state as a project version before committing.
+//
+// 5. Returns the updated Project entity and nil.
 func (p ProjectRepository) Update(ctx context.Context, pj *entities.Project) (*entities.Project, error) {
 	// existing check
 	existingProject := entities.Project{}
@@ -107,13 +134,49 @@ func (p ProjectRepository) Update(ctx context.Context, pj *entities.Project) (*e
 		return nil, fmt.Errorf("error checking existing project: %w", result.Error)
 	}
 
-	result = p.db.WithContext(ctx).Model(pj).Updates(pj)
+	// tổng spend/revenue do sổ cái project_transactions quản lý, status do ChangeStatus quản lý,
+	// bỏ qua chúng để không ghi đè giá trị đã được cập nhật song song sau khi đọc pj; đơn vị tiền không đổi sau khi tạo
+	// khóa dòng project để việc kiểm tra version và ghi version mới không bị xen giữa bởi request khác
+	tx := p.StartTransaction().WithContext(ctx)
+	if _, err := lockProject(tx, pj.ID); err != nil {
+		p.RollBackTransaction(tx)
+		return nil, err
+	}
+	result = tx.Model(pj).Where("version = ?", pj.Version).
+		Omit("project_spend_amount", "revenue_recognised_amount", "status", "owner_id", "slug", "version",
+			"budget_amount", "project_variance_amount",
+			"budget_currency", "project_spend_currency", "project_variance_currency", "revenue_recognised_currency").Updates(pj)
 	if result.Error != nil {
+		p.RollBackTransaction(tx)
 		return nil, fmt.Errorf("error updating project: %w", result.Error)
 	}
 
+	// dòng project đã được khóa ở trên nên không có dòng nào được cập nhật nghĩa là version đã khác
 	if result.RowsAffected == 0 {
-		return nil, errors.New("no rows affected, update might have failed")
+		p.RollBackTransaction(tx)
+		return nil, fmt.Errorf("%w: project %d is no longer at version %d", ErrProjectVersionConflict, pj.ID, pj.Version)
+	}
+
+	// budget và slug được ghi riêng vì Updates với struct bỏ qua giá trị 0/nil; variance tính theo spend đang có trong DB
+	// chứ không theo spend của pj, sổ cái có thể đã ghi thêm bút toán sau khi pj được đọc
+	err := tx.Model(pj).UpdateColumns(map[string]interface{}{
+		"budget_amount":           pj.Budget.Amount,
+		"project_variance_amount": gorm.Expr("? - project_spend_amount", pj.Budget.Amount),
+		"slug":                    pj.Slug,
+	}).Error
+	if err != nil {
+		p.RollBackTransaction(tx)
+		if errors.Is(err, gorm.ErrDuplicatedKey) {
+			return nil, ErrSlugAlreadyExists
+		}
+		return nil, fmt.Errorf("error updating project budget: %w", err)
+	}
+	if _, err := recordVersion(tx, pj.ID, entities.ProjectChangeUpdate, nil); err != nil {
+		p.RollBackTransaction(tx)
+		return nil, err
+	}
+	if err := p.CommitTransaction(tx); err != nil {
+		return nil, err
 	}
 
 	updatedProject := &entities.Project{}
@@ -124,45 +187,49 @@ func (p ProjectRepository) Update(ctx context.Context, pj *entities.Project) (*e
 	return updatedProject, nil
 }
 
-// Delete removes a project from the database by its name.
+// Delete soft-deletes the project with the given ID, moving it to the trash.
 //
-// The function accepts a context and a project name as parameters.
-// It uses the provided context to ensure that the operation is performed within the specified deadline.
+// The project row is locked for the duration of the transaction so the check below and the delete
+// cannot interleave with a ledger write or a restore.
 //
-// The function first attempts to find a project with the given name in the database.
-// If no project is found, it logs an error message and returns false.
-// If an error occurs during the search process, it logs the error message and returns false.
+// The function returns:
+//   - a wrapped gorm.ErrRecordNotFound if the project does not exist or is already in the trash.
+//   - ErrProjectHasPendingTimesheets if timesheets of the project are submitted and waiting for approval,
+//     since approving them posts spend into the project's ledger.
 //
-// If a project is found, the function proceeds to delete the project from the database.
-// If an error occurs during the deletion process, it logs an error message and returns false.
-// If no project is deleted (i.e., RowsAffected is 0), it logs an error message and returns false.
-//
-// If the project is successfully deleted, it logs an informational message and returns true.
-func (p ProjectRepository) Delete(ctx context.Context, name string) bool {
-	var project entities.Project
-	err := p.db.WithContext(ctx).Where("name = ?", name).First(&project)
-	if err.Error != nil {
-		if errors.Is(err.Error, gorm.ErrRecordNotFound) {
-			log.Errorf("Project with name '%s' not found", name)
-			return false
-		}
-		log.Errorf("Error when finding project with name '%s': %v", name, err.Error)
-		return false
+// Membership rows are kept so the project can be restored; PurgeDeleted removes them together with the project.
+func (p ProjectRepository) Delete(ctx context.Context, id int) error {
+	tx := p.StartTransaction().WithContext(ctx)
+
+	project, err := lockProject(tx, id)
+	if err != nil {
+		p.RollBackTransaction(tx)
+		return err
 	}
 
-	result := p.db.WithContext(ctx).Delete(&project)
-	if result.Error != nil {
-		log.Errorf("Failed to delete project with name '%s': %v", name, result.Error)
-		return false
+	var pending int64
+	err = tx.Model(&entities.Timesheet{}).
+		Where("project_id = ? AND status = ?", id, entities.TimesheetStatusSubmitted).
+		Count(&pending).Error
+	if err != nil {
+		p.RollBackTransaction(tx)
+		return fmt.Errorf("error checking pending timesheets: %w", err)
+	}
+	if pending > 0 {
+		p.RollBackTransaction(tx)
+		return fmt.Errorf("%w: project %d has %d", ErrProjectHasPendingTimesheets, id, pending)
 	}
 
-	if result.RowsAffected == 0 {
-		log.Errorf("No project deleted with name '%s'", name)
-		return false
+	if err := tx.Delete(project).Error; err != nil {
+		p.RollBackTransaction(tx)
+		return fmt.Errorf("error deleting project: %w", err)
+	}
+	if err := p.CommitTransaction(tx); err != nil {
+		return err
 	}
 
-	log.Infof("Successfully deleted project with name '%s'", name)
-	return true
+	log.Infof("Successfully deleted project %d", id)
+	return nil
 }
 
 func (p ProjectRepository) GetById(ctx context.Context, id int) (*entities.Project, error) {
@@ -170,13 +237,64 @@ func (p ProjectRepository) GetById(ctx context.Context, id int) (*entities.Proje
 	if err := p.db.WithContext(ctx).First(&project, id).Error; err != nil {
 		log.Error("Having error when find project: ", id, err)
 		if errors.Is(err, gorm.ErrRecordNotFound) {
-			return nil, fmt.Errorf("project %v not found", project)
+			return nil, fmt.Errorf("project %d not found: %w", id, err)
+		}
+		return nil, fmt.Errorf("error retrieving project: %w", err)
+	}
+	return &project, nil
+}
+
+// GetBySlug project có slug do user ownerID tạo
+func (p ProjectRepository) GetBySlug(ctx context.Context, ownerID int, slug string) (*entities.Project, error) {
+	var project entities.Project
+	if err := p.db.WithContext(ctx).Where("owner_id = ? AND slug = ?", ownerID, slug).First(&project).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, fmt.Errorf("project with slug '%s' of user %d not found: %w", slug, ownerID, err)
 		}
 		return nil, fmt.Errorf("error retrieving project: %w", err)
 	}
 	return &project, nil
 }
 
+// ChangeStatus saves pj.Status (and pj.ProjectEndedAt), appends the history row and records a project version
+// in one transaction.
+//
+// The update is conditional on the row still having fromStatus, so two concurrent transitions
+// cannot both succeed; the loser gets ErrProjectStatusChanged and nothing is written.
+func (p ProjectRepository) ChangeStatus(ctx context.Context, pj *entities.Project, fromStatus string, history *entities.ProjectStatusHistory) error {
+	tx := p.StartTransaction().WithContext(ctx)
+	result := tx.Model(&entities.Project{}).
+		Where("id = ? AND status = ?", pj.ID, fromStatus).
+		Updates(map[string]interface{}{"status": pj.Status, "project_ended_at": pj.ProjectEndedAt})
+	if result.Error != nil {
+		p.RollBackTransaction(tx)
+		return fmt.Errorf("error updating project status: %w", result.Error)
+	}
+	if result.RowsAffected == 0 {
+		p.RollBackTransaction(tx)
+		return ErrProjectStatusChanged
+	}
+
+	if err := tx.Create(history).Error; err != nil {
+		p.RollBackTransaction(tx)
+		return fmt.Errorf("error saving project status history: %w", err)
+	}
+	version, err := recordVersion(tx, pj.ID, entities.ProjectChangeStatus, nil)
+	if err != nil {
+		p.RollBackTransaction(tx)
+		return err
+	}
+	pj.Version = version.Version
+	return p.CommitTransaction(tx)
+}
+
+// GetStatusHistory returns the status transitions of a project, oldest first.
+func (p ProjectRepository) GetStatusHistory(ctx context.Context, projectID int) ([]entities.ProjectStatusHistory, error) {
+	var history []entities.ProjectStatusHistory
+	err := p.db.WithContext(ctx).Where("project_id = ?", projectID).Order("id").Find(&history).Error
+	return history, err
+}
+
 // NewProjectRepository constructor
 func NewProjectRepository(db *gorm.DB) IProjectRepository {
 	return &ProjectRepository{
@@ -0,0 +1,123 @@
+package repositories
+
+import (
+	"Backend_golang_project/internal/domain/entities"
+	"Backend_golang_project/internal/domain/money"
+	"Backend_golang_project/internal/pkg"
+	"context"
+	"errors"
+	"fmt"
+
+	log "github.com/sirupsen/logrus"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+// ErrProjectClosed project đã completed/archived, sổ cái không nhận thêm bút toán
+var ErrProjectClosed = errors.New("project is closed for new transactions")
+
+// transactionTotals cột tổng trên bảng projects tương ứng với từng target của bút toán
+var transactionTotals = map[string]string{
+	entities.TransactionTypeSpend:   "project_spend_amount",
+	entities.TransactionTypeRevenue: "revenue_recognised_amount",
+}
+
+type IProjectTransactionRepository interface {
+	Post(ctx context.Context, entry *entities.ProjectTransaction) (*entities.Project, error)
+	List(ctx context.Context, projectID int, spec PageSpec) (*pkg.Pagination[entities.ProjectTransaction], error)
+}
+
+type ProjectTransactionRepository struct {
+	base
+}
+
+// Post ghi một bút toán và cộng Amount vào cột tổng tương ứng trên projects trong cùng một transaction.
+// Dòng project được khóa bằng SELECT ... FOR UPDATE để kiểm tra trạng thái và để các bút toán song song
+// được cộng lần lượt; trả về project với tổng đã cập nhật
+func (r ProjectTransactionRepository) Post(ctx context.Context, entry *entities.ProjectTransaction) (*entities.Project, error) {
+	tx := r.StartTransaction().WithContext(ctx)
+
+	project, err := lockProject(tx, entry.ProjectID)
+	if err != nil {
+		r.RollBackTransaction(tx)
+		return nil, err
+	}
+	if err := postEntry(tx, project, entry); err != nil {
+		r.RollBackTransaction(tx)
+		return nil, err
+	}
+
+	if err := r.CommitTransaction(tx); err != nil {
+		return nil, err
+	}
+	return project, nil
+}
+
+// lockProject đọc project với SELECT ... FOR UPDATE, mọi thay đổi tổng tiền của project đều khóa dòng project trước
+func lockProject(tx *gorm.DB, projectID int) (*entities.Project, error) {
+	var project entities.Project
+	if err := tx.Clauses(clause.Locking{Strength: "UPDATE"}).First(&project, projectID).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, fmt.Errorf("project %d not found: %w", projectID, err)
+		}
+		return nil, fmt.Errorf("error locking project: %w", err)
+	}
+	return &project, nil
+}
+
+// postEntry ghi bút toán và cập nhật tổng của project trong tx, project phải đã được khóa bằng lockProject.
+// project được đọc lại sau khi cập nhật và trạng thái mới được ghi thành một version
+func postEntry(tx *gorm.DB, project *entities.Project, entry *entities.ProjectTransaction) error {
+	column, ok := transactionTotals[entry.Target]
+	if !ok {
+		return fmt.Errorf("unknown transaction target %q", entry.Target)
+	}
+	if project.FinancialsLocked() {
+		return ErrProjectClosed
+	}
+	if entry.Amount.Currency != project.Currency() {
+		return fmt.Errorf("%w: project is billed in %s, entry is in %s", money.ErrCurrencyMismatch, project.Currency(), entry.Amount.Currency)
+	}
+
+	if err := tx.Create(entry).Error; err != nil {
+		log.Error("Cannot create project transaction with err: ", err)
+		return err
+	}
+	err := tx.Model(&entities.Project{}).Where("id = ?", project.ID).
+		UpdateColumn(column, gorm.Expr(column+" + ?", entry.Amount.Amount)).Error
+	if err != nil {
+		return fmt.Errorf("error updating project totals: %w", err)
+	}
+	if err := tx.First(project, project.ID).Error; err != nil {
+		return fmt.Errorf("error fetching updated project: %w", err)
+	}
+	// variance = budget - spend, tính lại khi spend thay đổi khi dòng project vẫn đang bị khóa
+	if entry.Target == entities.TransactionTypeSpend {
+		project.ComputeVariance()
+		err := tx.Model(project).UpdateColumn("project_variance_amount", project.ProjectVariance.Amount).Error
+		if err != nil {
+			return fmt.Errorf("error updating project variance: %w", err)
+		}
+	}
+	// tổng tiền thay đổi sau mỗi bút toán, lịch sử tài chính của project được giữ đầy đủ qua các version
+	version, err := recordVersion(tx, project.ID, entities.ProjectChangeLedger, nil)
+	if err != nil {
+		return err
+	}
+	project.Version = version.Version
+	return nil
+}
+
+// List bút toán của project, mới nhất trước (theo effective_date rồi id)
+func (r ProjectTransactionRepository) List(ctx context.Context, projectID int, spec PageSpec) (*pkg.Pagination[entities.ProjectTransaction], error) {
+	db := r.db.WithContext(ctx).Model(&entities.ProjectTransaction{}).Where("project_id = ?", projectID)
+	return paginate[entities.ProjectTransaction](db, "project_transactions", TransactionSort, spec)
+}
+
+// TransactionSort thứ tự của List, tầng use case dùng nó để tạo/giải mã cursor
+var TransactionSort = []SortColumn{{Column: "effective_date", Desc: true}, {Column: "id", Desc: true}}
+
+// NewProjectTransactionRepository constructor
+func NewProjectTransactionRepository(db *gorm.DB) IProjectTransactionRepository {
+	return &ProjectTransactionRepository{base: base{db: db}}
+}
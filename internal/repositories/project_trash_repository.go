@@ -0,0 +1,116 @@
+package repositories
+
+import (
+	"Backend_golang_project/internal/domain/entities"
+	"Backend_golang_project/internal/pkg"
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+// ErrProjectNotDeleted project cần khôi phục không nằm trong thùng rác
+var ErrProjectNotDeleted = errors.New("project is not deleted")
+
+// TrashSort project bị xóa gần nhất trước
+var TrashSort = []SortColumn{{Column: "deleted_at", Desc: true}}
+
+// GetTrash các project đã bị xóa mềm, Keyset (nếu có) gồm deleted_at và id
+func (p ProjectRepository) GetTrash(ctx context.Context, spec PageSpec) (*pkg.Pagination[entities.Project], error) {
+	db := p.db.WithContext(ctx).Unscoped().Model(&entities.Project{}).Where("projects.deleted_at IS NOT NULL")
+	return paginate[entities.Project](db, "projects", TrashSort, spec)
+}
+
+// GetTrashByOwner như GetTrash nhưng chỉ gồm các project mà user là owner, membership được giữ lại khi xóa mềm
+func (p ProjectRepository) GetTrashByOwner(ctx context.Context, userID int, spec PageSpec) (*pkg.Pagination[entities.Project], error) {
+	db := p.db.WithContext(ctx).Unscoped().Model(&entities.Project{}).
+		Joins("JOIN user_projects ON user_projects.project_id = projects.id").
+		Where("user_projects.user_id = ? AND user_projects.role = ?", userID, entities.ProjectRoleOwner).
+		Where("projects.deleted_at IS NOT NULL")
+	return paginate[entities.Project](db, "projects", TrashSort, spec)
+}
+
+// Restore đưa project ra khỏi thùng rác, project chưa bị xóa trả về ErrProjectNotDeleted
+func (p ProjectRepository) Restore(ctx context.Context, id int) (*entities.Project, error) {
+	tx := p.StartTransaction().WithContext(ctx)
+
+	var project entities.Project
+	if err := tx.Unscoped().Clauses(clause.Locking{Strength: "UPDATE"}).First(&project, id).Error; err != nil {
+		p.RollBackTransaction(tx)
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, fmt.Errorf("project %d not found: %w", id, err)
+		}
+		return nil, fmt.Errorf("error retrieving project: %w", err)
+	}
+	if !project.DeletedAt.Valid {
+		p.RollBackTransaction(tx)
+		return nil, fmt.Errorf("%w: project %d", ErrProjectNotDeleted, id)
+	}
+
+	result := tx.Unscoped().Model(&entities.Project{}).
+		Where("id = ? AND deleted_at IS NOT NULL", id).
+		Update("deleted_at", nil)
+	if result.Error != nil {
+		p.RollBackTransaction(tx)
+		return nil, fmt.Errorf("error restoring project: %w", result.Error)
+	}
+	if err := p.CommitTransaction(tx); err != nil {
+		return nil, err
+	}
+
+	project.DeletedAt = gorm.DeletedAt{}
+	return &project, nil
+}
+
+// PurgeDeleted xóa hẳn tối đa limit project đã bị xóa mềm trước thời điểm before, trả về số project đã xóa.
+// Membership, milestone/task và lịch doanh thu của project bị xóa cùng; sổ cái, version, lịch sử trạng thái
+// và timesheet được giữ lại vì là lịch sử tài chính
+func (p ProjectRepository) PurgeDeleted(ctx context.Context, before time.Time, limit int) (int, error) {
+	tx := p.StartTransaction().WithContext(ctx)
+
+	// khóa các dòng sẽ bị xóa để Restore chạy song song phải chờ, project đã được khôi phục thì không còn khớp điều kiện
+	var ids []int
+	err := tx.Unscoped().Model(&entities.Project{}).Clauses(clause.Locking{Strength: "UPDATE"}).
+		Where("deleted_at IS NOT NULL AND deleted_at < ?", before).
+		Order("id").Limit(limit).
+		Pluck("id", &ids).Error
+	if err != nil {
+		p.RollBackTransaction(tx)
+		return 0, fmt.Errorf("error finding expired projects: %w", err)
+	}
+	if len(ids) == 0 {
+		p.RollBackTransaction(tx)
+		return 0, nil
+	}
+
+	schedules := tx.Model(&entities.RevenueSchedule{}).Select("id").Where("project_id IN ?", ids)
+	cleanups := []struct {
+		model interface{}
+		query *gorm.DB
+	}{
+		{&entities.UserProject{}, tx.Where("project_id IN ?", ids)},
+		{&entities.Task{}, tx.Where("project_id IN ?", ids)},
+		{&entities.Milestone{}, tx.Where("project_id IN ?", ids)},
+		{&entities.RevenueMilestone{}, tx.Where("schedule_id IN (?)", schedules)},
+		{&entities.RevenueSchedule{}, tx.Where("project_id IN ?", ids)},
+	}
+	for _, cleanup := range cleanups {
+		if err := cleanup.query.Delete(cleanup.model).Error; err != nil {
+			p.RollBackTransaction(tx)
+			return 0, fmt.Errorf("error purging project dependants: %w", err)
+		}
+	}
+
+	result := tx.Unscoped().Where("id IN ?", ids).Delete(&entities.Project{})
+	if result.Error != nil {
+		p.RollBackTransaction(tx)
+		return 0, fmt.Errorf("error purging projects: %w", result.Error)
+	}
+	if err := p.CommitTransaction(tx); err != nil {
+		return 0, err
+	}
+	return int(result.RowsAffected), nil
+}
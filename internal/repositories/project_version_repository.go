@@ -0,0 +1,127 @@
+package repositories
+
+import (
+	"Backend_golang_project/internal/domain/entities"
+	"Backend_golang_project/internal/pkg"
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"gorm.io/gorm"
+)
+
+// ProjectVersionSort version mới nhất trước
+var ProjectVersionSort = []SortColumn{{Column: "version", Desc: true}}
+
+// ListVersions các version của project, mới nhất trước
+func (p ProjectRepository) ListVersions(ctx context.Context, projectID int, spec PageSpec) (*pkg.Pagination[entities.ProjectVersion], error) {
+	db := p.db.WithContext(ctx).Model(&entities.ProjectVersion{}).Where("project_id = ?", projectID)
+	versions, err := paginate[entities.ProjectVersion](db, "project_versions", ProjectVersionSort, spec)
+	if err != nil {
+		return nil, fmt.Errorf("error listing project versions: %w", err)
+	}
+	return versions, nil
+}
+
+func (p ProjectRepository) GetVersion(ctx context.Context, projectID int, version int) (*entities.ProjectVersion, error) {
+	return projectVersion(p.db.WithContext(ctx), projectID, version)
+}
+
+// GetVersionAt version có hiệu lực tại thời điểm asOf, tức version cuối cùng được ghi không muộn hơn asOf
+func (p ProjectRepository) GetVersionAt(ctx context.Context, projectID int, asOf time.Time) (*entities.ProjectVersion, error) {
+	var version entities.ProjectVersion
+	err := p.db.WithContext(ctx).
+		Where("project_id = ? AND created_at <= ?", projectID, asOf).
+		Order("version DESC").
+		First(&version).Error
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, fmt.Errorf("project %d has no version at %s: %w", projectID, asOf.Format(time.RFC3339), err)
+		}
+		return nil, fmt.Errorf("error retrieving project version: %w", err)
+	}
+	return &version, nil
+}
+
+// RestoreVersion đưa name, category, budget và ngày bắt đầu/kết thúc của project về giá trị trong version.
+// Spend/revenue do sổ cái quản lý và status do ChangeStatus quản lý nên giữ nguyên, variance tính lại theo spend hiện tại;
+// bản thân việc khôi phục cũng tạo một version mới trỏ về version được khôi phục
+func (p ProjectRepository) RestoreVersion(ctx context.Context, projectID int, version int) (*entities.Project, error) {
+	tx := p.StartTransaction().WithContext(ctx)
+
+	project, err := lockProject(tx, projectID)
+	if err != nil {
+		p.RollBackTransaction(tx)
+		return nil, err
+	}
+	target, err := projectVersion(tx, projectID, version)
+	if err != nil {
+		p.RollBackTransaction(tx)
+		return nil, err
+	}
+
+	project.Name = target.Name
+	project.Category = target.Category
+	project.Budget.Amount = target.Budget.Amount
+	project.ProjectStartedAt = target.ProjectStartedAt
+	project.ProjectEndedAt = target.ProjectEndedAt
+	project.ComputeVariance()
+	err = tx.Model(project).
+		Select("name", "category", "budget_amount", "project_variance_amount", "project_started_at", "project_ended_at", "updated_at").
+		Updates(project).Error
+	if err != nil {
+		p.RollBackTransaction(tx)
+		return nil, fmt.Errorf("error restoring project: %w", err)
+	}
+
+	recorded, err := recordVersion(tx, projectID, entities.ProjectChangeRestore, &target.Version)
+	if err != nil {
+		p.RollBackTransaction(tx)
+		return nil, err
+	}
+	project.Version = recorded.Version
+	if err := p.CommitTransaction(tx); err != nil {
+		return nil, err
+	}
+	return project, nil
+}
+
+func projectVersion(db *gorm.DB, projectID int, version int) (*entities.ProjectVersion, error) {
+	var result entities.ProjectVersion
+	if err := db.Where("project_id = ? AND version = ?", projectID, version).First(&result).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, fmt.Errorf("version %d of project %d not found: %w", version, projectID, err)
+		}
+		return nil, fmt.Errorf("error retrieving project version: %w", err)
+	}
+	return &result, nil
+}
+
+// recordVersion tăng projects.version rồi chụp lại dòng projects hiện tại trong tx thành version đó, mọi thao tác
+// thay đổi dữ liệu của project đều gọi hàm này trước khi commit; xóa mềm và khôi phục khỏi thùng rác không đổi dữ liệu
+// nên không tạo version. Người thực hiện lấy từ context của tx, nil khi thay đổi do hệ thống thực hiện.
+// Lệnh tăng version khóa dòng project tới hết tx nên các version của cùng project được đánh số lần lượt
+func recordVersion(tx *gorm.DB, projectID int, changeType string, restoredFrom *int) (*entities.ProjectVersion, error) {
+	err := tx.Model(&entities.Project{}).
+		Where("id = ?", projectID).
+		UpdateColumn("version", gorm.Expr("version + 1")).Error
+	if err != nil {
+		return nil, fmt.Errorf("error incrementing project version: %w", err)
+	}
+	var project entities.Project
+	if err := tx.First(&project, projectID).Error; err != nil {
+		return nil, fmt.Errorf("error reading project for version: %w", err)
+	}
+
+	var changedBy *int
+	if userID, ok := pkg.UserIDFromContext(tx.Statement.Context); ok {
+		changedBy = &userID
+	}
+	version := entities.NewProjectVersion(&project, project.Version, changeType, changedBy)
+	version.RestoredFrom = restoredFrom
+	if err := tx.Create(version).Error; err != nil {
+		return nil, fmt.Errorf("error saving project version: %w", err)
+	}
+	return version, nil
+}
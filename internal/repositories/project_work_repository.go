@@ -0,0 +1,173 @@
+package repositories
+
+import (
+	"Backend_golang_project/internal/domain/entities"
+	"context"
+	"errors"
+	"fmt"
+
+	"gorm.io/gorm"
+)
+
+type IProjectWorkRepository interface {
+	ListMilestones(ctx context.Context, projectID int) ([]entities.Milestone, error)
+	GetMilestone(ctx context.Context, projectID int, id int) (*entities.Milestone, error)
+	SaveMilestone(ctx context.Context, milestone *entities.Milestone) (*entities.Milestone, error)
+	DeleteMilestone(ctx context.Context, projectID int, id int) error
+	ListTasks(ctx context.Context, projectID int) ([]entities.Task, error)
+	GetTask(ctx context.Context, projectID int, id int) (*entities.Task, error)
+	SaveTask(ctx context.Context, task *entities.Task) (*entities.Task, error)
+	DeleteTask(ctx context.Context, projectID int, id int) error
+	ProgressByProjects(ctx context.Context, projectIDs []int) (map[int]*entities.ProjectProgress, error)
+}
+
+type ProjectWorkRepository struct {
+	base
+}
+
+func (r ProjectWorkRepository) ListMilestones(ctx context.Context, projectID int) ([]entities.Milestone, error) {
+	var milestones []entities.Milestone
+	err := r.db.WithContext(ctx).Where("project_id = ?", projectID).Order(orderByDueDate).Find(&milestones).Error
+	if err != nil {
+		return nil, fmt.Errorf("error listing milestones: %w", err)
+	}
+	return milestones, nil
+}
+
+// GetMilestone chỉ tìm trong project đã cho, milestone của project khác được coi như không tồn tại
+func (r ProjectWorkRepository) GetMilestone(ctx context.Context, projectID int, id int) (*entities.Milestone, error) {
+	var milestone entities.Milestone
+	err := r.db.WithContext(ctx).Where("project_id = ?", projectID).First(&milestone, id).Error
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, fmt.Errorf("milestone %d of project %d not found: %w", id, projectID, err)
+		}
+		return nil, fmt.Errorf("error retrieving milestone: %w", err)
+	}
+	return &milestone, nil
+}
+
+// SaveMilestone tạo mới khi ID bằng 0, ngược lại ghi đè toàn bộ các cột của milestone
+func (r ProjectWorkRepository) SaveMilestone(ctx context.Context, milestone *entities.Milestone) (*entities.Milestone, error) {
+	if err := r.db.WithContext(ctx).Save(milestone).Error; err != nil {
+		return nil, fmt.Errorf("error saving milestone: %w", err)
+	}
+	return milestone, nil
+}
+
+// DeleteMilestone xóa milestone, các task thuộc milestone được giữ lại và tách khỏi milestone
+func (r ProjectWorkRepository) DeleteMilestone(ctx context.Context, projectID int, id int) error {
+	tx := r.StartTransaction().WithContext(ctx)
+
+	err := tx.Model(&entities.Task{}).
+		Where("project_id = ? AND milestone_id = ?", projectID, id).
+		Update("milestone_id", nil).Error
+	if err != nil {
+		r.RollBackTransaction(tx)
+		return fmt.Errorf("error detaching tasks from milestone: %w", err)
+	}
+
+	result := tx.Where("project_id = ?", projectID).Delete(&entities.Milestone{}, id)
+	if result.Error != nil {
+		r.RollBackTransaction(tx)
+		return fmt.Errorf("error deleting milestone: %w", result.Error)
+	}
+	if result.RowsAffected == 0 {
+		r.RollBackTransaction(tx)
+		return fmt.Errorf("milestone %d of project %d not found: %w", id, projectID, gorm.ErrRecordNotFound)
+	}
+	return r.CommitTransaction(tx)
+}
+
+func (r ProjectWorkRepository) ListTasks(ctx context.Context, projectID int) ([]entities.Task, error) {
+	var tasks []entities.Task
+	err := r.db.WithContext(ctx).Where("project_id = ?", projectID).Order(orderByDueDate).Find(&tasks).Error
+	if err != nil {
+		return nil, fmt.Errorf("error listing tasks: %w", err)
+	}
+	return tasks, nil
+}
+
+func (r ProjectWorkRepository) GetTask(ctx context.Context, projectID int, id int) (*entities.Task, error) {
+	var task entities.Task
+	err := r.db.WithContext(ctx).Where("project_id = ?", projectID).First(&task, id).Error
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, fmt.Errorf("task %d of project %d not found: %w", id, projectID, err)
+		}
+		return nil, fmt.Errorf("error retrieving task: %w", err)
+	}
+	return &task, nil
+}
+
+func (r ProjectWorkRepository) SaveTask(ctx context.Context, task *entities.Task) (*entities.Task, error) {
+	if err := r.db.WithContext(ctx).Save(task).Error; err != nil {
+		return nil, fmt.Errorf("error saving task: %w", err)
+	}
+	return task, nil
+}
+
+func (r ProjectWorkRepository) DeleteTask(ctx context.Context, projectID int, id int) error {
+	result := r.db.WithContext(ctx).Where("project_id = ?", projectID).Delete(&entities.Task{}, id)
+	if result.Error != nil {
+		return fmt.Errorf("error deleting task: %w", result.Error)
+	}
+	if result.RowsAffected == 0 {
+		return fmt.Errorf("task %d of project %d not found: %w", id, projectID, gorm.ErrRecordNotFound)
+	}
+	return nil
+}
+
+// workGroup một dòng của truy vấn gom nhóm milestone/task theo project và trạng thái
+type workGroup struct {
+	ProjectID int
+	Status    string
+	Count     int64
+	Hours     float64
+}
+
+// ProgressByProjects tổng hợp tiến độ của nhiều project bằng hai truy vấn gom nhóm,
+// project không có milestone/task nào vẫn có mặt trong kết quả với tiến độ 0
+func (r ProjectWorkRepository) ProgressByProjects(ctx context.Context, projectIDs []int) (map[int]*entities.ProjectProgress, error) {
+	progress := make(map[int]*entities.ProjectProgress, len(projectIDs))
+	if len(projectIDs) == 0 {
+		return progress, nil
+	}
+	for _, id := range projectIDs {
+		progress[id] = &entities.ProjectProgress{}
+	}
+
+	var milestones, tasks []workGroup
+	if err := r.groupWork(ctx, &entities.Milestone{}, projectIDs, &milestones); err != nil {
+		return nil, fmt.Errorf("error summarising milestones: %w", err)
+	}
+	if err := r.groupWork(ctx, &entities.Task{}, projectIDs, &tasks); err != nil {
+		return nil, fmt.Errorf("error summarising tasks: %w", err)
+	}
+	for _, group := range milestones {
+		progress[group.ProjectID].Milestones.Add(group.Status, group.Count, group.Hours)
+	}
+	for _, group := range tasks {
+		progress[group.ProjectID].Tasks.Add(group.Status, group.Count, group.Hours)
+	}
+	for _, p := range progress {
+		p.ComputePercent()
+	}
+	return progress, nil
+}
+
+func (r ProjectWorkRepository) groupWork(ctx context.Context, model interface{}, projectIDs []int, groups *[]workGroup) error {
+	return r.db.WithContext(ctx).Model(model).
+		Select("project_id, status, count(*) AS count, coalesce(sum(estimate_hours), 0) AS hours").
+		Where("project_id IN ?", projectIDs).
+		Group("project_id, status").
+		Scan(groups).Error
+}
+
+// orderByDueDate milestone/task chưa có hạn xếp sau cùng
+const orderByDueDate = "due_date IS NULL, due_date, id"
+
+// NewProjectWorkRepository constructor
+func NewProjectWorkRepository(db *gorm.DB) IProjectWorkRepository {
+	return &ProjectWorkRepository{base: base{db: db}}
+}
@@ -0,0 +1,82 @@
+package repositories
+
+import (
+	"Backend_golang_project/internal/domain/entities"
+	"context"
+	"fmt"
+	"gorm.io/gorm"
+	"sync"
+	"time"
+)
+
+// InMemoryRefreshTokenStore giữ refresh token trong bộ nhớ, dùng cho test và chạy local không cần MySQL
+type InMemoryRefreshTokenStore struct {
+	mu     sync.Mutex
+	tokens map[string]entities.RefreshToken
+}
+
+func (s *InMemoryRefreshTokenStore) Save(_ context.Context, token *entities.RefreshToken) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if _, exists := s.tokens[token.JTI]; exists {
+		return fmt.Errorf("refresh token %s already exists", token.JTI)
+	}
+	s.tokens[token.JTI] = *token
+	return nil
+}
+
+func (s *InMemoryRefreshTokenStore) GetByJTI(_ context.Context, jti string) (*entities.RefreshToken, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	token, ok := s.tokens[jti]
+	if !ok {
+		return nil, fmt.Errorf("refresh token %s not found: %w", jti, gorm.ErrRecordNotFound)
+	}
+	return &token, nil
+}
+
+func (s *InMemoryRefreshTokenStore) Rotate(_ context.Context, oldJTI string, next *entities.RefreshToken) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	old, ok := s.tokens[oldJTI]
+	if !ok || old.IsRevoked() {
+		return ErrRefreshTokenRevoked
+	}
+	now := time.Now()
+	old.RevokedAt = &now
+	old.ReplacedBy = next.JTI
+	s.tokens[oldJTI] = old
+	s.tokens[next.JTI] = *next
+	return nil
+}
+
+func (s *InMemoryRefreshTokenStore) RevokeFamily(_ context.Context, familyID string) error {
+	s.revokeWhere(func(token entities.RefreshToken) bool { return token.FamilyID == familyID })
+	return nil
+}
+
+func (s *InMemoryRefreshTokenStore) RevokeAllForUser(_ context.Context, userID int) error {
+	s.revokeWhere(func(token entities.RefreshToken) bool { return token.UserID == userID })
+	return nil
+}
+
+func (s *InMemoryRefreshTokenStore) revokeWhere(match func(token entities.RefreshToken) bool) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	now := time.Now()
+	for jti, token := range s.tokens {
+		if match(token) && !token.IsRevoked() {
+			token.RevokedAt = &now
+			s.tokens[jti] = token
+		}
+	}
+}
+
+// NewInMemoryRefreshTokenStore constructor
+func NewInMemoryRefreshTokenStore() IRefreshTokenStore {
+	return &InMemoryRefreshTokenStore{tokens: make(map[string]entities.RefreshToken)}
+}
@@ -0,0 +1,86 @@
+package repositories
+
+import (
+	"Backend_golang_project/internal/domain/entities"
+	"context"
+	"errors"
+	"fmt"
+	"gorm.io/gorm"
+	"time"
+)
+
+// ErrRefreshTokenRevoked được trả về khi rotate một token đã bị thu hồi hoặc đã được dùng để đổi token mới
+var ErrRefreshTokenRevoked = errors.New("refresh token has already been used or revoked")
+
+type IRefreshTokenStore interface {
+	Save(ctx context.Context, token *entities.RefreshToken) error
+	GetByJTI(ctx context.Context, jti string) (*entities.RefreshToken, error)
+	Rotate(ctx context.Context, oldJTI string, next *entities.RefreshToken) error
+	RevokeFamily(ctx context.Context, familyID string) error
+	RevokeAllForUser(ctx context.Context, userID int) error
+}
+
+type RefreshTokenRepository struct {
+	base
+}
+
+func (r RefreshTokenRepository) Save(ctx context.Context, token *entities.RefreshToken) error {
+	if err := r.db.WithContext(ctx).Create(token).Error; err != nil {
+		return fmt.Errorf("error saving refresh token: %w", err)
+	}
+	return nil
+}
+
+func (r RefreshTokenRepository) GetByJTI(ctx context.Context, jti string) (*entities.RefreshToken, error) {
+	var token entities.RefreshToken
+	if err := r.db.WithContext(ctx).Where("jti = ?", jti).First(&token).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, fmt.Errorf("refresh token %s not found: %w", jti, err)
+		}
+		return nil, fmt.Errorf("error retrieving refresh token: %w", err)
+	}
+	return &token, nil
+}
+
+// Rotate marks the old token as replaced and stores its successor in one transaction.
+//
+// The old token is only updated while it is still active, so when two requests race
+// with the same refresh token exactly one of them wins and the other gets ErrRefreshTokenRevoked.
+func (r RefreshTokenRepository) Rotate(ctx context.Context, oldJTI string, next *entities.RefreshToken) error {
+	tx := r.StartTransaction().WithContext(ctx)
+
+	result := tx.Model(&entities.RefreshToken{}).
+		Where("jti = ? AND revoked_at IS NULL", oldJTI).
+		Updates(map[string]interface{}{"revoked_at": time.Now(), "replaced_by": next.JTI})
+	if result.Error != nil {
+		r.RollBackTransaction(tx)
+		return fmt.Errorf("error revoking refresh token: %w", result.Error)
+	}
+	if result.RowsAffected == 0 {
+		r.RollBackTransaction(tx)
+		return ErrRefreshTokenRevoked
+	}
+
+	if err := tx.Create(next).Error; err != nil {
+		r.RollBackTransaction(tx)
+		return fmt.Errorf("error saving refresh token: %w", err)
+	}
+	return r.CommitTransaction(tx)
+}
+
+func (r RefreshTokenRepository) RevokeFamily(ctx context.Context, familyID string) error {
+	return r.db.WithContext(ctx).Model(&entities.RefreshToken{}).
+		Where("family_id = ? AND revoked_at IS NULL", familyID).
+		Update("revoked_at", time.Now()).Error
+}
+
+func (r RefreshTokenRepository) RevokeAllForUser(ctx context.Context, userID int) error {
+	return r.db.WithContext(ctx).Model(&entities.RefreshToken{}).
+		Where("user_id = ? AND revoked_at IS NULL", userID).
+		Update("revoked_at", time.Now()).Error
+}
+
+// NewRefreshTokenRepository constructor
+func NewRefreshTokenRepository(db *gorm.DB) IRefreshTokenStore {
+	return &RefreshTokenRepository{base: base{db: db}}
+}
@@ -0,0 +1,176 @@
+package repositories
+
+import (
+	"Backend_golang_project/internal/domain/entities"
+	"Backend_golang_project/internal/domain/money"
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+var (
+	// ErrRecognitionBackdated lịch đã được ghi nhận tới một ngày sau ngày được yêu cầu
+	ErrRecognitionBackdated = errors.New("revenue already recognised through a later date")
+	// ErrProjectNotBillable category của project không billable (đã đổi category hoặc thuộc tính category sau khi tạo lịch)
+	ErrProjectNotBillable = errors.New("project is not billable")
+)
+
+type IRevenueScheduleRepository interface {
+	Save(ctx context.Context, schedule *entities.RevenueSchedule) (*entities.RevenueSchedule, error)
+	GetByProject(ctx context.Context, projectID int) (*entities.RevenueSchedule, error)
+	GetBatch(ctx context.Context, afterID int, limit int) ([]entities.RevenueSchedule, error)
+	Recognise(ctx context.Context, projectID int, asOf time.Time, createdBy *int) (*entities.ProjectTransaction, error)
+}
+
+type RevenueScheduleRepository struct {
+	base
+}
+
+// Save tạo mới hoặc thay thế lịch của project, các milestone cũ bị thay bằng milestone của schedule.
+// RecognisedAmount/RecognisedThrough được giữ nguyên, chúng chỉ thay đổi qua Recognise
+func (r RevenueScheduleRepository) Save(ctx context.Context, schedule *entities.RevenueSchedule) (*entities.RevenueSchedule, error) {
+	tx := r.StartTransaction().WithContext(ctx)
+
+	var existing entities.RevenueSchedule
+	err := tx.Clauses(clause.Locking{Strength: "UPDATE"}).Where("project_id = ?", schedule.ProjectID).First(&existing).Error
+	switch {
+	case err == nil:
+		schedule.ID = existing.ID
+		schedule.RecognisedAmount = existing.RecognisedAmount
+		schedule.RecognisedThrough = existing.RecognisedThrough
+		schedule.CreatedBy = existing.CreatedBy
+		schedule.CreatedAt = existing.CreatedAt
+		if err := tx.Where("schedule_id = ?", existing.ID).Delete(&entities.RevenueMilestone{}).Error; err != nil {
+			r.RollBackTransaction(tx)
+			return nil, fmt.Errorf("error replacing revenue milestones: %w", err)
+		}
+	case !errors.Is(err, gorm.ErrRecordNotFound):
+		r.RollBackTransaction(tx)
+		return nil, fmt.Errorf("error loading revenue schedule: %w", err)
+	}
+
+	for i := range schedule.Milestones {
+		schedule.Milestones[i].ID = 0
+	}
+	if err := tx.Save(schedule).Error; err != nil {
+		r.RollBackTransaction(tx)
+		return nil, fmt.Errorf("error saving revenue schedule: %w", err)
+	}
+	if err := r.CommitTransaction(tx); err != nil {
+		return nil, err
+	}
+	return r.GetByProject(ctx, schedule.ProjectID)
+}
+
+func (r RevenueScheduleRepository) GetByProject(ctx context.Context, projectID int) (*entities.RevenueSchedule, error) {
+	var schedule entities.RevenueSchedule
+	err := r.db.WithContext(ctx).Preload("Milestones", orderMilestones).Where("project_id = ?", projectID).First(&schedule).Error
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, fmt.Errorf("revenue schedule of project %d not found: %w", projectID, err)
+		}
+		return nil, fmt.Errorf("error retrieving revenue schedule: %w", err)
+	}
+	return &schedule, nil
+}
+
+// GetBatch lịch theo id tăng dần bắt đầu sau afterID, dùng cho job ghi nhận doanh thu;
+// bỏ qua lịch của project đang nằm trong thùng rác
+func (r RevenueScheduleRepository) GetBatch(ctx context.Context, afterID int, limit int) ([]entities.RevenueSchedule, error) {
+	var schedules []entities.RevenueSchedule
+	err := r.db.WithContext(ctx).
+		Joins("JOIN projects ON projects.id = project_revenue_schedules.project_id AND projects.deleted_at IS NULL").
+		Where("project_revenue_schedules.id > ?", afterID).
+		Order("project_revenue_schedules.id").
+		Limit(limit).
+		Find(&schedules).Error
+	if err != nil {
+		return nil, fmt.Errorf("error listing revenue schedules: %w", err)
+	}
+	return schedules, nil
+}
+
+// Recognise ghi phần chênh lệch giữa doanh thu phải ghi nhận tới asOf và RecognisedAmount vào sổ cái:
+// revenue nếu dương, adjustment (target revenue) nếu âm. Project và lịch cùng bị khóa trong một transaction
+// nên hai lần chạy song song không ghi trùng; trả về nil nếu không có gì để ghi
+func (r RevenueScheduleRepository) Recognise(ctx context.Context, projectID int, asOf time.Time, createdBy *int) (*entities.ProjectTransaction, error) {
+	tx := r.StartTransaction().WithContext(ctx)
+
+	project, err := lockProject(tx, projectID)
+	if err != nil {
+		r.RollBackTransaction(tx)
+		return nil, err
+	}
+	category, err := categoryByName(tx, project.Category)
+	if err != nil {
+		r.RollBackTransaction(tx)
+		return nil, err
+	}
+	if !category.Billable {
+		r.RollBackTransaction(tx)
+		return nil, fmt.Errorf("%w: category is %s", ErrProjectNotBillable, project.Category)
+	}
+	var schedule entities.RevenueSchedule
+	err = tx.Clauses(clause.Locking{Strength: "UPDATE"}).Preload("Milestones", orderMilestones).
+		Where("project_id = ?", projectID).First(&schedule).Error
+	if err != nil {
+		r.RollBackTransaction(tx)
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, fmt.Errorf("revenue schedule of project %d not found: %w", projectID, err)
+		}
+		return nil, fmt.Errorf("error locking revenue schedule: %w", err)
+	}
+
+	asOfDay := entities.Day(asOf)
+	if schedule.RecognisedThrough != nil && asOfDay.Before(entities.Day(*schedule.RecognisedThrough)) {
+		r.RollBackTransaction(tx)
+		return nil, fmt.Errorf("%w: %s", ErrRecognitionBackdated, schedule.RecognisedThrough.Format(time.DateOnly))
+	}
+
+	delta := schedule.RecognisedAsOf(project, asOfDay) - schedule.RecognisedAmount
+	var entry *entities.ProjectTransaction
+	if delta != 0 {
+		entry = &entities.ProjectTransaction{
+			ProjectID:     projectID,
+			Type:          entities.TransactionTypeRevenue,
+			Target:        entities.TransactionTypeRevenue,
+			Amount:        money.New(delta, project.Currency()),
+			EffectiveDate: asOfDay,
+			Description:   fmt.Sprintf("Revenue recognition (%s) through %s", schedule.Method, asOfDay.Format(time.DateOnly)),
+			CreatedBy:     createdBy,
+		}
+		if delta < 0 {
+			entry.Type = entities.TransactionTypeAdjustment
+		}
+		if err := postEntry(tx, project, entry); err != nil {
+			r.RollBackTransaction(tx)
+			return nil, err
+		}
+	}
+
+	err = tx.Model(&schedule).UpdateColumns(map[string]interface{}{
+		"recognised_amount":  schedule.RecognisedAmount + delta,
+		"recognised_through": asOfDay,
+	}).Error
+	if err != nil {
+		r.RollBackTransaction(tx)
+		return nil, fmt.Errorf("error updating revenue schedule: %w", err)
+	}
+	if err := r.CommitTransaction(tx); err != nil {
+		return nil, err
+	}
+	return entry, nil
+}
+
+func orderMilestones(db *gorm.DB) *gorm.DB {
+	return db.Order("due_date, id")
+}
+
+// NewRevenueScheduleRepository constructor
+func NewRevenueScheduleRepository(db *gorm.DB) IRevenueScheduleRepository {
+	return &RevenueScheduleRepository{base: base{db: db}}
+}
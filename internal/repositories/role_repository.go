@@ -0,0 +1,43 @@
+package repositories
+
+import (
+	"Backend_golang_project/internal/domain/entities"
+	"context"
+	"errors"
+	"fmt"
+	"gorm.io/gorm"
+)
+
+type IRoleRepository interface {
+	ListRoles(ctx context.Context) ([]entities.Role, error)
+	GetRole(ctx context.Context, name string) (*entities.Role, error)
+}
+
+type RoleRepository struct {
+	base
+}
+
+// ListRoles trả về tất cả role kèm permission của từng role
+func (r RoleRepository) ListRoles(ctx context.Context) ([]entities.Role, error) {
+	var roles []entities.Role
+	if err := r.db.WithContext(ctx).Preload("Permissions").Order("name").Find(&roles).Error; err != nil {
+		return nil, fmt.Errorf("error retrieving roles: %w", err)
+	}
+	return roles, nil
+}
+
+func (r RoleRepository) GetRole(ctx context.Context, name string) (*entities.Role, error) {
+	var role entities.Role
+	if err := r.db.WithContext(ctx).Preload("Permissions").First(&role, "name = ?", name).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, fmt.Errorf("role %s not found: %w", name, err)
+		}
+		return nil, fmt.Errorf("error retrieving role: %w", err)
+	}
+	return &role, nil
+}
+
+// NewRoleRepository constructor
+func NewRoleRepository(db *gorm.DB) IRoleRepository {
+	return &RoleRepository{base: base{db: db}}
+}
@@ -0,0 +1,64 @@
+package test_test
+
+import (
+	"Backend_golang_project/internal/domain/entities"
+	"Backend_golang_project/internal/repositories"
+	"context"
+	"github.com/glebarez/sqlite"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/suite"
+	"gorm.io/gorm"
+)
+
+type AuditRepositoryTestSuite struct {
+	suite.Suite
+	repo repositories.IAuditRepository
+	day  time.Time
+}
+
+func (suite *AuditRepositoryTestSuite) SetupTest() {
+	db, err := gorm.Open(sqlite.Open(":memory:"), &gorm.Config{})
+	suite.Require().NoError(err)
+	suite.Require().NoError(db.AutoMigrate(&entities.AuditEvent{}))
+
+	admin, auditor := 1, 2
+	suite.day = time.Date(2024, 9, 1, 0, 0, 0, 0, time.UTC)
+	suite.Require().NoError(db.Create([]entities.AuditEvent{
+		{ActorID: &admin, Action: entities.AuditActionCreate, EntityType: entities.AuditEntityProject, EntityID: 5, CreatedAt: suite.day},
+		{ActorID: &admin, Action: entities.AuditActionUpdate, EntityType: entities.AuditEntityProject, EntityID: 5, CreatedAt: suite.day.Add(time.Hour)},
+		{ActorID: &auditor, Action: entities.AuditActionUpdate, EntityType: entities.AuditEntityUser, EntityID: 5, CreatedAt: suite.day.Add(2 * time.Hour)},
+		{Action: entities.AuditActionDelete, EntityType: entities.AuditEntityProject, EntityID: 5, CreatedAt: suite.day.AddDate(0, 0, 1)},
+	}).Error)
+	suite.repo = repositories.NewAuditRepository(db)
+}
+
+func (suite *AuditRepositoryTestSuite) TestList_NewestFirstByEntity() {
+	events, err := suite.repo.List(context.Background(), repositories.AuditQuery{EntityType: entities.AuditEntityProject, EntityID: 5},
+		repositories.PageSpec{Page: 1, PageSize: 2})
+
+	suite.Require().NoError(err)
+	suite.Require().Len(events.Items, 2)
+	suite.Equal(entities.AuditActionDelete, events.Items[0].Action)
+	suite.Nil(events.Items[0].ActorID)
+	suite.Equal(entities.AuditActionUpdate, events.Items[1].Action)
+	suite.True(events.HasNext)
+}
+
+func (suite *AuditRepositoryTestSuite) TestList_ActorAndTimeRange() {
+	from, to := suite.day, suite.day.AddDate(0, 0, 1)
+
+	events, err := suite.repo.List(context.Background(), repositories.AuditQuery{ActorID: 1, From: &from, To: &to},
+		repositories.PageSpec{Page: 1, PageSize: 10})
+
+	suite.Require().NoError(err)
+	suite.Require().Len(events.Items, 2)
+	for _, event := range events.Items {
+		suite.Equal(1, *event.ActorID)
+	}
+}
+
+func TestAuditRepositoryTestSuite(t *testing.T) {
+	suite.Run(t, new(AuditRepositoryTestSuite))
+}
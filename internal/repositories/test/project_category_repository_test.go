@@ -0,0 +1,73 @@
+package test_test
+
+import (
+	"Backend_golang_project/internal/domain/entities"
+	"Backend_golang_project/internal/repositories"
+	"context"
+	"github.com/glebarez/sqlite"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/suite"
+	"gorm.io/gorm"
+)
+
+type ProjectCategoryRepositoryTestSuite struct {
+	suite.Suite
+	db   *gorm.DB
+	repo repositories.IProjectCategoryRepository
+}
+
+func (suite *ProjectCategoryRepositoryTestSuite) SetupTest() {
+	// TranslateError giống cấu hình trong infrastructure/database
+	db, err := gorm.Open(sqlite.Open(":memory:"), &gorm.Config{TranslateError: true})
+	suite.Require().NoError(err)
+	suite.Require().NoError(db.AutoMigrate(&entities.Project{}, &entities.ProjectVersion{}, &entities.ProjectCategory{}))
+	suite.db = db
+	suite.repo = repositories.NewProjectCategoryRepository(db)
+}
+
+func (suite *ProjectCategoryRepositoryTestSuite) TestCreate_DuplicateName() {
+	ctx := context.Background()
+	_, err := suite.repo.Create(ctx, &entities.ProjectCategory{Name: "research", Billable: true})
+	suite.Require().NoError(err)
+
+	_, err = suite.repo.Create(ctx, &entities.ProjectCategory{Name: "research"})
+
+	suite.ErrorIs(err, repositories.ErrCategoryAlreadyExists)
+}
+
+func (suite *ProjectCategoryRepositoryTestSuite) TestUpdate_KeepsName() {
+	ctx := context.Background()
+	category, err := suite.repo.Create(ctx, &entities.ProjectCategory{Name: "research"})
+	suite.Require().NoError(err)
+
+	category.Name = "renamed"
+	category.Billable = true
+	category.DefaultRevenueMethod = entities.RecognitionMilestone
+	_, err = suite.repo.Update(ctx, category)
+	suite.Require().NoError(err)
+
+	stored, err := suite.repo.GetByName(ctx, "research")
+	suite.Require().NoError(err)
+	suite.True(stored.Billable)
+	suite.Equal(entities.RecognitionMilestone, stored.DefaultRevenueMethod)
+}
+
+func (suite *ProjectCategoryRepositoryTestSuite) TestDelete_InUse() {
+	ctx := context.Background()
+	category, err := suite.repo.Create(ctx, &entities.ProjectCategory{Name: "research"})
+	suite.Require().NoError(err)
+	suite.Require().NoError(suite.db.Create(&entities.Project{Name: "Apollo", Category: "research", ProjectStartedAt: time.Now()}).Error)
+
+	suite.ErrorIs(suite.repo.Delete(ctx, category.ID), repositories.ErrCategoryInUse)
+
+	suite.Require().NoError(suite.db.Where("category = ?", "research").Delete(&entities.Project{}).Error)
+	suite.NoError(suite.repo.Delete(ctx, category.ID))
+	_, err = suite.repo.Get(ctx, category.ID)
+	suite.ErrorIs(err, gorm.ErrRecordNotFound)
+}
+
+func TestProjectCategoryRepositoryTestSuite(t *testing.T) {
+	suite.Run(t, new(ProjectCategoryRepositoryTestSuite))
+}
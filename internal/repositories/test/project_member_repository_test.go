@@ -0,0 +1,49 @@
+package test_test
+
+import (
+	"Backend_golang_project/internal/domain/entities"
+	"Backend_golang_project/internal/repositories"
+	"context"
+	"testing"
+
+	"github.com/glebarez/sqlite"
+	"github.com/stretchr/testify/suite"
+	"gorm.io/gorm"
+)
+
+type ProjectMemberRepositoryTestSuite struct {
+	suite.Suite
+	db   *gorm.DB
+	repo repositories.IProjectMemberRepository
+}
+
+func (suite *ProjectMemberRepositoryTestSuite) SetupTest() {
+	// TranslateError giống cấu hình trong infrastructure/database
+	db, err := gorm.Open(sqlite.Open(":memory:"), &gorm.Config{TranslateError: true})
+	suite.Require().NoError(err)
+	suite.Require().NoError(entities.SetupJoinTables(db))
+	suite.Require().NoError(db.AutoMigrate(&entities.User{}, &entities.Project{}, &entities.UserProject{}))
+	suite.Require().NoError(db.Create(&entities.User{ID: 1, Email: "member@example.com", Username: "member"}).Error)
+	suite.Require().NoError(db.Create(&entities.Project{ID: 1, Name: "Apollo"}).Error)
+
+	suite.db = db
+	suite.repo = repositories.NewProjectMemberRepository(db)
+}
+
+func (suite *ProjectMemberRepositoryTestSuite) TestAddMember_Duplicate() {
+	ctx := context.Background()
+	_, err := suite.repo.AddMember(ctx, &entities.UserProject{ProjectID: 1, UserID: 1, Role: entities.ProjectRoleViewer})
+	suite.Require().NoError(err)
+
+	_, err = suite.repo.AddMember(ctx, &entities.UserProject{ProjectID: 1, UserID: 1, Role: entities.ProjectRoleManager})
+	suite.ErrorIs(err, repositories.ErrMemberAlreadyExists)
+}
+
+func (suite *ProjectMemberRepositoryTestSuite) TestAddMember_UnknownUser() {
+	_, err := suite.repo.AddMember(context.Background(), &entities.UserProject{ProjectID: 1, UserID: 99, Role: entities.ProjectRoleViewer})
+	suite.ErrorIs(err, gorm.ErrRecordNotFound)
+}
+
+func TestProjectMemberRepositorySuite(t *testing.T) {
+	suite.Run(t, new(ProjectMemberRepositoryTestSuite))
+}
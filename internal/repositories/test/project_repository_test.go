@@ -2,6 +2,8 @@ package test_test
 
 import (
 	"Backend_golang_project/internal/domain/entities"
+	"Backend_golang_project/internal/domain/money"
+	"Backend_golang_project/internal/pkg"
 	"Backend_golang_project/internal/repositories"
 	"context"
 	"github.com/glebarez/sqlite"
@@ -12,6 +14,8 @@ import (
 	"gorm.io/gorm"
 )
 
+var firstPage = repositories.PageSpec{Page: 1, PageSize: 10, IncludeTotal: true}
+
 type ProjectRepositoryTestSuite struct {
 	suite.Suite
 	mockDB *gorm.DB
@@ -19,12 +23,14 @@ type ProjectRepositoryTestSuite struct {
 }
 
 func (suite *ProjectRepositoryTestSuite) SetupTest() {
-	// Tạo một mock DB sử dụng SQLite in-memory database
-	db, err := gorm.Open(sqlite.Open(":memory:"), &gorm.Config{})
+	// Tạo một mock DB sử dụng SQLite in-memory database, TranslateError giống cấu hình trong infrastructure/database
+	db, err := gorm.Open(sqlite.Open(":memory:"), &gorm.Config{TranslateError: true})
 	suite.Require().NoError(err)
 
-	// Migrate schema
-	err = db.AutoMigrate(&entities.Project{})
+	// Migrate schema, bảng nối user_projects dùng model UserProject
+	suite.Require().NoError(entities.SetupJoinTables(db))
+	err = db.AutoMigrate(&entities.User{}, &entities.Project{}, &entities.UserProject{}, &entities.ProjectStatusHistory{}, &entities.ProjectVersion{},
+		&entities.Timesheet{})
 	suite.Require().NoError(err)
 
 	suite.mockDB = db
@@ -63,6 +69,7 @@ func (suite *ProjectRepositoryTestSuite) TestUpdate() {
 		ID:       createdProject.ID,
 		Name:     "Updated Project",
 		Category: "Updated Category",
+		Version:  createdProject.Version,
 	}
 
 	// Gọi hàm Update
@@ -82,6 +89,56 @@ func (suite *ProjectRepositoryTestSuite) TestUpdate() {
 	suite.Equal(updatedInfo.Category, fetchedProject.Category)
 }
 
+func (suite *ProjectRepositoryTestSuite) TestUpdate_VersionConflict() {
+	ctx := context.Background()
+	project, err := suite.repo.Create(ctx, &entities.Project{Name: "Shared", Status: entities.ProjectStatusDraft})
+	suite.Require().NoError(err)
+	suite.Equal(1, project.Version)
+
+	// hai người cùng đọc version 1, người ghi sau bị từ chối
+	first := &entities.Project{ID: project.ID, Name: "First edit", Version: 1}
+	updated, err := suite.repo.Update(ctx, first)
+	suite.Require().NoError(err)
+	suite.Equal(2, updated.Version)
+
+	second := &entities.Project{ID: project.ID, Name: "Second edit", Version: 1}
+	_, err = suite.repo.Update(ctx, second)
+	suite.ErrorIs(err, repositories.ErrProjectVersionConflict)
+
+	stored, err := suite.repo.GetById(ctx, project.ID)
+	suite.Require().NoError(err)
+	suite.Equal("First edit", stored.Name)
+	suite.Equal(2, stored.Version)
+
+	// các thao tác ghi khác cũng tăng version, trùng với version mới nhất trong lịch sử
+	stored.Status = entities.ProjectStatusActive
+	err = suite.repo.ChangeStatus(ctx, stored, entities.ProjectStatusDraft, &entities.ProjectStatusHistory{
+		ProjectID: project.ID, FromStatus: entities.ProjectStatusDraft, ToStatus: entities.ProjectStatusActive,
+	})
+	suite.Require().NoError(err)
+	suite.Equal(3, stored.Version)
+	latest, err := suite.repo.GetVersion(ctx, project.ID, 3)
+	suite.Require().NoError(err)
+	suite.Equal(entities.ProjectChangeStatus, latest.ChangeType)
+}
+
+func (suite *ProjectRepositoryTestSuite) TestUpdate_BudgetAndVariance() {
+	ctx := context.Background()
+	project, err := suite.repo.Create(ctx, &entities.Project{Name: "Budget", Category: "client",
+		Budget: money.New(1000, "VND"), ProjectSpend: money.New(300, "VND")})
+	suite.Require().NoError(err)
+	// spend trong DB thay đổi sau khi project được đọc, variance phải theo spend mới
+	suite.Require().NoError(suite.mockDB.Model(project).UpdateColumn("project_spend_amount", 450).Error)
+
+	project.Budget = money.New(0, "VND")
+	project.ProjectVariance = money.New(-300, "VND")
+	updated, err := suite.repo.Update(ctx, project)
+
+	suite.Require().NoError(err)
+	suite.Equal(int64(0), updated.Budget.Amount)
+	suite.Equal(int64(-450), updated.ProjectVariance.Amount)
+}
+
 func (suite *ProjectRepositoryTestSuite) TestUpdateNonExistentProject() {
 	ctx := context.Background()
 
@@ -128,8 +185,320 @@ func (suite *ProjectRepositoryTestSuite) TestDelete() {
 	suite.Require().NoError(err)
 
 	// Xóa project
-	result := suite.repo.Delete(ctx, createdProject.Name)
-	suite.True(result)
+	suite.Require().NoError(suite.repo.Delete(ctx, createdProject.ID))
+
+	// project đã ở trong thùng rác hoặc không tồn tại thì không xóa được nữa
+	suite.ErrorIs(suite.repo.Delete(ctx, createdProject.ID), gorm.ErrRecordNotFound)
+	suite.ErrorIs(suite.repo.Delete(ctx, 999), gorm.ErrRecordNotFound)
+}
+
+func (suite *ProjectRepositoryTestSuite) TestDelete_ByIDWithDuplicateNames() {
+	ctx := context.Background()
+	first, err := suite.repo.Create(ctx, &entities.Project{Name: "Same Name"})
+	suite.Require().NoError(err)
+	second, err := suite.repo.Create(ctx, &entities.Project{Name: "Same Name"})
+	suite.Require().NoError(err)
+
+	suite.Require().NoError(suite.repo.Delete(ctx, second.ID))
+
+	_, err = suite.repo.GetById(ctx, first.ID)
+	suite.NoError(err)
+	_, err = suite.repo.GetById(ctx, second.ID)
+	suite.ErrorIs(err, gorm.ErrRecordNotFound)
+}
+
+func (suite *ProjectRepositoryTestSuite) TestDelete_PendingTimesheets() {
+	ctx := context.Background()
+	project, err := suite.repo.Create(ctx, &entities.Project{Name: "Billable"})
+	suite.Require().NoError(err)
+	timesheet := &entities.Timesheet{UserID: 1, ProjectID: project.ID, WeekStart: time.Date(2024, 6, 3, 0, 0, 0, 0, time.UTC),
+		Status: entities.TimesheetStatusSubmitted}
+	suite.Require().NoError(suite.mockDB.Create(timesheet).Error)
+
+	err = suite.repo.Delete(ctx, project.ID)
+	suite.ErrorIs(err, repositories.ErrProjectHasPendingTimesheets)
+	_, err = suite.repo.GetById(ctx, project.ID)
+	suite.NoError(err)
+
+	// timesheet đã được duyệt thì không còn chặn việc xóa
+	suite.Require().NoError(suite.mockDB.Model(timesheet).Update("status", entities.TimesheetStatusApproved).Error)
+	suite.NoError(suite.repo.Delete(ctx, project.ID))
+}
+
+func (suite *ProjectRepositoryTestSuite) TestSlug_UniquePerOwner() {
+	ctx := context.Background()
+	owner, other := 1, 2
+	slug := func(s string) *string { return &s }
+
+	project, err := suite.repo.Create(ctx, &entities.Project{Name: "Website", OwnerID: &owner, Slug: slug("website")})
+	suite.Require().NoError(err)
+	_, err = suite.repo.Create(ctx, &entities.Project{Name: "Website v2", OwnerID: &owner, Slug: slug("website")})
+	suite.ErrorIs(err, repositories.ErrSlugAlreadyExists)
+	_, err = suite.repo.Create(ctx, &entities.Project{Name: "Website", OwnerID: &other, Slug: slug("website")})
+	suite.NoError(err)
+	// project không có slug không va chạm nhau
+	_, err = suite.repo.Create(ctx, &entities.Project{Name: "No slug", OwnerID: &owner})
+	suite.NoError(err)
+	_, err = suite.repo.Create(ctx, &entities.Project{Name: "No slug", OwnerID: &owner})
+	suite.NoError(err)
+
+	found, err := suite.repo.GetBySlug(ctx, owner, "website")
+	suite.Require().NoError(err)
+	suite.Equal(project.ID, found.ID)
+	_, err = suite.repo.GetBySlug(ctx, owner, "missing")
+	suite.ErrorIs(err, gorm.ErrRecordNotFound)
+
+	renamed, err := suite.repo.Create(ctx, &entities.Project{Name: "Blog", OwnerID: &owner, Slug: slug("blog")})
+	suite.Require().NoError(err)
+	renamed.Slug = slug("website")
+	_, err = suite.repo.Update(ctx, renamed)
+	suite.ErrorIs(err, repositories.ErrSlugAlreadyExists)
+
+	// slug nil khi cập nhật thì project không còn slug
+	renamed.Slug = nil
+	updated, err := suite.repo.Update(ctx, renamed)
+	suite.Require().NoError(err)
+	suite.Nil(updated.Slug)
+}
+
+func (suite *ProjectRepositoryTestSuite) TestGetListByMember() {
+	ctx := context.Background()
+
+	user := &entities.User{Email: "member@example.com", Password: "x", Username: "member"}
+	suite.Require().NoError(suite.mockDB.Create(user).Error)
+
+	owned, err := suite.repo.Create(ctx, &entities.Project{
+		Name:    "Owned Project",
+		Members: []entities.UserProject{{UserID: user.ID, Role: entities.ProjectRoleOwner}},
+	})
+	suite.Require().NoError(err)
+	_, err = suite.repo.Create(ctx, &entities.Project{Name: "Other Project"})
+	suite.Require().NoError(err)
+
+	pagination, err := suite.repo.GetListByMember(ctx, user.ID, repositories.ProjectQuery{}, firstPage)
+
+	suite.Require().NoError(err)
+	suite.Equal(1, *pagination.TotalRecords)
+	suite.Require().Len(pagination.Items, 1)
+	suite.Equal(owned.ID, pagination.Items[0].ID)
+}
+
+func (suite *ProjectRepositoryTestSuite) TestGetList_FilterAndSort() {
+	ctx := context.Background()
+	start := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+	projects := []*entities.Project{
+		{Name: "Apollo", Category: "client", ProjectSpend: money.New(300, "VND"), ProjectStartedAt: start},
+		{Name: "Apollo 100%", Category: "client", ProjectSpend: money.New(900, "VND"), ProjectStartedAt: start.AddDate(0, 1, 0)},
+		{Name: "Artemis", Category: "client", ProjectSpend: money.New(900, "VND"), ProjectStartedAt: start.AddDate(0, 2, 0)},
+		{Name: "Internal tools", Category: "system", ProjectSpend: money.New(5000, "VND"), ProjectStartedAt: start},
+	}
+	for _, project := range projects {
+		_, err := suite.repo.Create(ctx, project)
+		suite.Require().NoError(err)
+	}
+
+	spendMin := 500
+	pagination, err := suite.repo.GetList(ctx, repositories.ProjectQuery{
+		Category: "client",
+		SpendMin: &spendMin,
+		Sort:     []repositories.SortColumn{{Column: "project_spend_amount", Desc: true}, {Column: "name", Desc: true}},
+	}, firstPage)
+	suite.Require().NoError(err)
+	suite.Equal(2, *pagination.TotalRecords)
+	suite.Require().Len(pagination.Items, 2)
+	suite.Equal("Artemis", pagination.Items[0].Name)
+	suite.Equal("Apollo 100%", pagination.Items[1].Name)
+
+	// % trong tên được escape, không match mọi project
+	pagination, err = suite.repo.GetList(ctx, repositories.ProjectQuery{NameContains: "100%"}, firstPage)
+	suite.Require().NoError(err)
+	suite.Require().Len(pagination.Items, 1)
+	suite.Equal("Apollo 100%", pagination.Items[0].Name)
+
+	startedTo := start.AddDate(0, 1, 0)
+	pagination, err = suite.repo.GetList(ctx, repositories.ProjectQuery{StartedTo: &startedTo}, firstPage)
+	suite.Require().NoError(err)
+	suite.Equal(3, *pagination.TotalRecords)
+}
+
+func (suite *ProjectRepositoryTestSuite) TestGetList_Keyset() {
+	ctx := context.Background()
+	for _, project := range []*entities.Project{
+		{Name: "A", ProjectSpend: money.New(100, "VND")},
+		{Name: "B", ProjectSpend: money.New(300, "VND")},
+		{Name: "C", ProjectSpend: money.New(300, "VND")},
+		{Name: "D", ProjectSpend: money.New(200, "VND")},
+		{Name: "E", ProjectSpend: money.New(500, "VND")},
+	} {
+		_, err := suite.repo.Create(ctx, project)
+		suite.Require().NoError(err)
+	}
+	// thứ tự mong đợi theo -project_spend, id: E, B, C, D, A
+	query := repositories.ProjectQuery{Sort: []repositories.SortColumn{{Column: "project_spend_amount", Desc: true}}}
+
+	page, err := suite.repo.GetList(ctx, query, repositories.PageSpec{Page: 1, PageSize: 2})
+	suite.Require().NoError(err)
+	suite.Nil(page.TotalRecords)
+	suite.True(page.HasNext)
+	suite.False(page.HasPrev)
+	suite.Equal([]string{"E", "B"}, projectNames(page.Items))
+
+	last := page.Items[1]
+	page, err = suite.repo.GetList(ctx, query, repositories.PageSpec{PageSize: 2,
+		Keyset: &repositories.Keyset{Values: []interface{}{last.ProjectSpend.Amount, last.ID}}})
+	suite.Require().NoError(err)
+	suite.True(page.HasNext)
+	suite.True(page.HasPrev)
+	suite.Equal([]string{"C", "D"}, projectNames(page.Items))
+
+	first := page.Items[0]
+	page, err = suite.repo.GetList(ctx, query, repositories.PageSpec{PageSize: 2,
+		Keyset: &repositories.Keyset{Values: []interface{}{first.ProjectSpend.Amount, first.ID}, Backward: true}})
+	suite.Require().NoError(err)
+	suite.False(page.HasPrev)
+	suite.True(page.HasNext)
+	suite.Equal([]string{"E", "B"}, projectNames(page.Items))
+
+	// bản ghi biên (spend 50) đã bị xóa sau khi cursor được phát hành và đứng sau A nên không còn trang sau
+	page, err = suite.repo.GetList(ctx, query, repositories.PageSpec{PageSize: 2,
+		Keyset: &repositories.Keyset{Values: []interface{}{int64(50), 1000}, Backward: true}})
+	suite.Require().NoError(err)
+	suite.False(page.HasNext)
+	suite.True(page.HasPrev)
+	suite.Equal([]string{"D", "A"}, projectNames(page.Items))
+
+	_, err = suite.repo.GetList(ctx, query, repositories.PageSpec{PageSize: 2,
+		Keyset: &repositories.Keyset{Values: []interface{}{first.ID}}})
+	suite.ErrorIs(err, repositories.ErrKeysetMismatch)
+}
+
+func (suite *ProjectRepositoryTestSuite) TestChangeStatus() {
+	ctx := context.Background()
+	project, err := suite.repo.Create(ctx, &entities.Project{Name: "Lifecycle", Status: entities.ProjectStatusDraft})
+	suite.Require().NoError(err)
+
+	project.Status = entities.ProjectStatusActive
+	err = suite.repo.ChangeStatus(ctx, project, entities.ProjectStatusDraft, &entities.ProjectStatusHistory{
+		ProjectID: project.ID, FromStatus: entities.ProjectStatusDraft, ToStatus: entities.ProjectStatusActive, Reason: "Kick-off",
+	})
+	suite.Require().NoError(err)
+
+	// request thứ hai vẫn nghĩ project đang là draft thì không ghi gì cả
+	err = suite.repo.ChangeStatus(ctx, project, entities.ProjectStatusDraft, &entities.ProjectStatusHistory{
+		ProjectID: project.ID, FromStatus: entities.ProjectStatusDraft, ToStatus: entities.ProjectStatusActive, Reason: "Duplicate",
+	})
+	suite.ErrorIs(err, repositories.ErrProjectStatusChanged)
+
+	stored, err := suite.repo.GetById(ctx, project.ID)
+	suite.Require().NoError(err)
+	suite.Equal(entities.ProjectStatusActive, stored.Status)
+
+	history, err := suite.repo.GetStatusHistory(ctx, project.ID)
+	suite.Require().NoError(err)
+	suite.Require().Len(history, 1)
+	suite.Equal("Kick-off", history[0].Reason)
+}
+
+func (suite *ProjectRepositoryTestSuite) TestVersions_RecordEveryWrite() {
+	ctx := pkg.WithUserID(context.Background(), 7)
+	project, err := suite.repo.Create(ctx, &entities.Project{Name: "Versioned", Status: entities.ProjectStatusDraft,
+		Budget: money.New(1000, "VND")})
+	suite.Require().NoError(err)
+
+	_, err = suite.repo.Update(ctx, &entities.Project{ID: project.ID, Name: "Versioned", Budget: money.New(2000, "VND"),
+		Version: project.Version})
+	suite.Require().NoError(err)
+	project.Status = entities.ProjectStatusActive
+	err = suite.repo.ChangeStatus(ctx, project, entities.ProjectStatusDraft, &entities.ProjectStatusHistory{
+		ProjectID: project.ID, FromStatus: entities.ProjectStatusDraft, ToStatus: entities.ProjectStatusActive,
+	})
+	suite.Require().NoError(err)
+
+	page, err := suite.repo.ListVersions(ctx, project.ID, firstPage)
+	suite.Require().NoError(err)
+	suite.Require().Len(page.Items, 3)
+	// mới nhất trước
+	suite.Equal(3, page.Items[0].Version)
+	suite.Equal(entities.ProjectChangeStatus, page.Items[0].ChangeType)
+	suite.Equal(entities.ProjectStatusActive, page.Items[0].Status)
+	suite.Equal(entities.ProjectChangeUpdate, page.Items[1].ChangeType)
+	suite.Equal(int64(2000), page.Items[1].Budget.Amount)
+	suite.Equal(int64(2000), page.Items[1].ProjectVariance.Amount)
+	suite.Equal(entities.ProjectChangeCreate, page.Items[2].ChangeType)
+	suite.Equal(int64(1000), page.Items[2].Budget.Amount)
+	suite.Require().NotNil(page.Items[2].ChangedBy)
+	suite.Equal(7, *page.Items[2].ChangedBy)
+}
+
+func (suite *ProjectRepositoryTestSuite) TestGetVersionAt() {
+	ctx := context.Background()
+	project, err := suite.repo.Create(ctx, &entities.Project{Name: "Q1 name"})
+	suite.Require().NoError(err)
+	_, err = suite.repo.Update(ctx, &entities.Project{ID: project.ID, Name: "Q2 name", Version: project.Version})
+	suite.Require().NoError(err)
+	suite.setVersionTime(project.ID, 1, time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC))
+	suite.setVersionTime(project.ID, 2, time.Date(2024, 4, 1, 0, 0, 0, 0, time.UTC))
+
+	version, err := suite.repo.GetVersionAt(ctx, project.ID, time.Date(2024, 3, 31, 23, 59, 59, 0, time.UTC))
+	suite.Require().NoError(err)
+	suite.Equal("Q1 name", version.Name)
+
+	version, err = suite.repo.GetVersionAt(ctx, project.ID, time.Date(2024, 4, 1, 0, 0, 0, 0, time.UTC))
+	suite.Require().NoError(err)
+	suite.Equal("Q2 name", version.Name)
+
+	_, err = suite.repo.GetVersionAt(ctx, project.ID, time.Date(2023, 12, 31, 0, 0, 0, 0, time.UTC))
+	suite.ErrorIs(err, gorm.ErrRecordNotFound)
+}
+
+func (suite *ProjectRepositoryTestSuite) TestRestoreVersion() {
+	ctx := context.Background()
+	started := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+	project, err := suite.repo.Create(ctx, &entities.Project{Name: "Original", Category: "internal", ProjectStartedAt: started,
+		Budget: money.New(1000, "VND"), ProjectSpend: money.New(300, "VND"), ProjectVariance: money.New(700, "VND")})
+	suite.Require().NoError(err)
+	_, err = suite.repo.Update(ctx, &entities.Project{ID: project.ID, Name: "Renamed", Category: "client",
+		ProjectStartedAt: started.AddDate(0, 1, 0), Budget: money.New(5000, "VND"), Version: project.Version})
+	suite.Require().NoError(err)
+	// spend thay đổi sau version 1 không bị khôi phục
+	suite.Require().NoError(suite.mockDB.Model(&entities.Project{}).Where("id = ?", project.ID).
+		UpdateColumn("project_spend_amount", 400).Error)
+
+	restored, err := suite.repo.RestoreVersion(ctx, project.ID, 1)
+	suite.Require().NoError(err)
+	suite.Equal("Original", restored.Name)
+
+	stored, err := suite.repo.GetById(ctx, project.ID)
+	suite.Require().NoError(err)
+	suite.Equal("Original", stored.Name)
+	suite.Equal("internal", stored.Category)
+	suite.True(stored.ProjectStartedAt.Equal(started))
+	suite.Equal(int64(1000), stored.Budget.Amount)
+	suite.Equal(int64(400), stored.ProjectSpend.Amount)
+	suite.Equal(int64(600), stored.ProjectVariance.Amount)
+
+	latest, err := suite.repo.GetVersion(ctx, project.ID, 3)
+	suite.Require().NoError(err)
+	suite.Equal(entities.ProjectChangeRestore, latest.ChangeType)
+	suite.Require().NotNil(latest.RestoredFrom)
+	suite.Equal(1, *latest.RestoredFrom)
+
+	_, err = suite.repo.RestoreVersion(ctx, project.ID, 9)
+	suite.ErrorIs(err, gorm.ErrRecordNotFound)
+}
+
+func (suite *ProjectRepositoryTestSuite) setVersionTime(projectID int, version int, at time.Time) {
+	suite.Require().NoError(suite.mockDB.Model(&entities.ProjectVersion{}).
+		Where("project_id = ? AND version = ?", projectID, version).
+		UpdateColumn("created_at", at).Error)
+}
+
+func projectNames(projects []entities.Project) []string {
+	names := make([]string, 0, len(projects))
+	for _, project := range projects {
+		names = append(names, project.Name)
+	}
+	return names
 }
 
 func TestProjectRepositorySuite(t *testing.T) {
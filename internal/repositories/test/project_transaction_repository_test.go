@@ -0,0 +1,103 @@
+package test_test
+
+import (
+	"Backend_golang_project/internal/domain/entities"
+	"Backend_golang_project/internal/domain/money"
+	"Backend_golang_project/internal/repositories"
+	"context"
+	"github.com/glebarez/sqlite"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/suite"
+	"gorm.io/gorm"
+)
+
+type ProjectTransactionRepositoryTestSuite struct {
+	suite.Suite
+	db      *gorm.DB
+	repo    repositories.IProjectTransactionRepository
+	project *entities.Project
+}
+
+func (suite *ProjectTransactionRepositoryTestSuite) SetupTest() {
+	db, err := gorm.Open(sqlite.Open(":memory:"), &gorm.Config{})
+	suite.Require().NoError(err)
+	suite.Require().NoError(entities.SetupJoinTables(db))
+	suite.Require().NoError(db.AutoMigrate(&entities.User{}, &entities.Project{}, &entities.UserProject{}, &entities.ProjectTransaction{}, &entities.ProjectVersion{}))
+
+	suite.project = &entities.Project{Name: "Ledger", Status: entities.ProjectStatusActive, Budget: money.New(2000, "VND")}
+	suite.Require().NoError(db.Create(suite.project).Error)
+	suite.db = db
+	suite.repo = repositories.NewProjectTransactionRepository(db)
+}
+
+func (suite *ProjectTransactionRepositoryTestSuite) entry(target string, amount int64, effective time.Time) *entities.ProjectTransaction {
+	transactionType := target
+	if amount < 0 {
+		transactionType = entities.TransactionTypeAdjustment
+	}
+	return &entities.ProjectTransaction{
+		ProjectID:     suite.project.ID,
+		Type:          transactionType,
+		Target:        target,
+		Amount:        money.New(amount, "VND"),
+		EffectiveDate: effective,
+	}
+}
+
+func (suite *ProjectTransactionRepositoryTestSuite) TestPost_UpdatesTotals() {
+	ctx := context.Background()
+	day := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+
+	_, err := suite.repo.Post(ctx, suite.entry(entities.TransactionTypeSpend, 1000, day))
+	suite.Require().NoError(err)
+	_, err = suite.repo.Post(ctx, suite.entry(entities.TransactionTypeRevenue, 700, day))
+	suite.Require().NoError(err)
+	project, err := suite.repo.Post(ctx, suite.entry(entities.TransactionTypeSpend, -250, day.AddDate(0, 0, 1)))
+	suite.Require().NoError(err)
+
+	suite.Equal(money.New(750, "VND"), project.ProjectSpend)
+	suite.Equal(money.New(700, "VND"), project.RevenueRecognised)
+	suite.Equal(money.New(1250, "VND"), project.ProjectVariance)
+
+	page, err := suite.repo.List(ctx, suite.project.ID, repositories.PageSpec{Page: 1, PageSize: 10})
+	suite.Require().NoError(err)
+	suite.Require().Len(page.Items, 3)
+	// mới nhất trước
+	suite.Equal(int64(-250), page.Items[0].Amount.Amount)
+
+	// mỗi bút toán ghi một version với tổng tiền sau bút toán đó
+	var versions []entities.ProjectVersion
+	suite.Require().NoError(suite.db.Where("project_id = ?", suite.project.ID).Order("version").Find(&versions).Error)
+	suite.Require().Len(versions, 3)
+	suite.Equal(entities.ProjectChangeLedger, versions[0].ChangeType)
+	suite.Equal(int64(1000), versions[0].ProjectSpend.Amount)
+	suite.Equal(int64(700), versions[1].RevenueRecognised.Amount)
+	suite.Equal(int64(1250), versions[2].ProjectVariance.Amount)
+}
+
+func (suite *ProjectTransactionRepositoryTestSuite) TestPost_ClosedProject() {
+	ctx := context.Background()
+	suite.Require().NoError(suite.db.Model(suite.project).Update("status", entities.ProjectStatusCompleted).Error)
+
+	_, err := suite.repo.Post(ctx, suite.entry(entities.TransactionTypeSpend, 100, time.Now()))
+
+	suite.ErrorIs(err, repositories.ErrProjectClosed)
+	var count int64
+	suite.Require().NoError(suite.db.Model(&entities.ProjectTransaction{}).Count(&count).Error)
+	suite.Zero(count)
+}
+
+func (suite *ProjectTransactionRepositoryTestSuite) TestPost_CurrencyMismatch() {
+	entry := suite.entry(entities.TransactionTypeSpend, 100, time.Now())
+	entry.Amount = money.New(100, "USD")
+
+	_, err := suite.repo.Post(context.Background(), entry)
+
+	suite.ErrorIs(err, money.ErrCurrencyMismatch)
+}
+
+func TestProjectTransactionRepositorySuite(t *testing.T) {
+	suite.Run(t, new(ProjectTransactionRepositoryTestSuite))
+}
@@ -0,0 +1,146 @@
+package test_test
+
+import (
+	"Backend_golang_project/internal/domain/entities"
+	"Backend_golang_project/internal/domain/money"
+	"Backend_golang_project/internal/repositories"
+	"context"
+	"testing"
+	"time"
+
+	"github.com/glebarez/sqlite"
+	"github.com/stretchr/testify/suite"
+	"gorm.io/gorm"
+)
+
+type ProjectTrashRepositoryTestSuite struct {
+	suite.Suite
+	db   *gorm.DB
+	repo repositories.IProjectRepository
+}
+
+func (suite *ProjectTrashRepositoryTestSuite) SetupTest() {
+	db, err := gorm.Open(sqlite.Open(":memory:"), &gorm.Config{})
+	suite.Require().NoError(err)
+	suite.Require().NoError(entities.SetupJoinTables(db))
+	suite.Require().NoError(db.AutoMigrate(&entities.User{}, &entities.Project{}, &entities.UserProject{}, &entities.ProjectVersion{},
+		&entities.ProjectTransaction{}, &entities.Milestone{}, &entities.Task{}, &entities.RevenueSchedule{}, &entities.RevenueMilestone{}, &entities.Timesheet{}))
+	suite.Require().NoError(db.Create(&entities.User{ID: 1, Email: "owner@example.com", Username: "owner"}).Error)
+
+	suite.db = db
+	suite.repo = repositories.NewProjectRepository(db)
+}
+
+// deletedProject tạo project có owner là user 1 rồi xóa mềm với deleted_at = deletedAt
+func (suite *ProjectTrashRepositoryTestSuite) deletedProject(name string, deletedAt time.Time) *entities.Project {
+	ctx := context.Background()
+	project, err := suite.repo.Create(ctx, &entities.Project{Name: name})
+	suite.Require().NoError(err)
+	suite.Require().NoError(suite.db.Create(&entities.UserProject{ProjectID: project.ID, UserID: 1, Role: entities.ProjectRoleOwner}).Error)
+	suite.Require().NoError(suite.repo.Delete(ctx, project.ID))
+	suite.Require().NoError(suite.db.Unscoped().Model(&entities.Project{}).Where("id = ?", project.ID).
+		UpdateColumn("deleted_at", deletedAt).Error)
+	return project
+}
+
+func (suite *ProjectTrashRepositoryTestSuite) TestDelete_IsSoft() {
+	ctx := context.Background()
+	project := suite.deletedProject("Gone", time.Now())
+
+	_, err := suite.repo.GetById(ctx, project.ID)
+	suite.ErrorIs(err, gorm.ErrRecordNotFound)
+	page, err := suite.repo.GetList(ctx, repositories.ProjectQuery{}, firstPage)
+	suite.Require().NoError(err)
+	suite.Empty(page.Items)
+
+	// dòng project và membership vẫn còn để khôi phục
+	var stored entities.Project
+	suite.Require().NoError(suite.db.Unscoped().First(&stored, project.ID).Error)
+	suite.True(stored.DeletedAt.Valid)
+	var members int64
+	suite.Require().NoError(suite.db.Model(&entities.UserProject{}).Where("project_id = ?", project.ID).Count(&members).Error)
+	suite.Equal(int64(1), members)
+}
+
+func (suite *ProjectTrashRepositoryTestSuite) TestGetTrash() {
+	ctx := context.Background()
+	day := time.Date(2024, 5, 1, 0, 0, 0, 0, time.UTC)
+	suite.deletedProject("Older", day)
+	suite.deletedProject("Newer", day.AddDate(0, 0, 1))
+	_, err := suite.repo.Create(ctx, &entities.Project{Name: "Alive"})
+	suite.Require().NoError(err)
+
+	page, err := suite.repo.GetTrash(ctx, firstPage)
+	suite.Require().NoError(err)
+	suite.Equal([]string{"Newer", "Older"}, projectNames(page.Items))
+
+	page, err = suite.repo.GetTrashByOwner(ctx, 1, firstPage)
+	suite.Require().NoError(err)
+	suite.Len(page.Items, 2)
+	page, err = suite.repo.GetTrashByOwner(ctx, 2, firstPage)
+	suite.Require().NoError(err)
+	suite.Empty(page.Items)
+}
+
+func (suite *ProjectTrashRepositoryTestSuite) TestRestore() {
+	ctx := context.Background()
+	project := suite.deletedProject("Back", time.Now())
+
+	restored, err := suite.repo.Restore(ctx, project.ID)
+	suite.Require().NoError(err)
+	suite.False(restored.DeletedAt.Valid)
+
+	stored, err := suite.repo.GetById(ctx, project.ID)
+	suite.Require().NoError(err)
+	suite.Equal("Back", stored.Name)
+
+	_, err = suite.repo.Restore(ctx, project.ID)
+	suite.ErrorIs(err, repositories.ErrProjectNotDeleted)
+	_, err = suite.repo.Restore(ctx, 999)
+	suite.ErrorIs(err, gorm.ErrRecordNotFound)
+}
+
+func (suite *ProjectTrashRepositoryTestSuite) TestPurgeDeleted() {
+	ctx := context.Background()
+	now := time.Date(2024, 6, 1, 0, 0, 0, 0, time.UTC)
+	expired := suite.deletedProject("Expired", now.AddDate(0, 0, -31))
+	recent := suite.deletedProject("Recent", now.AddDate(0, 0, -1))
+
+	milestone := &entities.Milestone{ProjectID: expired.ID, Name: "Design"}
+	suite.Require().NoError(suite.db.Create(milestone).Error)
+	suite.Require().NoError(suite.db.Create(&entities.Task{ProjectID: expired.ID, MilestoneID: &milestone.ID, Title: "Wireframes"}).Error)
+	schedule := &entities.RevenueSchedule{ProjectID: expired.ID, Method: entities.RecognitionMilestone,
+		Milestones: []entities.RevenueMilestone{{Name: "Kick-off", Amount: 100}}}
+	suite.Require().NoError(suite.db.Create(schedule).Error)
+	suite.Require().NoError(suite.db.Create(&entities.ProjectTransaction{ProjectID: expired.ID, Type: entities.TransactionTypeSpend,
+		Target: entities.TransactionTypeSpend, Amount: money.New(100, "VND"), EffectiveDate: now}).Error)
+
+	purged, err := suite.repo.PurgeDeleted(ctx, now.AddDate(0, 0, -30), 10)
+	suite.Require().NoError(err)
+	suite.Equal(1, purged)
+
+	count := func(model interface{}, projectID int) int64 {
+		var n int64
+		suite.Require().NoError(suite.db.Unscoped().Model(model).Where("project_id = ?", projectID).Count(&n).Error)
+		return n
+	}
+	var projects int64
+	suite.Require().NoError(suite.db.Unscoped().Model(&entities.Project{}).Where("id = ?", expired.ID).Count(&projects).Error)
+	suite.Zero(projects)
+	suite.Zero(count(&entities.UserProject{}, expired.ID))
+	suite.Zero(count(&entities.Milestone{}, expired.ID))
+	suite.Zero(count(&entities.Task{}, expired.ID))
+	suite.Zero(count(&entities.RevenueSchedule{}, expired.ID))
+	var revenueMilestones int64
+	suite.Require().NoError(suite.db.Model(&entities.RevenueMilestone{}).Count(&revenueMilestones).Error)
+	suite.Zero(revenueMilestones)
+	// lịch sử tài chính được giữ lại
+	suite.Equal(int64(1), count(&entities.ProjectTransaction{}, expired.ID))
+	suite.Equal(int64(1), count(&entities.ProjectVersion{}, expired.ID))
+	// project chưa quá hạn vẫn nằm trong thùng rác
+	suite.Equal(int64(1), count(&entities.UserProject{}, recent.ID))
+}
+
+func TestProjectTrashRepositorySuite(t *testing.T) {
+	suite.Run(t, new(ProjectTrashRepositoryTestSuite))
+}
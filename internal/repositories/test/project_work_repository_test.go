@@ -0,0 +1,98 @@
+package test_test
+
+import (
+	"Backend_golang_project/internal/domain/entities"
+	"Backend_golang_project/internal/repositories"
+	"context"
+	"errors"
+	"github.com/glebarez/sqlite"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/suite"
+	"gorm.io/gorm"
+)
+
+type ProjectWorkRepositoryTestSuite struct {
+	suite.Suite
+	db   *gorm.DB
+	repo repositories.IProjectWorkRepository
+}
+
+func (suite *ProjectWorkRepositoryTestSuite) SetupTest() {
+	db, err := gorm.Open(sqlite.Open(":memory:"), &gorm.Config{})
+	suite.Require().NoError(err)
+	suite.Require().NoError(db.AutoMigrate(&entities.Project{}, &entities.ProjectVersion{}, &entities.Milestone{}, &entities.Task{}))
+	suite.db = db
+	suite.repo = repositories.NewProjectWorkRepository(db)
+}
+
+func (suite *ProjectWorkRepositoryTestSuite) task(projectID int, milestoneID *int, status string, hours float64) *entities.Task {
+	task, err := suite.repo.SaveTask(context.Background(), &entities.Task{ProjectID: projectID, MilestoneID: milestoneID, Title: "Task",
+		WorkItem: entities.WorkItem{Status: status, EstimateHours: hours}})
+	suite.Require().NoError(err)
+	return task
+}
+
+func (suite *ProjectWorkRepositoryTestSuite) TestDeleteMilestone_DetachesTasks() {
+	ctx := context.Background()
+	milestone, err := suite.repo.SaveMilestone(ctx, &entities.Milestone{ProjectID: 1, Name: "Beta", WorkItem: entities.WorkItem{Status: entities.WorkStatusTodo}})
+	suite.Require().NoError(err)
+	task := suite.task(1, &milestone.ID, entities.WorkStatusTodo, 2)
+
+	// milestone của project khác không xóa được qua project này
+	err = suite.repo.DeleteMilestone(ctx, 2, milestone.ID)
+	suite.True(errors.Is(err, gorm.ErrRecordNotFound))
+
+	suite.Require().NoError(suite.repo.DeleteMilestone(ctx, 1, milestone.ID))
+	reloaded, err := suite.repo.GetTask(ctx, 1, task.ID)
+	suite.Require().NoError(err)
+	suite.Nil(reloaded.MilestoneID)
+	_, err = suite.repo.GetMilestone(ctx, 1, milestone.ID)
+	suite.True(errors.Is(err, gorm.ErrRecordNotFound))
+}
+
+func (suite *ProjectWorkRepositoryTestSuite) TestListTasks_OrderedByDueDate() {
+	ctx := context.Background()
+	later, sooner := time.Date(2024, 5, 1, 0, 0, 0, 0, time.UTC), time.Date(2024, 4, 1, 0, 0, 0, 0, time.UTC)
+	undated := suite.task(1, nil, entities.WorkStatusTodo, 0)
+	for _, due := range []time.Time{later, sooner} {
+		due := due
+		_, err := suite.repo.SaveTask(ctx, &entities.Task{ProjectID: 1, Title: "Dated", WorkItem: entities.WorkItem{Status: entities.WorkStatusTodo, DueDate: &due}})
+		suite.Require().NoError(err)
+	}
+	suite.task(2, nil, entities.WorkStatusTodo, 0)
+
+	tasks, err := suite.repo.ListTasks(ctx, 1)
+
+	suite.Require().NoError(err)
+	suite.Require().Len(tasks, 3)
+	suite.True(tasks[0].DueDate.Equal(sooner))
+	suite.True(tasks[1].DueDate.Equal(later))
+	suite.Equal(undated.ID, tasks[2].ID)
+}
+
+func (suite *ProjectWorkRepositoryTestSuite) TestProgressByProjects() {
+	ctx := context.Background()
+	suite.task(1, nil, entities.WorkStatusDone, 3)
+	suite.task(1, nil, entities.WorkStatusDone, 1)
+	suite.task(1, nil, entities.WorkStatusInProgress, 4)
+	suite.task(1, nil, entities.WorkStatusCancelled, 10)
+	_, err := suite.repo.SaveMilestone(ctx, &entities.Milestone{ProjectID: 2, Name: "Launch", WorkItem: entities.WorkItem{Status: entities.WorkStatusDone}})
+	suite.Require().NoError(err)
+
+	progress, err := suite.repo.ProgressByProjects(ctx, []int{1, 2, 3})
+
+	suite.Require().NoError(err)
+	suite.Equal(int64(3), progress[1].Tasks.Total)
+	suite.Equal(int64(2), progress[1].Tasks.Done)
+	suite.InDelta(8, progress[1].Tasks.EstimateHours, 0.001)
+	suite.Equal(50, progress[1].Percent)
+	suite.Equal(100, progress[2].Percent)
+	suite.Require().NotNil(progress[3])
+	suite.Equal(0, progress[3].Percent)
+}
+
+func TestProjectWorkRepositoryTestSuite(t *testing.T) {
+	suite.Run(t, new(ProjectWorkRepositoryTestSuite))
+}
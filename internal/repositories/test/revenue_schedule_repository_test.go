@@ -0,0 +1,125 @@
+package test_test
+
+import (
+	"Backend_golang_project/internal/domain/entities"
+	"Backend_golang_project/internal/domain/money"
+	"Backend_golang_project/internal/repositories"
+	"context"
+	"github.com/glebarez/sqlite"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/suite"
+	"gorm.io/gorm"
+)
+
+type RevenueScheduleRepositoryTestSuite struct {
+	suite.Suite
+	db      *gorm.DB
+	repo    repositories.IRevenueScheduleRepository
+	project *entities.Project
+}
+
+func (suite *RevenueScheduleRepositoryTestSuite) SetupTest() {
+	db, err := gorm.Open(sqlite.Open(":memory:"), &gorm.Config{})
+	suite.Require().NoError(err)
+	suite.Require().NoError(entities.SetupJoinTables(db))
+	suite.Require().NoError(db.AutoMigrate(&entities.User{}, &entities.Project{}, &entities.UserProject{}, &entities.ProjectTransaction{}, &entities.ProjectVersion{},
+		&entities.RevenueSchedule{}, &entities.RevenueMilestone{}, &entities.ProjectCategory{}))
+	suite.Require().NoError(db.Create([]entities.ProjectCategory{
+		{Name: entities.ProjectCategoryClient, Billable: true}, {Name: "system"},
+	}).Error)
+
+	// 01/01 → 10/01/2024, 10 ngày
+	end := time.Date(2024, 1, 10, 0, 0, 0, 0, time.UTC)
+	suite.project = &entities.Project{Name: "Apollo", Category: entities.ProjectCategoryClient, Status: entities.ProjectStatusActive,
+		ProjectStartedAt: time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC), ProjectEndedAt: &end}
+	suite.Require().NoError(db.Create(suite.project).Error)
+	suite.db = db
+	suite.repo = repositories.NewRevenueScheduleRepository(db)
+}
+
+func (suite *RevenueScheduleRepositoryTestSuite) straightLine(contractValue int64) {
+	_, err := suite.repo.Save(context.Background(), &entities.RevenueSchedule{ProjectID: suite.project.ID,
+		Method: entities.RecognitionStraightLine, ContractValue: money.New(contractValue, "VND")})
+	suite.Require().NoError(err)
+}
+
+func (suite *RevenueScheduleRepositoryTestSuite) TestRecognise_PostsDifferenceOnly() {
+	ctx := context.Background()
+	suite.straightLine(1000)
+
+	entry, err := suite.repo.Recognise(ctx, suite.project.ID, time.Date(2024, 1, 3, 12, 0, 0, 0, time.UTC), nil)
+	suite.Require().NoError(err)
+	suite.Require().NotNil(entry)
+	suite.Equal(money.New(300, "VND"), entry.Amount)
+	suite.Equal(entities.TransactionTypeRevenue, entry.Type)
+
+	// chạy lại cùng ngày không ghi thêm
+	entry, err = suite.repo.Recognise(ctx, suite.project.ID, time.Date(2024, 1, 3, 23, 0, 0, 0, time.UTC), nil)
+	suite.Require().NoError(err)
+	suite.Nil(entry)
+
+	entry, err = suite.repo.Recognise(ctx, suite.project.ID, time.Date(2024, 2, 1, 0, 0, 0, 0, time.UTC), nil)
+	suite.Require().NoError(err)
+	suite.Equal(money.New(700, "VND"), entry.Amount)
+
+	var project entities.Project
+	suite.Require().NoError(suite.db.First(&project, suite.project.ID).Error)
+	suite.Equal(int64(1000), project.RevenueRecognised.Amount)
+
+	_, err = suite.repo.Recognise(ctx, suite.project.ID, time.Date(2024, 1, 5, 0, 0, 0, 0, time.UTC), nil)
+	suite.ErrorIs(err, repositories.ErrRecognitionBackdated)
+}
+
+func (suite *RevenueScheduleRepositoryTestSuite) TestSave_KeepsRecognisedAmount() {
+	ctx := context.Background()
+	suite.straightLine(1000)
+	_, err := suite.repo.Recognise(ctx, suite.project.ID, time.Date(2024, 1, 5, 0, 0, 0, 0, time.UTC), nil)
+	suite.Require().NoError(err)
+
+	// hợp đồng giảm còn 400 sau khi đã ghi nhận 500: lần chạy sau ghi adjustment âm
+	schedule, err := suite.repo.Save(ctx, &entities.RevenueSchedule{ProjectID: suite.project.ID, Method: entities.RecognitionMilestone,
+		ContractValue: money.New(400, "VND"), Milestones: []entities.RevenueMilestone{
+			{Name: "Design", Amount: 400, DueDate: time.Date(2024, 1, 2, 0, 0, 0, 0, time.UTC), CompletedAt: &suite.project.ProjectStartedAt},
+		}})
+	suite.Require().NoError(err)
+	suite.Equal(int64(500), schedule.RecognisedAmount)
+	suite.Len(schedule.Milestones, 1)
+
+	entry, err := suite.repo.Recognise(ctx, suite.project.ID, time.Date(2024, 1, 6, 0, 0, 0, 0, time.UTC), nil)
+	suite.Require().NoError(err)
+	suite.Equal(entities.TransactionTypeAdjustment, entry.Type)
+	suite.Equal(int64(-100), entry.Amount.Amount)
+}
+
+func (suite *RevenueScheduleRepositoryTestSuite) TestRecognise_NotBillable() {
+	suite.straightLine(1000)
+	suite.Require().NoError(suite.db.Model(suite.project).Update("category", "system").Error)
+
+	_, err := suite.repo.Recognise(context.Background(), suite.project.ID, time.Date(2024, 1, 5, 0, 0, 0, 0, time.UTC), nil)
+
+	suite.ErrorIs(err, repositories.ErrProjectNotBillable)
+}
+
+func (suite *RevenueScheduleRepositoryTestSuite) TestGetBatch_SkipsTrashedProjects() {
+	ctx := context.Background()
+	suite.straightLine(1000)
+	trashed := &entities.Project{Name: "Gemini", Category: entities.ProjectCategoryClient, Status: entities.ProjectStatusActive,
+		ProjectStartedAt: suite.project.ProjectStartedAt, ProjectEndedAt: suite.project.ProjectEndedAt}
+	suite.Require().NoError(suite.db.Create(trashed).Error)
+	_, err := suite.repo.Save(ctx, &entities.RevenueSchedule{ProjectID: trashed.ID,
+		Method: entities.RecognitionStraightLine, ContractValue: money.New(500, "VND")})
+	suite.Require().NoError(err)
+	suite.Require().NoError(suite.db.Delete(trashed).Error)
+
+	schedules, err := suite.repo.GetBatch(ctx, 0, 10)
+
+	suite.Require().NoError(err)
+	suite.Require().Len(schedules, 1)
+	suite.Equal(suite.project.ID, schedules[0].ProjectID)
+}
+
+func TestRevenueScheduleRepositorySuite(t *testing.T) {
+	suite.Run(t, new(RevenueScheduleRepositoryTestSuite))
+}
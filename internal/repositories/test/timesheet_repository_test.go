@@ -0,0 +1,157 @@
+package test_test
+
+import (
+	"Backend_golang_project/internal/domain/entities"
+	"Backend_golang_project/internal/domain/money"
+	"Backend_golang_project/internal/repositories"
+	"context"
+	"errors"
+	"github.com/glebarez/sqlite"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/suite"
+	"gorm.io/gorm"
+)
+
+type TimesheetRepositoryTestSuite struct {
+	suite.Suite
+	db      *gorm.DB
+	repo    repositories.ITimesheetRepository
+	project *entities.Project
+}
+
+func (suite *TimesheetRepositoryTestSuite) SetupTest() {
+	db, err := gorm.Open(sqlite.Open(":memory:"), &gorm.Config{})
+	suite.Require().NoError(err)
+	suite.Require().NoError(db.AutoMigrate(&entities.Project{}, &entities.ProjectVersion{}, &entities.ProjectTransaction{}, &entities.Timesheet{}, &entities.TimesheetEntry{}))
+
+	suite.project = &entities.Project{Name: "Apollo", Status: entities.ProjectStatusActive,
+		Budget: money.New(1000, "USD"), ProjectSpend: money.New(0, "USD"), ProjectStartedAt: time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)}
+	suite.Require().NoError(db.Create(suite.project).Error)
+	suite.db = db
+	suite.repo = repositories.NewTimesheetRepository(db)
+}
+
+func (suite *TimesheetRepositoryTestSuite) logTime(userID int, workDate time.Time, hours float64) *entities.TimesheetEntry {
+	entry, err := suite.repo.SaveEntry(context.Background(), &entities.TimesheetEntry{UserID: userID, ProjectID: suite.project.ID, WorkDate: workDate, Hours: hours})
+	suite.Require().NoError(err)
+	return entry
+}
+
+func (suite *TimesheetRepositoryTestSuite) TestSaveEntry_GroupsByWeek() {
+	// thứ Tư và thứ Sáu cùng tuần, thứ Hai tuần sau
+	wednesday := time.Date(2024, 3, 6, 15, 0, 0, 0, time.UTC)
+	first := suite.logTime(5, wednesday, 4)
+	second := suite.logTime(5, wednesday.AddDate(0, 0, 2), 3.5)
+	third := suite.logTime(5, wednesday.AddDate(0, 0, 5), 8)
+
+	suite.Equal(first.TimesheetID, second.TimesheetID)
+	suite.NotEqual(first.TimesheetID, third.TimesheetID)
+
+	timesheet, err := suite.repo.Get(context.Background(), first.TimesheetID)
+	suite.Require().NoError(err)
+	suite.True(timesheet.WeekStart.Equal(time.Date(2024, 3, 4, 0, 0, 0, 0, time.UTC)))
+	suite.Equal(entities.TimesheetStatusDraft, timesheet.Status)
+	suite.InDelta(7.5, timesheet.TotalHours(), 0.001)
+}
+
+func (suite *TimesheetRepositoryTestSuite) TestSubmittedTimesheetIsLocked() {
+	ctx := context.Background()
+	workDate := time.Date(2024, 3, 6, 0, 0, 0, 0, time.UTC)
+	entry := suite.logTime(5, workDate, 4)
+	suite.Require().NoError(suite.repo.Submit(ctx, entry.TimesheetID, time.Now()))
+
+	_, err := suite.repo.SaveEntry(ctx, &entities.TimesheetEntry{UserID: 5, ProjectID: suite.project.ID, WorkDate: workDate, Hours: 1})
+	suite.True(errors.Is(err, repositories.ErrTimesheetLocked))
+	entry.Hours = 2
+	_, err = suite.repo.SaveEntry(ctx, entry)
+	suite.True(errors.Is(err, repositories.ErrTimesheetLocked))
+	suite.True(errors.Is(suite.repo.DeleteEntry(ctx, entry.ID), repositories.ErrTimesheetLocked))
+	suite.True(errors.Is(suite.repo.Submit(ctx, entry.TimesheetID, time.Now()), repositories.ErrTimesheetLocked))
+
+	// bị từ chối thì sửa được và nộp lại
+	suite.Require().NoError(suite.repo.Reject(ctx, entry.TimesheetID, 7, "Split the hours by task", time.Now()))
+	_, err = suite.repo.SaveEntry(ctx, entry)
+	suite.NoError(err)
+	suite.NoError(suite.repo.Submit(ctx, entry.TimesheetID, time.Now()))
+}
+
+func (suite *TimesheetRepositoryTestSuite) TestApprove_PostsCostToLedger() {
+	ctx := context.Background()
+	workDate := time.Date(2024, 3, 6, 0, 0, 0, 0, time.UTC)
+	entry := suite.logTime(5, workDate, 2.5)
+	suite.logTime(5, workDate.AddDate(0, 0, 1), 1.25)
+	suite.Require().NoError(suite.repo.Submit(ctx, entry.TimesheetID, time.Now()))
+
+	// 40.00 USD một giờ: 2.5h = 100.00, 1.25h = 50.00
+	timesheet, project, err := suite.repo.Approve(ctx, entry.TimesheetID, 7, money.New(4000, "USD"), time.Now())
+
+	suite.Require().NoError(err)
+	suite.Equal(entities.TimesheetStatusApproved, timesheet.Status)
+	suite.Equal(money.New(15000, "USD"), timesheet.Cost)
+	suite.Require().NotNil(timesheet.TransactionID)
+	suite.Equal(int64(15000), project.ProjectSpend.Amount)
+	suite.Equal(int64(1000-15000), project.ProjectVariance.Amount)
+
+	var ledger entities.ProjectTransaction
+	suite.Require().NoError(suite.db.First(&ledger, *timesheet.TransactionID).Error)
+	suite.Equal(entities.TransactionTypeSpend, ledger.Type)
+	suite.Equal(int64(15000), ledger.Amount.Amount)
+
+	_, _, err = suite.repo.Approve(ctx, entry.TimesheetID, 7, money.New(4000, "USD"), time.Now())
+	suite.True(errors.Is(err, repositories.ErrTimesheetLocked), "approving twice must not post again")
+}
+
+func (suite *TimesheetRepositoryTestSuite) TestApprove_ClosedProject() {
+	ctx := context.Background()
+	entry := suite.logTime(5, time.Date(2024, 3, 6, 0, 0, 0, 0, time.UTC), 1)
+	suite.Require().NoError(suite.repo.Submit(ctx, entry.TimesheetID, time.Now()))
+	suite.Require().NoError(suite.db.Model(suite.project).Update("status", entities.ProjectStatusCompleted).Error)
+
+	_, _, err := suite.repo.Approve(ctx, entry.TimesheetID, 7, money.New(4000, "USD"), time.Now())
+
+	suite.True(errors.Is(err, repositories.ErrProjectClosed))
+	timesheet, err := suite.repo.Get(ctx, entry.TimesheetID)
+	suite.Require().NoError(err)
+	suite.Equal(entities.TimesheetStatusSubmitted, timesheet.Status)
+}
+
+func (suite *TimesheetRepositoryTestSuite) TestSummary() {
+	ctx := context.Background()
+	week1 := time.Date(2024, 3, 4, 0, 0, 0, 0, time.UTC)
+	week2 := week1.AddDate(0, 0, 7)
+	approved := suite.logTime(5, week1, 2)
+	suite.logTime(5, week2, 3)
+	suite.logTime(6, week1, 4)
+	suite.Require().NoError(suite.repo.Submit(ctx, approved.TimesheetID, time.Now()))
+	_, _, err := suite.repo.Approve(ctx, approved.TimesheetID, 7, money.New(1000, "USD"), time.Now())
+	suite.Require().NoError(err)
+
+	byUser, err := suite.repo.Summary(ctx, repositories.TimesheetSummaryQuery{ProjectID: suite.project.ID, ByUser: true})
+	suite.Require().NoError(err)
+	suite.Require().Len(byUser, 2)
+	suite.Equal(5, *byUser[0].UserID)
+	suite.InDelta(5, byUser[0].Hours, 0.001)
+	suite.InDelta(2, byUser[0].ApprovedHours, 0.001)
+	suite.Equal(money.New(2000, "USD"), byUser[0].ApprovedCost)
+	suite.Nil(byUser[0].WeekStart)
+
+	byWeek, err := suite.repo.Summary(ctx, repositories.TimesheetSummaryQuery{UserID: 5, ByWeek: true})
+	suite.Require().NoError(err)
+	suite.Require().Len(byWeek, 2)
+	suite.Nil(byWeek[0].UserID)
+	suite.Require().NotNil(byWeek[0].WeekStart)
+	suite.True(byWeek[0].WeekStart.Equal(week1))
+	suite.InDelta(3, byWeek[1].Hours, 0.001)
+
+	from := week2
+	filtered, err := suite.repo.Summary(ctx, repositories.TimesheetSummaryQuery{ProjectID: suite.project.ID, From: &from})
+	suite.Require().NoError(err)
+	suite.Require().Len(filtered, 1)
+	suite.InDelta(3, filtered[0].Hours, 0.001)
+}
+
+func TestTimesheetRepositoryTestSuite(t *testing.T) {
+	suite.Run(t, new(TimesheetRepositoryTestSuite))
+}
@@ -0,0 +1,129 @@
+package test_test
+
+import (
+	"Backend_golang_project/internal/domain/entities"
+	"Backend_golang_project/internal/repositories"
+	"context"
+	"github.com/glebarez/sqlite"
+	"testing"
+
+	"github.com/stretchr/testify/suite"
+	"gorm.io/gorm"
+)
+
+type UserRepositoryTestSuite struct {
+	suite.Suite
+	db   *gorm.DB
+	repo repositories.IUserRepository
+}
+
+func (suite *UserRepositoryTestSuite) SetupTest() {
+	// TranslateError giống cấu hình trong infrastructure/database
+	db, err := gorm.Open(sqlite.Open(":memory:"), &gorm.Config{TranslateError: true})
+	suite.Require().NoError(err)
+	suite.Require().NoError(entities.SetupJoinTables(db))
+	suite.Require().NoError(db.AutoMigrate(&entities.User{}, &entities.Project{}, &entities.UserProject{}, &entities.ProjectVersion{}))
+
+	suite.db = db
+	suite.repo = repositories.NewUserRepository(db)
+}
+
+func (suite *UserRepositoryTestSuite) createUser(email string) *entities.User {
+	user, err := suite.repo.CreateUser(context.Background(), &entities.User{Email: email, Password: "hash", Username: email})
+	suite.Require().NoError(err)
+	return user
+}
+
+func (suite *UserRepositoryTestSuite) TestCreateUser_DuplicateEmail() {
+	suite.createUser("frank@example.com")
+
+	_, err := suite.repo.CreateUser(context.Background(), &entities.User{Email: "frank@example.com", Password: "hash", Username: "frank"})
+
+	suite.ErrorIs(err, repositories.ErrEmailAlreadyExists)
+}
+
+func (suite *UserRepositoryTestSuite) TestUpdateProfile_DuplicateEmail() {
+	suite.createUser("grace@example.com")
+	heidi := suite.createUser("heidi@example.com")
+
+	err := suite.repo.UpdateProfile(context.Background(), heidi.ID, map[string]interface{}{"email": "grace@example.com"})
+
+	suite.ErrorIs(err, repositories.ErrEmailAlreadyExists)
+}
+
+func (suite *UserRepositoryTestSuite) TestUpdateRole_LastAdmin() {
+	ctx := context.Background()
+	alice := suite.createUser("alice@example.com")
+	bob := suite.createUser("bob@example.com")
+	suite.Require().NoError(suite.repo.UpdateRole(ctx, alice.ID, entities.RoleAdmin))
+	suite.Require().NoError(suite.repo.UpdateRole(ctx, bob.ID, entities.RoleAdmin))
+
+	suite.Require().NoError(suite.repo.UpdateRole(ctx, alice.ID, entities.RoleMember))
+	suite.ErrorIs(suite.repo.UpdateRole(ctx, bob.ID, entities.RoleMember), repositories.ErrLastAdmin)
+
+	role, err := suite.repo.GetRole(ctx, bob.ID)
+	suite.Require().NoError(err)
+	suite.Equal(entities.RoleAdmin, role)
+}
+
+func (suite *UserRepositoryTestSuite) TestDeleteUser_IsSoft() {
+	ctx := context.Background()
+	ivan := suite.createUser("ivan@example.com")
+
+	suite.Require().NoError(suite.repo.DeleteUser(ctx, ivan.ID))
+
+	_, err := suite.repo.GetUserByEmail(ctx, "ivan@example.com")
+	suite.Error(err)
+	_, err = suite.repo.GetRole(ctx, ivan.ID)
+	suite.ErrorIs(err, gorm.ErrRecordNotFound)
+}
+
+func (suite *UserRepositoryTestSuite) TestDeleteUser_LastAdmin() {
+	ctx := context.Background()
+	judy := suite.createUser("judy@example.com")
+	suite.Require().NoError(suite.repo.UpdateRole(ctx, judy.ID, entities.RoleAdmin))
+
+	suite.ErrorIs(suite.repo.DeleteUser(ctx, judy.ID), repositories.ErrLastAdmin)
+
+	_, err := suite.repo.GetRole(ctx, judy.ID)
+	suite.NoError(err)
+}
+
+func (suite *UserRepositoryTestSuite) TestDeleteUser_SoleProjectOwner() {
+	ctx := context.Background()
+	kate := suite.createUser("kate@example.com")
+	leo := suite.createUser("leo@example.com")
+	project := &entities.Project{Name: "Apollo", Category: "client"}
+	suite.Require().NoError(suite.db.Create(project).Error)
+	suite.Require().NoError(suite.db.Create(&entities.UserProject{ProjectID: project.ID, UserID: kate.ID, Role: entities.ProjectRoleOwner}).Error)
+	suite.Require().NoError(suite.db.Create(&entities.UserProject{ProjectID: project.ID, UserID: leo.ID, Role: entities.ProjectRoleManager}).Error)
+
+	suite.ErrorIs(suite.repo.DeleteUser(ctx, kate.ID), repositories.ErrUserOwnsProjects)
+
+	// có owner khác thì xóa được
+	suite.Require().NoError(suite.db.Model(&entities.UserProject{}).
+		Where("project_id = ? AND user_id = ?", project.ID, leo.ID).Update("role", entities.ProjectRoleOwner).Error)
+	suite.NoError(suite.repo.DeleteUser(ctx, kate.ID))
+}
+
+func (suite *UserRepositoryTestSuite) TestGetUsersBatch_AfterID() {
+	ctx := context.Background()
+	first := suite.createUser("judy@example.com")
+	second := suite.createUser("karl@example.com")
+	third := suite.createUser("liam@example.com")
+
+	batch, err := suite.repo.GetUsersBatch(ctx, 0, 2)
+	suite.Require().NoError(err)
+	suite.Require().Len(batch, 2)
+	suite.Equal(first.ID, batch[0].ID)
+	suite.Equal(second.ID, batch[1].ID)
+
+	batch, err = suite.repo.GetUsersBatch(ctx, batch[1].ID, 2)
+	suite.Require().NoError(err)
+	suite.Require().Len(batch, 1)
+	suite.Equal(third.ID, batch[0].ID)
+}
+
+func TestUserRepositoryTestSuite(t *testing.T) {
+	suite.Run(t, new(UserRepositoryTestSuite))
+}
@@ -0,0 +1,126 @@
+package test_test
+
+import (
+	"Backend_golang_project/internal/domain/entities"
+	"Backend_golang_project/internal/repositories"
+	"context"
+	"github.com/glebarez/sqlite"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/suite"
+	"gorm.io/gorm"
+)
+
+type UserTokenRepositoryTestSuite struct {
+	suite.Suite
+	db   *gorm.DB
+	repo repositories.IUserTokenRepository
+}
+
+func (suite *UserTokenRepositoryTestSuite) SetupTest() {
+	db, err := gorm.Open(sqlite.Open(":memory:"), &gorm.Config{})
+	suite.Require().NoError(err)
+	suite.Require().NoError(db.AutoMigrate(&entities.User{}, &entities.UserToken{}))
+
+	suite.db = db
+	suite.repo = repositories.NewUserTokenRepository(db)
+}
+
+func (suite *UserTokenRepositoryTestSuite) TestConsumeIsSingleUse() {
+	ctx := context.Background()
+	now := time.Now()
+	suite.Require().NoError(suite.repo.Create(ctx, &entities.UserToken{
+		UserID:    1,
+		Purpose:   entities.UserTokenPasswordReset,
+		TokenHash: "hash-1",
+		ExpiresAt: now.Add(time.Hour),
+	}))
+
+	token, err := suite.repo.Consume(ctx, entities.UserTokenPasswordReset, "hash-1", now)
+	suite.Require().NoError(err)
+	suite.Equal(1, token.UserID)
+
+	_, err = suite.repo.Consume(ctx, entities.UserTokenPasswordReset, "hash-1", now)
+	suite.ErrorIs(err, repositories.ErrUserTokenInvalid)
+}
+
+func (suite *UserTokenRepositoryTestSuite) TestConsumeRejectsExpiredAndWrongPurpose() {
+	ctx := context.Background()
+	now := time.Now()
+	suite.Require().NoError(suite.repo.Create(ctx, &entities.UserToken{
+		UserID:    1,
+		Purpose:   entities.UserTokenEmailVerification,
+		TokenHash: "hash-2",
+		ExpiresAt: now.Add(-time.Minute),
+	}))
+
+	_, err := suite.repo.Consume(ctx, entities.UserTokenEmailVerification, "hash-2", now)
+	suite.ErrorIs(err, repositories.ErrUserTokenInvalid)
+
+	_, err = suite.repo.Consume(ctx, entities.UserTokenPasswordReset, "hash-2", now.Add(-time.Hour))
+	suite.ErrorIs(err, repositories.ErrUserTokenInvalid)
+}
+
+func (suite *UserTokenRepositoryTestSuite) TestInvalidateForUser() {
+	ctx := context.Background()
+	now := time.Now()
+	suite.Require().NoError(suite.repo.Create(ctx, &entities.UserToken{
+		UserID:    2,
+		Purpose:   entities.UserTokenPasswordReset,
+		TokenHash: "hash-3",
+		ExpiresAt: now.Add(time.Hour),
+	}))
+
+	suite.Require().NoError(suite.repo.InvalidateForUser(ctx, 2, entities.UserTokenPasswordReset, now))
+
+	latest, err := suite.repo.LatestActive(ctx, 2, entities.UserTokenPasswordReset, now)
+	suite.Require().NoError(err)
+	suite.Nil(latest)
+}
+
+func (suite *UserTokenRepositoryTestSuite) TestResetPasswordConsumesTokenAndUpdatesPassword() {
+	ctx := context.Background()
+	now := time.Now()
+	suite.Require().NoError(suite.db.Create(&entities.User{ID: 3, Username: "carol", Email: "carol@example.com", Password: "old-hash"}).Error)
+	suite.Require().NoError(suite.repo.Create(ctx, &entities.UserToken{
+		UserID:    3,
+		Purpose:   entities.UserTokenPasswordReset,
+		TokenHash: "hash-4",
+		ExpiresAt: now.Add(time.Hour),
+	}))
+
+	token, err := suite.repo.ResetPassword(ctx, "hash-4", "new-hash", now)
+	suite.Require().NoError(err)
+	suite.Equal(3, token.UserID)
+
+	var user entities.User
+	suite.Require().NoError(suite.db.First(&user, 3).Error)
+	suite.Equal("new-hash", user.Password)
+
+	_, err = suite.repo.ResetPassword(ctx, "hash-4", "other-hash", now)
+	suite.ErrorIs(err, repositories.ErrUserTokenInvalid)
+}
+
+func (suite *UserTokenRepositoryTestSuite) TestResetPasswordRollsBackWhenUserIsGone() {
+	ctx := context.Background()
+	now := time.Now()
+	suite.Require().NoError(suite.repo.Create(ctx, &entities.UserToken{
+		UserID:    99,
+		Purpose:   entities.UserTokenPasswordReset,
+		TokenHash: "hash-5",
+		ExpiresAt: now.Add(time.Hour),
+	}))
+
+	_, err := suite.repo.ResetPassword(ctx, "hash-5", "new-hash", now)
+	suite.ErrorIs(err, repositories.ErrUserTokenInvalid)
+
+	// không ghi được mật khẩu thì token không bị đánh dấu đã dùng
+	latest, err := suite.repo.LatestActive(ctx, 99, entities.UserTokenPasswordReset, now)
+	suite.Require().NoError(err)
+	suite.NotNil(latest)
+}
+
+func TestUserTokenRepositoryTestSuite(t *testing.T) {
+	suite.Run(t, new(UserTokenRepositoryTestSuite))
+}
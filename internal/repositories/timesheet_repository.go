@@ -0,0 +1,382 @@
+package repositories
+
+import (
+	"Backend_golang_project/internal/domain/entities"
+	"Backend_golang_project/internal/domain/money"
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+// ErrTimesheetLocked timesheet không ở trạng thái cho phép thao tác (ví dụ sửa dòng giờ công của timesheet đã nộp)
+var ErrTimesheetLocked = errors.New("timesheet cannot be changed in its current status")
+
+// TimesheetQuery điều kiện lọc timesheet, trường rỗng thì không lọc theo trường đó
+type TimesheetQuery struct {
+	UserID    int
+	ProjectID int
+	WeekStart *time.Time
+	Status    string
+}
+
+// TimesheetSummaryQuery luôn gom theo project, ByUser/ByWeek tách thêm theo user/tuần.
+// From/To lọc theo ngày làm việc của từng dòng giờ công
+type TimesheetSummaryQuery struct {
+	ProjectID int
+	UserID    int
+	From      *time.Time
+	To        *time.Time
+	ByUser    bool
+	ByWeek    bool
+}
+
+type ITimesheetRepository interface {
+	SaveEntry(ctx context.Context, entry *entities.TimesheetEntry) (*entities.TimesheetEntry, error)
+	GetEntry(ctx context.Context, id int) (*entities.TimesheetEntry, error)
+	DeleteEntry(ctx context.Context, id int) error
+	Get(ctx context.Context, id int) (*entities.Timesheet, error)
+	List(ctx context.Context, query TimesheetQuery) ([]entities.Timesheet, error)
+	Submit(ctx context.Context, id int, submittedAt time.Time) error
+	Reject(ctx context.Context, id int, reviewerID int, note string, reviewedAt time.Time) error
+	Approve(ctx context.Context, id int, reviewerID int, rate money.Money, reviewedAt time.Time) (*entities.Timesheet, *entities.Project, error)
+	Summary(ctx context.Context, query TimesheetSummaryQuery) ([]entities.TimesheetSummary, error)
+}
+
+type TimesheetRepository struct {
+	base
+}
+
+// SaveEntry thêm hoặc sửa một dòng giờ công. Dòng được gắn vào timesheet của user trên project trong tuần
+// của WorkDate (tạo timesheet draft nếu chưa có); cả timesheet cũ (khi sửa) và timesheet mới đều phải còn sửa được
+func (r TimesheetRepository) SaveEntry(ctx context.Context, entry *entities.TimesheetEntry) (*entities.TimesheetEntry, error) {
+	tx := r.StartTransaction().WithContext(ctx)
+
+	if entry.ID != 0 {
+		var existing entities.TimesheetEntry
+		if err := tx.First(&existing, entry.ID).Error; err != nil {
+			r.RollBackTransaction(tx)
+			return nil, fmt.Errorf("error loading timesheet entry: %w", err)
+		}
+		if _, err := lockEditableTimesheet(tx, existing.TimesheetID); err != nil {
+			r.RollBackTransaction(tx)
+			return nil, err
+		}
+		entry.CreatedAt = existing.CreatedAt
+	}
+
+	timesheet, err := r.weekTimesheet(tx, entry.UserID, entry.ProjectID, entities.WeekStart(entry.WorkDate))
+	if err != nil {
+		r.RollBackTransaction(tx)
+		return nil, err
+	}
+	if !timesheet.Editable() {
+		r.RollBackTransaction(tx)
+		return nil, fmt.Errorf("%w: timesheet of week %s is %s", ErrTimesheetLocked, timesheet.WeekStart.Format(time.DateOnly), timesheet.Status)
+	}
+
+	entry.TimesheetID = timesheet.ID
+	entry.WorkDate = entities.Day(entry.WorkDate)
+	if err := tx.Save(entry).Error; err != nil {
+		r.RollBackTransaction(tx)
+		return nil, fmt.Errorf("error saving timesheet entry: %w", err)
+	}
+	if err := r.CommitTransaction(tx); err != nil {
+		return nil, err
+	}
+	return entry, nil
+}
+
+// weekTimesheet khóa timesheet của tuần, tạo mới ở trạng thái draft nếu chưa có
+func (r TimesheetRepository) weekTimesheet(tx *gorm.DB, userID int, projectID int, weekStart time.Time) (*entities.Timesheet, error) {
+	var timesheet entities.Timesheet
+	err := tx.Clauses(clause.Locking{Strength: "UPDATE"}).
+		Where("user_id = ? AND project_id = ? AND week_start = ?", userID, projectID, weekStart).
+		First(&timesheet).Error
+	switch {
+	case err == nil:
+		return &timesheet, nil
+	case !errors.Is(err, gorm.ErrRecordNotFound):
+		return nil, fmt.Errorf("error loading timesheet: %w", err)
+	}
+
+	timesheet = entities.Timesheet{UserID: userID, ProjectID: projectID, WeekStart: weekStart, Status: entities.TimesheetStatusDraft}
+	if err := tx.Create(&timesheet).Error; err != nil {
+		return nil, fmt.Errorf("error creating timesheet: %w", err)
+	}
+	return &timesheet, nil
+}
+
+// lockEditableTimesheet khóa timesheet và trả về ErrTimesheetLocked nếu nó đã được nộp hoặc duyệt
+func lockEditableTimesheet(tx *gorm.DB, id int) (*entities.Timesheet, error) {
+	var timesheet entities.Timesheet
+	if err := tx.Clauses(clause.Locking{Strength: "UPDATE"}).First(&timesheet, id).Error; err != nil {
+		return nil, fmt.Errorf("error locking timesheet: %w", err)
+	}
+	if !timesheet.Editable() {
+		return nil, fmt.Errorf("%w: timesheet %d is %s", ErrTimesheetLocked, id, timesheet.Status)
+	}
+	return &timesheet, nil
+}
+
+func (r TimesheetRepository) GetEntry(ctx context.Context, id int) (*entities.TimesheetEntry, error) {
+	var entry entities.TimesheetEntry
+	if err := r.db.WithContext(ctx).First(&entry, id).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, fmt.Errorf("timesheet entry %d not found: %w", id, err)
+		}
+		return nil, fmt.Errorf("error retrieving timesheet entry: %w", err)
+	}
+	return &entry, nil
+}
+
+func (r TimesheetRepository) DeleteEntry(ctx context.Context, id int) error {
+	tx := r.StartTransaction().WithContext(ctx)
+
+	var entry entities.TimesheetEntry
+	if err := tx.First(&entry, id).Error; err != nil {
+		r.RollBackTransaction(tx)
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return fmt.Errorf("timesheet entry %d not found: %w", id, err)
+		}
+		return fmt.Errorf("error loading timesheet entry: %w", err)
+	}
+	if _, err := lockEditableTimesheet(tx, entry.TimesheetID); err != nil {
+		r.RollBackTransaction(tx)
+		return err
+	}
+	if err := tx.Delete(&entry).Error; err != nil {
+		r.RollBackTransaction(tx)
+		return fmt.Errorf("error deleting timesheet entry: %w", err)
+	}
+	return r.CommitTransaction(tx)
+}
+
+func (r TimesheetRepository) Get(ctx context.Context, id int) (*entities.Timesheet, error) {
+	var timesheet entities.Timesheet
+	if err := r.db.WithContext(ctx).Preload("Entries", orderEntries).First(&timesheet, id).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, fmt.Errorf("timesheet %d not found: %w", id, err)
+		}
+		return nil, fmt.Errorf("error retrieving timesheet: %w", err)
+	}
+	return &timesheet, nil
+}
+
+// List timesheet kèm các dòng giờ công, tuần mới nhất trước
+func (r TimesheetRepository) List(ctx context.Context, query TimesheetQuery) ([]entities.Timesheet, error) {
+	db := r.db.WithContext(ctx).Preload("Entries", orderEntries)
+	if query.UserID != 0 {
+		db = db.Where("user_id = ?", query.UserID)
+	}
+	if query.ProjectID != 0 {
+		db = db.Where("project_id = ?", query.ProjectID)
+	}
+	if query.WeekStart != nil {
+		db = db.Where("week_start = ?", *query.WeekStart)
+	}
+	if query.Status != "" {
+		db = db.Where("status = ?", query.Status)
+	}
+
+	var timesheets []entities.Timesheet
+	if err := db.Order("week_start DESC, id").Find(&timesheets).Error; err != nil {
+		return nil, fmt.Errorf("error listing timesheets: %w", err)
+	}
+	return timesheets, nil
+}
+
+// Submit chỉ đổi trạng thái khi timesheet đang draft/rejected, điều kiện nằm trong câu UPDATE
+// để hai lần nộp/duyệt song song không ghi đè lên nhau
+func (r TimesheetRepository) Submit(ctx context.Context, id int, submittedAt time.Time) error {
+	result := r.db.WithContext(ctx).Model(&entities.Timesheet{}).
+		Where("id = ? AND status IN ?", id, []string{entities.TimesheetStatusDraft, entities.TimesheetStatusRejected}).
+		Updates(map[string]interface{}{"status": entities.TimesheetStatusSubmitted, "submitted_at": submittedAt})
+	if result.Error != nil {
+		return fmt.Errorf("error submitting timesheet: %w", result.Error)
+	}
+	if result.RowsAffected == 0 {
+		return fmt.Errorf("%w: timesheet %d is not a draft", ErrTimesheetLocked, id)
+	}
+	return nil
+}
+
+func (r TimesheetRepository) Reject(ctx context.Context, id int, reviewerID int, note string, reviewedAt time.Time) error {
+	result := r.db.WithContext(ctx).Model(&entities.Timesheet{}).
+		Where("id = ? AND status = ?", id, entities.TimesheetStatusSubmitted).
+		Updates(map[string]interface{}{
+			"status":      entities.TimesheetStatusRejected,
+			"reviewed_by": reviewerID,
+			"reviewed_at": reviewedAt,
+			"review_note": note,
+		})
+	if result.Error != nil {
+		return fmt.Errorf("error rejecting timesheet: %w", result.Error)
+	}
+	if result.RowsAffected == 0 {
+		return fmt.Errorf("%w: timesheet %d is not submitted", ErrTimesheetLocked, id)
+	}
+	return nil
+}
+
+// Approve duyệt timesheet đã nộp: tính Cost của từng dòng theo rate (đã quy đổi sang đơn vị tiền của project)
+// và ghi tổng chi phí vào sổ cái thành bút toán spend trong cùng transaction.
+// Timesheet và project đều bị khóa nên một timesheet không bị ghi vào sổ cái hai lần
+func (r TimesheetRepository) Approve(ctx context.Context, id int, reviewerID int, rate money.Money, reviewedAt time.Time) (*entities.Timesheet, *entities.Project, error) {
+	tx := r.StartTransaction().WithContext(ctx)
+
+	var timesheet entities.Timesheet
+	err := tx.Clauses(clause.Locking{Strength: "UPDATE"}).Preload("Entries", orderEntries).First(&timesheet, id).Error
+	if err != nil {
+		r.RollBackTransaction(tx)
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, nil, fmt.Errorf("timesheet %d not found: %w", id, err)
+		}
+		return nil, nil, fmt.Errorf("error locking timesheet: %w", err)
+	}
+	if timesheet.Status != entities.TimesheetStatusSubmitted {
+		r.RollBackTransaction(tx)
+		return nil, nil, fmt.Errorf("%w: timesheet %d is %s", ErrTimesheetLocked, id, timesheet.Status)
+	}
+	project, err := lockProject(tx, timesheet.ProjectID)
+	if err != nil {
+		r.RollBackTransaction(tx)
+		return nil, nil, err
+	}
+	if project.FinancialsLocked() {
+		r.RollBackTransaction(tx)
+		return nil, nil, ErrProjectClosed
+	}
+
+	var total int64
+	for i := range timesheet.Entries {
+		entry := &timesheet.Entries[i]
+		entry.Cost = entities.EntryCost(entry.Hours, rate.Amount)
+		total += entry.Cost
+		if err := tx.Model(entry).UpdateColumn("cost", entry.Cost).Error; err != nil {
+			r.RollBackTransaction(tx)
+			return nil, nil, fmt.Errorf("error updating timesheet entry cost: %w", err)
+		}
+	}
+
+	timesheet.Status = entities.TimesheetStatusApproved
+	timesheet.ReviewedBy = &reviewerID
+	timesheet.ReviewedAt = &reviewedAt
+	timesheet.ReviewNote = ""
+	timesheet.HourlyRate = rate
+	timesheet.Cost = money.New(total, rate.Currency)
+	if total > 0 {
+		entry := &entities.ProjectTransaction{
+			ProjectID:     project.ID,
+			Type:          entities.TransactionTypeSpend,
+			Target:        entities.TransactionTypeSpend,
+			Amount:        timesheet.Cost,
+			EffectiveDate: timesheet.WeekStart.AddDate(0, 0, 6),
+			Description:   fmt.Sprintf("Timesheet #%d: user %d, week of %s", timesheet.ID, timesheet.UserID, timesheet.WeekStart.Format(time.DateOnly)),
+			CreatedBy:     &reviewerID,
+		}
+		if err := postEntry(tx, project, entry); err != nil {
+			r.RollBackTransaction(tx)
+			return nil, nil, err
+		}
+		timesheet.TransactionID = &entry.ID
+	}
+	if err := tx.Omit(clause.Associations).Save(&timesheet).Error; err != nil {
+		r.RollBackTransaction(tx)
+		return nil, nil, fmt.Errorf("error approving timesheet: %w", err)
+	}
+
+	if err := r.CommitTransaction(tx); err != nil {
+		return nil, nil, err
+	}
+	return &timesheet, project, nil
+}
+
+// timesheetSummaryRow một dòng của truy vấn Summary trước khi chuyển sang entities.TimesheetSummary
+type timesheetSummaryRow struct {
+	ProjectID     int
+	UserID        *int
+	WeekStart     *time.Time
+	Currency      string
+	Hours         float64
+	ApprovedHours float64
+	ApprovedCost  int64
+}
+
+// Summary tổng giờ và chi phí đã duyệt, chi phí theo đơn vị tiền của từng project
+func (r TimesheetRepository) Summary(ctx context.Context, query TimesheetSummaryQuery) ([]entities.TimesheetSummary, error) {
+	columns := []string{"e.project_id", "p.project_spend_currency"}
+	order := "e.project_id"
+	if query.ByUser {
+		columns = append(columns, "e.user_id")
+		order += ", e.user_id"
+	}
+	if query.ByWeek {
+		columns = append(columns, "t.week_start")
+		order = "t.week_start, " + order
+	}
+
+	db := r.db.WithContext(ctx).Table("timesheet_entries AS e").
+		Joins("JOIN timesheets AS t ON t.id = e.timesheet_id").
+		Joins("JOIN projects AS p ON p.id = e.project_id AND p.deleted_at IS NULL").
+		Select(
+			"e.project_id, p.project_spend_currency AS currency, "+
+				groupedColumn(query.ByUser, "e.user_id", "user_id")+", "+
+				groupedColumn(query.ByWeek, "t.week_start", "week_start")+", "+
+				"SUM(e.hours) AS hours, "+
+				"SUM(CASE WHEN t.status = ? THEN e.hours ELSE 0 END) AS approved_hours, "+
+				"SUM(e.cost) AS approved_cost", entities.TimesheetStatusApproved)
+	if query.ProjectID != 0 {
+		db = db.Where("e.project_id = ?", query.ProjectID)
+	}
+	if query.UserID != 0 {
+		db = db.Where("e.user_id = ?", query.UserID)
+	}
+	if query.From != nil {
+		db = db.Where("e.work_date >= ?", entities.Day(*query.From))
+	}
+	if query.To != nil {
+		db = db.Where("e.work_date <= ?", entities.Day(*query.To))
+	}
+
+	var rows []timesheetSummaryRow
+	for _, column := range columns {
+		db = db.Group(column)
+	}
+	if err := db.Order(order).Scan(&rows).Error; err != nil {
+		return nil, fmt.Errorf("error summarising timesheets: %w", err)
+	}
+
+	summaries := make([]entities.TimesheetSummary, 0, len(rows))
+	for _, row := range rows {
+		summaries = append(summaries, entities.TimesheetSummary{
+			ProjectID:     row.ProjectID,
+			UserID:        row.UserID,
+			WeekStart:     row.WeekStart,
+			Hours:         row.Hours,
+			ApprovedHours: row.ApprovedHours,
+			ApprovedCost:  money.New(row.ApprovedCost, row.Currency),
+		})
+	}
+	return summaries, nil
+}
+
+// groupedColumn cột được chọn nếu có gom nhóm theo nó, ngược lại là NULL để Scan để trống trường tương ứng
+func groupedColumn(grouped bool, column string, alias string) string {
+	if grouped {
+		return column + " AS " + alias
+	}
+	return "NULL AS " + alias
+}
+
+func orderEntries(db *gorm.DB) *gorm.DB {
+	return db.Order("work_date, id")
+}
+
+// NewTimesheetRepository constructor
+func NewTimesheetRepository(db *gorm.DB) ITimesheetRepository {
+	return &TimesheetRepository{base: base{db: db}}
+}
@@ -2,40 +2,97 @@ package repositories
 
 import (
 	"Backend_golang_project/internal/domain/entities"
+	"Backend_golang_project/internal/domain/money"
+	"Backend_golang_project/internal/pkg"
 	"context"
 	"errors"
 	"fmt"
 	log "github.com/sirupsen/logrus"
 	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+	"time"
+)
+
+var (
+	// ErrEmailAlreadyExists vi phạm unique index uni_users_email
+	ErrEmailAlreadyExists = errors.New("email is already registered")
+	// ErrLastAdmin thao tác sẽ khiến hệ thống không còn admin nào
+	ErrLastAdmin = errors.New("the system must keep at least one admin")
+	// ErrUserOwnsProjects user là owner duy nhất của ít nhất một project, kể cả project trong thùng rác
+	ErrUserOwnsProjects = errors.New("user is the only owner of one or more projects")
 )
 
 type IUserRepository interface {
 	CreateUser(ctx context.Context, user *entities.User) (*entities.User, error)
 	GetUserById(ctx context.Context, ID int) (*entities.User, error)
 	GetUserByEmail(ctx context.Context, email string) (*entities.User, error)
-	GetUsersBatch(ctx context.Context, offset, limit int) ([]*entities.User, error)
+	GetUsersBatch(ctx context.Context, afterID, limit int) ([]*entities.User, error)
+	ListUsers(ctx context.Context, spec PageSpec) (*pkg.Pagination[entities.User], error)
 	GetTotalCount(ctx context.Context) (int64, error)
+	GetRole(ctx context.Context, ID int) (string, error)
+	UpdateRole(ctx context.Context, ID int, role string) error
+	UpdatePassword(ctx context.Context, ID int, passwordHash string) error
+	MarkEmailVerified(ctx context.Context, ID int, verifiedAt time.Time) error
+	UpdateProfile(ctx context.Context, ID int, changes map[string]interface{}) error
+	DeleteUser(ctx context.Context, ID int) error
+	GetHourlyRate(ctx context.Context, ID int) (money.Money, error)
+	UpdateHourlyRate(ctx context.Context, ID int, rate money.Money) error
 }
 
 type UserRepository struct {
 	base
 }
 
-func (u UserRepository) GetUsersBatch(ctx context.Context, offset, limit int) ([]*entities.User, error) {
+// GetUsersBatch lấy tối đa limit user có id > afterID theo thứ tự id, batch đầu tiên truyền afterID = 0.
+// Dùng keyset thay cho OFFSET để batch cuối của bảng lớn không phải quét lại toàn bộ các dòng phía trước
+func (u UserRepository) GetUsersBatch(ctx context.Context, afterID, limit int) ([]*entities.User, error) {
 	var users []*entities.User
-	result := u.db.WithContext(ctx).Offset(offset).Limit(limit).Find(&users)
+	result := u.db.WithContext(ctx).Where("id > ?", afterID).Order("id").Limit(limit).Find(&users)
 	return users, result.Error
 }
 
+// ListUsers danh sách user sắp xếp theo id, Keyset (nếu có) chỉ gồm giá trị id
+func (u UserRepository) ListUsers(ctx context.Context, spec PageSpec) (*pkg.Pagination[entities.User], error) {
+	return paginate[entities.User](u.db.WithContext(ctx).Model(&entities.User{}), "users", nil, spec)
+}
+
 func (u UserRepository) GetTotalCount(ctx context.Context) (int64, error) {
 	var count int64
 	result := u.db.WithContext(ctx).Model(&entities.User{}).Count(&count)
 	return count, result.Error
 }
 
+// CreateUser lưu user cùng các project lồng trong request,
+// user tạo ra những project này nên được gán vai trò owner trong user_projects, mỗi project được ghi version đầu tiên
 func (u UserRepository) CreateUser(ctx context.Context, user *entities.User) (*entities.User, error) {
-	if err := u.db.WithContext(ctx).Create(user).Error; err != nil {
+	tx := u.StartTransaction().WithContext(ctx)
+	if err := tx.Create(user).Error; err != nil {
+		u.RollBackTransaction(tx)
 		log.Error("Cannot create response with err:", err.Error())
+		if errors.Is(err, gorm.ErrDuplicatedKey) {
+			return nil, ErrEmailAlreadyExists
+		}
+		return nil, err
+	}
+
+	if len(user.Projects) > 0 {
+		err := tx.Model(&entities.UserProject{}).
+			Where("user_id = ?", user.ID).
+			Update("role", entities.ProjectRoleOwner).Error
+		if err != nil {
+			u.RollBackTransaction(tx)
+			log.Error("Cannot assign project owner with err:", err.Error())
+			return nil, err
+		}
+		for _, project := range user.Projects {
+			if _, err := recordVersion(tx, project.ID, entities.ProjectChangeCreate, nil); err != nil {
+				u.RollBackTransaction(tx)
+				return nil, err
+			}
+		}
+	}
+
+	if err := u.CommitTransaction(tx); err != nil {
 		return nil, err
 	}
 	return user, nil
@@ -60,13 +117,159 @@ func (u UserRepository) GetUserByEmail(ctx context.Context, email string) (*enti
 	if err := u.db.WithContext(ctx).Where("email = ?", email).First(&user).Error; err != nil {
 		log.Error("Can not find response with email: ", email, err)
 		if errors.Is(err, gorm.ErrRecordNotFound) {
-			return nil, fmt.Errorf("response %v not found", user)
+			return nil, fmt.Errorf("user with email %s not found: %w", email, err)
 		}
 		return nil, fmt.Errorf("error retrieving response: %w", err)
 	}
 	return &user, nil
 }
 
+// GetRole chỉ đọc cột role, tránh preload toàn bộ project của user như GetUserById
+func (u UserRepository) GetRole(ctx context.Context, ID int) (string, error) {
+	var user entities.User
+	if err := u.db.WithContext(ctx).Select("id", "role").First(&user, ID).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return "", fmt.Errorf("user %d not found: %w", ID, err)
+		}
+		return "", fmt.Errorf("error retrieving user role: %w", err)
+	}
+	return user.Role, nil
+}
+
+// UpdateRole không kiểm tra RowsAffected vì MySQL trả về 0 khi role không đổi.
+// Hạ quyền admin cuối cùng trả về ErrLastAdmin, việc kiểm tra và cập nhật nằm trong cùng transaction
+func (u UserRepository) UpdateRole(ctx context.Context, ID int, role string) error {
+	tx := u.StartTransaction().WithContext(ctx)
+	if role != entities.RoleAdmin {
+		if err := ensureAnotherAdmin(tx, ID); err != nil {
+			u.RollBackTransaction(tx)
+			return err
+		}
+	}
+	result := tx.Model(&entities.User{}).Where("id = ?", ID).Update("role", role)
+	if result.Error != nil {
+		u.RollBackTransaction(tx)
+		return fmt.Errorf("error updating user role: %w", result.Error)
+	}
+	return u.CommitTransaction(tx)
+}
+
+// ensureAnotherAdmin khóa các dòng admin bằng SELECT ... FOR UPDATE rồi trả về ErrLastAdmin nếu userID là admin duy nhất;
+// hai request hạ quyền hai admin cuối cùng song song sẽ chạy lần lượt nên request sau thấy chỉ còn một admin
+func ensureAnotherAdmin(tx *gorm.DB, userID int) error {
+	var admins []int
+	err := tx.Model(&entities.User{}).Clauses(clause.Locking{Strength: "UPDATE"}).
+		Where("role = ?", entities.RoleAdmin).
+		Pluck("id", &admins).Error
+	if err != nil {
+		return fmt.Errorf("error locking admins: %w", err)
+	}
+	if len(admins) == 1 && admins[0] == userID {
+		return ErrLastAdmin
+	}
+	return nil
+}
+
+func (u UserRepository) UpdatePassword(ctx context.Context, ID int, passwordHash string) error {
+	err := u.db.WithContext(ctx).Model(&entities.User{}).Where("id = ?", ID).Update("password", passwordHash).Error
+	if err != nil {
+		return fmt.Errorf("error updating user password: %w", err)
+	}
+	return nil
+}
+
+// MarkEmailVerified chỉ ghi lần xác thực đầu tiên, bấm lại link cũ không đổi thời điểm xác thực
+func (u UserRepository) MarkEmailVerified(ctx context.Context, ID int, verifiedAt time.Time) error {
+	err := u.db.WithContext(ctx).Model(&entities.User{}).
+		Where("id = ? AND email_verified_at IS NULL", ID).
+		Update("email_verified_at", verifiedAt).Error
+	if err != nil {
+		return fmt.Errorf("error marking email verified: %w", err)
+	}
+	return nil
+}
+
+// UpdateProfile chỉ cập nhật các cột có trong changes, trùng email trả về ErrEmailAlreadyExists
+func (u UserRepository) UpdateProfile(ctx context.Context, ID int, changes map[string]interface{}) error {
+	err := u.db.WithContext(ctx).Model(&entities.User{}).Where("id = ?", ID).Updates(changes).Error
+	if err != nil {
+		if errors.Is(err, gorm.ErrDuplicatedKey) {
+			return ErrEmailAlreadyExists
+		}
+		return fmt.Errorf("error updating user: %w", err)
+	}
+	return nil
+}
+
+// DeleteUser xóa mềm user trong transaction cùng với các kiểm tra: không xóa admin cuối cùng (ErrLastAdmin)
+// và không để lại project không có owner (ErrUserOwnsProjects, user phải trao quyền owner trước)
+func (u UserRepository) DeleteUser(ctx context.Context, ID int) error {
+	tx := u.StartTransaction().WithContext(ctx)
+	var user entities.User
+	if err := tx.Clauses(clause.Locking{Strength: "UPDATE"}).Select("id", "role").First(&user, ID).Error; err != nil {
+		u.RollBackTransaction(tx)
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return fmt.Errorf("user %d not found: %w", ID, err)
+		}
+		return fmt.Errorf("error retrieving user: %w", err)
+	}
+	if user.Role == entities.RoleAdmin {
+		if err := ensureAnotherAdmin(tx, ID); err != nil {
+			u.RollBackTransaction(tx)
+			return err
+		}
+	}
+	if err := ensureNoSoleOwnedProjects(tx, ID); err != nil {
+		u.RollBackTransaction(tx)
+		return err
+	}
+	if err := tx.Delete(&entities.User{}, ID).Error; err != nil {
+		u.RollBackTransaction(tx)
+		return fmt.Errorf("error deleting user: %w", err)
+	}
+	return u.CommitTransaction(tx)
+}
+
+// ensureNoSoleOwnedProjects khóa các dòng owner của những project user đang sở hữu rồi trả về ErrUserOwnsProjects
+// nếu có project không còn owner nào khác
+func ensureNoSoleOwnedProjects(tx *gorm.DB, userID int) error {
+	var projectIDs []int
+	err := tx.Table("user_projects AS up").Clauses(clause.Locking{Strength: "UPDATE"}).
+		Joins("JOIN projects ON projects.id = up.project_id").
+		Where("up.user_id = ? AND up.role = ? AND up.deleted_at IS NULL", userID, entities.ProjectRoleOwner).
+		Where("NOT EXISTS (?)", tx.Table("user_projects AS other").Select("1").
+			Where("other.project_id = up.project_id AND other.user_id <> up.user_id AND other.role = ? AND other.deleted_at IS NULL", entities.ProjectRoleOwner)).
+		Pluck("up.project_id", &projectIDs).Error
+	if err != nil {
+		return fmt.Errorf("error checking owned projects: %w", err)
+	}
+	if len(projectIDs) > 0 {
+		return fmt.Errorf("%w: %v", ErrUserOwnsProjects, projectIDs)
+	}
+	return nil
+}
+
+// GetHourlyRate chỉ đọc các cột đơn giá, dùng khi duyệt timesheet
+func (u UserRepository) GetHourlyRate(ctx context.Context, ID int) (money.Money, error) {
+	var user entities.User
+	if err := u.db.WithContext(ctx).Select("id", "hourly_rate_amount", "hourly_rate_currency").First(&user, ID).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return money.Money{}, fmt.Errorf("user %d not found: %w", ID, err)
+		}
+		return money.Money{}, fmt.Errorf("error retrieving user hourly rate: %w", err)
+	}
+	return user.HourlyRate, nil
+}
+
+func (u UserRepository) UpdateHourlyRate(ctx context.Context, ID int, rate money.Money) error {
+	err := u.db.WithContext(ctx).Model(&entities.User{}).Where("id = ?", ID).
+		Updates(map[string]interface{}{"hourly_rate_amount": rate.Amount, "hourly_rate_currency": rate.Currency}).Error
+	if err != nil {
+		return fmt.Errorf("error updating user hourly rate: %w", err)
+	}
+	return nil
+}
+
 // NewUserRepository constructor
 func NewUserRepository(db *gorm.DB) IUserRepository {
 	return &UserRepository{base: base{db: db}}
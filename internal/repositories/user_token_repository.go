@@ -0,0 +1,118 @@
+package repositories
+
+import (
+	"Backend_golang_project/internal/domain/entities"
+	"context"
+	"errors"
+	"fmt"
+	"gorm.io/gorm"
+	"time"
+)
+
+var ErrUserTokenInvalid = errors.New("token is invalid, expired or already used")
+
+type IUserTokenRepository interface {
+	Create(ctx context.Context, token *entities.UserToken) error
+	// Consume đánh dấu token đã dùng, trả về ErrUserTokenInvalid nếu token không tồn tại, hết hạn hoặc đã dùng
+	Consume(ctx context.Context, purpose string, tokenHash string, now time.Time) (*entities.UserToken, error)
+	// ResetPassword dùng token đặt lại mật khẩu và ghi mật khẩu mới của user trong cùng một transaction,
+	// lỗi khi ghi mật khẩu thì token vẫn còn dùng được; user không còn tồn tại thì trả về ErrUserTokenInvalid
+	ResetPassword(ctx context.Context, tokenHash string, passwordHash string, now time.Time) (*entities.UserToken, error)
+	// LatestActive token còn hiệu lực được tạo gần nhất của user, nil nếu không có
+	LatestActive(ctx context.Context, userID int, purpose string, now time.Time) (*entities.UserToken, error)
+	InvalidateForUser(ctx context.Context, userID int, purpose string, now time.Time) error
+}
+
+type UserTokenRepository struct {
+	base
+}
+
+func (r UserTokenRepository) Create(ctx context.Context, token *entities.UserToken) error {
+	if err := r.db.WithContext(ctx).Create(token).Error; err != nil {
+		return fmt.Errorf("error saving user token: %w", err)
+	}
+	return nil
+}
+
+// Consume dùng UPDATE có điều kiện used_at IS NULL để hai request dùng cùng token thì chỉ một request thành công
+func (r UserTokenRepository) Consume(ctx context.Context, purpose string, tokenHash string, now time.Time) (*entities.UserToken, error) {
+	return consumeToken(r.db.WithContext(ctx), purpose, tokenHash, now)
+}
+
+func (r UserTokenRepository) ResetPassword(ctx context.Context, tokenHash string, passwordHash string, now time.Time) (*entities.UserToken, error) {
+	var token *entities.UserToken
+	err := r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		var err error
+		token, err = consumeToken(tx, entities.UserTokenPasswordReset, tokenHash, now)
+		if err != nil {
+			return err
+		}
+		result := tx.Model(&entities.User{}).Where("id = ?", token.UserID).Update("password", passwordHash)
+		if result.Error != nil {
+			return fmt.Errorf("error updating user password: %w", result.Error)
+		}
+		// user đã bị xóa sau khi token được phát hành
+		if result.RowsAffected == 0 {
+			return ErrUserTokenInvalid
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	return token, nil
+}
+
+func consumeToken(tx *gorm.DB, purpose string, tokenHash string, now time.Time) (*entities.UserToken, error) {
+	var token entities.UserToken
+	err := tx.Where("token_hash = ? AND purpose = ?", tokenHash, purpose).First(&token).Error
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, ErrUserTokenInvalid
+		}
+		return nil, fmt.Errorf("error retrieving user token: %w", err)
+	}
+	if token.UsedAt != nil || !now.Before(token.ExpiresAt) {
+		return nil, ErrUserTokenInvalid
+	}
+
+	result := tx.Model(&entities.UserToken{}).
+		Where("id = ? AND used_at IS NULL", token.ID).
+		Update("used_at", now)
+	if result.Error != nil {
+		return nil, fmt.Errorf("error consuming user token: %w", result.Error)
+	}
+	if result.RowsAffected == 0 {
+		return nil, ErrUserTokenInvalid
+	}
+	token.UsedAt = &now
+	return &token, nil
+}
+
+func (r UserTokenRepository) LatestActive(ctx context.Context, userID int, purpose string, now time.Time) (*entities.UserToken, error) {
+	var token entities.UserToken
+	err := r.db.WithContext(ctx).
+		Where("user_id = ? AND purpose = ? AND used_at IS NULL AND expires_at > ?", userID, purpose, now).
+		Order("created_at DESC").
+		First(&token).Error
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, nil
+		}
+		return nil, fmt.Errorf("error retrieving user token: %w", err)
+	}
+	return &token, nil
+}
+
+// InvalidateForUser đánh dấu mọi token chưa dùng của user là đã dùng, gọi trước khi phát hành token mới
+// để chỉ link trong email mới nhất còn hiệu lực
+func (r UserTokenRepository) InvalidateForUser(ctx context.Context, userID int, purpose string, now time.Time) error {
+	return r.db.WithContext(ctx).Model(&entities.UserToken{}).
+		Where("user_id = ? AND purpose = ? AND used_at IS NULL", userID, purpose).
+		Update("used_at", now).Error
+}
+
+// NewUserTokenRepository constructor
+func NewUserTokenRepository(db *gorm.DB) IUserTokenRepository {
+	return &UserTokenRepository{base: base{db: db}}
+}
@@ -0,0 +1,215 @@
+package use_cases
+
+import (
+	"Backend_golang_project/infrastructure/config"
+	"Backend_golang_project/infrastructure/mailer"
+	dto "Backend_golang_project/internal/domain/dto/request"
+	"Backend_golang_project/internal/domain/entities"
+	"Backend_golang_project/internal/pkg"
+	"Backend_golang_project/internal/repositories"
+	"context"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	log "github.com/sirupsen/logrus"
+	"gorm.io/gorm"
+	"net/url"
+	"strings"
+	"time"
+)
+
+// verificationResendInterval mỗi lần đăng nhập khi chưa xác thực sẽ gửi lại email,
+// nhưng không gửi quá một thư trong khoảng thời gian này
+const verificationResendInterval = time.Minute
+
+// backgroundMailTimeout thời gian tối đa cho một lần gửi mail chạy ngoài request
+const backgroundMailTimeout = 30 * time.Second
+
+type IAccountService interface {
+	ForgotPassword(ctx context.Context, req dto.ForgotPasswordRequest) error
+	ResetPassword(ctx context.Context, req dto.ResetPasswordRequest) error
+	SendVerification(ctx context.Context, user *entities.User) error
+	VerifyEmail(ctx context.Context, req dto.VerifyEmailRequest) error
+}
+
+type AccountService struct {
+	config          *config.Config
+	userRepository  repositories.IUserRepository
+	tokenRepository repositories.IUserTokenRepository
+	tokenStore      repositories.IRefreshTokenStore
+	attemptStore    repositories.ILoginAttemptStore
+	mailer          mailer.Mailer
+	hasher          *pkg.PasswordHasher
+}
+
+// ForgotPassword luôn trả về nil và tìm user, phát hành token, gửi email ở goroutine riêng để
+// status code lẫn thời gian phản hồi không lộ email nào đã đăng ký; lỗi chỉ được ghi log
+func (s *AccountService) ForgotPassword(ctx context.Context, req dto.ForgotPasswordRequest) error {
+	background, cancel := context.WithTimeout(pkg.Detach(ctx), backgroundMailTimeout)
+	go func() {
+		defer cancel()
+		s.sendPasswordReset(background, req.Email)
+	}()
+	return nil
+}
+
+func (s *AccountService) sendPasswordReset(ctx context.Context, email string) {
+	user, err := s.userRepository.GetUserByEmail(ctx, email)
+	if err != nil || user == nil {
+		if err != nil && !errors.Is(err, gorm.ErrRecordNotFound) {
+			log.WithField("email", email).Error("Cannot load user for password reset ", err)
+			return
+		}
+		log.WithField("email", email).Info("Password reset requested for unknown email")
+		return
+	}
+
+	ttl := time.Duration(s.config.Mail.ResetTokenTTLMinutes) * time.Minute
+	if ttl <= 0 {
+		ttl = 30 * time.Minute
+	}
+	token, err := s.issueToken(ctx, user.ID, entities.UserTokenPasswordReset, ttl)
+	if err != nil {
+		log.WithField("user_id", user.ID).Error("Cannot issue password reset token ", err)
+		return
+	}
+
+	err = s.mailer.Send(ctx, mailer.Message{
+		To:      user.Email,
+		Subject: "Reset your password",
+		Body: fmt.Sprintf("Hi %s,\n\nOpen the link below to choose a new password. It expires in %s and can only be used once.\n\n%s\n\nIf you did not ask for a password reset you can ignore this email.\n",
+			user.Username, ttl, s.link("/reset-password", token)),
+	})
+	if err != nil {
+		log.WithField("user_id", user.ID).Error("Cannot send password reset email ", err)
+	}
+}
+
+// ResetPassword dùng token và đổi mật khẩu trong cùng một transaction rồi thu hồi mọi refresh token của user,
+// các phiên đang đăng nhập phải đăng nhập lại
+func (s *AccountService) ResetPassword(ctx context.Context, req dto.ResetPasswordRequest) error {
+	hash, err := s.hasher.Hash(req.Password)
+	if err != nil {
+		return err
+	}
+	token, err := s.tokenRepository.ResetPassword(ctx, hashUserToken(req.Token), hash, time.Now())
+	if err != nil {
+		if errors.Is(err, repositories.ErrUserTokenInvalid) {
+			return ErrInvalidUserToken
+		}
+		return err
+	}
+	if err := s.tokenStore.RevokeAllForUser(ctx, token.UserID); err != nil {
+		log.Error("Cannot revoke sessions after password reset ", err)
+		return err
+	}
+
+	// user đã chứng minh sở hữu email nên gỡ khóa đăng nhập theo email
+	if user, err := s.userRepository.GetUserById(ctx, token.UserID); err == nil {
+		if err := s.attemptStore.Reset(ctx, emailAttemptKey(user.Email)); err != nil {
+			log.Error("Cannot reset login attempts ", err)
+		}
+	}
+
+	log.WithField("user_id", token.UserID).Info("Password reset")
+	return nil
+}
+
+func (s *AccountService) SendVerification(ctx context.Context, user *entities.User) error {
+	latest, err := s.tokenRepository.LatestActive(ctx, user.ID, entities.UserTokenEmailVerification, time.Now())
+	if err != nil {
+		return err
+	}
+	if latest != nil && time.Since(latest.CreatedAt) < verificationResendInterval {
+		return nil
+	}
+
+	ttl := time.Duration(s.config.Mail.VerificationTokenTTLHours) * time.Hour
+	if ttl <= 0 {
+		ttl = 24 * time.Hour
+	}
+	token, err := s.issueToken(ctx, user.ID, entities.UserTokenEmailVerification, ttl)
+	if err != nil {
+		return err
+	}
+
+	return s.mailer.Send(ctx, mailer.Message{
+		To:      user.Email,
+		Subject: "Verify your email address",
+		Body: fmt.Sprintf("Hi %s,\n\nPlease confirm your email address by opening the link below. It expires in %s.\n\n%s\n",
+			user.Username, ttl, s.link("/verify-email", token)),
+	})
+}
+
+func (s *AccountService) VerifyEmail(ctx context.Context, req dto.VerifyEmailRequest) error {
+	now := time.Now()
+	token, err := s.tokenRepository.Consume(ctx, entities.UserTokenEmailVerification, hashUserToken(req.Token), now)
+	if err != nil {
+		if errors.Is(err, repositories.ErrUserTokenInvalid) {
+			return ErrInvalidUserToken
+		}
+		return err
+	}
+	if err := s.userRepository.MarkEmailVerified(ctx, token.UserID, now); err != nil {
+		return err
+	}
+
+	log.WithField("user_id", token.UserID).Info("Email verified")
+	return nil
+}
+
+// issueToken vô hiệu các token cũ cùng mục đích rồi lưu hash của token mới, token gốc chỉ nằm trong email
+func (s *AccountService) issueToken(ctx context.Context, userID int, purpose string, ttl time.Duration) (string, error) {
+	now := time.Now()
+	if err := s.tokenRepository.InvalidateForUser(ctx, userID, purpose, now); err != nil {
+		return "", err
+	}
+
+	raw := make([]byte, 32)
+	if _, err := rand.Read(raw); err != nil {
+		return "", err
+	}
+	token := base64.RawURLEncoding.EncodeToString(raw)
+
+	err := s.tokenRepository.Create(ctx, &entities.UserToken{
+		UserID:    userID,
+		Purpose:   purpose,
+		TokenHash: hashUserToken(token),
+		ExpiresAt: now.Add(ttl),
+	})
+	if err != nil {
+		return "", err
+	}
+	return token, nil
+}
+
+func (s *AccountService) link(path string, token string) string {
+	return strings.TrimRight(s.config.Mail.AppBaseURL, "/") + path + "?token=" + url.QueryEscape(token)
+}
+
+func hashUserToken(token string) string {
+	sum := sha256.Sum256([]byte(token))
+	return hex.EncodeToString(sum[:])
+}
+
+func NewAccountService(
+	config *config.Config,
+	userRepository repositories.IUserRepository,
+	tokenRepository repositories.IUserTokenRepository,
+	tokenStore repositories.IRefreshTokenStore,
+	attemptStore repositories.ILoginAttemptStore,
+	mailer mailer.Mailer,
+) IAccountService {
+	return &AccountService{
+		config:          config,
+		userRepository:  userRepository,
+		tokenRepository: tokenRepository,
+		tokenStore:      tokenStore,
+		attemptStore:    attemptStore,
+		mailer:          mailer,
+		hasher:          newPasswordHasher(config.Password),
+	}
+}
@@ -0,0 +1,70 @@
+package use_cases
+
+import (
+	"Backend_golang_project/internal/domain/entities"
+	"Backend_golang_project/internal/pkg"
+	"Backend_golang_project/internal/repositories"
+	"context"
+	"fmt"
+	"time"
+)
+
+// AuditFilter điều kiện lọc audit log theo entity, người thực hiện và khoảng thời gian [From, To)
+type AuditFilter struct {
+	EntityType string
+	EntityID   int
+	ActorID    int
+	Action     string
+	From       *time.Time
+	To         *time.Time
+}
+
+type IAuditService interface {
+	ListEvents(ctx context.Context, filter AuditFilter, page pkg.PageRequest) (*pkg.Pagination[entities.AuditEvent], error)
+}
+
+type AuditService struct {
+	repository   repositories.IAuditRepository
+	cursorSigner *pkg.CursorSigner
+}
+
+// ListEvents audit event mới nhất trước, cursor chỉ chứa id của event biên
+func (s *AuditService) ListEvents(ctx context.Context, filter AuditFilter, page pkg.PageRequest) (*pkg.Pagination[entities.AuditEvent], error) {
+	if filter.EntityID > 0 && filter.EntityType == "" {
+		return nil, fmt.Errorf("%w: entity_id requires entity_type", ErrInvalidAuditFilter)
+	}
+	if filter.From != nil && filter.To != nil && !filter.From.Before(*filter.To) {
+		return nil, fmt.Errorf("%w: from must be before to", ErrInvalidAuditFilter)
+	}
+
+	const sort = "-id"
+	spec, err := pageSpec(s.cursorSigner, page, sort, []cursorKind{cursorInt})
+	if err != nil {
+		return nil, err
+	}
+	events, err := s.repository.List(ctx, repositories.AuditQuery{
+		EntityType: filter.EntityType,
+		EntityID:   filter.EntityID,
+		ActorID:    filter.ActorID,
+		Action:     filter.Action,
+		From:       filter.From,
+		To:         filter.To,
+	}, spec)
+	if err != nil {
+		return nil, err
+	}
+	err = setPageCursors(s.cursorSigner, events, sort, func(event entities.AuditEvent) []interface{} {
+		return []interface{}{event.ID}
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to encode page cursor: %w", err)
+	}
+	return events, nil
+}
+
+func NewAuditService(repository repositories.IAuditRepository, cursorSigner *pkg.CursorSigner) IAuditService {
+	return &AuditService{
+		repository:   repository,
+		cursorSigner: cursorSigner,
+	}
+}
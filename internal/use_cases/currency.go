@@ -0,0 +1,32 @@
+package use_cases
+
+import (
+	"Backend_golang_project/infrastructure/exchange"
+	"Backend_golang_project/internal/domain/entities"
+	"Backend_golang_project/internal/domain/money"
+	"context"
+	"fmt"
+)
+
+// convertProjectMoney quy đổi các khoản tiền của project sang currency để hiển thị, không ghi lại vào DB
+func convertProjectMoney(ctx context.Context, rates exchange.RateProvider, project *entities.Project, currency string) error {
+	for _, amount := range []*money.Money{&project.Budget, &project.ProjectSpend, &project.ProjectVariance, &project.RevenueRecognised} {
+		converted, err := convertMoney(ctx, rates, *amount, currency)
+		if err != nil {
+			return err
+		}
+		*amount = converted
+	}
+	return nil
+}
+
+func convertMoney(ctx context.Context, rates exchange.RateProvider, amount money.Money, currency string) (money.Money, error) {
+	if amount.Currency == currency {
+		return amount, nil
+	}
+	rate, err := rates.Rate(ctx, amount.Currency, currency)
+	if err != nil {
+		return amount, fmt.Errorf("%w: %v", ErrCurrencyConversion, err)
+	}
+	return amount.Convert(currency, rate)
+}
@@ -0,0 +1,41 @@
+package use_cases
+
+import (
+	"Backend_golang_project/internal/repositories"
+	"errors"
+)
+
+var (
+	ErrForbidden              = errors.New("you do not have permission to perform this action")
+	ErrProjectAccessDenied    = errors.New("you do not have access to this project")
+	ErrInvalidProjectRole     = errors.New("invalid project role")
+	ErrLastProjectOwner       = errors.New("a project must keep at least one owner")
+	ErrInvalidProjectFilter   = errors.New("invalid project filter")
+	ErrInvalidCursor          = errors.New("invalid page cursor")
+	ErrInvalidTransition      = errors.New("project status transition is not allowed")
+	ErrFinancialsLocked       = errors.New("financial fields of a completed or archived project cannot be changed")
+	ErrInvalidTransaction     = errors.New("invalid project transaction")
+	ErrCurrencyConversion     = errors.New("cannot convert currency")
+	ErrInvalidRevenueSchedule = errors.New("invalid revenue schedule")
+	ErrProjectNotBillable     = errors.New("revenue recognition is only available for projects in a billable category")
+	ErrInvalidWorkItem        = errors.New("invalid milestone or task")
+	ErrInvalidTimesheet       = errors.New("invalid timesheet entry")
+	ErrTimesheetLocked        = errors.New("timesheet cannot be changed in its current status")
+	ErrInvalidCategory        = errors.New("invalid project category")
+	ErrCategoryInUse          = errors.New("project category is used by existing projects")
+	ErrCategoryAlreadyExists  = errors.New("project category already exists")
+	ErrInvalidAuditFilter     = errors.New("invalid audit log filter")
+	ErrInvalidRole            = errors.New("role does not exist")
+	// ErrLastAdmin được kiểm tra trong transaction của repository
+	ErrLastAdmin = repositories.ErrLastAdmin
+	// ErrUserOwnsProjects user phải trao quyền owner các project của mình trước khi xóa tài khoản
+	ErrUserOwnsProjects = repositories.ErrUserOwnsProjects
+
+	ErrInvalidCredentials = errors.New("invalid email or password")
+	ErrAccountLocked      = errors.New("too many failed login attempts")
+	ErrEmailNotVerified   = errors.New("email address is not verified, a verification link has been sent")
+	ErrInvalidUserToken   = errors.New("token is invalid, expired or already used")
+
+	ErrInvalidRefreshToken = errors.New("invalid refresh token")
+	ErrRefreshTokenReused  = errors.New("refresh token has already been used, all sessions of this login were revoked")
+)
@@ -0,0 +1,137 @@
+package use_cases
+
+import (
+	"Backend_golang_project/infrastructure/config"
+	"Backend_golang_project/internal/repositories"
+	"context"
+	"fmt"
+	log "github.com/sirupsen/logrus"
+	"strings"
+	"time"
+)
+
+// LockedError được trả về khi email hoặc IP đang bị khóa, RetryAfter dùng cho header Retry-After
+type LockedError struct {
+	// ByIP true nếu IP bị khóa, false nếu tài khoản (email) bị khóa
+	ByIP       bool
+	RetryAfter time.Duration
+}
+
+func (e *LockedError) Error() string {
+	if e.ByIP {
+		return fmt.Sprintf("too many failed login attempts from this address, retry after %s", e.RetryAfter.Round(time.Second))
+	}
+	return fmt.Sprintf("account is temporarily locked, retry after %s", e.RetryAfter.Round(time.Second))
+}
+
+func (e *LockedError) Is(target error) bool {
+	return target == ErrAccountLocked
+}
+
+// loginThrottle đếm số lần đăng nhập sai theo email và theo IP
+type loginThrottle struct {
+	store  repositories.ILoginAttemptStore
+	config config.LoginThrottle
+	now    func() time.Time
+}
+
+func newLoginThrottle(store repositories.ILoginAttemptStore, cfg config.LoginThrottle) *loginThrottle {
+	if cfg.MaxFailuresPerEmail <= 0 {
+		cfg.MaxFailuresPerEmail = 5
+	}
+	if cfg.MaxFailuresPerIP <= 0 {
+		cfg.MaxFailuresPerIP = 20
+	}
+	if cfg.FailureWindowMinutes <= 0 {
+		cfg.FailureWindowMinutes = 15
+	}
+	if cfg.BaseLockoutSeconds <= 0 {
+		cfg.BaseLockoutSeconds = 30
+	}
+	if cfg.MaxLockoutSeconds <= 0 {
+		cfg.MaxLockoutSeconds = 3600
+	}
+	return &loginThrottle{store: store, config: cfg, now: time.Now}
+}
+
+func emailAttemptKey(email string) string {
+	return "email:" + strings.ToLower(strings.TrimSpace(email))
+}
+
+func ipAttemptKey(ip string) string {
+	return "ip:" + ip
+}
+
+// check trả về *LockedError nếu email hoặc IP đang bị khóa
+func (t *loginThrottle) check(ctx context.Context, email string, ip string) error {
+	now := t.now()
+	for _, key := range t.keys(email, ip) {
+		attempt, err := t.store.Get(ctx, key)
+		if err != nil {
+			return err
+		}
+		if attempt != nil && attempt.IsLocked(now) {
+			return &LockedError{ByIP: strings.HasPrefix(key, "ip:"), RetryAfter: attempt.LockedUntil.Sub(now)}
+		}
+	}
+	return nil
+}
+
+// registerFailure tăng bộ đếm của email và IP, khóa khóa nào vượt ngưỡng
+func (t *loginThrottle) registerFailure(ctx context.Context, email string, ip string) error {
+	now := t.now()
+	window := time.Duration(t.config.FailureWindowMinutes) * time.Minute
+	for _, key := range t.keys(email, ip) {
+		attempt, err := t.store.RegisterFailure(ctx, key, now, window)
+		if err != nil {
+			return err
+		}
+
+		maxFailures := t.config.MaxFailuresPerEmail
+		if strings.HasPrefix(key, "ip:") {
+			maxFailures = t.config.MaxFailuresPerIP
+		}
+		if attempt.Failures < maxFailures {
+			continue
+		}
+
+		lockout := t.lockoutFor(attempt.Failures - maxFailures)
+		if err := t.store.Lock(ctx, key, now.Add(lockout)); err != nil {
+			return err
+		}
+		log.WithFields(log.Fields{
+			"key":      key,
+			"failures": attempt.Failures,
+			"lockout":  lockout.String(),
+		}).Warn("Locked login after repeated failures")
+	}
+	return nil
+}
+
+// reset chỉ xóa bộ đếm của email, bộ đếm của IP giữ nguyên để một tài khoản hợp lệ
+// không thể dùng để xóa dấu vết dò mật khẩu các tài khoản khác từ cùng IP
+func (t *loginThrottle) reset(ctx context.Context, email string) error {
+	return t.store.Reset(ctx, emailAttemptKey(email))
+}
+
+// lockoutFor thời gian khóa tăng gấp đôi sau mỗi lần sai vượt ngưỡng: base, 2*base, 4*base, ... tối đa max
+func (t *loginThrottle) lockoutFor(overLimit int) time.Duration {
+	base := time.Duration(t.config.BaseLockoutSeconds) * time.Second
+	maxLockout := time.Duration(t.config.MaxLockoutSeconds) * time.Second
+	lockout := base
+	for i := 0; i < overLimit && lockout < maxLockout; i++ {
+		lockout *= 2
+	}
+	if lockout > maxLockout {
+		lockout = maxLockout
+	}
+	return lockout
+}
+
+func (t *loginThrottle) keys(email string, ip string) []string {
+	keys := []string{emailAttemptKey(email)}
+	if ip != "" {
+		keys = append(keys, ipAttemptKey(ip))
+	}
+	return keys
+}
@@ -0,0 +1,119 @@
+package use_cases
+
+import (
+	"Backend_golang_project/internal/pkg"
+	"Backend_golang_project/internal/repositories"
+	"encoding/json"
+	"fmt"
+	"strings"
+	"time"
+)
+
+// cursorKind kiểu của một giá trị trong cursor, cần để giải mã lại đúng kiểu từ JSON
+// (số đi ra là json.Number, thời gian đi ra là chuỗi RFC 3339)
+type cursorKind int
+
+const (
+	cursorInt cursorKind = iota
+	cursorString
+	cursorTime
+)
+
+// pageCursor nội dung của next_cursor/prev_cursor, Sort là chuỗi sắp xếp lúc tạo cursor
+// để cursor không bị dùng lại với một thứ tự sắp xếp khác
+type pageCursor struct {
+	Sort     string        `json:"s"`
+	Values   []interface{} `json:"v"`
+	Backward bool          `json:"b,omitempty"`
+}
+
+// sortKey chuỗi chuẩn của các cột sắp xếp, ví dụ "-project_spend,name,id"
+func sortKey(columns []repositories.SortColumn) string {
+	fields := make([]string, 0, len(columns))
+	for _, column := range columns {
+		if column.Desc {
+			fields = append(fields, "-"+column.Column)
+		} else {
+			fields = append(fields, column.Column)
+		}
+	}
+	return strings.Join(fields, ",")
+}
+
+// pageSpec chuyển PageRequest thành repositories.PageSpec, giải mã cursor nếu có
+func pageSpec(signer *pkg.CursorSigner, page pkg.PageRequest, sort string, kinds []cursorKind) (repositories.PageSpec, error) {
+	spec := repositories.PageSpec{Page: page.Page, PageSize: page.PageSize, IncludeTotal: page.IncludeTotal}
+	if spec.Page < 1 {
+		spec.Page = 1
+	}
+	if spec.PageSize < 1 {
+		spec.PageSize = 10
+	}
+	if page.Cursor == "" {
+		return spec, nil
+	}
+
+	var cursor pageCursor
+	if err := signer.Decode(page.Cursor, &cursor); err != nil {
+		return spec, fmt.Errorf("%w: %v", ErrInvalidCursor, err)
+	}
+	if cursor.Sort != sort || len(cursor.Values) != len(kinds) {
+		return spec, fmt.Errorf("%w: cursor was issued for a different sort order", ErrInvalidCursor)
+	}
+
+	values := make([]interface{}, len(kinds))
+	for i, kind := range kinds {
+		value, err := decodeCursorValue(cursor.Values[i], kind)
+		if err != nil {
+			return spec, fmt.Errorf("%w: %v", ErrInvalidCursor, err)
+		}
+		values[i] = value
+	}
+	spec.Keyset = &repositories.Keyset{Values: values, Backward: cursor.Backward}
+	return spec, nil
+}
+
+func decodeCursorValue(raw interface{}, kind cursorKind) (interface{}, error) {
+	switch kind {
+	case cursorInt:
+		number, ok := raw.(json.Number)
+		if !ok {
+			return nil, fmt.Errorf("expected number, got %T", raw)
+		}
+		return number.Int64()
+	case cursorTime:
+		text, ok := raw.(string)
+		if !ok {
+			return nil, fmt.Errorf("expected time, got %T", raw)
+		}
+		return time.Parse(time.RFC3339Nano, text)
+	default:
+		text, ok := raw.(string)
+		if !ok {
+			return nil, fmt.Errorf("expected string, got %T", raw)
+		}
+		return text, nil
+	}
+}
+
+// setPageCursors tạo next_cursor từ bản ghi cuối và prev_cursor từ bản ghi đầu của trang
+func setPageCursors[T any](signer *pkg.CursorSigner, page *pkg.Pagination[T], sort string, values func(T) []interface{}) error {
+	if len(page.Items) == 0 {
+		return nil
+	}
+	if page.HasNext {
+		cursor, err := signer.Encode(pageCursor{Sort: sort, Values: values(page.Items[len(page.Items)-1])})
+		if err != nil {
+			return err
+		}
+		page.NextCursor = cursor
+	}
+	if page.HasPrev {
+		cursor, err := signer.Encode(pageCursor{Sort: sort, Values: values(page.Items[0]), Backward: true})
+		if err != nil {
+			return err
+		}
+		page.PrevCursor = cursor
+	}
+	return nil
+}
@@ -0,0 +1,115 @@
+package use_cases
+
+import (
+	"Backend_golang_project/infrastructure/config"
+	"Backend_golang_project/infrastructure/eventbus"
+	"Backend_golang_project/infrastructure/mailer"
+	"Backend_golang_project/internal/domain/entities"
+	"Backend_golang_project/internal/domain/events"
+	"Backend_golang_project/internal/pkg"
+	"Backend_golang_project/internal/repositories"
+	"context"
+	"fmt"
+	"time"
+
+	log "github.com/sirupsen/logrus"
+)
+
+// defaultBudgetAlertThreshold cảnh báo khi đã chi hết budget nếu config không đặt ngưỡng
+const defaultBudgetAlertThreshold = 100
+
+// publishOverBudget chỉ phát event khi project vừa vượt ngưỡng (wasOver là trạng thái trước thay đổi),
+// để mỗi bút toán tiếp theo của một project đã vượt ngân sách không gửi thêm cảnh báo
+func (p ProjectService) publishOverBudget(ctx context.Context, wasOver bool, project *entities.Project) {
+	publishOverBudget(ctx, p.events, p.budgetAlertThreshold, wasOver, project)
+}
+
+// publishOverBudget dùng chung cho mọi nơi làm thay đổi spend của project (sổ cái, duyệt timesheet)
+func publishOverBudget(ctx context.Context, bus eventbus.Bus, threshold int, wasOver bool, project *entities.Project) {
+	if wasOver || !project.OverBudget(threshold) {
+		return
+	}
+	bus.Publish(ctx, events.ProjectOverBudget{
+		ProjectID:  project.ID,
+		Name:       project.Name,
+		Budget:     project.Budget,
+		Spend:      project.ProjectSpend,
+		Variance:   project.ProjectVariance,
+		Threshold:  threshold,
+		OccurredAt: time.Now(),
+	})
+}
+
+// budgetAlertThreshold ngưỡng cảnh báo trong config, mặc định defaultBudgetAlertThreshold
+func budgetAlertThreshold(config *config.Config) int {
+	if config.Budget.AlertThreshold <= 0 {
+		return defaultBudgetAlertThreshold
+	}
+	return config.Budget.AlertThreshold
+}
+
+// BudgetAlertSubscriber gửi email cảnh báo vượt ngân sách tới owner và manager của project.
+// InMemoryBus gọi subscriber ngay trong Publish nên việc đọc member và gửi mail chạy ở goroutine riêng
+// trên context tách khỏi request (có timeout), request ghi bút toán không phải chờ SMTP
+type BudgetAlertSubscriber struct {
+	memberRepository repositories.IProjectMemberRepository
+	mailer           mailer.Mailer
+}
+
+func (s *BudgetAlertSubscriber) Handle(ctx context.Context, event eventbus.Event) error {
+	overBudget, ok := event.(events.ProjectOverBudget)
+	if !ok {
+		return fmt.Errorf("unexpected event %T", event)
+	}
+	background, cancel := context.WithTimeout(pkg.Detach(ctx), backgroundMailTimeout)
+	go func() {
+		defer cancel()
+		s.notify(background, overBudget)
+	}()
+	return nil
+}
+
+func (s *BudgetAlertSubscriber) notify(ctx context.Context, overBudget events.ProjectOverBudget) {
+	defer func() {
+		if recovered := recover(); recovered != nil {
+			log.WithFields(log.Fields{
+				"project_id": overBudget.ProjectID,
+				"error":      recovered,
+			}).Error("Over budget alert panicked")
+		}
+	}()
+
+	members, err := s.memberRepository.ListMembers(ctx, overBudget.ProjectID)
+	if err != nil {
+		log.WithFields(log.Fields{
+			"project_id": overBudget.ProjectID,
+			"error":      err,
+		}).Error("Cannot load members for over budget alert")
+		return
+	}
+
+	for _, member := range members {
+		if !member.CanManageMembers() || member.User == nil {
+			continue
+		}
+		err := s.mailer.Send(ctx, mailer.Message{
+			To:      member.User.Email,
+			Subject: fmt.Sprintf("Project %s has used %d%% of its budget", overBudget.Name, overBudget.Threshold),
+			Body: fmt.Sprintf("Hi %s,\n\nProject %s has spent %s of its %s budget (variance %s).\n",
+				member.User.Username, overBudget.Name, overBudget.Spend, overBudget.Budget, overBudget.Variance),
+		})
+		if err != nil {
+			log.WithFields(log.Fields{
+				"project_id": overBudget.ProjectID,
+				"user_id":    member.UserID,
+				"error":      err,
+			}).Error("Cannot send over budget alert")
+		}
+	}
+}
+
+// RegisterBudgetAlerts đăng ký BudgetAlertSubscriber vào bus, gọi qua fx.Invoke khi khởi động
+func RegisterBudgetAlerts(bus eventbus.Bus, memberRepository repositories.IProjectMemberRepository, mailer mailer.Mailer) {
+	subscriber := &BudgetAlertSubscriber{memberRepository: memberRepository, mailer: mailer}
+	bus.Subscribe(events.ProjectOverBudgetEvent, subscriber.Handle)
+}
@@ -0,0 +1,171 @@
+package use_cases
+
+import (
+	"Backend_golang_project/internal/domain/dto/request"
+	"Backend_golang_project/internal/domain/entities"
+	"Backend_golang_project/internal/pkg"
+	"Backend_golang_project/internal/repositories"
+	"context"
+	"errors"
+	"fmt"
+	log "github.com/sirupsen/logrus"
+	"sync"
+	"time"
+)
+
+// categoryCacheTTL validator valid_category đọc category từ bộ nhớ, thay đổi trên instance khác
+// được nhận sau tối đa khoảng thời gian này
+const categoryCacheTTL = time.Minute
+
+type IProjectCategoryService interface {
+	pkg.CategoryCatalog
+	List(ctx context.Context) ([]entities.ProjectCategory, error)
+	Get(ctx context.Context, id int) (*entities.ProjectCategory, error)
+	Create(ctx context.Context, req request.CreateProjectCategoryRequest) (*entities.ProjectCategory, error)
+	Update(ctx context.Context, id int, req request.ProjectCategoryRequest) (*entities.ProjectCategory, error)
+	Delete(ctx context.Context, id int) error
+}
+
+type ProjectCategoryService struct {
+	repository repositories.IProjectCategoryRepository
+
+	mu       sync.RWMutex
+	names    map[string]struct{}
+	loadedAt time.Time
+}
+
+// IsValidCategory dùng cho validator valid_category. Không đọc được bảng project_categories thì dùng danh sách
+// đã cache gần nhất; chưa có cache thì chấp nhận category để lỗi DB được trả về 500 ở bước đọc/ghi project
+// thay vì 400, khóa ngoại fk_projects_category vẫn chặn category không tồn tại
+func (s *ProjectCategoryService) IsValidCategory(name string) bool {
+	names, err := s.categoryNames(context.Background())
+	if err != nil {
+		log.Error("Error loading project categories: ", err)
+		if names == nil {
+			return true
+		}
+	}
+	_, ok := names[name]
+	return ok
+}
+
+func (s *ProjectCategoryService) List(ctx context.Context) ([]entities.ProjectCategory, error) {
+	return s.repository.List(ctx)
+}
+
+func (s *ProjectCategoryService) Get(ctx context.Context, id int) (*entities.ProjectCategory, error) {
+	return s.repository.Get(ctx, id)
+}
+
+func (s *ProjectCategoryService) Create(ctx context.Context, req request.CreateProjectCategoryRequest) (*entities.ProjectCategory, error) {
+	category := req.ToProjectCategoryEntity()
+	if err := validateCategory(category); err != nil {
+		return nil, err
+	}
+
+	created, err := s.repository.Create(ctx, category)
+	if err != nil {
+		return nil, categoryError(err)
+	}
+	s.invalidate()
+	log.WithField("category", created.Name).Info("Created project category")
+	return created, nil
+}
+
+func (s *ProjectCategoryService) Update(ctx context.Context, id int, req request.ProjectCategoryRequest) (*entities.ProjectCategory, error) {
+	category, err := s.repository.Get(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	req.ApplyTo(category)
+	if err := validateCategory(category); err != nil {
+		return nil, err
+	}
+
+	updated, err := s.repository.Update(ctx, category)
+	if err != nil {
+		return nil, err
+	}
+	s.invalidate()
+	log.WithField("category", updated.Name).Info("Updated project category")
+	return updated, nil
+}
+
+func (s *ProjectCategoryService) Delete(ctx context.Context, id int) error {
+	if err := s.repository.Delete(ctx, id); err != nil {
+		return categoryError(err)
+	}
+	s.invalidate()
+	log.WithField("category_id", id).Info("Deleted project category")
+	return nil
+}
+
+func validateCategory(category *entities.ProjectCategory) error {
+	if category.Name == "" {
+		return fmt.Errorf("%w: name is required", ErrInvalidCategory)
+	}
+	if category.DefaultRevenueMethod == "" {
+		return nil
+	}
+	if !category.Billable {
+		return fmt.Errorf("%w: only billable categories have a default revenue method", ErrInvalidCategory)
+	}
+	if !entities.IsValidRecognitionMethod(category.DefaultRevenueMethod) {
+		return fmt.Errorf("%w: unknown revenue method %q", ErrInvalidCategory, category.DefaultRevenueMethod)
+	}
+	return nil
+}
+
+func categoryError(err error) error {
+	switch {
+	case errors.Is(err, repositories.ErrCategoryAlreadyExists):
+		return fmt.Errorf("%w: %v", ErrCategoryAlreadyExists, err)
+	case errors.Is(err, repositories.ErrCategoryInUse):
+		return fmt.Errorf("%w: %v", ErrCategoryInUse, err)
+	}
+	return err
+}
+
+// categoryNames đọc lại bảng khi cache hết hạn; đọc lỗi thì trả về danh sách cũ (nil nếu chưa từng đọc được) kèm lỗi
+func (s *ProjectCategoryService) categoryNames(ctx context.Context) (map[string]struct{}, error) {
+	s.mu.RLock()
+	if s.names != nil && time.Since(s.loadedAt) < categoryCacheTTL {
+		names := s.names
+		s.mu.RUnlock()
+		return names, nil
+	}
+	s.mu.RUnlock()
+
+	categories, err := s.repository.List(ctx)
+	if err != nil {
+		s.mu.RLock()
+		stale := s.names
+		s.mu.RUnlock()
+		return stale, err
+	}
+
+	names := make(map[string]struct{}, len(categories))
+	for _, category := range categories {
+		names[category.Name] = struct{}{}
+	}
+
+	s.mu.Lock()
+	s.names = names
+	s.loadedAt = time.Now()
+	s.mu.Unlock()
+	return names, nil
+}
+
+// invalidate buộc lần kiểm tra tiếp theo đọc lại bảng project_categories,
+// danh sách cũ được giữ lại để dùng khi đọc lại bị lỗi
+func (s *ProjectCategoryService) invalidate() {
+	s.mu.Lock()
+	s.loadedAt = time.Time{}
+	s.mu.Unlock()
+}
+
+func NewProjectCategoryService(repository repositories.IProjectCategoryRepository) IProjectCategoryService {
+	return &ProjectCategoryService{
+		repository: repository,
+	}
+}
@@ -0,0 +1,151 @@
+package use_cases
+
+import (
+	"Backend_golang_project/internal/domain/entities"
+	"Backend_golang_project/internal/domain/money"
+	"Backend_golang_project/internal/repositories"
+	"fmt"
+	"strings"
+	"time"
+)
+
+// projectSortField một trường được phép sort. value lấy giá trị của trường từ project để tạo cursor,
+// nil với cột có thể NULL (project_ended_at): sort theo cột này chỉ phân trang được theo offset
+type projectSortField struct {
+	column string
+	kind   cursorKind
+	value  func(p entities.Project) interface{}
+}
+
+// projectSortColumns whitelist các trường được phép dùng trong sort, key là tên trong query string
+var projectSortColumns = map[string]projectSortField{
+	"id":                 {column: "id", kind: cursorInt, value: func(p entities.Project) interface{} { return p.ID }},
+	"name":               {column: "name", kind: cursorString, value: func(p entities.Project) interface{} { return p.Name }},
+	"category":           {column: "category", kind: cursorString, value: func(p entities.Project) interface{} { return p.Category }},
+	"status":             {column: "status", kind: cursorString, value: func(p entities.Project) interface{} { return p.Status }},
+	"budget":             {column: "budget_amount", kind: cursorInt, value: func(p entities.Project) interface{} { return p.Budget.Amount }},
+	"project_spend":      {column: "project_spend_amount", kind: cursorInt, value: func(p entities.Project) interface{} { return p.ProjectSpend.Amount }},
+	"project_variance":   {column: "project_variance_amount", kind: cursorInt, value: func(p entities.Project) interface{} { return p.ProjectVariance.Amount }},
+	"revenue_recognised": {column: "revenue_recognised_amount", kind: cursorInt, value: func(p entities.Project) interface{} { return p.RevenueRecognised.Amount }},
+	"project_started_at": {column: "project_started_at", kind: cursorTime, value: func(p entities.Project) interface{} { return p.ProjectStartedAt }},
+	"project_ended_at":   {column: "project_ended_at", kind: cursorTime},
+	"created_at":         {column: "created_at", kind: cursorTime, value: func(p entities.Project) interface{} { return p.CreatedAt }},
+	"updated_at":         {column: "updated_at", kind: cursorTime, value: func(p entities.Project) interface{} { return p.UpdatedAt }},
+}
+
+// ProjectFilter điều kiện lọc danh sách project, dùng chung cho API danh sách, export và báo cáo.
+// Sort có dạng "-project_spend,name": dấu '-' là giảm dần, các trường cách nhau bởi dấu phẩy.
+// Currency không phải điều kiện lọc: nếu có, các khoản tiền trong kết quả được quy đổi sang đơn vị này
+type ProjectFilter struct {
+	Category     string
+	Status       string
+	NameContains string
+	StartedFrom  *time.Time
+	StartedTo    *time.Time
+	EndedFrom    *time.Time
+	EndedTo      *time.Time
+	SpendMin     *int
+	SpendMax     *int
+	VarianceMin  *int
+	VarianceMax  *int
+	Sort         string
+	Currency     string
+}
+
+// Query kiểm tra filter và chuyển thành repositories.ProjectQuery, lỗi đều bọc ErrInvalidProjectFilter
+func (f ProjectFilter) Query() (repositories.ProjectQuery, error) {
+	query := repositories.ProjectQuery{
+		Category:     f.Category,
+		Status:       f.Status,
+		NameContains: strings.TrimSpace(f.NameContains),
+		StartedFrom:  f.StartedFrom,
+		StartedTo:    f.StartedTo,
+		EndedFrom:    f.EndedFrom,
+		EndedTo:      f.EndedTo,
+		SpendMin:     f.SpendMin,
+		SpendMax:     f.SpendMax,
+		VarianceMin:  f.VarianceMin,
+		VarianceMax:  f.VarianceMax,
+	}
+
+	if f.StartedFrom != nil && f.StartedTo != nil && f.StartedTo.Before(*f.StartedFrom) {
+		return query, fmt.Errorf("%w: started_to is before started_from", ErrInvalidProjectFilter)
+	}
+	if f.EndedFrom != nil && f.EndedTo != nil && f.EndedTo.Before(*f.EndedFrom) {
+		return query, fmt.Errorf("%w: ended_to is before ended_from", ErrInvalidProjectFilter)
+	}
+	if f.SpendMin != nil && f.SpendMax != nil && *f.SpendMax < *f.SpendMin {
+		return query, fmt.Errorf("%w: spend_max is less than spend_min", ErrInvalidProjectFilter)
+	}
+	if f.VarianceMin != nil && f.VarianceMax != nil && *f.VarianceMax < *f.VarianceMin {
+		return query, fmt.Errorf("%w: variance_max is less than variance_min", ErrInvalidProjectFilter)
+	}
+
+	if f.Currency != "" && !money.IsSupported(f.Currency) {
+		return query, fmt.Errorf("%w: unsupported currency %q", ErrInvalidProjectFilter, f.Currency)
+	}
+
+	sort, err := parseProjectSort(f.Sort)
+	if err != nil {
+		return query, err
+	}
+	query.Sort = sort
+	return query, nil
+}
+
+func parseProjectSort(sort string) ([]repositories.SortColumn, error) {
+	if strings.TrimSpace(sort) == "" {
+		return nil, nil
+	}
+
+	var columns []repositories.SortColumn
+	seen := map[string]bool{}
+	for _, field := range strings.Split(sort, ",") {
+		field = strings.TrimSpace(field)
+		desc := strings.HasPrefix(field, "-")
+		field = strings.TrimPrefix(field, "-")
+
+		sortField, ok := projectSortColumns[field]
+		if !ok {
+			return nil, fmt.Errorf("%w: cannot sort by %q", ErrInvalidProjectFilter, field)
+		}
+		if seen[sortField.column] {
+			continue
+		}
+		seen[sortField.column] = true
+		columns = append(columns, repositories.SortColumn{Column: sortField.column, Desc: desc})
+	}
+	return columns, nil
+}
+
+// projectKeyset các cột sắp xếp thật sự (đã thêm id) cùng kiểu và cách lấy giá trị của chúng để tạo/giải mã cursor.
+// ok = false khi có cột không dùng được cho keyset
+func projectKeyset(sort []repositories.SortColumn) (columns []repositories.SortColumn, kinds []cursorKind, values func(entities.Project) []interface{}, ok bool) {
+	columns = repositories.SortColumns(sort)
+	fields := make([]projectSortField, 0, len(columns))
+	for _, column := range columns {
+		field := projectSortFieldByColumn(column.Column)
+		if field.value == nil {
+			return columns, nil, nil, false
+		}
+		fields = append(fields, field)
+		kinds = append(kinds, field.kind)
+	}
+	values = func(p entities.Project) []interface{} {
+		result := make([]interface{}, 0, len(fields))
+		for _, field := range fields {
+			result = append(result, field.value(p))
+		}
+		return result
+	}
+	return columns, kinds, values, true
+}
+
+func projectSortFieldByColumn(column string) projectSortField {
+	for _, field := range projectSortColumns {
+		if field.column == column {
+			return field
+		}
+	}
+	return projectSortField{}
+}
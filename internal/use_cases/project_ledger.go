@@ -0,0 +1,103 @@
+package use_cases
+
+import (
+	"Backend_golang_project/internal/domain/dto/request"
+	"Backend_golang_project/internal/domain/entities"
+	"Backend_golang_project/internal/domain/money"
+	"Backend_golang_project/internal/pkg"
+	"Backend_golang_project/internal/repositories"
+	"context"
+	"errors"
+	"fmt"
+
+	log "github.com/sirupsen/logrus"
+)
+
+// PostTransaction ghi một bút toán vào sổ cái của project, tổng spend/revenue của project
+// được repository cập nhật trong cùng transaction
+func (p ProjectService) PostTransaction(ctx context.Context, id int, req request.PostTransactionRequest) (*entities.ProjectTransaction, error) {
+	entry := req.ToProjectTransactionEntity(id)
+	if err := validateTransaction(entry); err != nil {
+		return nil, err
+	}
+	if callerID, ok := pkg.UserIDFromContext(ctx); ok {
+		entry.CreatedBy = &callerID
+	}
+
+	project, err := p.ledger.Post(ctx, entry)
+	if err != nil {
+		if errors.Is(err, repositories.ErrProjectClosed) {
+			return nil, fmt.Errorf("%w: %v", ErrFinancialsLocked, err)
+		}
+		if errors.Is(err, money.ErrCurrencyMismatch) {
+			return nil, fmt.Errorf("%w: %v", ErrInvalidTransaction, err)
+		}
+		log.Error("Error posting project transaction: ", err)
+		return nil, err
+	}
+
+	log.WithFields(log.Fields{
+		"project_id":         id,
+		"type":               entry.Type,
+		"target":             entry.Target,
+		"amount":             entry.Amount.String(),
+		"project_spend":      project.ProjectSpend.String(),
+		"revenue_recognised": project.RevenueRecognised.String(),
+	}).Info("Project transaction posted")
+
+	// chỉ bút toán spend mới làm thay đổi mức sử dụng ngân sách
+	if entry.Target == entities.TransactionTypeSpend {
+		before := *project
+		before.ProjectSpend.Amount -= entry.Amount.Amount
+		p.publishOverBudget(ctx, before.OverBudget(p.budgetAlertThreshold), project)
+	}
+	return entry, nil
+}
+
+func (p ProjectService) ListTransactions(ctx context.Context, id int, page pkg.PageRequest) (*pkg.Pagination[entities.ProjectTransaction], error) {
+	if _, err := p.projectRepository.GetById(ctx, id); err != nil {
+		return nil, err
+	}
+
+	sort := sortKey(repositories.TransactionSort)
+	spec, err := pageSpec(p.cursorSigner, page, sort, []cursorKind{cursorTime, cursorInt})
+	if err != nil {
+		return nil, err
+	}
+	transactions, err := p.ledger.List(ctx, id, spec)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list project transactions: %w", err)
+	}
+	err = setPageCursors(p.cursorSigner, transactions, sort, func(entry entities.ProjectTransaction) []interface{} {
+		return []interface{}{entry.EffectiveDate, entry.ID}
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to encode page cursor: %w", err)
+	}
+	return transactions, nil
+}
+
+func validateTransaction(entry *entities.ProjectTransaction) error {
+	switch entry.Type {
+	case entities.TransactionTypeSpend, entities.TransactionTypeRevenue:
+		if entry.Target != entry.Type {
+			return fmt.Errorf("%w: target of a %s entry must be %s", ErrInvalidTransaction, entry.Type, entry.Type)
+		}
+		if entry.Amount.Amount <= 0 {
+			return fmt.Errorf("%w: amount must be positive, use an adjustment to correct a total", ErrInvalidTransaction)
+		}
+	case entities.TransactionTypeAdjustment:
+		if entities.TransactionTarget(entry.Target) == "" {
+			return fmt.Errorf("%w: adjustment needs a target (spend or revenue)", ErrInvalidTransaction)
+		}
+		if entry.Amount.Amount == 0 {
+			return fmt.Errorf("%w: amount must not be zero", ErrInvalidTransaction)
+		}
+	default:
+		return fmt.Errorf("%w: unknown type %q", ErrInvalidTransaction, entry.Type)
+	}
+	if !money.IsSupported(entry.Amount.Currency) {
+		return fmt.Errorf("%w: unsupported currency %q", ErrInvalidTransaction, entry.Amount.Currency)
+	}
+	return nil
+}
@@ -0,0 +1,359 @@
+package use_cases
+
+import (
+	"Backend_golang_project/internal/domain/dto/request"
+	"Backend_golang_project/internal/domain/entities"
+	"Backend_golang_project/internal/pkg"
+	"Backend_golang_project/internal/repositories"
+	"context"
+	"errors"
+	"fmt"
+	log "github.com/sirupsen/logrus"
+	"gorm.io/gorm"
+	"time"
+)
+
+// ProjectPolicy bọc IProjectService và kiểm tra membership của người gọi trong user_projects
+// trước mỗi thao tác đọc/ghi. Role toàn hệ thống có project:read_all (admin, auditor) được đọc mọi project,
+// role có project:manage_all (admin) được sửa/xóa mọi project mà không cần membership
+type ProjectPolicy struct {
+	next              IProjectService
+	projectRepository repositories.IProjectRepository
+	memberRepository  repositories.IProjectMemberRepository
+	userRepository    repositories.IUserRepository
+	roleService       IRoleService
+}
+
+var (
+	readRoles   = []string{entities.ProjectRoleOwner, entities.ProjectRoleManager, entities.ProjectRoleContributor, entities.ProjectRoleViewer}
+	updateRoles = []string{entities.ProjectRoleOwner, entities.ProjectRoleManager}
+	deleteRoles = []string{entities.ProjectRoleOwner}
+	// contributor được tạo và sửa task nhưng không được sửa milestone
+	taskRoles = []string{entities.ProjectRoleOwner, entities.ProjectRoleManager, entities.ProjectRoleContributor}
+)
+
+func (p ProjectPolicy) Create(ctx context.Context, request request.CreateProjectRequest) (*entities.Project, error) {
+	if _, ok := pkg.UserIDFromContext(ctx); !ok {
+		return nil, ErrForbidden
+	}
+	return p.next.Create(ctx, request)
+}
+
+func (p ProjectPolicy) Delete(ctx context.Context, id int) error {
+	if err := p.authorize(ctx, id, deleteRoles, entities.PermissionProjectManageAll); err != nil {
+		return err
+	}
+	return p.next.Delete(ctx, id)
+}
+
+func (p ProjectPolicy) Update(ctx context.Context, id int, request request.UpdateProjectRequest) (*entities.Project, error) {
+	if err := p.authorize(ctx, id, updateRoles, entities.PermissionProjectManageAll); err != nil {
+		return nil, err
+	}
+	return p.next.Update(ctx, id, request)
+}
+
+func (p ProjectPolicy) GetById(ctx context.Context, id int) (*entities.Project, error) {
+	if err := p.authorize(ctx, id, readRoles, entities.PermissionProjectReadAll); err != nil {
+		return nil, err
+	}
+	return p.next.GetById(ctx, id)
+}
+
+func (p ProjectPolicy) GetByIdAsOf(ctx context.Context, id int, asOf time.Time) (*entities.Project, error) {
+	if err := p.authorize(ctx, id, readRoles, entities.PermissionProjectReadAll); err != nil {
+		return nil, err
+	}
+	return p.next.GetByIdAsOf(ctx, id, asOf)
+}
+
+// GetBySlug slug chỉ dùng để tìm project, quyền đọc vẫn kiểm tra theo membership của project tìm được
+func (p ProjectPolicy) GetBySlug(ctx context.Context, ownerID int, slug string) (*entities.Project, error) {
+	project, err := p.projectRepository.GetBySlug(ctx, ownerID, slug)
+	if err != nil {
+		return nil, err
+	}
+	if err := p.authorize(ctx, project.ID, readRoles, entities.PermissionProjectReadAll); err != nil {
+		return nil, err
+	}
+	return p.next.GetBySlug(ctx, ownerID, slug)
+}
+
+// GetProjectList chỉ trả về các project mà người gọi là thành viên, trừ khi họ có project:read_all
+func (p ProjectPolicy) GetProjectList(ctx context.Context, filter ProjectFilter, page pkg.PageRequest) (*pkg.Pagination[entities.Project], error) {
+	callerID, readAll, err := p.caller(ctx, entities.PermissionProjectReadAll)
+	if err != nil {
+		return nil, err
+	}
+	if readAll {
+		return p.next.GetProjectList(ctx, filter, page)
+	}
+	return p.next.GetMemberProjectList(ctx, callerID, filter, page)
+}
+
+func (p ProjectPolicy) GetMemberProjectList(ctx context.Context, userID int, filter ProjectFilter, page pkg.PageRequest) (*pkg.Pagination[entities.Project], error) {
+	callerID, readAll, err := p.caller(ctx, entities.PermissionProjectReadAll)
+	if err != nil {
+		return nil, err
+	}
+	if !readAll && callerID != userID {
+		return nil, ErrForbidden
+	}
+	return p.next.GetMemberProjectList(ctx, userID, filter, page)
+}
+
+// GetTrash chỉ trả về các project mà người gọi là owner, trừ khi họ có project:manage_all
+func (p ProjectPolicy) GetTrash(ctx context.Context, page pkg.PageRequest) (*pkg.Pagination[entities.Project], error) {
+	callerID, manageAll, err := p.caller(ctx, entities.PermissionProjectManageAll)
+	if err != nil {
+		return nil, err
+	}
+	if manageAll {
+		return p.next.GetTrash(ctx, page)
+	}
+	return p.next.GetOwnerTrash(ctx, callerID, page)
+}
+
+func (p ProjectPolicy) GetOwnerTrash(ctx context.Context, userID int, page pkg.PageRequest) (*pkg.Pagination[entities.Project], error) {
+	callerID, manageAll, err := p.caller(ctx, entities.PermissionProjectManageAll)
+	if err != nil {
+		return nil, err
+	}
+	if !manageAll && callerID != userID {
+		return nil, ErrForbidden
+	}
+	return p.next.GetOwnerTrash(ctx, userID, page)
+}
+
+// Restore cùng quyền với Delete, membership vẫn còn khi project nằm trong thùng rác
+func (p ProjectPolicy) Restore(ctx context.Context, id int) (*entities.Project, error) {
+	if err := p.authorize(ctx, id, deleteRoles, entities.PermissionProjectManageAll); err != nil {
+		return nil, err
+	}
+	return p.next.Restore(ctx, id)
+}
+
+// AddMember và RemoveMember tự kiểm tra quyền owner/manager trong ProjectService
+func (p ProjectPolicy) AddMember(ctx context.Context, projectID int, request request.AddProjectMemberRequest) (*entities.UserProject, error) {
+	return p.next.AddMember(ctx, projectID, request)
+}
+
+func (p ProjectPolicy) RemoveMember(ctx context.Context, projectID int, userID int) error {
+	return p.next.RemoveMember(ctx, projectID, userID)
+}
+
+func (p ProjectPolicy) ListMembers(ctx context.Context, projectID int) ([]entities.UserProject, error) {
+	if err := p.authorize(ctx, projectID, readRoles, entities.PermissionProjectReadAll); err != nil {
+		return nil, err
+	}
+	return p.next.ListMembers(ctx, projectID)
+}
+
+func (p ProjectPolicy) Transition(ctx context.Context, id int, request request.ProjectTransitionRequest) (*entities.Project, error) {
+	if err := p.authorize(ctx, id, updateRoles, entities.PermissionProjectManageAll); err != nil {
+		return nil, err
+	}
+	return p.next.Transition(ctx, id, request)
+}
+
+func (p ProjectPolicy) GetStatusHistory(ctx context.Context, id int) ([]entities.ProjectStatusHistory, error) {
+	if err := p.authorize(ctx, id, readRoles, entities.PermissionProjectReadAll); err != nil {
+		return nil, err
+	}
+	return p.next.GetStatusHistory(ctx, id)
+}
+
+func (p ProjectPolicy) ListVersions(ctx context.Context, id int, page pkg.PageRequest) (*pkg.Pagination[entities.ProjectVersion], error) {
+	if err := p.authorize(ctx, id, readRoles, entities.PermissionProjectReadAll); err != nil {
+		return nil, err
+	}
+	return p.next.ListVersions(ctx, id, page)
+}
+
+func (p ProjectPolicy) RestoreVersion(ctx context.Context, id int, version int) (*entities.Project, error) {
+	if err := p.authorize(ctx, id, updateRoles, entities.PermissionProjectManageAll); err != nil {
+		return nil, err
+	}
+	return p.next.RestoreVersion(ctx, id, version)
+}
+
+func (p ProjectPolicy) PostTransaction(ctx context.Context, id int, request request.PostTransactionRequest) (*entities.ProjectTransaction, error) {
+	if err := p.authorize(ctx, id, updateRoles, entities.PermissionProjectManageAll); err != nil {
+		return nil, err
+	}
+	return p.next.PostTransaction(ctx, id, request)
+}
+
+func (p ProjectPolicy) ListTransactions(ctx context.Context, id int, page pkg.PageRequest) (*pkg.Pagination[entities.ProjectTransaction], error) {
+	if err := p.authorize(ctx, id, readRoles, entities.PermissionProjectReadAll); err != nil {
+		return nil, err
+	}
+	return p.next.ListTransactions(ctx, id, page)
+}
+
+func (p ProjectPolicy) SetRevenueSchedule(ctx context.Context, id int, request request.RevenueScheduleRequest) (*entities.RevenueSchedule, error) {
+	if err := p.authorize(ctx, id, updateRoles, entities.PermissionProjectManageAll); err != nil {
+		return nil, err
+	}
+	return p.next.SetRevenueSchedule(ctx, id, request)
+}
+
+func (p ProjectPolicy) GetRevenueSchedule(ctx context.Context, id int) (*entities.RevenueSchedule, error) {
+	if err := p.authorize(ctx, id, readRoles, entities.PermissionProjectReadAll); err != nil {
+		return nil, err
+	}
+	return p.next.GetRevenueSchedule(ctx, id)
+}
+
+func (p ProjectPolicy) PreviewRevenueSchedule(ctx context.Context, id int, request request.RevenueScheduleRequest) ([]RevenuePreviewLine, error) {
+	if err := p.authorize(ctx, id, readRoles, entities.PermissionProjectReadAll); err != nil {
+		return nil, err
+	}
+	return p.next.PreviewRevenueSchedule(ctx, id, request)
+}
+
+func (p ProjectPolicy) RecogniseRevenue(ctx context.Context, id int, request request.RecogniseRevenueRequest) (*entities.ProjectTransaction, error) {
+	if err := p.authorize(ctx, id, updateRoles, entities.PermissionProjectManageAll); err != nil {
+		return nil, err
+	}
+	return p.next.RecogniseRevenue(ctx, id, request)
+}
+
+func (p ProjectPolicy) ListMilestones(ctx context.Context, id int) ([]entities.Milestone, error) {
+	if err := p.authorize(ctx, id, readRoles, entities.PermissionProjectReadAll); err != nil {
+		return nil, err
+	}
+	return p.next.ListMilestones(ctx, id)
+}
+
+func (p ProjectPolicy) GetMilestone(ctx context.Context, id int, milestoneID int) (*entities.Milestone, error) {
+	if err := p.authorize(ctx, id, readRoles, entities.PermissionProjectReadAll); err != nil {
+		return nil, err
+	}
+	return p.next.GetMilestone(ctx, id, milestoneID)
+}
+
+func (p ProjectPolicy) CreateMilestone(ctx context.Context, id int, request request.MilestoneRequest) (*entities.Milestone, error) {
+	if err := p.authorize(ctx, id, updateRoles, entities.PermissionProjectManageAll); err != nil {
+		return nil, err
+	}
+	return p.next.CreateMilestone(ctx, id, request)
+}
+
+func (p ProjectPolicy) UpdateMilestone(ctx context.Context, id int, milestoneID int, request request.MilestoneRequest) (*entities.Milestone, error) {
+	if err := p.authorize(ctx, id, updateRoles, entities.PermissionProjectManageAll); err != nil {
+		return nil, err
+	}
+	return p.next.UpdateMilestone(ctx, id, milestoneID, request)
+}
+
+func (p ProjectPolicy) DeleteMilestone(ctx context.Context, id int, milestoneID int) error {
+	if err := p.authorize(ctx, id, updateRoles, entities.PermissionProjectManageAll); err != nil {
+		return err
+	}
+	return p.next.DeleteMilestone(ctx, id, milestoneID)
+}
+
+func (p ProjectPolicy) ListTasks(ctx context.Context, id int) ([]entities.Task, error) {
+	if err := p.authorize(ctx, id, readRoles, entities.PermissionProjectReadAll); err != nil {
+		return nil, err
+	}
+	return p.next.ListTasks(ctx, id)
+}
+
+func (p ProjectPolicy) GetTask(ctx context.Context, id int, taskID int) (*entities.Task, error) {
+	if err := p.authorize(ctx, id, readRoles, entities.PermissionProjectReadAll); err != nil {
+		return nil, err
+	}
+	return p.next.GetTask(ctx, id, taskID)
+}
+
+func (p ProjectPolicy) CreateTask(ctx context.Context, id int, request request.TaskRequest) (*entities.Task, error) {
+	if err := p.authorize(ctx, id, taskRoles, entities.PermissionProjectManageAll); err != nil {
+		return nil, err
+	}
+	return p.next.CreateTask(ctx, id, request)
+}
+
+func (p ProjectPolicy) UpdateTask(ctx context.Context, id int, taskID int, request request.TaskRequest) (*entities.Task, error) {
+	if err := p.authorize(ctx, id, taskRoles, entities.PermissionProjectManageAll); err != nil {
+		return nil, err
+	}
+	return p.next.UpdateTask(ctx, id, taskID, request)
+}
+
+func (p ProjectPolicy) DeleteTask(ctx context.Context, id int, taskID int) error {
+	if err := p.authorize(ctx, id, taskRoles, entities.PermissionProjectManageAll); err != nil {
+		return err
+	}
+	return p.next.DeleteTask(ctx, id, taskID)
+}
+
+// authorize trả về nil nếu role toàn hệ thống của người gọi có permission bypass
+// hoặc người gọi có một trong các vai trò cho phép trong project
+func (p ProjectPolicy) authorize(ctx context.Context, projectID int, allowedRoles []string, bypass string) error {
+	callerID, granted, err := p.caller(ctx, bypass)
+	if err != nil {
+		return err
+	}
+	if granted {
+		return nil
+	}
+
+	member, err := p.memberRepository.GetMember(ctx, projectID, callerID)
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			log.WithFields(log.Fields{
+				"project_id": projectID,
+				"user_id":    callerID,
+			}).Warn("Denied access to project for non-member")
+			return ErrProjectAccessDenied
+		}
+		return fmt.Errorf("error checking project membership: %w", err)
+	}
+
+	for _, role := range allowedRoles {
+		if member.Role == role {
+			return nil
+		}
+	}
+	return ErrProjectAccessDenied
+}
+
+// caller đọc role hiện tại từ DB thay vì claim roles của token,
+// để việc hạ quyền có hiệu lực ngay mà không phải chờ access token hết hạn
+func (p ProjectPolicy) caller(ctx context.Context, permission string) (int, bool, error) {
+	callerID, ok := pkg.UserIDFromContext(ctx)
+	if !ok {
+		return 0, false, ErrForbidden
+	}
+	role, err := p.userRepository.GetRole(ctx, callerID)
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return 0, false, ErrForbidden
+		}
+		return 0, false, err
+	}
+	granted, err := p.roleService.HasPermission(ctx, []string{role}, permission)
+	if err != nil {
+		return 0, false, err
+	}
+	return callerID, granted, nil
+}
+
+// NewProjectPolicy được đăng ký bằng fx.Decorate để bọc IProjectService gốc
+func NewProjectPolicy(
+	service IProjectService,
+	projectRepository repositories.IProjectRepository,
+	memberRepository repositories.IProjectMemberRepository,
+	userRepository repositories.IUserRepository,
+	roleService IRoleService,
+) IProjectService {
+	return &ProjectPolicy{
+		next:              service,
+		projectRepository: projectRepository,
+		memberRepository:  memberRepository,
+		userRepository:    userRepository,
+		roleService:       roleService,
+	}
+}
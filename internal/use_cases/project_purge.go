@@ -0,0 +1,48 @@
+package use_cases
+
+import (
+	"Backend_golang_project/infrastructure/config"
+	"Backend_golang_project/internal/repositories"
+	"context"
+	"time"
+)
+
+// projectPurgeBatch số project bị xóa hẳn trong mỗi transaction khi dọn thùng rác
+const projectPurgeBatch = 100
+
+type IProjectPurgeService interface {
+	PurgeExpired(ctx context.Context, now time.Time) (int, error)
+}
+
+// ProjectPurgeService xóa hẳn các project đã nằm trong thùng rác quá trash.retentionDays ngày,
+// được job chạy định kỳ gọi nên không qua ProjectPolicy
+type ProjectPurgeService struct {
+	projectRepository repositories.IProjectRepository
+	retention         time.Duration
+}
+
+// PurgeExpired trả về số project đã bị xóa hẳn, retention không dương thì không xóa gì
+func (s *ProjectPurgeService) PurgeExpired(ctx context.Context, now time.Time) (int, error) {
+	if s.retention <= 0 {
+		return 0, nil
+	}
+	before := now.Add(-s.retention)
+	purged := 0
+	for {
+		count, err := s.projectRepository.PurgeDeleted(ctx, before, projectPurgeBatch)
+		purged += count
+		if err != nil {
+			return purged, err
+		}
+		if count < projectPurgeBatch {
+			return purged, nil
+		}
+	}
+}
+
+func NewProjectPurgeService(projectRepository repositories.IProjectRepository, config *config.Config) IProjectPurgeService {
+	return &ProjectPurgeService{
+		projectRepository: projectRepository,
+		retention:         time.Duration(config.Trash.RetentionDays) * 24 * time.Hour,
+	}
+}
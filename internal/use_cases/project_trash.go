@@ -0,0 +1,56 @@
+package use_cases
+
+import (
+	"Backend_golang_project/internal/domain/entities"
+	"Backend_golang_project/internal/pkg"
+	"Backend_golang_project/internal/repositories"
+	"context"
+	"fmt"
+	log "github.com/sirupsen/logrus"
+)
+
+// GetTrash mọi project đang nằm trong thùng rác, bị xóa gần nhất trước
+func (p ProjectService) GetTrash(ctx context.Context, page pkg.PageRequest) (*pkg.Pagination[entities.Project], error) {
+	return p.listTrash(page, func(spec repositories.PageSpec) (*pkg.Pagination[entities.Project], error) {
+		return p.projectRepository.GetTrash(ctx, spec)
+	})
+}
+
+// GetOwnerTrash các project trong thùng rác mà user là owner
+func (p ProjectService) GetOwnerTrash(ctx context.Context, userID int, page pkg.PageRequest) (*pkg.Pagination[entities.Project], error) {
+	return p.listTrash(page, func(spec repositories.PageSpec) (*pkg.Pagination[entities.Project], error) {
+		return p.projectRepository.GetTrashByOwner(ctx, userID, spec)
+	})
+}
+
+func (p ProjectService) listTrash(page pkg.PageRequest,
+	fetch func(spec repositories.PageSpec) (*pkg.Pagination[entities.Project], error)) (*pkg.Pagination[entities.Project], error) {
+	sort := sortKey(repositories.TrashSort)
+	spec, err := pageSpec(p.cursorSigner, page, sort, []cursorKind{cursorTime, cursorInt})
+	if err != nil {
+		return nil, err
+	}
+	projects, err := fetch(spec)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list deleted projects: %w", err)
+	}
+	err = setPageCursors(p.cursorSigner, projects, sort, func(project entities.Project) []interface{} {
+		return []interface{}{project.DeletedAt.Time, project.ID}
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to encode page cursor: %w", err)
+	}
+	return projects, nil
+}
+
+// Restore đưa project ra khỏi thùng rác cùng với toàn bộ membership và dữ liệu của nó
+func (p ProjectService) Restore(ctx context.Context, id int) (*entities.Project, error) {
+	project, err := p.projectRepository.Restore(ctx, id)
+	if err != nil {
+		log.Error("Error restoring project: ", err)
+		return nil, err
+	}
+
+	log.WithField("project_id", id).Info("Project restored from trash")
+	return project, nil
+}
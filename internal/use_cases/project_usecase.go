@@ -1,32 +1,129 @@
 package use_cases
 
 import (
+	"Backend_golang_project/infrastructure/config"
+	"Backend_golang_project/infrastructure/eventbus"
+	"Backend_golang_project/infrastructure/exchange"
 	"Backend_golang_project/internal/domain/dto/request"
 	"Backend_golang_project/internal/domain/entities"
+	"Backend_golang_project/internal/domain/money"
 	"Backend_golang_project/internal/pkg"
 	"Backend_golang_project/internal/repositories"
 	"context"
+	"errors"
 	"fmt"
 	log "github.com/sirupsen/logrus"
+	"gorm.io/gorm"
+	"strings"
+	"time"
 )
 
 type IProjectService interface {
 	Create(ctx context.Context, request request.CreateProjectRequest) (*entities.Project, error)
-	Delete(ctx context.Context, name string) error
+	Delete(ctx context.Context, id int) error
 	Update(ctx context.Context, id int, request request.UpdateProjectRequest) (*entities.Project, error)
 	GetById(ctx context.Context, id int) (*entities.Project, error)
-	GetProjectList(ctx context.Context, page int, pageSize int) (*pkg.Pagination, error)
+	GetByIdAsOf(ctx context.Context, id int, asOf time.Time) (*entities.Project, error)
+	GetBySlug(ctx context.Context, ownerID int, slug string) (*entities.Project, error)
+	GetProjectList(ctx context.Context, filter ProjectFilter, page pkg.PageRequest) (*pkg.Pagination[entities.Project], error)
+	GetMemberProjectList(ctx context.Context, userID int, filter ProjectFilter, page pkg.PageRequest) (*pkg.Pagination[entities.Project], error)
+	AddMember(ctx context.Context, projectID int, request request.AddProjectMemberRequest) (*entities.UserProject, error)
+	RemoveMember(ctx context.Context, projectID int, userID int) error
+	ListMembers(ctx context.Context, projectID int) ([]entities.UserProject, error)
+	Transition(ctx context.Context, id int, request request.ProjectTransitionRequest) (*entities.Project, error)
+	GetStatusHistory(ctx context.Context, id int) ([]entities.ProjectStatusHistory, error)
+	ListVersions(ctx context.Context, id int, page pkg.PageRequest) (*pkg.Pagination[entities.ProjectVersion], error)
+	RestoreVersion(ctx context.Context, id int, version int) (*entities.Project, error)
+	GetTrash(ctx context.Context, page pkg.PageRequest) (*pkg.Pagination[entities.Project], error)
+	GetOwnerTrash(ctx context.Context, userID int, page pkg.PageRequest) (*pkg.Pagination[entities.Project], error)
+	Restore(ctx context.Context, id int) (*entities.Project, error)
+	PostTransaction(ctx context.Context, id int, request request.PostTransactionRequest) (*entities.ProjectTransaction, error)
+	ListTransactions(ctx context.Context, id int, page pkg.PageRequest) (*pkg.Pagination[entities.ProjectTransaction], error)
+	SetRevenueSchedule(ctx context.Context, id int, request request.RevenueScheduleRequest) (*entities.RevenueSchedule, error)
+	GetRevenueSchedule(ctx context.Context, id int) (*entities.RevenueSchedule, error)
+	PreviewRevenueSchedule(ctx context.Context, id int, request request.RevenueScheduleRequest) ([]RevenuePreviewLine, error)
+	RecogniseRevenue(ctx context.Context, id int, request request.RecogniseRevenueRequest) (*entities.ProjectTransaction, error)
+	ListMilestones(ctx context.Context, id int) ([]entities.Milestone, error)
+	GetMilestone(ctx context.Context, id int, milestoneID int) (*entities.Milestone, error)
+	CreateMilestone(ctx context.Context, id int, request request.MilestoneRequest) (*entities.Milestone, error)
+	UpdateMilestone(ctx context.Context, id int, milestoneID int, request request.MilestoneRequest) (*entities.Milestone, error)
+	DeleteMilestone(ctx context.Context, id int, milestoneID int) error
+	ListTasks(ctx context.Context, id int) ([]entities.Task, error)
+	GetTask(ctx context.Context, id int, taskID int) (*entities.Task, error)
+	CreateTask(ctx context.Context, id int, request request.TaskRequest) (*entities.Task, error)
+	UpdateTask(ctx context.Context, id int, taskID int, request request.TaskRequest) (*entities.Task, error)
+	DeleteTask(ctx context.Context, id int, taskID int) error
 }
 
 type ProjectService struct {
 	projectRepository repositories.IProjectRepository
+	memberRepository  repositories.IProjectMemberRepository
+	ledger            repositories.IProjectTransactionRepository
+	schedules         repositories.IRevenueScheduleRepository
+	work              repositories.IProjectWorkRepository
+	categories        repositories.IProjectCategoryRepository
+	cursorSigner      *pkg.CursorSigner
+	rates             exchange.RateProvider
+	events            eventbus.Bus
+
+	budgetAlertThreshold int
+}
+
+func (p ProjectService) GetProjectList(ctx context.Context, filter ProjectFilter, page pkg.PageRequest) (*pkg.Pagination[entities.Project], error) {
+	return p.listProjects(ctx, filter, page, func(query repositories.ProjectQuery, spec repositories.PageSpec) (*pkg.Pagination[entities.Project], error) {
+		return p.projectRepository.GetList(ctx, query, spec)
+	})
 }
 
-func (p ProjectService) GetProjectList(ctx context.Context, page int, pageSize int) (*pkg.Pagination, error) {
-	pagination, err := p.projectRepository.GetList(ctx, page, pageSize)
+func (p ProjectService) GetMemberProjectList(ctx context.Context, userID int, filter ProjectFilter, page pkg.PageRequest) (*pkg.Pagination[entities.Project], error) {
+	return p.listProjects(ctx, filter, page, func(query repositories.ProjectQuery, spec repositories.PageSpec) (*pkg.Pagination[entities.Project], error) {
+		return p.projectRepository.GetListByMember(ctx, userID, query, spec)
+	})
+}
+
+// listProjects phân trang theo cursor nếu client gửi cursor, ngược lại theo page.
+// Trang nào cũng trả về next_cursor/prev_cursor (khi sort cho phép) để client chuyển sang cursor được
+func (p ProjectService) listProjects(ctx context.Context, filter ProjectFilter, page pkg.PageRequest,
+	fetch func(query repositories.ProjectQuery, spec repositories.PageSpec) (*pkg.Pagination[entities.Project], error)) (*pkg.Pagination[entities.Project], error) {
+	query, err := filter.Query()
+	if err != nil {
+		return nil, err
+	}
+
+	columns, kinds, values, keysetOK := projectKeyset(query.Sort)
+	if page.Cursor != "" && !keysetOK {
+		return nil, fmt.Errorf("%w: cursor pagination is not supported for this sort, use page instead", ErrInvalidProjectFilter)
+	}
+	sort := sortKey(columns)
+	spec, err := pageSpec(p.cursorSigner, page, sort, kinds)
+	if err != nil {
+		return nil, err
+	}
+
+	pagination, err := fetch(query, spec)
 	if err != nil {
 		return nil, fmt.Errorf("failed to get project list: %w", err)
 	}
+	if keysetOK {
+		if err := setPageCursors(p.cursorSigner, pagination, sort, values); err != nil {
+			return nil, fmt.Errorf("failed to encode page cursor: %w", err)
+		}
+	}
+	projects := make([]*entities.Project, 0, len(pagination.Items))
+	for i := range pagination.Items {
+		projects = append(projects, &pagination.Items[i])
+	}
+	if err := p.attachProgress(ctx, projects...); err != nil {
+		return nil, fmt.Errorf("failed to get project progress: %w", err)
+	}
+	// quy đổi sau khi tạo cursor vì cursor phải mang giá trị gốc đang lưu trong DB
+	if filter.Currency != "" {
+		for i := range pagination.Items {
+			if err := convertProjectMoney(ctx, p.rates, &pagination.Items[i], filter.Currency); err != nil {
+				return nil, err
+			}
+		}
+	}
 	return pagination, nil
 }
 
@@ -36,6 +133,28 @@ func (p ProjectService) Create(ctx context.Context, request request.CreateProjec
 	if entity.ProjectEndedAt != nil && entity.ProjectEndedAt.Before(entity.ProjectStartedAt) {
 		return nil, fmt.Errorf("project ended time is invalid")
 	}
+	if !money.IsSupported(entity.Currency()) {
+		return nil, fmt.Errorf("%w: %q", money.ErrUnsupportedCurrency, entity.Currency())
+	}
+	entity.ComputeVariance()
+	// người tạo project trở thành owner, được lưu cùng transaction với project
+	var createdBy *int
+	if callerID, ok := pkg.UserIDFromContext(ctx); ok {
+		entity.Members = []entities.UserProject{{UserID: callerID, Role: entities.ProjectRoleOwner}}
+		entity.OwnerID = &callerID
+		createdBy = &callerID
+	}
+	// spend ban đầu được ghi thành bút toán số dư đầu kỳ để tổng luôn khớp với sổ cái
+	if entity.ProjectSpend.Amount != 0 {
+		entity.Transactions = []entities.ProjectTransaction{{
+			Type:          entities.TransactionTypeAdjustment,
+			Target:        entities.TransactionTypeSpend,
+			Amount:        entity.ProjectSpend,
+			EffectiveDate: entity.ProjectStartedAt,
+			Description:   "Opening balance",
+			CreatedBy:     createdBy,
+		}}
+	}
 	data, err := p.projectRepository.Create(ctx, entity)
 	if err != nil {
 		log.Error("Error in service.Create with error: ", err)
@@ -43,45 +162,83 @@ func (p ProjectService) Create(ctx context.Context, request request.CreateProjec
 	}
 
 	log.Info("Project created successfully", entity)
+	p.publishOverBudget(ctx, false, data)
 	return data, nil
 }
 
-func (p ProjectService) Delete(ctx context.Context, name string) error {
-	// Thực hiện xóa project qua repository
-	deleted := p.projectRepository.Delete(ctx, name)
-
-	if deleted {
-		// Nếu xóa thành công, ghi log thành công và trả về nil (không có lỗi)
-		log.WithFields(log.Fields{
-			"name": name,
-		}).Info("Project deleted successfully")
-		return nil
-	} else {
-		err := fmt.Errorf("failed to delete project with name: %s", name)
+// Delete chuyển project vào thùng rác, trả về gorm.ErrRecordNotFound (được bọc) nếu project không tồn tại
+// và repositories.ErrProjectHasPendingTimesheets nếu project còn timesheet chờ duyệt
+func (p ProjectService) Delete(ctx context.Context, id int) error {
+	if err := p.projectRepository.Delete(ctx, id); err != nil {
 		log.WithFields(log.Fields{
-			"name":  name,
-			"error": err,
+			"project_id": id,
+			"error":      err,
 		}).Error("Delete project failed")
 		return err
 	}
+
+	log.WithFields(log.Fields{
+		"project_id": id,
+	}).Info("Project deleted successfully")
+	return nil
 }
 
+// updateAttempts số lần Update đọc lại project và ghi lại khi bị request khác ghi chen vào,
+// chỉ áp dụng khi người gọi không gửi If-Match
+const updateAttempts = 3
+
+// Update ghi đè các trường trong req lên project. Repository chỉ ghi khi project vẫn ở version đã đọc:
+// người gọi có gửi version (If-Match) mà project đã ở version khác thì trả về repositories.ErrProjectVersionConflict,
+// không gửi thì Update đọc lại project và thử lại để không ghi đè thay đổi của request khác bằng giá trị cũ
 func (p ProjectService) Update(ctx context.Context, id int, req request.UpdateProjectRequest) (*entities.Project, error) {
+	for attempt := 1; ; attempt++ {
+		updatedProject, err := p.update(ctx, id, req)
+		if errors.Is(err, repositories.ErrProjectVersionConflict) && req.ExpectedVersion == nil && attempt < updateAttempts {
+			log.WithFields(log.Fields{
+				"project_id": id,
+				"attempt":    attempt,
+			}).Warn("Project was modified concurrently, retrying update")
+			continue
+		}
+		return updatedProject, err
+	}
+}
+
+func (p ProjectService) update(ctx context.Context, id int, req request.UpdateProjectRequest) (*entities.Project, error) {
 	// 1. Lấy project hiện tại từ repository
 	existingProject, err := p.projectRepository.GetById(ctx, id)
 	if err != nil {
 		log.Error("Error fetching existing project: ", err)
 		return nil, fmt.Errorf("error fetching existing project: %w", err)
 	}
+	if req.ExpectedVersion != nil && *req.ExpectedVersion != existingProject.Version {
+		return nil, fmt.Errorf("%w: project %d is at version %d, expected %d",
+			repositories.ErrProjectVersionConflict, id, existingProject.Version, *req.ExpectedVersion)
+	}
 
-	// 2. Cập nhật thông tin project
+	// 2. Số liệu tài chính của project đã kết thúc được chốt lại
+	if existingProject.FinancialsLocked() && req.Budget != nil && *req.Budget != existingProject.Budget.Amount {
+		return nil, fmt.Errorf("%w: project is %s", ErrFinancialsLocked, existingProject.Status)
+	}
+	wasOverBudget := existingProject.OverBudget(p.budgetAlertThreshold)
+
+	// 3. Cập nhật thông tin project, spend/revenue chỉ thay đổi qua sổ cái, variance tính lại từ budget
 	existingProject.Name = req.Name
-	existingProject.Category = req.Category
-	existingProject.ProjectSpend = req.ProjectSpend
-	existingProject.ProjectVariance = req.ProjectVariance
-	existingProject.RevenueRecognised = req.RevenueRecognised
+	if req.Category != "" {
+		existingProject.Category = req.Category
+	}
+	if req.Budget != nil {
+		existingProject.Budget = money.New(*req.Budget, existingProject.Currency())
+	}
+	existingProject.ComputeVariance()
 	existingProject.ProjectStartedAt = req.ProjectStartedAt
 	existingProject.ProjectEndedAt = req.ProjectEndedAt
+	if req.Slug != nil {
+		existingProject.Slug = nil
+		if *req.Slug != "" {
+			existingProject.Slug = req.Slug
+		}
+	}
 
 	updatedProject, err := p.projectRepository.Update(ctx, existingProject)
 	if err != nil {
@@ -90,6 +247,7 @@ func (p ProjectService) Update(ctx context.Context, id int, req request.UpdatePr
 	}
 
 	log.Info("Project updated successfully", updatedProject)
+	p.publishOverBudget(ctx, wasOverBudget, updatedProject)
 	return updatedProject, nil
 }
 
@@ -99,11 +257,193 @@ func (p ProjectService) GetById(ctx context.Context, id int) (*entities.Project,
 		log.Error("Failed to get getByIdproject by ID:", err)
 		return nil, err
 	}
+	if err := p.attachProgress(ctx, getById); err != nil {
+		return nil, fmt.Errorf("failed to get project progress: %w", err)
+	}
 	return getById, nil
 }
 
-func NewProjectService(repository repositories.IProjectRepository) IProjectService {
+// GetBySlug project có slug do user ownerID tạo, kèm tiến độ như GetById
+func (p ProjectService) GetBySlug(ctx context.Context, ownerID int, slug string) (*entities.Project, error) {
+	project, err := p.projectRepository.GetBySlug(ctx, ownerID, slug)
+	if err != nil {
+		return nil, err
+	}
+	if err := p.attachProgress(ctx, project); err != nil {
+		return nil, fmt.Errorf("failed to get project progress: %w", err)
+	}
+	return project, nil
+}
+
+func (p ProjectService) AddMember(ctx context.Context, projectID int, req request.AddProjectMemberRequest) (*entities.UserProject, error) {
+	if !entities.IsValidProjectRole(req.Role) {
+		return nil, ErrInvalidProjectRole
+	}
+	if _, err := p.projectRepository.GetById(ctx, projectID); err != nil {
+		return nil, err
+	}
+
+	caller, err := p.authorizeMemberManagement(ctx, projectID)
+	if err != nil {
+		return nil, err
+	}
+	// chỉ owner mới được trao quyền owner cho người khác
+	if req.Role == entities.ProjectRoleOwner && caller.Role != entities.ProjectRoleOwner {
+		return nil, ErrForbidden
+	}
+
+	member, err := p.memberRepository.AddMember(ctx, req.ToUserProjectEntity(projectID))
+	if err != nil {
+		log.Error("Error adding project member: ", err)
+		return nil, err
+	}
+
+	log.WithFields(log.Fields{
+		"project_id": projectID,
+		"user_id":    member.UserID,
+		"role":       member.Role,
+	}).Info("Project member added")
+	return member, nil
+}
+
+func (p ProjectService) RemoveMember(ctx context.Context, projectID int, userID int) error {
+	caller, err := p.authorizeMemberManagement(ctx, projectID)
+	if err != nil {
+		return err
+	}
+
+	target, err := p.memberRepository.GetMember(ctx, projectID, userID)
+	if err != nil {
+		return err
+	}
+	if target.Role == entities.ProjectRoleOwner {
+		if caller.Role != entities.ProjectRoleOwner {
+			return ErrForbidden
+		}
+		owners, err := p.memberRepository.CountByRole(ctx, projectID, entities.ProjectRoleOwner)
+		if err != nil {
+			return fmt.Errorf("error counting project owners: %w", err)
+		}
+		if owners <= 1 {
+			return ErrLastProjectOwner
+		}
+	}
+
+	if err := p.memberRepository.RemoveMember(ctx, projectID, userID); err != nil {
+		log.Error("Error removing project member: ", err)
+		return err
+	}
+
+	log.WithFields(log.Fields{
+		"project_id": projectID,
+		"user_id":    userID,
+	}).Info("Project member removed")
+	return nil
+}
+
+func (p ProjectService) ListMembers(ctx context.Context, projectID int) ([]entities.UserProject, error) {
+	if _, err := p.projectRepository.GetById(ctx, projectID); err != nil {
+		return nil, err
+	}
+	return p.memberRepository.ListMembers(ctx, projectID)
+}
+
+// Transition chuyển project sang trạng thái mới theo sơ đồ trong entities.ProjectStatus và ghi lại lịch sử.
+// Khi chuyển sang completed mà chưa có ngày kết thúc thì lấy thời điểm hiện tại
+func (p ProjectService) Transition(ctx context.Context, id int, req request.ProjectTransitionRequest) (*entities.Project, error) {
+	project, err := p.projectRepository.GetById(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	if !entities.IsValidProjectStatus(req.Status) {
+		return nil, fmt.Errorf("%w: unknown status %q", ErrInvalidTransition, req.Status)
+	}
+	if !project.CanTransitionTo(req.Status) {
+		return nil, fmt.Errorf("%w: %s -> %s", ErrInvalidTransition, project.Status, req.Status)
+	}
+
+	fromStatus := project.Status
+	project.Status = req.Status
+	if req.Status == entities.ProjectStatusCompleted && project.ProjectEndedAt == nil {
+		now := time.Now()
+		project.ProjectEndedAt = &now
+	}
+
+	history := &entities.ProjectStatusHistory{
+		ProjectID:  project.ID,
+		FromStatus: fromStatus,
+		ToStatus:   req.Status,
+		Reason:     strings.TrimSpace(req.Reason),
+	}
+	if callerID, ok := pkg.UserIDFromContext(ctx); ok {
+		history.ChangedBy = &callerID
+	}
+
+	if err := p.projectRepository.ChangeStatus(ctx, project, fromStatus, history); err != nil {
+		if errors.Is(err, repositories.ErrProjectStatusChanged) {
+			return nil, fmt.Errorf("%w: %v", ErrInvalidTransition, err)
+		}
+		log.Error("Error changing project status: ", err)
+		return nil, err
+	}
+
+	log.WithFields(log.Fields{
+		"project_id": project.ID,
+		"from":       fromStatus,
+		"to":         req.Status,
+	}).Info("Project status changed")
+	return project, nil
+}
+
+func (p ProjectService) GetStatusHistory(ctx context.Context, id int) ([]entities.ProjectStatusHistory, error) {
+	if _, err := p.projectRepository.GetById(ctx, id); err != nil {
+		return nil, err
+	}
+	return p.projectRepository.GetStatusHistory(ctx, id)
+}
+
+// authorizeMemberManagement trả về membership của người gọi nếu họ là owner hoặc manager của project
+func (p ProjectService) authorizeMemberManagement(ctx context.Context, projectID int) (*entities.UserProject, error) {
+	callerID, ok := pkg.UserIDFromContext(ctx)
+	if !ok {
+		return nil, ErrForbidden
+	}
+
+	caller, err := p.memberRepository.GetMember(ctx, projectID, callerID)
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, ErrForbidden
+		}
+		return nil, err
+	}
+	if !caller.CanManageMembers() {
+		return nil, ErrForbidden
+	}
+	return caller, nil
+}
+
+func NewProjectService(
+	repository repositories.IProjectRepository,
+	memberRepository repositories.IProjectMemberRepository,
+	ledger repositories.IProjectTransactionRepository,
+	schedules repositories.IRevenueScheduleRepository,
+	work repositories.IProjectWorkRepository,
+	categories repositories.IProjectCategoryRepository,
+	cursorSigner *pkg.CursorSigner,
+	rates exchange.RateProvider,
+	events eventbus.Bus,
+	config *config.Config,
+) IProjectService {
 	return &ProjectService{
-		projectRepository: repository,
+		projectRepository:    repository,
+		memberRepository:     memberRepository,
+		ledger:               ledger,
+		schedules:            schedules,
+		work:                 work,
+		categories:           categories,
+		rates:                rates,
+		cursorSigner:         cursorSigner,
+		events:               events,
+		budgetAlertThreshold: budgetAlertThreshold(config),
 	}
 }
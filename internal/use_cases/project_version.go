@@ -0,0 +1,85 @@
+package use_cases
+
+import (
+	"Backend_golang_project/internal/domain/entities"
+	"Backend_golang_project/internal/pkg"
+	"Backend_golang_project/internal/repositories"
+	"context"
+	"errors"
+	"fmt"
+	log "github.com/sirupsen/logrus"
+	"gorm.io/gorm"
+	"time"
+)
+
+// ListVersions lịch sử version của project, mới nhất trước
+func (p ProjectService) ListVersions(ctx context.Context, id int, page pkg.PageRequest) (*pkg.Pagination[entities.ProjectVersion], error) {
+	if _, err := p.projectRepository.GetById(ctx, id); err != nil {
+		return nil, err
+	}
+
+	sort := sortKey(repositories.ProjectVersionSort)
+	spec, err := pageSpec(p.cursorSigner, page, sort, []cursorKind{cursorInt, cursorInt})
+	if err != nil {
+		return nil, err
+	}
+	versions, err := p.projectRepository.ListVersions(ctx, id, spec)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list project versions: %w", err)
+	}
+	err = setPageCursors(p.cursorSigner, versions, sort, func(version entities.ProjectVersion) []interface{} {
+		return []interface{}{version.Version, version.ID}
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to encode page cursor: %w", err)
+	}
+	return versions, nil
+}
+
+// GetByIdAsOf trạng thái của project tại thời điểm asOf, dựng lại từ version có hiệu lực lúc đó.
+// Progress là số liệu hiện tại của milestone/task nên không được gắn vào kết quả
+func (p ProjectService) GetByIdAsOf(ctx context.Context, id int, asOf time.Time) (*entities.Project, error) {
+	version, err := p.projectRepository.GetVersionAt(ctx, id, asOf)
+	if err != nil {
+		return nil, err
+	}
+	return version.Project(), nil
+}
+
+// RestoreVersion đưa các trường người dùng sửa được (name, category, budget, ngày bắt đầu/kết thúc) về giá trị của version.
+// Spend/revenue và status không bị khôi phục; project đã chốt số liệu không được đổi budget như với Update
+func (p ProjectService) RestoreVersion(ctx context.Context, id int, version int) (*entities.Project, error) {
+	existingProject, err := p.projectRepository.GetById(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	target, err := p.projectRepository.GetVersion(ctx, id, version)
+	if err != nil {
+		return nil, err
+	}
+
+	if existingProject.FinancialsLocked() && target.Budget.Amount != existingProject.Budget.Amount {
+		return nil, fmt.Errorf("%w: project is %s", ErrFinancialsLocked, existingProject.Status)
+	}
+	// category của version cũ có thể đã bị admin xóa sau đó
+	if _, err := p.categories.GetByName(ctx, target.Category); err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, fmt.Errorf("%w: category %s of version %d no longer exists", ErrInvalidCategory, target.Category, version)
+		}
+		return nil, err
+	}
+	wasOverBudget := existingProject.OverBudget(p.budgetAlertThreshold)
+
+	restored, err := p.projectRepository.RestoreVersion(ctx, id, version)
+	if err != nil {
+		log.Error("Error restoring project version: ", err)
+		return nil, err
+	}
+
+	log.WithFields(log.Fields{
+		"project_id": id,
+		"version":    version,
+	}).Info("Project version restored")
+	p.publishOverBudget(ctx, wasOverBudget, restored)
+	return restored, nil
+}
@@ -0,0 +1,207 @@
+package use_cases
+
+import (
+	"Backend_golang_project/internal/domain/dto/request"
+	"Backend_golang_project/internal/domain/entities"
+	"Backend_golang_project/internal/pkg"
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	log "github.com/sirupsen/logrus"
+	"gorm.io/gorm"
+)
+
+func (p ProjectService) ListMilestones(ctx context.Context, id int) ([]entities.Milestone, error) {
+	if _, err := p.projectRepository.GetById(ctx, id); err != nil {
+		return nil, err
+	}
+	return p.work.ListMilestones(ctx, id)
+}
+
+func (p ProjectService) GetMilestone(ctx context.Context, id int, milestoneID int) (*entities.Milestone, error) {
+	if _, err := p.projectRepository.GetById(ctx, id); err != nil {
+		return nil, err
+	}
+	return p.work.GetMilestone(ctx, id, milestoneID)
+}
+
+func (p ProjectService) CreateMilestone(ctx context.Context, id int, req request.MilestoneRequest) (*entities.Milestone, error) {
+	if _, err := p.projectRepository.GetById(ctx, id); err != nil {
+		return nil, err
+	}
+	if err := p.validateAssignee(ctx, id, req.AssigneeID); err != nil {
+		return nil, err
+	}
+
+	milestone := &entities.Milestone{ProjectID: id}
+	req.ApplyTo(milestone, time.Now())
+	if callerID, ok := pkg.UserIDFromContext(ctx); ok {
+		milestone.CreatedBy = &callerID
+	}
+	saved, err := p.work.SaveMilestone(ctx, milestone)
+	if err != nil {
+		log.Error("Error creating milestone: ", err)
+		return nil, err
+	}
+
+	log.WithFields(log.Fields{
+		"project_id":   id,
+		"milestone_id": saved.ID,
+	}).Info("Milestone created")
+	return saved, nil
+}
+
+func (p ProjectService) UpdateMilestone(ctx context.Context, id int, milestoneID int, req request.MilestoneRequest) (*entities.Milestone, error) {
+	if _, err := p.projectRepository.GetById(ctx, id); err != nil {
+		return nil, err
+	}
+	milestone, err := p.work.GetMilestone(ctx, id, milestoneID)
+	if err != nil {
+		return nil, err
+	}
+	if err := p.validateAssignee(ctx, id, req.AssigneeID); err != nil {
+		return nil, err
+	}
+
+	req.ApplyTo(milestone, time.Now())
+	saved, err := p.work.SaveMilestone(ctx, milestone)
+	if err != nil {
+		log.Error("Error updating milestone: ", err)
+		return nil, err
+	}
+	return saved, nil
+}
+
+// DeleteMilestone xóa milestone, các task của milestone vẫn thuộc project nhưng không còn gắn với milestone nào
+func (p ProjectService) DeleteMilestone(ctx context.Context, id int, milestoneID int) error {
+	if _, err := p.projectRepository.GetById(ctx, id); err != nil {
+		return err
+	}
+	if err := p.work.DeleteMilestone(ctx, id, milestoneID); err != nil {
+		return err
+	}
+	log.WithFields(log.Fields{
+		"project_id":   id,
+		"milestone_id": milestoneID,
+	}).Info("Milestone deleted")
+	return nil
+}
+
+func (p ProjectService) ListTasks(ctx context.Context, id int) ([]entities.Task, error) {
+	if _, err := p.projectRepository.GetById(ctx, id); err != nil {
+		return nil, err
+	}
+	return p.work.ListTasks(ctx, id)
+}
+
+func (p ProjectService) GetTask(ctx context.Context, id int, taskID int) (*entities.Task, error) {
+	if _, err := p.projectRepository.GetById(ctx, id); err != nil {
+		return nil, err
+	}
+	return p.work.GetTask(ctx, id, taskID)
+}
+
+func (p ProjectService) CreateTask(ctx context.Context, id int, req request.TaskRequest) (*entities.Task, error) {
+	if _, err := p.projectRepository.GetById(ctx, id); err != nil {
+		return nil, err
+	}
+	if err := p.validateTask(ctx, id, req); err != nil {
+		return nil, err
+	}
+
+	task := &entities.Task{ProjectID: id}
+	req.ApplyTo(task, time.Now())
+	if callerID, ok := pkg.UserIDFromContext(ctx); ok {
+		task.CreatedBy = &callerID
+	}
+	saved, err := p.work.SaveTask(ctx, task)
+	if err != nil {
+		log.Error("Error creating task: ", err)
+		return nil, err
+	}
+
+	log.WithFields(log.Fields{
+		"project_id": id,
+		"task_id":    saved.ID,
+	}).Info("Task created")
+	return saved, nil
+}
+
+func (p ProjectService) UpdateTask(ctx context.Context, id int, taskID int, req request.TaskRequest) (*entities.Task, error) {
+	if _, err := p.projectRepository.GetById(ctx, id); err != nil {
+		return nil, err
+	}
+	task, err := p.work.GetTask(ctx, id, taskID)
+	if err != nil {
+		return nil, err
+	}
+	if err := p.validateTask(ctx, id, req); err != nil {
+		return nil, err
+	}
+
+	req.ApplyTo(task, time.Now())
+	saved, err := p.work.SaveTask(ctx, task)
+	if err != nil {
+		log.Error("Error updating task: ", err)
+		return nil, err
+	}
+	return saved, nil
+}
+
+func (p ProjectService) DeleteTask(ctx context.Context, id int, taskID int) error {
+	if _, err := p.projectRepository.GetById(ctx, id); err != nil {
+		return err
+	}
+	if err := p.work.DeleteTask(ctx, id, taskID); err != nil {
+		return err
+	}
+	log.WithFields(log.Fields{
+		"project_id": id,
+		"task_id":    taskID,
+	}).Info("Task deleted")
+	return nil
+}
+
+// validateTask milestone của task phải thuộc cùng project, người được giao phải là thành viên project
+func (p ProjectService) validateTask(ctx context.Context, id int, req request.TaskRequest) error {
+	if req.MilestoneID != nil {
+		if _, err := p.work.GetMilestone(ctx, id, *req.MilestoneID); err != nil {
+			if errors.Is(err, gorm.ErrRecordNotFound) {
+				return fmt.Errorf("%w: milestone %d does not belong to project %d", ErrInvalidWorkItem, *req.MilestoneID, id)
+			}
+			return err
+		}
+	}
+	return p.validateAssignee(ctx, id, req.AssigneeID)
+}
+
+func (p ProjectService) validateAssignee(ctx context.Context, id int, assigneeID *int) error {
+	if assigneeID == nil {
+		return nil
+	}
+	if _, err := p.memberRepository.GetMember(ctx, id, *assigneeID); err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return fmt.Errorf("%w: user %d is not a member of project %d", ErrInvalidWorkItem, *assigneeID, id)
+		}
+		return err
+	}
+	return nil
+}
+
+// attachProgress gắn tiến độ tổng hợp từ milestone và task vào các project bằng một lần truy vấn
+func (p ProjectService) attachProgress(ctx context.Context, projects ...*entities.Project) error {
+	ids := make([]int, 0, len(projects))
+	for _, project := range projects {
+		ids = append(ids, project.ID)
+	}
+	progress, err := p.work.ProgressByProjects(ctx, ids)
+	if err != nil {
+		return err
+	}
+	for _, project := range projects {
+		project.Progress = progress[project.ID]
+	}
+	return nil
+}
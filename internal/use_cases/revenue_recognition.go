@@ -0,0 +1,55 @@
+package use_cases
+
+import (
+	"Backend_golang_project/internal/repositories"
+	"context"
+	"time"
+
+	log "github.com/sirupsen/logrus"
+)
+
+// revenueRecognitionBatch số lịch đọc mỗi lần khi ghi nhận doanh thu cho toàn bộ project
+const revenueRecognitionBatch = 100
+
+type IRevenueRecognitionService interface {
+	RecogniseAll(ctx context.Context, asOf time.Time) (int, error)
+}
+
+// RevenueRecognitionService ghi nhận doanh thu cho mọi lịch, được job chạy định kỳ gọi nên không qua ProjectPolicy
+type RevenueRecognitionService struct {
+	schedules repositories.IRevenueScheduleRepository
+}
+
+// RecogniseAll trả về số bút toán đã ghi. Lỗi của một project (project đã đóng, không còn là client...)
+// chỉ được ghi log để các project khác vẫn được xử lý, chỉ lỗi khi đọc danh sách lịch mới được trả về
+func (s *RevenueRecognitionService) RecogniseAll(ctx context.Context, asOf time.Time) (int, error) {
+	posted := 0
+	afterID := 0
+	for {
+		schedules, err := s.schedules.GetBatch(ctx, afterID, revenueRecognitionBatch)
+		if err != nil {
+			return posted, err
+		}
+		for _, schedule := range schedules {
+			entry, err := recogniseRevenue(ctx, s.schedules, schedule.ProjectID, asOf, nil)
+			if err != nil {
+				log.WithFields(log.Fields{
+					"project_id": schedule.ProjectID,
+					"error":      err,
+				}).Warn("Skipped revenue recognition")
+				continue
+			}
+			if entry != nil {
+				posted++
+			}
+		}
+		if len(schedules) < revenueRecognitionBatch {
+			return posted, nil
+		}
+		afterID = schedules[len(schedules)-1].ID
+	}
+}
+
+func NewRevenueRecognitionService(schedules repositories.IRevenueScheduleRepository) IRevenueRecognitionService {
+	return &RevenueRecognitionService{schedules: schedules}
+}
@@ -0,0 +1,224 @@
+package use_cases
+
+import (
+	"Backend_golang_project/internal/domain/dto/request"
+	"Backend_golang_project/internal/domain/entities"
+	"Backend_golang_project/internal/domain/money"
+	"Backend_golang_project/internal/pkg"
+	"Backend_golang_project/internal/repositories"
+	"context"
+	"errors"
+	"fmt"
+	"sort"
+	"time"
+
+	log "github.com/sirupsen/logrus"
+)
+
+// RevenuePreviewLine một dòng của bản xem trước lịch ghi nhận doanh thu: Amount ghi nhận tại Date,
+// Cumulative là tổng đã ghi nhận tới hết Date
+type RevenuePreviewLine struct {
+	Date        time.Time   `json:"date"`
+	Description string      `json:"description"`
+	Amount      money.Money `json:"amount"`
+	Cumulative  money.Money `json:"cumulative"`
+}
+
+// SetRevenueSchedule tạo hoặc thay lịch ghi nhận doanh thu của project client. Doanh thu đã ghi nhận theo lịch cũ
+// được giữ, lần ghi nhận tiếp theo chỉ ghi phần chênh lệch theo lịch mới
+func (p ProjectService) SetRevenueSchedule(ctx context.Context, id int, req request.RevenueScheduleRequest) (*entities.RevenueSchedule, error) {
+	project, schedule, err := p.buildRevenueSchedule(ctx, id, req)
+	if err != nil {
+		return nil, err
+	}
+	if project.FinancialsLocked() {
+		return nil, fmt.Errorf("%w: project is %s", ErrFinancialsLocked, project.Status)
+	}
+	if callerID, ok := pkg.UserIDFromContext(ctx); ok {
+		schedule.CreatedBy = &callerID
+	}
+
+	saved, err := p.schedules.Save(ctx, schedule)
+	if err != nil {
+		log.Error("Error saving revenue schedule: ", err)
+		return nil, err
+	}
+	log.WithFields(log.Fields{
+		"project_id":     id,
+		"method":         saved.Method,
+		"contract_value": saved.ContractValue.String(),
+	}).Info("Revenue schedule saved")
+	return saved, nil
+}
+
+func (p ProjectService) GetRevenueSchedule(ctx context.Context, id int) (*entities.RevenueSchedule, error) {
+	return p.schedules.GetByProject(ctx, id)
+}
+
+// PreviewRevenueSchedule tính các mốc ghi nhận của lịch trong request mà không lưu lại:
+// straight_line theo cuối mỗi tháng, milestone theo ngày hoàn thành (chưa hoàn thành thì theo due_date),
+// percent_complete một dòng tại ngày hôm nay
+func (p ProjectService) PreviewRevenueSchedule(ctx context.Context, id int, req request.RevenueScheduleRequest) ([]RevenuePreviewLine, error) {
+	project, schedule, err := p.buildRevenueSchedule(ctx, id, req)
+	if err != nil {
+		return nil, err
+	}
+
+	var dates []time.Time
+	descriptions := map[time.Time]string{}
+	switch schedule.Method {
+	case entities.RecognitionStraightLine:
+		end := entities.Day(*project.ProjectEndedAt)
+		for month := entities.Day(project.ProjectStartedAt).AddDate(0, 0, 1-project.ProjectStartedAt.UTC().Day()); !month.After(end); month = month.AddDate(0, 1, 0) {
+			date := month.AddDate(0, 1, -1)
+			if date.After(end) {
+				date = end
+			}
+			dates = append(dates, date)
+			descriptions[date] = date.Format("January 2006")
+		}
+	case entities.RecognitionMilestone:
+		// giả định milestone chưa hoàn thành sẽ hoàn thành đúng hạn
+		for i := range schedule.Milestones {
+			milestone := &schedule.Milestones[i]
+			if milestone.CompletedAt == nil {
+				dueDate := milestone.DueDate
+				milestone.CompletedAt = &dueDate
+			}
+			date := entities.Day(*milestone.CompletedAt)
+			if _, ok := descriptions[date]; ok {
+				descriptions[date] += ", " + milestone.Name
+				continue
+			}
+			dates = append(dates, date)
+			descriptions[date] = milestone.Name
+		}
+		sort.Slice(dates, func(i, j int) bool { return dates[i].Before(dates[j]) })
+	case entities.RecognitionPercentComplete:
+		today := entities.Day(time.Now())
+		dates = append(dates, today)
+		descriptions[today] = fmt.Sprintf("%d%% complete", schedule.PercentComplete)
+	}
+
+	lines := make([]RevenuePreviewLine, 0, len(dates))
+	var previous int64
+	for _, date := range dates {
+		cumulative := schedule.RecognisedAsOf(project, date)
+		lines = append(lines, RevenuePreviewLine{
+			Date:        date,
+			Description: descriptions[date],
+			Amount:      money.New(cumulative-previous, project.Currency()),
+			Cumulative:  money.New(cumulative, project.Currency()),
+		})
+		previous = cumulative
+	}
+	return lines, nil
+}
+
+// RecogniseRevenue ghi nhận doanh thu của project theo lịch tới hết ngày as_of, trả về nil nếu không có gì để ghi.
+// as_of không được ở tương lai: doanh thu chưa phát sinh không được ghi trước, job ghi nhận hằng giờ
+// cũng sẽ bị ErrRecognitionBackdated cho tới ngày đó
+func (p ProjectService) RecogniseRevenue(ctx context.Context, id int, req request.RecogniseRevenueRequest) (*entities.ProjectTransaction, error) {
+	now := time.Now()
+	asOf := now
+	if req.AsOf != nil {
+		asOf = *req.AsOf
+	}
+	if entities.Day(asOf).After(entities.Day(now)) {
+		return nil, fmt.Errorf("%w: as_of %s is in the future", ErrInvalidRevenueSchedule, entities.Day(asOf).Format(time.DateOnly))
+	}
+	var createdBy *int
+	if callerID, ok := pkg.UserIDFromContext(ctx); ok {
+		createdBy = &callerID
+	}
+	return recogniseRevenue(ctx, p.schedules, id, asOf, createdBy)
+}
+
+// buildRevenueSchedule kiểm tra project và chuyển request thành lịch hợp lệ
+func (p ProjectService) buildRevenueSchedule(ctx context.Context, id int, req request.RevenueScheduleRequest) (*entities.Project, *entities.RevenueSchedule, error) {
+	project, err := p.projectRepository.GetById(ctx, id)
+	if err != nil {
+		return nil, nil, err
+	}
+	category, err := p.categories.GetByName(ctx, project.Category)
+	if err != nil {
+		return nil, nil, err
+	}
+	if !category.Billable {
+		return nil, nil, fmt.Errorf("%w: project %d is %s", ErrProjectNotBillable, id, project.Category)
+	}
+	schedule := req.ToRevenueScheduleEntity(project)
+	// không chỉ định method thì dùng phương pháp mặc định của category
+	if schedule.Method == "" {
+		schedule.Method = category.DefaultRevenueMethod
+	}
+	if schedule.Method == "" {
+		return nil, nil, fmt.Errorf("%w: method is required, category %s has no default method", ErrInvalidRevenueSchedule, category.Name)
+	}
+	if err := validateRevenueSchedule(project, schedule); err != nil {
+		return nil, nil, err
+	}
+	return project, schedule, nil
+}
+
+func validateRevenueSchedule(project *entities.Project, schedule *entities.RevenueSchedule) error {
+	if !entities.IsValidRecognitionMethod(schedule.Method) {
+		return fmt.Errorf("%w: unknown method %q", ErrInvalidRevenueSchedule, schedule.Method)
+	}
+	if schedule.ContractValue.Amount <= 0 {
+		return fmt.Errorf("%w: contract value must be positive", ErrInvalidRevenueSchedule)
+	}
+	if schedule.Method != entities.RecognitionMilestone && len(schedule.Milestones) > 0 {
+		return fmt.Errorf("%w: milestones are only used by the milestone method", ErrInvalidRevenueSchedule)
+	}
+
+	switch schedule.Method {
+	case entities.RecognitionStraightLine:
+		if project.ProjectEndedAt == nil || project.ProjectEndedAt.Before(project.ProjectStartedAt) {
+			return fmt.Errorf("%w: straight-line recognition needs a project end date", ErrInvalidRevenueSchedule)
+		}
+	case entities.RecognitionMilestone:
+		if len(schedule.Milestones) == 0 {
+			return fmt.Errorf("%w: at least one milestone is required", ErrInvalidRevenueSchedule)
+		}
+		var total int64
+		for _, milestone := range schedule.Milestones {
+			if milestone.Amount <= 0 {
+				return fmt.Errorf("%w: milestone %q must have a positive amount", ErrInvalidRevenueSchedule, milestone.Name)
+			}
+			total += milestone.Amount
+		}
+		if total != schedule.ContractValue.Amount {
+			return fmt.Errorf("%w: milestones add up to %d, contract value is %d", ErrInvalidRevenueSchedule, total, schedule.ContractValue.Amount)
+		}
+	case entities.RecognitionPercentComplete:
+		if schedule.PercentComplete < 0 || schedule.PercentComplete > 100 {
+			return fmt.Errorf("%w: percent complete must be between 0 and 100", ErrInvalidRevenueSchedule)
+		}
+	}
+	return nil
+}
+
+// recogniseRevenue dùng chung cho API và job, chuyển lỗi của repository thành lỗi nghiệp vụ
+func recogniseRevenue(ctx context.Context, schedules repositories.IRevenueScheduleRepository, projectID int, asOf time.Time, createdBy *int) (*entities.ProjectTransaction, error) {
+	entry, err := schedules.Recognise(ctx, projectID, asOf, createdBy)
+	if err != nil {
+		switch {
+		case errors.Is(err, repositories.ErrProjectClosed):
+			return nil, fmt.Errorf("%w: %v", ErrFinancialsLocked, err)
+		case errors.Is(err, repositories.ErrProjectNotBillable):
+			return nil, fmt.Errorf("%w: %v", ErrProjectNotBillable, err)
+		case errors.Is(err, repositories.ErrRecognitionBackdated):
+			return nil, fmt.Errorf("%w: %v", ErrInvalidRevenueSchedule, err)
+		}
+		return nil, err
+	}
+	if entry != nil {
+		log.WithFields(log.Fields{
+			"project_id": projectID,
+			"as_of":      entities.Day(asOf).Format(time.DateOnly),
+			"amount":     entry.Amount.String(),
+		}).Info("Revenue recognised")
+	}
+	return entry, nil
+}
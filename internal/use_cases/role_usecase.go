@@ -0,0 +1,120 @@
+package use_cases
+
+import (
+	"Backend_golang_project/internal/domain/entities"
+	"Backend_golang_project/internal/pkg"
+	"Backend_golang_project/internal/repositories"
+	"context"
+	"errors"
+	log "github.com/sirupsen/logrus"
+	"gorm.io/gorm"
+	"sync"
+	"time"
+)
+
+// permissionCacheTTL role và permission hầu như không đổi nên được cache trong bộ nhớ,
+// sau khoảng thời gian này sẽ đọc lại từ bảng role_permissions
+const permissionCacheTTL = time.Minute
+
+type IRoleService interface {
+	HasPermission(ctx context.Context, roles []string, permission string) (bool, error)
+	ListRoles(ctx context.Context) ([]entities.Role, error)
+	AssignRole(ctx context.Context, userID int, role string) error
+}
+
+type RoleService struct {
+	roleRepository repositories.IRoleRepository
+	userRepository repositories.IUserRepository
+
+	mu          sync.RWMutex
+	permissions map[string]map[string]struct{}
+	loadedAt    time.Time
+}
+
+// HasPermission trả về true nếu một trong các role được cấp permission
+func (s *RoleService) HasPermission(ctx context.Context, roles []string, permission string) (bool, error) {
+	permissions, err := s.permissionsByRole(ctx)
+	if err != nil {
+		return false, err
+	}
+	for _, role := range roles {
+		if _, ok := permissions[role][permission]; ok {
+			return true, nil
+		}
+	}
+	return false, nil
+}
+
+func (s *RoleService) ListRoles(ctx context.Context) ([]entities.Role, error) {
+	return s.roleRepository.ListRoles(ctx)
+}
+
+// AssignRole đổi role toàn hệ thống của user, không cho phép hạ quyền admin cuối cùng (ErrLastAdmin).
+// Access token đang lưu hành vẫn giữ claim roles cũ tới khi hết hạn, lần refresh tiếp theo sẽ nhận role mới
+func (s *RoleService) AssignRole(ctx context.Context, userID int, role string) error {
+	if _, err := s.roleRepository.GetRole(ctx, role); err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return ErrInvalidRole
+		}
+		return err
+	}
+
+	current, err := s.userRepository.GetRole(ctx, userID)
+	if err != nil {
+		return err
+	}
+	if current == role {
+		return nil
+	}
+
+	// repository khóa các dòng admin và trả về ErrLastAdmin nếu đây là admin cuối cùng
+	if err := s.userRepository.UpdateRole(ctx, userID, role); err != nil {
+		return err
+	}
+
+	callerID, _ := pkg.UserIDFromContext(ctx)
+	log.WithFields(log.Fields{
+		"user_id":   userID,
+		"from_role": current,
+		"to_role":   role,
+		"actor_id":  callerID,
+	}).Info("Assigned user role")
+	return nil
+}
+
+func (s *RoleService) permissionsByRole(ctx context.Context) (map[string]map[string]struct{}, error) {
+	s.mu.RLock()
+	if s.permissions != nil && time.Since(s.loadedAt) < permissionCacheTTL {
+		permissions := s.permissions
+		s.mu.RUnlock()
+		return permissions, nil
+	}
+	s.mu.RUnlock()
+
+	roles, err := s.roleRepository.ListRoles(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	permissions := make(map[string]map[string]struct{}, len(roles))
+	for _, role := range roles {
+		granted := make(map[string]struct{}, len(role.Permissions))
+		for _, permission := range role.Permissions {
+			granted[permission.Name] = struct{}{}
+		}
+		permissions[role.Name] = granted
+	}
+
+	s.mu.Lock()
+	s.permissions = permissions
+	s.loadedAt = time.Now()
+	s.mu.Unlock()
+	return permissions, nil
+}
+
+func NewRoleService(roleRepository repositories.IRoleRepository, userRepository repositories.IUserRepository) IRoleService {
+	return &RoleService{
+		roleRepository: roleRepository,
+		userRepository: userRepository,
+	}
+}
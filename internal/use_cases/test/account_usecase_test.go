@@ -0,0 +1,206 @@
+package test
+
+import (
+	"Backend_golang_project/infrastructure/mailer"
+	dto "Backend_golang_project/internal/domain/dto/request"
+	"Backend_golang_project/internal/domain/entities"
+	"Backend_golang_project/internal/repositories"
+	"Backend_golang_project/internal/use_cases"
+	"context"
+	"errors"
+	"fmt"
+	"net/url"
+	"regexp"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+	"gorm.io/gorm"
+)
+
+// MockAccountService là một mock của IAccountService
+type MockAccountService struct {
+	mock.Mock
+}
+
+func (m *MockAccountService) ForgotPassword(ctx context.Context, req dto.ForgotPasswordRequest) error {
+	return m.Called(ctx, req).Error(0)
+}
+
+func (m *MockAccountService) ResetPassword(ctx context.Context, req dto.ResetPasswordRequest) error {
+	return m.Called(ctx, req).Error(0)
+}
+
+func (m *MockAccountService) SendVerification(ctx context.Context, user *entities.User) error {
+	return m.Called(ctx, user).Error(0)
+}
+
+func (m *MockAccountService) VerifyEmail(ctx context.Context, req dto.VerifyEmailRequest) error {
+	return m.Called(ctx, req).Error(0)
+}
+
+// MockUserTokenRepository là một mock của IUserTokenRepository
+type MockUserTokenRepository struct {
+	mock.Mock
+}
+
+func (m *MockUserTokenRepository) Create(ctx context.Context, token *entities.UserToken) error {
+	return m.Called(ctx, token).Error(0)
+}
+
+func (m *MockUserTokenRepository) Consume(ctx context.Context, purpose string, tokenHash string, now time.Time) (*entities.UserToken, error) {
+	args := m.Called(ctx, purpose, tokenHash, now)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*entities.UserToken), args.Error(1)
+}
+
+func (m *MockUserTokenRepository) ResetPassword(ctx context.Context, tokenHash string, passwordHash string, now time.Time) (*entities.UserToken, error) {
+	args := m.Called(ctx, tokenHash, passwordHash, now)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*entities.UserToken), args.Error(1)
+}
+
+func (m *MockUserTokenRepository) LatestActive(ctx context.Context, userID int, purpose string, now time.Time) (*entities.UserToken, error) {
+	args := m.Called(ctx, userID, purpose, now)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*entities.UserToken), args.Error(1)
+}
+
+func (m *MockUserTokenRepository) InvalidateForUser(ctx context.Context, userID int, purpose string, now time.Time) error {
+	return m.Called(ctx, userID, purpose, now).Error(0)
+}
+
+func newAccountService(userRepo *MockUserRepository, tokenRepo *MockUserTokenRepository, tokenStore repositories.IRefreshTokenStore, outbox *mailer.MemoryMailer) use_cases.IAccountService {
+	cfg := newTestConfig()
+	cfg.Mail.AppBaseURL = "http://localhost:3000/"
+	return use_cases.NewAccountService(cfg, userRepo, tokenRepo, tokenStore, repositories.NewInMemoryLoginAttemptStore(), outbox)
+}
+
+var tokenInLink = regexp.MustCompile(`\?token=(\S+)`)
+
+func TestAccountService_ForgotPassword_SendsHashedToken(t *testing.T) {
+	userRepo := new(MockUserRepository)
+	tokenRepo := new(MockUserTokenRepository)
+	outbox := mailer.NewMemoryMailer()
+	service := newAccountService(userRepo, tokenRepo, repositories.NewInMemoryRefreshTokenStore(), outbox)
+	ctx := context.Background()
+	user := &entities.User{ID: 3, Email: "carol@example.com", Username: "carol"}
+
+	var saved *entities.UserToken
+	userRepo.On("GetUserByEmail", mock.Anything, "carol@example.com").Return(user, nil)
+	tokenRepo.On("InvalidateForUser", mock.Anything, 3, entities.UserTokenPasswordReset, mock.Anything).Return(nil)
+	tokenRepo.On("Create", mock.Anything, mock.Anything).Run(func(args mock.Arguments) {
+		saved = args.Get(1).(*entities.UserToken)
+	}).Return(nil)
+
+	err := service.ForgotPassword(ctx, dto.ForgotPasswordRequest{Email: "carol@example.com"})
+	require.NoError(t, err)
+
+	// email được gửi ở goroutine riêng, không nằm trên đường xử lý request
+	require.Eventually(t, func() bool { return len(outbox.Messages()) == 1 }, time.Second, 10*time.Millisecond)
+	messages := outbox.Messages()
+	assert.Equal(t, "carol@example.com", messages[0].To)
+	assert.Contains(t, messages[0].Body, "http://localhost:3000/reset-password?token=")
+
+	match := tokenInLink.FindStringSubmatch(messages[0].Body)
+	require.Len(t, match, 2)
+	rawToken, err := url.QueryUnescape(match[1])
+	require.NoError(t, err)
+	assert.NotEqual(t, rawToken, saved.TokenHash, "only the hash of the token may be stored")
+	assert.Len(t, saved.TokenHash, 64)
+	assert.WithinDuration(t, time.Now().Add(30*time.Minute), saved.ExpiresAt, time.Minute)
+}
+
+func TestAccountService_ForgotPassword_UnknownEmailIsSilent(t *testing.T) {
+	userRepo := new(MockUserRepository)
+	outbox := mailer.NewMemoryMailer()
+	service := newAccountService(userRepo, new(MockUserTokenRepository), repositories.NewInMemoryRefreshTokenStore(), outbox)
+	ctx := context.Background()
+	looked := make(chan struct{})
+	userRepo.On("GetUserByEmail", mock.Anything, "nobody@example.com").
+		Run(func(mock.Arguments) { close(looked) }).
+		Return(nil, fmt.Errorf("user not found: %w", gorm.ErrRecordNotFound))
+
+	err := service.ForgotPassword(ctx, dto.ForgotPasswordRequest{Email: "nobody@example.com"})
+
+	assert.NoError(t, err)
+	<-looked
+	assert.Empty(t, outbox.Messages())
+}
+
+func TestAccountService_ForgotPassword_DatabaseErrorIsSilent(t *testing.T) {
+	userRepo := new(MockUserRepository)
+	tokenRepo := new(MockUserTokenRepository)
+	service := newAccountService(userRepo, tokenRepo, repositories.NewInMemoryRefreshTokenStore(), mailer.NewMemoryMailer())
+	ctx := context.Background()
+	looked := make(chan struct{})
+	userRepo.On("GetUserByEmail", mock.Anything, "carol@example.com").
+		Run(func(mock.Arguments) { close(looked) }).
+		Return(nil, errors.New("connection refused"))
+
+	// lỗi DB cũng trả về nil như email không tồn tại, chỉ được ghi log
+	err := service.ForgotPassword(ctx, dto.ForgotPasswordRequest{Email: "carol@example.com"})
+
+	assert.NoError(t, err)
+	<-looked
+	tokenRepo.AssertNotCalled(t, "Create", mock.Anything, mock.Anything)
+}
+
+func TestAccountService_ResetPassword_RevokesSessions(t *testing.T) {
+	userRepo := new(MockUserRepository)
+	tokenRepo := new(MockUserTokenRepository)
+	tokenStore := repositories.NewInMemoryRefreshTokenStore()
+	service := newAccountService(userRepo, tokenRepo, tokenStore, mailer.NewMemoryMailer())
+	ctx := context.Background()
+	require.NoError(t, tokenStore.Save(ctx, &entities.RefreshToken{JTI: "jti-1", FamilyID: "family-1", UserID: 3, ExpiresAt: time.Now().Add(time.Hour)}))
+
+	tokenRepo.On("ResetPassword", ctx, mock.Anything, mock.AnythingOfType("string"), mock.Anything).
+		Return(&entities.UserToken{ID: 1, UserID: 3}, nil)
+	userRepo.On("GetUserById", ctx, 3).Return(&entities.User{ID: 3, Email: "carol@example.com"}, nil)
+
+	err := service.ResetPassword(ctx, dto.ResetPasswordRequest{Token: "raw-token", Password: "NewPassw0rd"})
+	require.NoError(t, err)
+
+	stored, err := tokenStore.GetByJTI(ctx, "jti-1")
+	require.NoError(t, err)
+	assert.True(t, stored.IsRevoked())
+	tokenRepo.AssertExpectations(t)
+}
+
+func TestAccountService_ResetPassword_InvalidToken(t *testing.T) {
+	userRepo := new(MockUserRepository)
+	tokenRepo := new(MockUserTokenRepository)
+	service := newAccountService(userRepo, tokenRepo, repositories.NewInMemoryRefreshTokenStore(), mailer.NewMemoryMailer())
+	ctx := context.Background()
+	tokenRepo.On("ResetPassword", ctx, mock.Anything, mock.Anything, mock.Anything).
+		Return(nil, repositories.ErrUserTokenInvalid)
+
+	err := service.ResetPassword(ctx, dto.ResetPasswordRequest{Token: "used-token", Password: "NewPassw0rd"})
+
+	assert.ErrorIs(t, err, use_cases.ErrInvalidUserToken)
+	userRepo.AssertNotCalled(t, "GetUserById", mock.Anything, mock.Anything)
+}
+
+func TestAccountService_SendVerification_SkipsRecentlySent(t *testing.T) {
+	tokenRepo := new(MockUserTokenRepository)
+	outbox := mailer.NewMemoryMailer()
+	service := newAccountService(new(MockUserRepository), tokenRepo, repositories.NewInMemoryRefreshTokenStore(), outbox)
+	ctx := context.Background()
+	user := &entities.User{ID: 4, Email: "dave@example.com"}
+	tokenRepo.On("LatestActive", ctx, 4, entities.UserTokenEmailVerification, mock.Anything).
+		Return(&entities.UserToken{UserID: 4, CreatedAt: time.Now().Add(-10 * time.Second)}, nil)
+
+	err := service.SendVerification(ctx, user)
+
+	assert.NoError(t, err)
+	assert.Empty(t, outbox.Messages())
+	tokenRepo.AssertNotCalled(t, "Create", mock.Anything, mock.Anything)
+}
@@ -0,0 +1,62 @@
+package test
+
+import (
+	"Backend_golang_project/internal/domain/entities"
+	"Backend_golang_project/internal/pkg"
+	"Backend_golang_project/internal/repositories"
+	"Backend_golang_project/internal/use_cases"
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+)
+
+type MockAuditRepository struct {
+	mock.Mock
+}
+
+func (m *MockAuditRepository) List(ctx context.Context, query repositories.AuditQuery, spec repositories.PageSpec) (*pkg.Pagination[entities.AuditEvent], error) {
+	args := m.Called(ctx, query, spec)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*pkg.Pagination[entities.AuditEvent]), args.Error(1)
+}
+
+func TestAuditService_ListEvents_InvalidFilter(t *testing.T) {
+	repo := new(MockAuditRepository)
+	service := use_cases.NewAuditService(repo, pkg.NewCursorSigner(newTestConfig()))
+	from := time.Date(2024, 9, 2, 0, 0, 0, 0, time.UTC)
+	to := from.AddDate(0, 0, -1)
+
+	_, err := service.ListEvents(context.Background(), use_cases.AuditFilter{EntityID: 5}, pkg.PageRequest{})
+	assert.ErrorIs(t, err, use_cases.ErrInvalidAuditFilter)
+	_, err = service.ListEvents(context.Background(), use_cases.AuditFilter{From: &from, To: &to}, pkg.PageRequest{})
+	assert.ErrorIs(t, err, use_cases.ErrInvalidAuditFilter)
+	repo.AssertNotCalled(t, "List", mock.Anything, mock.Anything, mock.Anything)
+}
+
+func TestAuditService_ListEvents_CursorRoundTrip(t *testing.T) {
+	repo := new(MockAuditRepository)
+	signer := pkg.NewCursorSigner(newTestConfig())
+	service := use_cases.NewAuditService(repo, signer)
+	ctx := context.Background()
+	query := repositories.AuditQuery{EntityType: entities.AuditEntityProject, EntityID: 5}
+	repo.On("List", ctx, query, repositories.PageSpec{Page: 1, PageSize: 2}).
+		Return(&pkg.Pagination[entities.AuditEvent]{Items: []entities.AuditEvent{{ID: 9}, {ID: 8}}, PageSize: 2, HasNext: true}, nil)
+	repo.On("List", ctx, query, repositories.PageSpec{Page: 1, PageSize: 2, Keyset: &repositories.Keyset{Values: []interface{}{int64(8)}}}).
+		Return(&pkg.Pagination[entities.AuditEvent]{Items: []entities.AuditEvent{{ID: 7}}, PageSize: 2, HasPrev: true}, nil)
+	filter := use_cases.AuditFilter{EntityType: entities.AuditEntityProject, EntityID: 5}
+
+	first, err := service.ListEvents(ctx, filter, pkg.PageRequest{PageSize: 2})
+	require.NoError(t, err)
+	require.NotEmpty(t, first.NextCursor)
+
+	second, err := service.ListEvents(ctx, filter, pkg.PageRequest{PageSize: 2, Cursor: first.NextCursor})
+	require.NoError(t, err)
+	assert.Equal(t, 7, second.Items[0].ID)
+	repo.AssertExpectations(t)
+}
@@ -0,0 +1,175 @@
+package test
+
+import (
+	dto "Backend_golang_project/internal/domain/dto/request"
+	"Backend_golang_project/internal/domain/entities"
+	"Backend_golang_project/internal/pkg"
+	"Backend_golang_project/internal/repositories"
+	"Backend_golang_project/internal/use_cases"
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+	"gorm.io/gorm"
+)
+
+func newThrottledUserService(t *testing.T, attempts repositories.ILoginAttemptStore, accounts use_cases.IAccountService) use_cases.IUserService {
+	cfg := newTestConfig()
+	cfg.JwtConfig.LoginThrottle.MaxFailuresPerEmail = 3
+	cfg.JwtConfig.LoginThrottle.MaxFailuresPerIP = 5
+	cfg.JwtConfig.LoginThrottle.BaseLockoutSeconds = 30
+	cfg.JwtConfig.LoginThrottle.MaxLockoutSeconds = 100
+
+	hash, err := pkg.NewPasswordHasher(pkg.PasswordAlgorithmBcrypt, 0, pkg.Argon2Params{}).Hash("correct-password")
+	require.NoError(t, err)
+
+	verifiedAt := time.Now().Add(-time.Hour)
+	userRepo := new(MockUserRepository)
+	userRepo.On("GetUserByEmail", mock.Anything, "alice@example.com").
+		Return(&entities.User{ID: 1, Email: "alice@example.com", Password: hash, Role: entities.RoleMember, EmailVerifiedAt: &verifiedAt}, nil)
+	userRepo.On("GetUserByEmail", mock.Anything, "bob@example.com").
+		Return(&entities.User{ID: 2, Email: "bob@example.com", Password: hash, Role: entities.RoleMember}, nil)
+	userRepo.On("GetUserByEmail", mock.Anything, mock.Anything).Return(nil, fmt.Errorf("user not found: %w", gorm.ErrRecordNotFound))
+
+	return use_cases.NewUserService(cfg, userRepo, nil, repositories.NewInMemoryRefreshTokenStore(), newTestKeySet(t, cfg), attempts, accounts)
+}
+
+func login(ctx context.Context, service use_cases.IUserService, email string, password string) error {
+	_, _, err := service.Login(ctx, dto.LoginRequest{Email: email, Password: password})
+	return err
+}
+
+func TestUserService_Login_WrongPasswordReturnsError(t *testing.T) {
+	service := newThrottledUserService(t, repositories.NewInMemoryLoginAttemptStore(), new(MockAccountService))
+
+	err := login(context.Background(), service, "alice@example.com", "wrong")
+
+	assert.ErrorIs(t, err, use_cases.ErrInvalidCredentials)
+}
+
+func TestUserService_Login_DatabaseErrorIsNotAFailedAttempt(t *testing.T) {
+	cfg := newTestConfig()
+	outage := errors.New("connection refused")
+	userRepo := new(MockUserRepository)
+	userRepo.On("GetUserByEmail", mock.Anything, "alice@example.com").Return(nil, outage)
+	attempts := repositories.NewInMemoryLoginAttemptStore()
+	service := use_cases.NewUserService(cfg, userRepo, nil, repositories.NewInMemoryRefreshTokenStore(),
+		newTestKeySet(t, cfg), attempts, new(MockAccountService))
+	ctx := pkg.WithClientIP(context.Background(), "10.0.0.3")
+
+	err := login(ctx, service, "alice@example.com", "correct-password")
+
+	assert.ErrorIs(t, err, outage)
+	assert.NotErrorIs(t, err, use_cases.ErrInvalidCredentials)
+	// DB lỗi không được tính là một lần đăng nhập sai
+	emailAttempt, err := attempts.Get(ctx, "email:alice@example.com")
+	require.NoError(t, err)
+	assert.Nil(t, emailAttempt)
+	ipAttempt, err := attempts.Get(ctx, "ip:10.0.0.3")
+	require.NoError(t, err)
+	assert.Nil(t, ipAttempt)
+}
+
+func TestUserService_Login_LocksAccountWithBackoff(t *testing.T) {
+	attempts := repositories.NewInMemoryLoginAttemptStore()
+	service := newThrottledUserService(t, attempts, new(MockAccountService))
+	ctx := context.Background()
+
+	for i := 0; i < 3; i++ {
+		assert.ErrorIs(t, login(ctx, service, "alice@example.com", "wrong"), use_cases.ErrInvalidCredentials)
+	}
+
+	// đúng mật khẩu vẫn bị từ chối khi tài khoản đang khóa
+	err := login(ctx, service, "Alice@Example.com", "correct-password")
+	var lockedErr *use_cases.LockedError
+	require.True(t, errors.As(err, &lockedErr))
+	assert.ErrorIs(t, err, use_cases.ErrAccountLocked)
+	assert.False(t, lockedErr.ByIP)
+	assert.InDelta(t, 30*time.Second, lockedErr.RetryAfter, float64(time.Second))
+
+	// các lần sai tiếp theo sau khi hết khóa nhân đôi thời gian khóa, tối đa MaxLockoutSeconds
+	for _, expected := range []time.Duration{60 * time.Second, 100 * time.Second} {
+		require.NoError(t, attempts.Lock(ctx, "email:alice@example.com", time.Now().Add(-time.Second)))
+
+		assert.ErrorIs(t, login(ctx, service, "alice@example.com", "wrong"), use_cases.ErrInvalidCredentials)
+		attempt, err := attempts.Get(ctx, "email:alice@example.com")
+		require.NoError(t, err)
+		assert.WithinDuration(t, time.Now().Add(expected), *attempt.LockedUntil, time.Second)
+	}
+}
+
+func TestUserService_Login_SuccessResetsEmailCounter(t *testing.T) {
+	attempts := repositories.NewInMemoryLoginAttemptStore()
+	service := newThrottledUserService(t, attempts, new(MockAccountService))
+	ctx := pkg.WithClientIP(context.Background(), "10.0.0.1")
+
+	assert.ErrorIs(t, login(ctx, service, "alice@example.com", "wrong"), use_cases.ErrInvalidCredentials)
+	assert.NoError(t, login(ctx, service, "alice@example.com", "correct-password"))
+
+	emailAttempt, err := attempts.Get(ctx, "email:alice@example.com")
+	require.NoError(t, err)
+	assert.Nil(t, emailAttempt)
+
+	ipAttempt, err := attempts.Get(ctx, "ip:10.0.0.1")
+	require.NoError(t, err)
+	assert.Equal(t, 1, ipAttempt.Failures)
+}
+
+func TestUserService_Login_ThrottlesByIP(t *testing.T) {
+	service := newThrottledUserService(t, repositories.NewInMemoryLoginAttemptStore(), new(MockAccountService))
+	ctx := pkg.WithClientIP(context.Background(), "10.0.0.2")
+
+	// mỗi email chỉ sai một lần nhưng cùng IP thì bị chặn sau 5 lần
+	for i := 0; i < 5; i++ {
+		email := fmt.Sprintf("user%d@example.com", i)
+		assert.ErrorIs(t, login(ctx, service, email, "guess"), use_cases.ErrInvalidCredentials)
+	}
+
+	err := login(ctx, service, "alice@example.com", "correct-password")
+	var lockedErr *use_cases.LockedError
+	require.True(t, errors.As(err, &lockedErr))
+	assert.True(t, lockedErr.ByIP)
+}
+
+func TestUserService_Login_UnverifiedEmailSendsVerification(t *testing.T) {
+	accounts := new(MockAccountService)
+	service := newThrottledUserService(t, repositories.NewInMemoryLoginAttemptStore(), accounts)
+	ctx := context.Background()
+	accounts.On("SendVerification", ctx, mock.MatchedBy(func(user *entities.User) bool { return user.ID == 2 })).Return(nil)
+
+	err := login(ctx, service, "bob@example.com", "correct-password")
+
+	assert.ErrorIs(t, err, use_cases.ErrEmailNotVerified)
+	accounts.AssertExpectations(t)
+}
+
+func TestUserService_Login_UpgradesOutdatedHash(t *testing.T) {
+	cfg := newTestConfig()
+	cfg.Password.Algorithm = "argon2id"
+	cfg.Password.Argon2.MemoryKiB = 1024
+	cfg.Password.Argon2.Iterations = 1
+	cfg.Password.Argon2.Parallelism = 1
+
+	// hash bcrypt cũ, cấu hình hiện tại là argon2id
+	hash, err := pkg.NewPasswordHasher(pkg.PasswordAlgorithmBcrypt, 0, pkg.Argon2Params{}).Hash("correct-password")
+	require.NoError(t, err)
+	verifiedAt := time.Now().Add(-time.Hour)
+	userRepo := new(MockUserRepository)
+	userRepo.On("GetUserByEmail", mock.Anything, "judy@example.com").
+		Return(&entities.User{ID: 9, Email: "judy@example.com", Password: hash, Role: entities.RoleMember, EmailVerifiedAt: &verifiedAt}, nil)
+	userRepo.On("UpdatePassword", mock.Anything, 9, mock.MatchedBy(func(newHash string) bool {
+		return strings.HasPrefix(newHash, "$argon2id$")
+	})).Return(nil)
+
+	service := use_cases.NewUserService(cfg, userRepo, nil, repositories.NewInMemoryRefreshTokenStore(),
+		newTestKeySet(t, cfg), repositories.NewInMemoryLoginAttemptStore(), new(MockAccountService))
+
+	require.NoError(t, login(context.Background(), service, "judy@example.com", "correct-password"))
+	userRepo.AssertExpectations(t)
+}
@@ -0,0 +1,159 @@
+package test
+
+import (
+	"Backend_golang_project/infrastructure/eventbus"
+	"Backend_golang_project/infrastructure/mailer"
+	"Backend_golang_project/internal/domain/dto/request"
+	"Backend_golang_project/internal/domain/entities"
+	"Backend_golang_project/internal/domain/events"
+	"Backend_golang_project/internal/domain/money"
+	"Backend_golang_project/internal/pkg"
+	"Backend_golang_project/internal/use_cases"
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+)
+
+// newBudgetService service với ngưỡng cảnh báo 80% và bus ghi lại các event over budget đã phát
+func newBudgetService(repo *MockProjectRepository, ledger *MockProjectTransactionRepository) (use_cases.IProjectService, *[]events.ProjectOverBudget) {
+	cfg := newTestConfig()
+	cfg.Budget.AlertThreshold = 80
+	bus := eventbus.NewBus()
+	published := &[]events.ProjectOverBudget{}
+	bus.Subscribe(events.ProjectOverBudgetEvent, func(_ context.Context, event eventbus.Event) error {
+		*published = append(*published, event.(events.ProjectOverBudget))
+		return nil
+	})
+	service := use_cases.NewProjectService(repo, new(MockProjectMemberRepository), ledger, new(MockRevenueScheduleRepository), newWorkRepository(), newCategoryRepository(), pkg.NewCursorSigner(cfg),
+		newTestRates(), bus, cfg)
+	return service, published
+}
+
+func TestProjectService_Create_ComputesVariance(t *testing.T) {
+	mockRepo := new(MockProjectRepository)
+	service, published := newBudgetService(mockRepo, new(MockProjectTransactionRepository))
+	ctx := context.Background()
+
+	mockRepo.On("Create", ctx, mock.MatchedBy(func(project *entities.Project) bool {
+		return project.ProjectVariance == money.New(600, "USD")
+	})).Return(&entities.Project{ID: 1, Budget: money.New(1000, "USD"), ProjectSpend: money.New(400, "USD")}, nil)
+
+	_, err := service.Create(ctx, request.CreateProjectRequest{
+		Name: "Merlin", Category: "client", Currency: "USD", Budget: 1000, ProjectSpend: 400, ProjectStartedAt: time.Now(),
+	})
+
+	require.NoError(t, err)
+	mockRepo.AssertExpectations(t)
+	assert.Empty(t, *published)
+}
+
+func TestProjectService_PostTransaction_PublishesOverBudgetOnce(t *testing.T) {
+	ledger := new(MockProjectTransactionRepository)
+	service, published := newBudgetService(new(MockProjectRepository), ledger)
+	ctx := context.Background()
+	spend := func(amount int64) request.PostTransactionRequest {
+		return request.PostTransactionRequest{Type: entities.TransactionTypeSpend, Amount: amount, Currency: "VND", EffectiveDate: time.Now()}
+	}
+	project := func(spend int64) *entities.Project {
+		return &entities.Project{ID: 1, Name: "Apollo", Budget: money.New(1000, "VND"), ProjectSpend: money.New(spend, "VND"),
+			ProjectVariance: money.New(1000-spend, "VND")}
+	}
+
+	// 700 → 850 vượt ngưỡng 80%, 850 → 900 đã vượt từ trước nên không cảnh báo lại
+	ledger.On("Post", ctx, mock.MatchedBy(func(entry *entities.ProjectTransaction) bool { return entry.Amount.Amount == 700 })).Return(project(700), nil)
+	ledger.On("Post", ctx, mock.MatchedBy(func(entry *entities.ProjectTransaction) bool { return entry.Amount.Amount == 150 })).Return(project(850), nil)
+	ledger.On("Post", ctx, mock.MatchedBy(func(entry *entities.ProjectTransaction) bool { return entry.Amount.Amount == 50 })).Return(project(900), nil)
+
+	for _, amount := range []int64{700, 150, 50} {
+		_, err := service.PostTransaction(ctx, 1, spend(amount))
+		require.NoError(t, err)
+	}
+
+	require.Len(t, *published, 1)
+	assert.Equal(t, 1, (*published)[0].ProjectID)
+	assert.Equal(t, 80, (*published)[0].Threshold)
+	assert.Equal(t, money.New(850, "VND"), (*published)[0].Spend)
+	assert.Equal(t, money.New(150, "VND"), (*published)[0].Variance)
+}
+
+func TestProjectService_Update_LowerBudgetPublishesOverBudget(t *testing.T) {
+	mockRepo := new(MockProjectRepository)
+	service, published := newBudgetService(mockRepo, new(MockProjectTransactionRepository))
+	ctx := context.Background()
+	existing := &entities.Project{ID: 1, Name: "Apollo", Status: entities.ProjectStatusActive,
+		Budget: money.New(1000, "VND"), ProjectSpend: money.New(500, "VND")}
+	budget := int64(600)
+
+	mockRepo.On("GetById", ctx, 1).Return(existing, nil)
+	mockRepo.On("Update", ctx, mock.MatchedBy(func(project *entities.Project) bool {
+		return project.Budget == money.New(600, "VND") && project.ProjectVariance == money.New(100, "VND")
+	})).Return(&entities.Project{ID: 1, Name: "Apollo", Budget: money.New(600, "VND"), ProjectSpend: money.New(500, "VND")}, nil)
+
+	_, err := service.Update(ctx, 1, request.UpdateProjectRequest{Name: "Apollo", Budget: &budget})
+
+	require.NoError(t, err)
+	mockRepo.AssertExpectations(t)
+	assert.Len(t, *published, 1)
+}
+
+func TestBudgetAlertSubscriber_MailsOwnersAndManagers(t *testing.T) {
+	memberRepo := new(MockProjectMemberRepository)
+	memory := mailer.NewMemoryMailer()
+	bus := eventbus.NewBus()
+	use_cases.RegisterBudgetAlerts(bus, memberRepo, memory)
+	ctx := context.Background()
+
+	memberRepo.On("ListMembers", mock.Anything, 1).Return([]entities.UserProject{
+		{UserID: 1, Role: entities.ProjectRoleOwner, User: &entities.User{Username: "alice", Email: "alice@example.com"}},
+		{UserID: 2, Role: entities.ProjectRoleManager, User: &entities.User{Username: "bob", Email: "bob@example.com"}},
+		{UserID: 3, Role: entities.ProjectRoleContributor, User: &entities.User{Username: "carol", Email: "carol@example.com"}},
+	}, nil)
+
+	bus.Publish(ctx, events.ProjectOverBudget{ProjectID: 1, Name: "Apollo", Threshold: 90,
+		Budget: money.New(100000, "USD"), Spend: money.New(95050, "USD"), Variance: money.New(4950, "USD")})
+
+	// email được gửi ở goroutine riêng, Publish không chờ mailer
+	require.Eventually(t, func() bool { return len(memory.Messages()) == 2 }, time.Second, 10*time.Millisecond)
+	messages := memory.Messages()
+	assert.Equal(t, "alice@example.com", messages[0].To)
+	assert.Equal(t, "bob@example.com", messages[1].To)
+	assert.Contains(t, messages[0].Body, "950.50 USD of its 1000.00 USD budget")
+}
+
+// blockingMailer chặn Send cho tới khi release được đóng, mô phỏng SMTP chậm
+type blockingMailer struct {
+	release chan struct{}
+}
+
+func (m *blockingMailer) Send(ctx context.Context, _ mailer.Message) error {
+	<-m.release
+	return nil
+}
+
+func TestBudgetAlertSubscriber_PublishDoesNotWaitForMailer(t *testing.T) {
+	memberRepo := new(MockProjectMemberRepository)
+	slow := &blockingMailer{release: make(chan struct{})}
+	defer close(slow.release)
+	bus := eventbus.NewBus()
+	use_cases.RegisterBudgetAlerts(bus, memberRepo, slow)
+
+	memberRepo.On("ListMembers", mock.Anything, 1).Return([]entities.UserProject{
+		{UserID: 1, Role: entities.ProjectRoleOwner, User: &entities.User{Username: "alice", Email: "alice@example.com"}},
+	}, nil)
+
+	published := make(chan struct{})
+	go func() {
+		bus.Publish(context.Background(), events.ProjectOverBudget{ProjectID: 1, Name: "Apollo", Threshold: 90})
+		close(published)
+	}()
+
+	select {
+	case <-published:
+	case <-time.After(time.Second):
+		t.Fatal("Publish blocked on the mailer")
+	}
+}
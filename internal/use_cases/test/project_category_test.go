@@ -0,0 +1,159 @@
+package test
+
+import (
+	"Backend_golang_project/internal/domain/dto/request"
+	"Backend_golang_project/internal/domain/entities"
+	"Backend_golang_project/internal/repositories"
+	"Backend_golang_project/internal/use_cases"
+	"context"
+	"errors"
+	"fmt"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+	"gorm.io/gorm"
+)
+
+type MockProjectCategoryRepository struct {
+	mock.Mock
+}
+
+func (m *MockProjectCategoryRepository) List(ctx context.Context) ([]entities.ProjectCategory, error) {
+	args := m.Called(ctx)
+	return args.Get(0).([]entities.ProjectCategory), args.Error(1)
+}
+
+func (m *MockProjectCategoryRepository) Get(ctx context.Context, id int) (*entities.ProjectCategory, error) {
+	args := m.Called(ctx, id)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*entities.ProjectCategory), args.Error(1)
+}
+
+func (m *MockProjectCategoryRepository) GetByName(ctx context.Context, name string) (*entities.ProjectCategory, error) {
+	args := m.Called(ctx, name)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*entities.ProjectCategory), args.Error(1)
+}
+
+func (m *MockProjectCategoryRepository) Create(ctx context.Context, category *entities.ProjectCategory) (*entities.ProjectCategory, error) {
+	args := m.Called(ctx, category)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*entities.ProjectCategory), args.Error(1)
+}
+
+func (m *MockProjectCategoryRepository) Update(ctx context.Context, category *entities.ProjectCategory) (*entities.ProjectCategory, error) {
+	args := m.Called(ctx, category)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*entities.ProjectCategory), args.Error(1)
+}
+
+func (m *MockProjectCategoryRepository) Delete(ctx context.Context, id int) error {
+	args := m.Called(ctx, id)
+	return args.Error(0)
+}
+
+// seedCategories giống dữ liệu có sẵn trong migration 016
+var seedCategories = []entities.ProjectCategory{
+	{ID: 1, Name: entities.ProjectCategoryClient, Billable: true},
+	{ID: 2, Name: "non-billable"},
+	{ID: 3, Name: "system"},
+}
+
+// newCategoryRepository trả về category có sẵn cho các test của ProjectService không quan tâm tới category
+func newCategoryRepository() *MockProjectCategoryRepository {
+	categories := new(MockProjectCategoryRepository)
+	for i := range seedCategories {
+		category := seedCategories[i]
+		categories.On("GetByName", mock.Anything, category.Name).Return(&category, nil).Maybe()
+	}
+	categories.On("List", mock.Anything).Return(seedCategories, nil).Maybe()
+	return categories
+}
+
+func TestProjectCategoryService_IsValidCategory_Cached(t *testing.T) {
+	repo := new(MockProjectCategoryRepository)
+	repo.On("List", mock.Anything).Return(seedCategories, nil).Once()
+	service := use_cases.NewProjectCategoryService(repo)
+
+	assert.True(t, service.IsValidCategory("client"))
+	assert.True(t, service.IsValidCategory("system"))
+	assert.False(t, service.IsValidCategory("research"))
+	repo.AssertNumberOfCalls(t, "List", 1)
+}
+
+func TestProjectCategoryService_IsValidCategory_DatabaseError(t *testing.T) {
+	repo := new(MockProjectCategoryRepository)
+	ctx := context.Background()
+	outage := errors.New("connection refused")
+	repo.On("List", mock.Anything).Return([]entities.ProjectCategory(nil), outage).Once()
+	service := use_cases.NewProjectCategoryService(repo)
+
+	// chưa có cache thì không trả về 400, lỗi DB sẽ lộ ra ở bước đọc/ghi project
+	assert.True(t, service.IsValidCategory("research"))
+
+	repo.On("List", mock.Anything).Return(seedCategories, nil).Once()
+	require.True(t, service.IsValidCategory("client"))
+	repo.On("Delete", ctx, 3).Return(nil)
+	require.NoError(t, service.Delete(ctx, 3))
+
+	// đọc lại lỗi thì dùng danh sách cũ
+	repo.On("List", mock.Anything).Return([]entities.ProjectCategory(nil), outage)
+	assert.True(t, service.IsValidCategory("client"))
+	assert.False(t, service.IsValidCategory("research"))
+}
+
+func TestProjectCategoryService_Create_RefreshesCache(t *testing.T) {
+	repo := new(MockProjectCategoryRepository)
+	ctx := context.Background()
+	repo.On("List", mock.Anything).Return(seedCategories, nil).Once()
+	service := use_cases.NewProjectCategoryService(repo)
+	require.False(t, service.IsValidCategory("research"))
+
+	research := entities.ProjectCategory{ID: 4, Name: "research", Billable: true, DefaultRevenueMethod: entities.RecognitionMilestone}
+	repo.On("Create", ctx, mock.MatchedBy(func(category *entities.ProjectCategory) bool {
+		return category.Name == "research" && category.Billable && category.DefaultRevenueMethod == entities.RecognitionMilestone
+	})).Return(&research, nil)
+	repo.On("List", mock.Anything).Return(append(seedCategories, research), nil).Once()
+
+	created, err := service.Create(ctx, request.CreateProjectCategoryRequest{Name: " Research ", ProjectCategoryRequest: request.ProjectCategoryRequest{
+		Billable: true, DefaultRevenueMethod: entities.RecognitionMilestone,
+	}})
+
+	require.NoError(t, err)
+	assert.Equal(t, 4, created.ID)
+	assert.True(t, service.IsValidCategory("research"))
+	repo.AssertExpectations(t)
+}
+
+func TestProjectCategoryService_Update_DefaultMethodNeedsBillable(t *testing.T) {
+	repo := new(MockProjectCategoryRepository)
+	ctx := context.Background()
+	repo.On("Get", ctx, 3).Return(&entities.ProjectCategory{ID: 3, Name: "system"}, nil)
+	service := use_cases.NewProjectCategoryService(repo)
+
+	_, err := service.Update(ctx, 3, request.ProjectCategoryRequest{DefaultRevenueMethod: entities.RecognitionStraightLine})
+
+	assert.ErrorIs(t, err, use_cases.ErrInvalidCategory)
+	repo.AssertNotCalled(t, "Update", mock.Anything, mock.Anything)
+}
+
+func TestProjectCategoryService_Delete_InUse(t *testing.T) {
+	repo := new(MockProjectCategoryRepository)
+	ctx := context.Background()
+	repo.On("Delete", ctx, 1).Return(fmt.Errorf("%w: 3 projects in client", repositories.ErrCategoryInUse))
+	repo.On("Delete", ctx, 9).Return(fmt.Errorf("project category 9 not found: %w", gorm.ErrRecordNotFound))
+	service := use_cases.NewProjectCategoryService(repo)
+
+	assert.ErrorIs(t, service.Delete(ctx, 1), use_cases.ErrCategoryInUse)
+	assert.ErrorIs(t, service.Delete(ctx, 9), gorm.ErrRecordNotFound)
+}
@@ -0,0 +1,70 @@
+package test
+
+import (
+	"Backend_golang_project/internal/domain/dto/request"
+	"Backend_golang_project/internal/domain/entities"
+	"Backend_golang_project/internal/repositories"
+	"context"
+	"fmt"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+)
+
+func TestProjectService_Update_IfMatchMismatch(t *testing.T) {
+	mockRepo := new(MockProjectRepository)
+	service := newProjectService(mockRepo, new(MockProjectMemberRepository))
+	ctx := context.Background()
+	stale := 3
+
+	mockRepo.On("GetById", ctx, 1).Return(&entities.Project{ID: 1, Name: "Apollo", Version: 4}, nil)
+
+	_, err := service.Update(ctx, 1, request.UpdateProjectRequest{Name: "Renamed", ExpectedVersion: &stale})
+
+	assert.ErrorIs(t, err, repositories.ErrProjectVersionConflict)
+	mockRepo.AssertNotCalled(t, "Update", mock.Anything, mock.Anything)
+}
+
+func TestProjectService_Update_ConditionalOnReadVersion(t *testing.T) {
+	mockRepo := new(MockProjectRepository)
+	service := newProjectService(mockRepo, new(MockProjectMemberRepository))
+	ctx := context.Background()
+	expected := 4
+	conflict := fmt.Errorf("%w: project 1 is no longer at version 4", repositories.ErrProjectVersionConflict)
+
+	// project được ghi bởi request khác giữa lúc đọc và lúc ghi, có If-Match thì không thử lại
+	mockRepo.On("GetById", ctx, 1).Return(&entities.Project{ID: 1, Name: "Apollo", Version: 4}, nil)
+	mockRepo.On("Update", ctx, mock.MatchedBy(func(project *entities.Project) bool {
+		return project.Version == 4
+	})).Return(nil, conflict)
+
+	_, err := service.Update(ctx, 1, request.UpdateProjectRequest{Name: "Renamed", ExpectedVersion: &expected})
+
+	assert.ErrorIs(t, err, repositories.ErrProjectVersionConflict)
+	mockRepo.AssertNumberOfCalls(t, "Update", 1)
+}
+
+func TestProjectService_Update_RetriesWithoutIfMatch(t *testing.T) {
+	mockRepo := new(MockProjectRepository)
+	service := newProjectService(mockRepo, new(MockProjectMemberRepository))
+	ctx := context.Background()
+	conflict := fmt.Errorf("%w: project 1 is no longer at version 4", repositories.ErrProjectVersionConflict)
+
+	mockRepo.On("GetById", ctx, 1).Return(&entities.Project{ID: 1, Name: "Apollo", Category: "client", Version: 4}, nil).Once()
+	mockRepo.On("GetById", ctx, 1).Return(&entities.Project{ID: 1, Name: "Apollo", Category: "system", Version: 5}, nil).Once()
+	mockRepo.On("Update", ctx, mock.MatchedBy(func(project *entities.Project) bool {
+		return project.Version == 4
+	})).Return(nil, conflict).Once()
+	// lần thử lại dùng dữ liệu vừa đọc nên category do request khác đổi không bị ghi đè
+	mockRepo.On("Update", ctx, mock.MatchedBy(func(project *entities.Project) bool {
+		return project.Version == 5 && project.Category == "system" && project.Name == "Renamed"
+	})).Return(&entities.Project{ID: 1, Name: "Renamed", Category: "system", Version: 6}, nil).Once()
+
+	updated, err := service.Update(ctx, 1, request.UpdateProjectRequest{Name: "Renamed"})
+
+	require.NoError(t, err)
+	assert.Equal(t, 6, updated.Version)
+	mockRepo.AssertExpectations(t)
+}
@@ -0,0 +1,105 @@
+package test
+
+import (
+	"Backend_golang_project/infrastructure/eventbus"
+	"Backend_golang_project/internal/domain/dto/request"
+	"Backend_golang_project/internal/domain/entities"
+	"Backend_golang_project/internal/domain/money"
+	"Backend_golang_project/internal/pkg"
+	"Backend_golang_project/internal/repositories"
+	"Backend_golang_project/internal/use_cases"
+	"context"
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+// MockProjectTransactionRepository là một mock của IProjectTransactionRepository
+type MockProjectTransactionRepository struct {
+	mock.Mock
+}
+
+func (m *MockProjectTransactionRepository) Post(ctx context.Context, entry *entities.ProjectTransaction) (*entities.Project, error) {
+	args := m.Called(ctx, entry)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*entities.Project), args.Error(1)
+}
+
+func (m *MockProjectTransactionRepository) List(ctx context.Context, projectID int, spec repositories.PageSpec) (*pkg.Pagination[entities.ProjectTransaction], error) {
+	args := m.Called(ctx, projectID, spec)
+	return args.Get(0).(*pkg.Pagination[entities.ProjectTransaction]), args.Error(1)
+}
+
+func newLedgerService() (use_cases.IProjectService, *MockProjectTransactionRepository) {
+	ledger := new(MockProjectTransactionRepository)
+	service := use_cases.NewProjectService(new(MockProjectRepository), new(MockProjectMemberRepository), ledger, new(MockRevenueScheduleRepository), newWorkRepository(), newCategoryRepository(),
+		pkg.NewCursorSigner(newTestConfig()), newTestRates(), eventbus.NewBus(), newTestConfig())
+	return service, ledger
+}
+
+func TestProjectService_PostTransaction(t *testing.T) {
+	service, ledger := newLedgerService()
+	ctx := pkg.WithUserID(context.Background(), 3)
+	effective := time.Date(2024, 5, 1, 0, 0, 0, 0, time.UTC)
+
+	ledger.On("Post", ctx, mock.MatchedBy(func(entry *entities.ProjectTransaction) bool {
+		return entry.ProjectID == 1 && entry.Target == entities.TransactionTypeSpend && entry.Amount == money.New(1500, "VND") &&
+			entry.CreatedBy != nil && *entry.CreatedBy == 3
+	})).Return(&entities.Project{ID: 1, ProjectSpend: money.New(1500, "VND")}, nil)
+
+	entry, err := service.PostTransaction(ctx, 1, request.PostTransactionRequest{
+		Type: entities.TransactionTypeSpend, Amount: 1500, Currency: "vnd", EffectiveDate: effective,
+	})
+
+	assert.NoError(t, err)
+	assert.Equal(t, money.New(1500, "VND"), entry.Amount)
+	ledger.AssertExpectations(t)
+}
+
+func TestProjectService_PostTransaction_Invalid(t *testing.T) {
+	service, ledger := newLedgerService()
+	effective := time.Now()
+
+	requests := map[string]request.PostTransactionRequest{
+		"negative spend":        {Type: entities.TransactionTypeSpend, Amount: -10, Currency: "VND", EffectiveDate: effective},
+		"spend with target":     {Type: entities.TransactionTypeSpend, Target: entities.TransactionTypeRevenue, Amount: 10, Currency: "VND", EffectiveDate: effective},
+		"adjustment w/o target": {Type: entities.TransactionTypeAdjustment, Amount: -10, Currency: "VND", EffectiveDate: effective},
+		"unknown currency":      {Type: entities.TransactionTypeRevenue, Amount: 10, Currency: "XYZ", EffectiveDate: effective},
+	}
+	for name, req := range requests {
+		_, err := service.PostTransaction(context.Background(), 1, req)
+		assert.ErrorIs(t, err, use_cases.ErrInvalidTransaction, name)
+	}
+	ledger.AssertNotCalled(t, "Post", mock.Anything, mock.Anything)
+}
+
+func TestProjectService_PostTransaction_CurrencyMismatch(t *testing.T) {
+	service, ledger := newLedgerService()
+	ctx := context.Background()
+
+	ledger.On("Post", ctx, mock.Anything).Return(nil, fmt.Errorf("%w: project is VND", money.ErrCurrencyMismatch))
+
+	_, err := service.PostTransaction(ctx, 1, request.PostTransactionRequest{
+		Type: entities.TransactionTypeSpend, Amount: 1000, Currency: "USD", EffectiveDate: time.Now(),
+	})
+
+	assert.ErrorIs(t, err, use_cases.ErrInvalidTransaction)
+}
+
+func TestProjectService_PostTransaction_ClosedProject(t *testing.T) {
+	service, ledger := newLedgerService()
+	ctx := context.Background()
+
+	ledger.On("Post", ctx, mock.Anything).Return(nil, repositories.ErrProjectClosed)
+
+	_, err := service.PostTransaction(ctx, 1, request.PostTransactionRequest{
+		Type: entities.TransactionTypeAdjustment, Target: entities.TransactionTypeRevenue, Amount: -200, Currency: "VND", EffectiveDate: time.Now(),
+	})
+
+	assert.ErrorIs(t, err, use_cases.ErrFinancialsLocked)
+}
@@ -0,0 +1,486 @@
+package test
+
+import (
+	"Backend_golang_project/internal/domain/dto/request"
+	"Backend_golang_project/internal/domain/entities"
+	"Backend_golang_project/internal/domain/money"
+	"Backend_golang_project/internal/pkg"
+	"Backend_golang_project/internal/repositories"
+	"Backend_golang_project/internal/use_cases"
+	"context"
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"gorm.io/gorm"
+)
+
+// MockUserRepository là một mock của IUserRepository
+type MockUserRepository struct {
+	mock.Mock
+}
+
+func (m *MockUserRepository) CreateUser(ctx context.Context, user *entities.User) (*entities.User, error) {
+	args := m.Called(ctx, user)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*entities.User), args.Error(1)
+}
+
+func (m *MockUserRepository) GetUserById(ctx context.Context, ID int) (*entities.User, error) {
+	args := m.Called(ctx, ID)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*entities.User), args.Error(1)
+}
+
+func (m *MockUserRepository) GetUserByEmail(ctx context.Context, email string) (*entities.User, error) {
+	args := m.Called(ctx, email)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*entities.User), args.Error(1)
+}
+
+func (m *MockUserRepository) GetUsersBatch(ctx context.Context, afterID, limit int) ([]*entities.User, error) {
+	args := m.Called(ctx, afterID, limit)
+	return args.Get(0).([]*entities.User), args.Error(1)
+}
+
+func (m *MockUserRepository) ListUsers(ctx context.Context, spec repositories.PageSpec) (*pkg.Pagination[entities.User], error) {
+	args := m.Called(ctx, spec)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*pkg.Pagination[entities.User]), args.Error(1)
+}
+
+func (m *MockUserRepository) GetTotalCount(ctx context.Context) (int64, error) {
+	args := m.Called(ctx)
+	return args.Get(0).(int64), args.Error(1)
+}
+
+func (m *MockUserRepository) GetRole(ctx context.Context, ID int) (string, error) {
+	args := m.Called(ctx, ID)
+	return args.String(0), args.Error(1)
+}
+
+func (m *MockUserRepository) UpdateRole(ctx context.Context, ID int, role string) error {
+	return m.Called(ctx, ID, role).Error(0)
+}
+
+func (m *MockUserRepository) UpdatePassword(ctx context.Context, ID int, passwordHash string) error {
+	return m.Called(ctx, ID, passwordHash).Error(0)
+}
+
+func (m *MockUserRepository) MarkEmailVerified(ctx context.Context, ID int, verifiedAt time.Time) error {
+	return m.Called(ctx, ID, verifiedAt).Error(0)
+}
+
+func (m *MockUserRepository) UpdateProfile(ctx context.Context, ID int, changes map[string]interface{}) error {
+	return m.Called(ctx, ID, changes).Error(0)
+}
+
+func (m *MockUserRepository) GetHourlyRate(ctx context.Context, ID int) (money.Money, error) {
+	args := m.Called(ctx, ID)
+	return args.Get(0).(money.Money), args.Error(1)
+}
+
+func (m *MockUserRepository) UpdateHourlyRate(ctx context.Context, ID int, rate money.Money) error {
+	args := m.Called(ctx, ID, rate)
+	return args.Error(0)
+}
+
+func (m *MockUserRepository) DeleteUser(ctx context.Context, ID int) error {
+	return m.Called(ctx, ID).Error(0)
+}
+
+// MockProjectService là một mock của IProjectService, đóng vai service gốc được policy bọc lại
+type MockProjectService struct {
+	mock.Mock
+}
+
+func (m *MockProjectService) Create(ctx context.Context, request request.CreateProjectRequest) (*entities.Project, error) {
+	args := m.Called(ctx, request)
+	return args.Get(0).(*entities.Project), args.Error(1)
+}
+
+func (m *MockProjectService) Delete(ctx context.Context, id int) error {
+	return m.Called(ctx, id).Error(0)
+}
+
+func (m *MockProjectService) Update(ctx context.Context, id int, request request.UpdateProjectRequest) (*entities.Project, error) {
+	args := m.Called(ctx, id, request)
+	return args.Get(0).(*entities.Project), args.Error(1)
+}
+
+func (m *MockProjectService) GetById(ctx context.Context, id int) (*entities.Project, error) {
+	args := m.Called(ctx, id)
+	return args.Get(0).(*entities.Project), args.Error(1)
+}
+
+func (m *MockProjectService) GetProjectList(ctx context.Context, filter use_cases.ProjectFilter, page pkg.PageRequest) (*pkg.Pagination[entities.Project], error) {
+	args := m.Called(ctx, filter, page)
+	return args.Get(0).(*pkg.Pagination[entities.Project]), args.Error(1)
+}
+
+func (m *MockProjectService) GetMemberProjectList(ctx context.Context, userID int, filter use_cases.ProjectFilter, page pkg.PageRequest) (*pkg.Pagination[entities.Project], error) {
+	args := m.Called(ctx, userID, filter, page)
+	return args.Get(0).(*pkg.Pagination[entities.Project]), args.Error(1)
+}
+
+func (m *MockProjectService) AddMember(ctx context.Context, projectID int, request request.AddProjectMemberRequest) (*entities.UserProject, error) {
+	args := m.Called(ctx, projectID, request)
+	return args.Get(0).(*entities.UserProject), args.Error(1)
+}
+
+func (m *MockProjectService) RemoveMember(ctx context.Context, projectID int, userID int) error {
+	return m.Called(ctx, projectID, userID).Error(0)
+}
+
+func (m *MockProjectService) ListMembers(ctx context.Context, projectID int) ([]entities.UserProject, error) {
+	args := m.Called(ctx, projectID)
+	return args.Get(0).([]entities.UserProject), args.Error(1)
+}
+
+var firstPage = pkg.PageRequest{Page: 1, PageSize: 10}
+
+func (m *MockProjectService) Transition(ctx context.Context, id int, request request.ProjectTransitionRequest) (*entities.Project, error) {
+	args := m.Called(ctx, id, request)
+	return args.Get(0).(*entities.Project), args.Error(1)
+}
+
+func (m *MockProjectService) GetStatusHistory(ctx context.Context, id int) ([]entities.ProjectStatusHistory, error) {
+	args := m.Called(ctx, id)
+	return args.Get(0).([]entities.ProjectStatusHistory), args.Error(1)
+}
+
+func (m *MockProjectService) GetByIdAsOf(ctx context.Context, id int, asOf time.Time) (*entities.Project, error) {
+	args := m.Called(ctx, id, asOf)
+	return args.Get(0).(*entities.Project), args.Error(1)
+}
+
+func (m *MockProjectService) GetBySlug(ctx context.Context, ownerID int, slug string) (*entities.Project, error) {
+	args := m.Called(ctx, ownerID, slug)
+	return args.Get(0).(*entities.Project), args.Error(1)
+}
+
+func (m *MockProjectService) ListVersions(ctx context.Context, id int, page pkg.PageRequest) (*pkg.Pagination[entities.ProjectVersion], error) {
+	args := m.Called(ctx, id, page)
+	return args.Get(0).(*pkg.Pagination[entities.ProjectVersion]), args.Error(1)
+}
+
+func (m *MockProjectService) RestoreVersion(ctx context.Context, id int, version int) (*entities.Project, error) {
+	args := m.Called(ctx, id, version)
+	return args.Get(0).(*entities.Project), args.Error(1)
+}
+
+func (m *MockProjectService) GetTrash(ctx context.Context, page pkg.PageRequest) (*pkg.Pagination[entities.Project], error) {
+	args := m.Called(ctx, page)
+	return args.Get(0).(*pkg.Pagination[entities.Project]), args.Error(1)
+}
+
+func (m *MockProjectService) GetOwnerTrash(ctx context.Context, userID int, page pkg.PageRequest) (*pkg.Pagination[entities.Project], error) {
+	args := m.Called(ctx, userID, page)
+	return args.Get(0).(*pkg.Pagination[entities.Project]), args.Error(1)
+}
+
+func (m *MockProjectService) Restore(ctx context.Context, id int) (*entities.Project, error) {
+	args := m.Called(ctx, id)
+	return args.Get(0).(*entities.Project), args.Error(1)
+}
+
+func (m *MockProjectService) PostTransaction(ctx context.Context, id int, request request.PostTransactionRequest) (*entities.ProjectTransaction, error) {
+	args := m.Called(ctx, id, request)
+	return args.Get(0).(*entities.ProjectTransaction), args.Error(1)
+}
+
+func (m *MockProjectService) ListTransactions(ctx context.Context, id int, page pkg.PageRequest) (*pkg.Pagination[entities.ProjectTransaction], error) {
+	args := m.Called(ctx, id, page)
+	return args.Get(0).(*pkg.Pagination[entities.ProjectTransaction]), args.Error(1)
+}
+
+func (m *MockProjectService) SetRevenueSchedule(ctx context.Context, id int, req request.RevenueScheduleRequest) (*entities.RevenueSchedule, error) {
+	args := m.Called(ctx, id, req)
+	return args.Get(0).(*entities.RevenueSchedule), args.Error(1)
+}
+
+func (m *MockProjectService) GetRevenueSchedule(ctx context.Context, id int) (*entities.RevenueSchedule, error) {
+	args := m.Called(ctx, id)
+	return args.Get(0).(*entities.RevenueSchedule), args.Error(1)
+}
+
+func (m *MockProjectService) PreviewRevenueSchedule(ctx context.Context, id int, req request.RevenueScheduleRequest) ([]use_cases.RevenuePreviewLine, error) {
+	args := m.Called(ctx, id, req)
+	return args.Get(0).([]use_cases.RevenuePreviewLine), args.Error(1)
+}
+
+func (m *MockProjectService) RecogniseRevenue(ctx context.Context, id int, req request.RecogniseRevenueRequest) (*entities.ProjectTransaction, error) {
+	args := m.Called(ctx, id, req)
+	return args.Get(0).(*entities.ProjectTransaction), args.Error(1)
+}
+
+func (m *MockProjectService) ListMilestones(ctx context.Context, id int) ([]entities.Milestone, error) {
+	args := m.Called(ctx, id)
+	return args.Get(0).([]entities.Milestone), args.Error(1)
+}
+
+func (m *MockProjectService) GetMilestone(ctx context.Context, id int, milestoneID int) (*entities.Milestone, error) {
+	args := m.Called(ctx, id, milestoneID)
+	return args.Get(0).(*entities.Milestone), args.Error(1)
+}
+
+func (m *MockProjectService) CreateMilestone(ctx context.Context, id int, req request.MilestoneRequest) (*entities.Milestone, error) {
+	args := m.Called(ctx, id, req)
+	return args.Get(0).(*entities.Milestone), args.Error(1)
+}
+
+func (m *MockProjectService) UpdateMilestone(ctx context.Context, id int, milestoneID int, req request.MilestoneRequest) (*entities.Milestone, error) {
+	args := m.Called(ctx, id, milestoneID, req)
+	return args.Get(0).(*entities.Milestone), args.Error(1)
+}
+
+func (m *MockProjectService) DeleteMilestone(ctx context.Context, id int, milestoneID int) error {
+	args := m.Called(ctx, id, milestoneID)
+	return args.Error(0)
+}
+
+func (m *MockProjectService) ListTasks(ctx context.Context, id int) ([]entities.Task, error) {
+	args := m.Called(ctx, id)
+	return args.Get(0).([]entities.Task), args.Error(1)
+}
+
+func (m *MockProjectService) GetTask(ctx context.Context, id int, taskID int) (*entities.Task, error) {
+	args := m.Called(ctx, id, taskID)
+	return args.Get(0).(*entities.Task), args.Error(1)
+}
+
+func (m *MockProjectService) CreateTask(ctx context.Context, id int, req request.TaskRequest) (*entities.Task, error) {
+	args := m.Called(ctx, id, req)
+	return args.Get(0).(*entities.Task), args.Error(1)
+}
+
+func (m *MockProjectService) UpdateTask(ctx context.Context, id int, taskID int, req request.TaskRequest) (*entities.Task, error) {
+	args := m.Called(ctx, id, taskID, req)
+	return args.Get(0).(*entities.Task), args.Error(1)
+}
+
+func (m *MockProjectService) DeleteTask(ctx context.Context, id int, taskID int) error {
+	args := m.Called(ctx, id, taskID)
+	return args.Error(0)
+}
+
+func newProjectPolicy(next *MockProjectService, repo *MockProjectRepository, memberRepo *MockProjectMemberRepository, userRepo *MockUserRepository) use_cases.IProjectService {
+	roleService := use_cases.NewRoleService(newSeededRoleRepository(), userRepo)
+	return use_cases.NewProjectPolicy(next, repo, memberRepo, userRepo, roleService)
+}
+
+func TestProjectPolicy_GetById_NonMemberDenied(t *testing.T) {
+	next := new(MockProjectService)
+	memberRepo := new(MockProjectMemberRepository)
+	userRepo := new(MockUserRepository)
+	policy := newProjectPolicy(next, new(MockProjectRepository), memberRepo, userRepo)
+	ctx := pkg.WithUserID(context.Background(), 5)
+
+	userRepo.On("GetRole", ctx, 5).Return(entities.UserRoleMember, nil)
+	memberRepo.On("GetMember", ctx, 1, 5).Return(nil, fmt.Errorf("not a member: %w", gorm.ErrRecordNotFound))
+
+	_, err := policy.GetById(ctx, 1)
+
+	assert.ErrorIs(t, err, use_cases.ErrProjectAccessDenied)
+	next.AssertNotCalled(t, "GetById", mock.Anything, mock.Anything)
+}
+
+func TestProjectPolicy_Update_ViewerDenied(t *testing.T) {
+	next := new(MockProjectService)
+	memberRepo := new(MockProjectMemberRepository)
+	userRepo := new(MockUserRepository)
+	policy := newProjectPolicy(next, new(MockProjectRepository), memberRepo, userRepo)
+	ctx := pkg.WithUserID(context.Background(), 5)
+
+	userRepo.On("GetRole", ctx, 5).Return(entities.UserRoleMember, nil)
+	memberRepo.On("GetMember", ctx, 1, 5).Return(&entities.UserProject{ProjectID: 1, UserID: 5, Role: entities.ProjectRoleViewer}, nil)
+
+	_, err := policy.Update(ctx, 1, request.UpdateProjectRequest{Name: "Renamed"})
+
+	assert.ErrorIs(t, err, use_cases.ErrProjectAccessDenied)
+	next.AssertNotCalled(t, "Update", mock.Anything, mock.Anything, mock.Anything)
+}
+
+func TestProjectPolicy_Tasks_ContributorAllowedMilestonesDenied(t *testing.T) {
+	next := new(MockProjectService)
+	memberRepo := new(MockProjectMemberRepository)
+	userRepo := new(MockUserRepository)
+	policy := newProjectPolicy(next, new(MockProjectRepository), memberRepo, userRepo)
+	ctx := pkg.WithUserID(context.Background(), 5)
+	taskReq := request.TaskRequest{Title: "Write tests"}
+
+	userRepo.On("GetRole", ctx, 5).Return(entities.UserRoleMember, nil)
+	memberRepo.On("GetMember", ctx, 1, 5).Return(&entities.UserProject{ProjectID: 1, UserID: 5, Role: entities.ProjectRoleContributor}, nil)
+	next.On("CreateTask", ctx, 1, taskReq).Return(&entities.Task{ID: 7, ProjectID: 1, Title: "Write tests"}, nil)
+
+	task, err := policy.CreateTask(ctx, 1, taskReq)
+	assert.NoError(t, err)
+	assert.Equal(t, 7, task.ID)
+
+	_, err = policy.CreateMilestone(ctx, 1, request.MilestoneRequest{Name: "Beta"})
+	assert.ErrorIs(t, err, use_cases.ErrProjectAccessDenied)
+	next.AssertNotCalled(t, "CreateMilestone", mock.Anything, mock.Anything, mock.Anything)
+}
+
+func TestProjectPolicy_Delete_OwnerAllowed(t *testing.T) {
+	next := new(MockProjectService)
+	memberRepo := new(MockProjectMemberRepository)
+	userRepo := new(MockUserRepository)
+	policy := newProjectPolicy(next, new(MockProjectRepository), memberRepo, userRepo)
+	ctx := pkg.WithUserID(context.Background(), 5)
+
+	userRepo.On("GetRole", ctx, 5).Return(entities.UserRoleMember, nil)
+	memberRepo.On("GetMember", ctx, 3, 5).Return(&entities.UserProject{ProjectID: 3, UserID: 5, Role: entities.ProjectRoleOwner}, nil)
+	next.On("Delete", ctx, 3).Return(nil)
+
+	err := policy.Delete(ctx, 3)
+
+	assert.NoError(t, err)
+	next.AssertExpectations(t)
+}
+
+func TestProjectPolicy_Delete_ManagerForbidden(t *testing.T) {
+	next := new(MockProjectService)
+	memberRepo := new(MockProjectMemberRepository)
+	userRepo := new(MockUserRepository)
+	policy := newProjectPolicy(next, new(MockProjectRepository), memberRepo, userRepo)
+	ctx := pkg.WithUserID(context.Background(), 5)
+
+	userRepo.On("GetRole", ctx, 5).Return(entities.UserRoleMember, nil)
+	memberRepo.On("GetMember", ctx, 3, 5).Return(&entities.UserProject{ProjectID: 3, UserID: 5, Role: entities.ProjectRoleManager}, nil)
+
+	err := policy.Delete(ctx, 3)
+
+	assert.ErrorIs(t, err, use_cases.ErrProjectAccessDenied)
+	next.AssertNotCalled(t, "Delete", mock.Anything, mock.Anything)
+}
+
+func TestProjectPolicy_GetBySlug_ChecksMembership(t *testing.T) {
+	next := new(MockProjectService)
+	repo := new(MockProjectRepository)
+	memberRepo := new(MockProjectMemberRepository)
+	userRepo := new(MockUserRepository)
+	policy := newProjectPolicy(next, repo, memberRepo, userRepo)
+	ctx := pkg.WithUserID(context.Background(), 5)
+
+	repo.On("GetBySlug", ctx, 9, "website").Return(&entities.Project{ID: 3, Name: "Website"}, nil)
+	userRepo.On("GetRole", ctx, 5).Return(entities.UserRoleMember, nil)
+	memberRepo.On("GetMember", ctx, 3, 5).Return(nil, fmt.Errorf("member not found: %w", gorm.ErrRecordNotFound))
+
+	_, err := policy.GetBySlug(ctx, 9, "website")
+
+	assert.ErrorIs(t, err, use_cases.ErrProjectAccessDenied)
+	next.AssertNotCalled(t, "GetBySlug", mock.Anything, mock.Anything, mock.Anything)
+}
+
+func TestProjectPolicy_GetProjectList_ScopedToCaller(t *testing.T) {
+	next := new(MockProjectService)
+	userRepo := new(MockUserRepository)
+	policy := newProjectPolicy(next, new(MockProjectRepository), new(MockProjectMemberRepository), userRepo)
+	ctx := pkg.WithUserID(context.Background(), 5)
+	expected := &pkg.Pagination[entities.Project]{CurrentPage: 1}
+
+	userRepo.On("GetRole", ctx, 5).Return(entities.UserRoleMember, nil)
+	filter := use_cases.ProjectFilter{Category: "client"}
+	next.On("GetMemberProjectList", ctx, 5, filter, firstPage).Return(expected, nil)
+
+	result, err := policy.GetProjectList(ctx, filter, firstPage)
+
+	assert.NoError(t, err)
+	assert.Equal(t, expected, result)
+	next.AssertNotCalled(t, "GetProjectList", mock.Anything, mock.Anything, mock.Anything, mock.Anything)
+}
+
+func TestProjectPolicy_GetProjectList_AdminSeesAll(t *testing.T) {
+	next := new(MockProjectService)
+	userRepo := new(MockUserRepository)
+	policy := newProjectPolicy(next, new(MockProjectRepository), new(MockProjectMemberRepository), userRepo)
+	ctx := pkg.WithUserID(context.Background(), 1)
+	expected := &pkg.Pagination[entities.Project]{CurrentPage: 1}
+
+	userRepo.On("GetRole", ctx, 1).Return(entities.UserRoleAdmin, nil)
+	next.On("GetProjectList", ctx, use_cases.ProjectFilter{}, firstPage).Return(expected, nil)
+
+	result, err := policy.GetProjectList(ctx, use_cases.ProjectFilter{}, firstPage)
+
+	assert.NoError(t, err)
+	assert.Equal(t, expected, result)
+}
+
+func TestProjectPolicy_Update_AuditorDenied(t *testing.T) {
+	next := new(MockProjectService)
+	memberRepo := new(MockProjectMemberRepository)
+	userRepo := new(MockUserRepository)
+	policy := newProjectPolicy(next, new(MockProjectRepository), memberRepo, userRepo)
+	ctx := pkg.WithUserID(context.Background(), 7)
+
+	userRepo.On("GetRole", ctx, 7).Return(entities.RoleAuditor, nil)
+	memberRepo.On("GetMember", ctx, 1, 7).Return(nil, fmt.Errorf("not a member: %w", gorm.ErrRecordNotFound))
+
+	_, err := policy.Update(ctx, 1, request.UpdateProjectRequest{Name: "Renamed"})
+
+	assert.ErrorIs(t, err, use_cases.ErrProjectAccessDenied)
+	next.AssertNotCalled(t, "Update", mock.Anything, mock.Anything, mock.Anything)
+}
+
+func TestProjectPolicy_GetById_AuditorReadsAll(t *testing.T) {
+	next := new(MockProjectService)
+	memberRepo := new(MockProjectMemberRepository)
+	userRepo := new(MockUserRepository)
+	policy := newProjectPolicy(next, new(MockProjectRepository), memberRepo, userRepo)
+	ctx := pkg.WithUserID(context.Background(), 7)
+	expected := &entities.Project{ID: 1}
+
+	userRepo.On("GetRole", ctx, 7).Return(entities.RoleAuditor, nil)
+	next.On("GetById", ctx, 1).Return(expected, nil)
+
+	result, err := policy.GetById(ctx, 1)
+
+	assert.NoError(t, err)
+	assert.Equal(t, expected, result)
+	memberRepo.AssertNotCalled(t, "GetMember", mock.Anything, mock.Anything, mock.Anything)
+}
+
+func TestProjectPolicy_GetTrash_ScopedToOwner(t *testing.T) {
+	next := new(MockProjectService)
+	userRepo := new(MockUserRepository)
+	policy := newProjectPolicy(next, new(MockProjectRepository), new(MockProjectMemberRepository), userRepo)
+	ctx := pkg.WithUserID(context.Background(), 5)
+	expected := &pkg.Pagination[entities.Project]{CurrentPage: 1}
+
+	// auditor đọc được mọi project nhưng không có project:manage_all nên chỉ thấy thùng rác của mình
+	userRepo.On("GetRole", ctx, 5).Return(entities.RoleAuditor, nil)
+	next.On("GetOwnerTrash", ctx, 5, firstPage).Return(expected, nil)
+
+	result, err := policy.GetTrash(ctx, firstPage)
+
+	assert.NoError(t, err)
+	assert.Equal(t, expected, result)
+	next.AssertNotCalled(t, "GetTrash", mock.Anything, mock.Anything)
+}
+
+func TestProjectPolicy_Restore_ManagerDenied(t *testing.T) {
+	next := new(MockProjectService)
+	memberRepo := new(MockProjectMemberRepository)
+	userRepo := new(MockUserRepository)
+	policy := newProjectPolicy(next, new(MockProjectRepository), memberRepo, userRepo)
+	ctx := pkg.WithUserID(context.Background(), 5)
+
+	userRepo.On("GetRole", ctx, 5).Return(entities.UserRoleMember, nil)
+	memberRepo.On("GetMember", ctx, 1, 5).Return(&entities.UserProject{ProjectID: 1, UserID: 5, Role: entities.ProjectRoleManager}, nil)
+
+	_, err := policy.Restore(ctx, 1)
+
+	assert.ErrorIs(t, err, use_cases.ErrProjectAccessDenied)
+	next.AssertNotCalled(t, "Restore", mock.Anything, mock.Anything)
+}
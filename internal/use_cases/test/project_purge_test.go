@@ -0,0 +1,43 @@
+package test
+
+import (
+	"Backend_golang_project/internal/use_cases"
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+)
+
+func TestProjectPurgeService_PurgeExpired_Batches(t *testing.T) {
+	repo := new(MockProjectRepository)
+	cfg := newTestConfig()
+	cfg.Trash.RetentionDays = 30
+	service := use_cases.NewProjectPurgeService(repo, cfg)
+	ctx := context.Background()
+	now := time.Date(2024, 6, 1, 0, 0, 0, 0, time.UTC)
+	before := now.AddDate(0, 0, -30)
+
+	// lô đầy thì đọc tiếp, lô thiếu là lô cuối
+	repo.On("PurgeDeleted", ctx, before, 100).Return(100, nil).Once()
+	repo.On("PurgeDeleted", ctx, before, 100).Return(3, nil).Once()
+
+	purged, err := service.PurgeExpired(ctx, now)
+
+	require.NoError(t, err)
+	assert.Equal(t, 103, purged)
+	repo.AssertExpectations(t)
+}
+
+func TestProjectPurgeService_PurgeExpired_RetentionDisabled(t *testing.T) {
+	repo := new(MockProjectRepository)
+	service := use_cases.NewProjectPurgeService(repo, newTestConfig())
+
+	purged, err := service.PurgeExpired(context.Background(), time.Now())
+
+	require.NoError(t, err)
+	assert.Zero(t, purged)
+	repo.AssertNotCalled(t, "PurgeDeleted", mock.Anything, mock.Anything, mock.Anything)
+}
@@ -0,0 +1,197 @@
+package test
+
+import (
+	"Backend_golang_project/infrastructure/eventbus"
+	"Backend_golang_project/internal/domain/dto/request"
+	"Backend_golang_project/internal/domain/entities"
+	"Backend_golang_project/internal/domain/money"
+	"Backend_golang_project/internal/pkg"
+	"Backend_golang_project/internal/repositories"
+	"Backend_golang_project/internal/use_cases"
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+)
+
+type MockRevenueScheduleRepository struct {
+	mock.Mock
+}
+
+func (m *MockRevenueScheduleRepository) Save(ctx context.Context, schedule *entities.RevenueSchedule) (*entities.RevenueSchedule, error) {
+	args := m.Called(ctx, schedule)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*entities.RevenueSchedule), args.Error(1)
+}
+
+func (m *MockRevenueScheduleRepository) GetByProject(ctx context.Context, projectID int) (*entities.RevenueSchedule, error) {
+	args := m.Called(ctx, projectID)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*entities.RevenueSchedule), args.Error(1)
+}
+
+func (m *MockRevenueScheduleRepository) GetBatch(ctx context.Context, afterID int, limit int) ([]entities.RevenueSchedule, error) {
+	args := m.Called(ctx, afterID, limit)
+	return args.Get(0).([]entities.RevenueSchedule), args.Error(1)
+}
+
+func (m *MockRevenueScheduleRepository) Recognise(ctx context.Context, projectID int, asOf time.Time, createdBy *int) (*entities.ProjectTransaction, error) {
+	args := m.Called(ctx, projectID, asOf, createdBy)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*entities.ProjectTransaction), args.Error(1)
+}
+
+func clientProject(start, end time.Time) *entities.Project {
+	return &entities.Project{ID: 1, Name: "Apollo", Category: entities.ProjectCategoryClient, Status: entities.ProjectStatusActive,
+		ProjectSpend: money.New(0, "USD"), ProjectStartedAt: start, ProjectEndedAt: &end}
+}
+
+func TestProjectService_SetRevenueSchedule_RejectsNonBillable(t *testing.T) {
+	mockRepo := new(MockProjectRepository)
+	service := newProjectService(mockRepo, new(MockProjectMemberRepository))
+	ctx := context.Background()
+
+	for _, category := range []string{"non-billable", "system"} {
+		project := clientProject(time.Now(), time.Now().AddDate(0, 1, 0))
+		project.Category = category
+		mockRepo.On("GetById", ctx, 1).Return(project, nil).Once()
+
+		_, err := service.SetRevenueSchedule(ctx, 1, request.RevenueScheduleRequest{Method: entities.RecognitionPercentComplete, ContractValue: 1000})
+
+		assert.ErrorIs(t, err, use_cases.ErrProjectNotBillable, category)
+	}
+}
+
+func TestProjectService_SetRevenueSchedule_Invalid(t *testing.T) {
+	mockRepo := new(MockProjectRepository)
+	service := newProjectService(mockRepo, new(MockProjectMemberRepository))
+	ctx := context.Background()
+	due := time.Date(2024, 2, 1, 0, 0, 0, 0, time.UTC)
+	openEnded := clientProject(due, due)
+	openEnded.ProjectEndedAt = nil
+	mockRepo.On("GetById", ctx, 1).Return(openEnded, nil)
+
+	requests := map[string]request.RevenueScheduleRequest{
+		"straight line w/o end date": {Method: entities.RecognitionStraightLine, ContractValue: 1000},
+		"milestones do not add up": {Method: entities.RecognitionMilestone, ContractValue: 1000, Milestones: []request.RevenueMilestoneRequest{
+			{Name: "Design", Amount: 400, DueDate: due}, {Name: "Build", Amount: 500, DueDate: due},
+		}},
+		"milestone w/o milestones": {Method: entities.RecognitionMilestone, ContractValue: 1000},
+		"percent with milestones": {Method: entities.RecognitionPercentComplete, ContractValue: 1000, Milestones: []request.RevenueMilestoneRequest{
+			{Name: "Design", Amount: 1000, DueDate: due},
+		}},
+	}
+	for name, req := range requests {
+		_, err := service.SetRevenueSchedule(ctx, 1, req)
+		assert.ErrorIs(t, err, use_cases.ErrInvalidRevenueSchedule, name)
+	}
+}
+
+func TestProjectService_RecogniseRevenue_RejectsFutureAsOf(t *testing.T) {
+	schedules := new(MockRevenueScheduleRepository)
+	service := use_cases.NewProjectService(new(MockProjectRepository), new(MockProjectMemberRepository), new(MockProjectTransactionRepository), schedules,
+		newWorkRepository(), newCategoryRepository(), pkg.NewCursorSigner(newTestConfig()), newTestRates(), eventbus.NewBus(), newTestConfig())
+	ctx := context.Background()
+	tomorrow := time.Now().AddDate(0, 0, 1)
+
+	_, err := service.RecogniseRevenue(ctx, 1, request.RecogniseRevenueRequest{AsOf: &tomorrow})
+
+	assert.ErrorIs(t, err, use_cases.ErrInvalidRevenueSchedule)
+	schedules.AssertNotCalled(t, "Recognise", mock.Anything, mock.Anything, mock.Anything, mock.Anything)
+}
+
+func TestProjectService_PreviewRevenueSchedule_StraightLine(t *testing.T) {
+	mockRepo := new(MockProjectRepository)
+	service := newProjectService(mockRepo, new(MockProjectMemberRepository))
+	ctx := context.Background()
+	// 15/01 → 31/03/2024 là 77 ngày: 17 ngày tháng 1, 29 ngày tháng 2, 31 ngày tháng 3
+	mockRepo.On("GetById", ctx, 1).Return(clientProject(
+		time.Date(2024, 1, 15, 9, 0, 0, 0, time.UTC), time.Date(2024, 3, 31, 18, 0, 0, 0, time.UTC)), nil)
+
+	lines, err := service.PreviewRevenueSchedule(ctx, 1, request.RevenueScheduleRequest{Method: entities.RecognitionStraightLine, ContractValue: 7700})
+
+	require.NoError(t, err)
+	require.Len(t, lines, 3)
+	assert.Equal(t, time.Date(2024, 1, 31, 0, 0, 0, 0, time.UTC), lines[0].Date)
+	assert.Equal(t, money.New(1700, "USD"), lines[0].Amount)
+	assert.Equal(t, time.Date(2024, 2, 29, 0, 0, 0, 0, time.UTC), lines[1].Date)
+	assert.Equal(t, money.New(2900, "USD"), lines[1].Amount)
+	assert.Equal(t, money.New(3100, "USD"), lines[2].Amount)
+	assert.Equal(t, money.New(7700, "USD"), lines[2].Cumulative)
+}
+
+func TestProjectService_PreviewRevenueSchedule_Milestones(t *testing.T) {
+	mockRepo := new(MockProjectRepository)
+	service := newProjectService(mockRepo, new(MockProjectMemberRepository))
+	ctx := context.Background()
+	start := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+	completed := time.Date(2024, 1, 20, 15, 0, 0, 0, time.UTC)
+	mockRepo.On("GetById", ctx, 1).Return(clientProject(start, start.AddDate(0, 6, 0)), nil)
+
+	lines, err := service.PreviewRevenueSchedule(ctx, 1, request.RevenueScheduleRequest{
+		Method: entities.RecognitionMilestone, ContractValue: 1000,
+		Milestones: []request.RevenueMilestoneRequest{
+			{Name: "Launch", Amount: 500, DueDate: time.Date(2024, 5, 1, 0, 0, 0, 0, time.UTC)},
+			{Name: "Design", Amount: 200, DueDate: time.Date(2024, 2, 1, 0, 0, 0, 0, time.UTC), CompletedAt: &completed},
+			{Name: "Build", Amount: 300, DueDate: time.Date(2024, 3, 1, 0, 0, 0, 0, time.UTC)},
+		},
+	})
+
+	require.NoError(t, err)
+	require.Len(t, lines, 3)
+	assert.Equal(t, []string{"Design", "Build", "Launch"}, []string{lines[0].Description, lines[1].Description, lines[2].Description})
+	assert.Equal(t, time.Date(2024, 1, 20, 0, 0, 0, 0, time.UTC), lines[0].Date)
+	assert.Equal(t, money.New(300, "USD"), lines[1].Amount)
+	assert.Equal(t, money.New(1000, "USD"), lines[2].Cumulative)
+}
+
+func TestRevenueRecognitionService_RecogniseAll_SkipsFailedProjects(t *testing.T) {
+	schedules := new(MockRevenueScheduleRepository)
+	service := use_cases.NewRevenueRecognitionService(schedules)
+	ctx := context.Background()
+	asOf := time.Date(2024, 6, 30, 0, 0, 0, 0, time.UTC)
+
+	schedules.On("GetBatch", ctx, 0, 100).Return([]entities.RevenueSchedule{{ID: 1, ProjectID: 10}, {ID: 2, ProjectID: 20}, {ID: 3, ProjectID: 30}}, nil)
+	schedules.On("Recognise", ctx, 10, asOf, (*int)(nil)).Return(nil, repositories.ErrProjectClosed)
+	schedules.On("Recognise", ctx, 20, asOf, (*int)(nil)).Return(&entities.ProjectTransaction{ProjectID: 20, Amount: money.New(500, "VND")}, nil)
+	schedules.On("Recognise", ctx, 30, asOf, (*int)(nil)).Return(nil, nil)
+
+	posted, err := service.RecogniseAll(ctx, asOf)
+
+	require.NoError(t, err)
+	assert.Equal(t, 1, posted)
+	schedules.AssertExpectations(t)
+}
+
+func TestProjectService_PreviewRevenueSchedule_CategoryDefaultMethod(t *testing.T) {
+	mockRepo := new(MockProjectRepository)
+	ctx := context.Background()
+	mockRepo.On("GetById", ctx, 1).Return(clientProject(
+		time.Date(2024, 1, 15, 0, 0, 0, 0, time.UTC), time.Date(2024, 3, 31, 0, 0, 0, 0, time.UTC)), nil)
+	req := request.RevenueScheduleRequest{ContractValue: 7700}
+
+	// category client có sẵn không có phương pháp mặc định
+	_, err := newProjectService(mockRepo, new(MockProjectMemberRepository)).PreviewRevenueSchedule(ctx, 1, req)
+	assert.ErrorIs(t, err, use_cases.ErrInvalidRevenueSchedule)
+
+	categories := new(MockProjectCategoryRepository)
+	categories.On("GetByName", ctx, entities.ProjectCategoryClient).Return(&entities.ProjectCategory{
+		Name: entities.ProjectCategoryClient, Billable: true, DefaultRevenueMethod: entities.RecognitionStraightLine}, nil)
+	service := use_cases.NewProjectService(mockRepo, new(MockProjectMemberRepository), new(MockProjectTransactionRepository), new(MockRevenueScheduleRepository),
+		newWorkRepository(), categories, pkg.NewCursorSigner(newTestConfig()), newTestRates(), eventbus.NewBus(), newTestConfig())
+
+	lines, err := service.PreviewRevenueSchedule(ctx, 1, req)
+
+	require.NoError(t, err)
+	require.Len(t, lines, 3)
+	assert.Equal(t, money.New(7700, "USD"), lines[2].Cumulative)
+}
@@ -0,0 +1,82 @@
+package test
+
+import (
+	"Backend_golang_project/internal/domain/dto/request"
+	"Backend_golang_project/internal/domain/entities"
+	"Backend_golang_project/internal/domain/money"
+	"Backend_golang_project/internal/pkg"
+	"Backend_golang_project/internal/repositories"
+	"Backend_golang_project/internal/use_cases"
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+func TestProjectService_Transition_RecordsHistory(t *testing.T) {
+	mockRepo := new(MockProjectRepository)
+	service := newProjectService(mockRepo, new(MockProjectMemberRepository))
+	ctx := pkg.WithUserID(context.Background(), 7)
+
+	mockRepo.On("GetById", ctx, 1).Return(&entities.Project{ID: 1, Status: entities.ProjectStatusActive}, nil)
+	mockRepo.On("ChangeStatus", ctx, mock.MatchedBy(func(pj *entities.Project) bool {
+		// chuyển sang completed thì ngày kết thúc được điền tự động
+		return pj.Status == entities.ProjectStatusCompleted && pj.ProjectEndedAt != nil
+	}), entities.ProjectStatusActive, mock.MatchedBy(func(h *entities.ProjectStatusHistory) bool {
+		return h.ProjectID == 1 && h.FromStatus == entities.ProjectStatusActive && h.ToStatus == entities.ProjectStatusCompleted &&
+			h.Reason == "Delivered" && h.ChangedBy != nil && *h.ChangedBy == 7
+	})).Return(nil)
+
+	project, err := service.Transition(ctx, 1, request.ProjectTransitionRequest{Status: entities.ProjectStatusCompleted, Reason: " Delivered "})
+
+	assert.NoError(t, err)
+	assert.Equal(t, entities.ProjectStatusCompleted, project.Status)
+	mockRepo.AssertExpectations(t)
+}
+
+func TestProjectService_Transition_RejectsInvalidMove(t *testing.T) {
+	mockRepo := new(MockProjectRepository)
+	service := newProjectService(mockRepo, new(MockProjectMemberRepository))
+	ctx := context.Background()
+
+	mockRepo.On("GetById", ctx, 1).Return(&entities.Project{ID: 1, Status: entities.ProjectStatusDraft}, nil)
+
+	for _, status := range []string{entities.ProjectStatusCompleted, entities.ProjectStatusOnHold, entities.ProjectStatusDraft, "paused"} {
+		_, err := service.Transition(ctx, 1, request.ProjectTransitionRequest{Status: status, Reason: "test"})
+		assert.ErrorIs(t, err, use_cases.ErrInvalidTransition, status)
+	}
+	mockRepo.AssertNotCalled(t, "ChangeStatus", mock.Anything, mock.Anything, mock.Anything, mock.Anything)
+}
+
+func TestProjectService_Transition_ConcurrentChange(t *testing.T) {
+	mockRepo := new(MockProjectRepository)
+	service := newProjectService(mockRepo, new(MockProjectMemberRepository))
+	ctx := context.Background()
+
+	mockRepo.On("GetById", ctx, 1).Return(&entities.Project{ID: 1, Status: entities.ProjectStatusActive}, nil)
+	mockRepo.On("ChangeStatus", ctx, mock.Anything, entities.ProjectStatusActive, mock.Anything).Return(repositories.ErrProjectStatusChanged)
+
+	_, err := service.Transition(ctx, 1, request.ProjectTransitionRequest{Status: entities.ProjectStatusOnHold, Reason: "Waiting for client"})
+
+	assert.ErrorIs(t, err, use_cases.ErrInvalidTransition)
+}
+
+func TestProjectService_Update_FinancialsLocked(t *testing.T) {
+	mockRepo := new(MockProjectRepository)
+	service := newProjectService(mockRepo, new(MockProjectMemberRepository))
+	ctx := context.Background()
+	completed := &entities.Project{ID: 1, Name: "Done", Status: entities.ProjectStatusCompleted, Budget: money.New(500, "VND")}
+	budget, sameBudget := int64(900), int64(500)
+
+	mockRepo.On("GetById", ctx, 1).Return(completed, nil)
+
+	_, err := service.Update(ctx, 1, request.UpdateProjectRequest{Name: "Done", Budget: &budget})
+	assert.ErrorIs(t, err, use_cases.ErrFinancialsLocked)
+	mockRepo.AssertNotCalled(t, "Update", mock.Anything, mock.Anything)
+
+	// đổi tên vẫn được phép khi không đụng tới số liệu tài chính
+	mockRepo.On("Update", ctx, mock.AnythingOfType("*entities.Project")).Return(completed, nil)
+	_, err = service.Update(ctx, 1, request.UpdateProjectRequest{Name: "Done (final)", Budget: &sameBudget})
+	assert.NoError(t, err)
+}
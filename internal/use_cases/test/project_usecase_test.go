@@ -1,15 +1,19 @@
 package test
 
 import (
+	"Backend_golang_project/infrastructure/eventbus"
+	"Backend_golang_project/infrastructure/exchange"
 	"Backend_golang_project/internal/domain/dto/request"
 	"Backend_golang_project/internal/domain/entities"
+	"Backend_golang_project/internal/domain/money"
 	"Backend_golang_project/internal/pkg"
+	"Backend_golang_project/internal/repositories"
 	"Backend_golang_project/internal/use_cases"
-
-	//"Backend_golang_project/internal/repositories"
 	"context"
 	"errors"
+	"fmt"
 	"testing"
+	"time"
 
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/mock"
@@ -20,12 +24,28 @@ type MockProjectRepository struct {
 	mock.Mock
 }
 
-func (m *MockProjectRepository) GetList(ctx context.Context, page int, pageSize int) (*pkg.Pagination, error) {
-	args := m.Called(ctx, page, pageSize)
+func (m *MockProjectRepository) GetList(ctx context.Context, query repositories.ProjectQuery, spec repositories.PageSpec) (*pkg.Pagination[entities.Project], error) {
+	args := m.Called(ctx, query, spec)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*pkg.Pagination[entities.Project]), args.Error(1)
+}
+
+func (m *MockProjectRepository) GetListByMember(ctx context.Context, userID int, query repositories.ProjectQuery, spec repositories.PageSpec) (*pkg.Pagination[entities.Project], error) {
+	args := m.Called(ctx, userID, query, spec)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*pkg.Pagination[entities.Project]), args.Error(1)
+}
+
+func (m *MockProjectRepository) GetBySlug(ctx context.Context, ownerID int, slug string) (*entities.Project, error) {
+	args := m.Called(ctx, ownerID, slug)
 	if args.Get(0) == nil {
 		return nil, args.Error(1)
 	}
-	return args.Get(0).(*pkg.Pagination), args.Error(1)
+	return args.Get(0).(*entities.Project), args.Error(1)
 }
 
 func (m *MockProjectRepository) Create(ctx context.Context, pj *entities.Project) (*entities.Project, error) {
@@ -35,12 +55,14 @@ func (m *MockProjectRepository) Create(ctx context.Context, pj *entities.Project
 
 func (m *MockProjectRepository) Update(ctx context.Context, pj *entities.Project) (*entities.Project, error) {
 	args := m.Called(ctx, pj)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
 	return args.Get(0).(*entities.Project), args.Error(1)
 }
 
-func (m *MockProjectRepository) Delete(ctx context.Context, name string) bool {
-	args := m.Called(ctx, name)
-	return args.Bool(0)
+func (m *MockProjectRepository) Delete(ctx context.Context, id int) error {
+	return m.Called(ctx, id).Error(0)
 }
 
 func (m *MockProjectRepository) GetById(ctx context.Context, id int) (*entities.Project, error) {
@@ -48,9 +70,115 @@ func (m *MockProjectRepository) GetById(ctx context.Context, id int) (*entities.
 	return args.Get(0).(*entities.Project), args.Error(1)
 }
 
+func (m *MockProjectRepository) ChangeStatus(ctx context.Context, pj *entities.Project, fromStatus string, history *entities.ProjectStatusHistory) error {
+	return m.Called(ctx, pj, fromStatus, history).Error(0)
+}
+
+func (m *MockProjectRepository) GetStatusHistory(ctx context.Context, projectID int) ([]entities.ProjectStatusHistory, error) {
+	args := m.Called(ctx, projectID)
+	return args.Get(0).([]entities.ProjectStatusHistory), args.Error(1)
+}
+
+func (m *MockProjectRepository) ListVersions(ctx context.Context, projectID int, spec repositories.PageSpec) (*pkg.Pagination[entities.ProjectVersion], error) {
+	args := m.Called(ctx, projectID, spec)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*pkg.Pagination[entities.ProjectVersion]), args.Error(1)
+}
+
+func (m *MockProjectRepository) GetVersion(ctx context.Context, projectID int, version int) (*entities.ProjectVersion, error) {
+	args := m.Called(ctx, projectID, version)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*entities.ProjectVersion), args.Error(1)
+}
+
+func (m *MockProjectRepository) GetVersionAt(ctx context.Context, projectID int, asOf time.Time) (*entities.ProjectVersion, error) {
+	args := m.Called(ctx, projectID, asOf)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*entities.ProjectVersion), args.Error(1)
+}
+
+func (m *MockProjectRepository) RestoreVersion(ctx context.Context, projectID int, version int) (*entities.Project, error) {
+	args := m.Called(ctx, projectID, version)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*entities.Project), args.Error(1)
+}
+
+func (m *MockProjectRepository) GetTrash(ctx context.Context, spec repositories.PageSpec) (*pkg.Pagination[entities.Project], error) {
+	args := m.Called(ctx, spec)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*pkg.Pagination[entities.Project]), args.Error(1)
+}
+
+func (m *MockProjectRepository) GetTrashByOwner(ctx context.Context, userID int, spec repositories.PageSpec) (*pkg.Pagination[entities.Project], error) {
+	args := m.Called(ctx, userID, spec)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*pkg.Pagination[entities.Project]), args.Error(1)
+}
+
+func (m *MockProjectRepository) Restore(ctx context.Context, id int) (*entities.Project, error) {
+	args := m.Called(ctx, id)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*entities.Project), args.Error(1)
+}
+
+func (m *MockProjectRepository) PurgeDeleted(ctx context.Context, before time.Time, limit int) (int, error) {
+	args := m.Called(ctx, before, limit)
+	return args.Int(0), args.Error(1)
+}
+
+// MockProjectMemberRepository là một mock của IProjectMemberRepository
+type MockProjectMemberRepository struct {
+	mock.Mock
+}
+
+func (m *MockProjectMemberRepository) AddMember(ctx context.Context, member *entities.UserProject) (*entities.UserProject, error) {
+	args := m.Called(ctx, member)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*entities.UserProject), args.Error(1)
+}
+
+func (m *MockProjectMemberRepository) RemoveMember(ctx context.Context, projectID int, userID int) error {
+	args := m.Called(ctx, projectID, userID)
+	return args.Error(0)
+}
+
+func (m *MockProjectMemberRepository) GetMember(ctx context.Context, projectID int, userID int) (*entities.UserProject, error) {
+	args := m.Called(ctx, projectID, userID)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*entities.UserProject), args.Error(1)
+}
+
+func (m *MockProjectMemberRepository) ListMembers(ctx context.Context, projectID int) ([]entities.UserProject, error) {
+	args := m.Called(ctx, projectID)
+	return args.Get(0).([]entities.UserProject), args.Error(1)
+}
+
+func (m *MockProjectMemberRepository) CountByRole(ctx context.Context, projectID int, role string) (int64, error) {
+	args := m.Called(ctx, projectID, role)
+	return args.Get(0).(int64), args.Error(1)
+}
+
 func TestProjectService_Create(t *testing.T) {
 	mockRepo := new(MockProjectRepository)
-	service := use_cases.NewProjectService(mockRepo)
+	service := newProjectService(mockRepo, new(MockProjectMemberRepository))
 
 	ctx := context.Background()
 	request := request.CreateProjectRequest{
@@ -73,21 +201,21 @@ func TestProjectService_Create(t *testing.T) {
 
 func TestProjectService_Delete(t *testing.T) {
 	mockRepo := new(MockProjectRepository)
-	service := use_cases.NewProjectService(mockRepo)
+	service := newProjectService(mockRepo, new(MockProjectMemberRepository))
 
 	ctx := context.Background()
-	projectName := "Test Project"
 
-	mockRepo.On("Delete", ctx, projectName).Return(true)
-
-	service.Delete(ctx, projectName)
+	mockRepo.On("Delete", ctx, 1).Return(nil)
+	mockRepo.On("Delete", ctx, 2).Return(fmt.Errorf("%w: project 2 has 1", repositories.ErrProjectHasPendingTimesheets))
 
+	assert.NoError(t, service.Delete(ctx, 1))
+	assert.ErrorIs(t, service.Delete(ctx, 2), repositories.ErrProjectHasPendingTimesheets)
 	mockRepo.AssertExpectations(t)
 }
 
 func TestProjectService_GetById(t *testing.T) {
 	mockRepo := new(MockProjectRepository)
-	service := use_cases.NewProjectService(mockRepo)
+	service := newProjectService(mockRepo, new(MockProjectMemberRepository))
 
 	ctx := context.Background()
 	projectID := 1
@@ -108,7 +236,7 @@ func TestProjectService_GetById(t *testing.T) {
 
 func TestProjectService_GetById_Error(t *testing.T) {
 	mockRepo := new(MockProjectRepository)
-	service := use_cases.NewProjectService(mockRepo)
+	service := newProjectService(mockRepo, new(MockProjectMemberRepository))
 
 	ctx := context.Background()
 	projectID := 1
@@ -125,53 +253,65 @@ func TestProjectService_GetById_Error(t *testing.T) {
 	mockRepo.AssertExpectations(t)
 }
 
+func newProjectService(repo repositories.IProjectRepository, memberRepo repositories.IProjectMemberRepository) use_cases.IProjectService {
+	return use_cases.NewProjectService(repo, memberRepo, new(MockProjectTransactionRepository), new(MockRevenueScheduleRepository), newWorkRepository(), newCategoryRepository(), pkg.NewCursorSigner(newTestConfig()),
+		newTestRates(), eventbus.NewBus(), newTestConfig())
+}
+
+func newTestRates() exchange.RateProvider {
+	rates, err := exchange.NewStaticRateProvider("VND", map[string]string{"USD": "25000", "EUR": "27500"})
+	if err != nil {
+		panic(err)
+	}
+	return rates
+}
+
 func TestProjectService_GetProjectList(t *testing.T) {
 	mockRepo := new(MockProjectRepository)
-	service := use_cases.NewProjectService(mockRepo)
+	service := newProjectService(mockRepo, new(MockProjectMemberRepository))
 
 	ctx := context.Background()
-	page := 1
-	pageSize := 10
+	page := pkg.PageRequest{Page: 1, PageSize: 10, IncludeTotal: true}
+	totalPages, totalRecords := 1, 2
 
 	expectedProjects := []entities.Project{
 		{ID: 1, Name: "Project 1"},
 		{ID: 2, Name: "Project 2"},
 	}
 
-	expectedPagination := &pkg.Pagination{
-		Projects:     expectedProjects,
-		TotalPages:   1,
-		TotalRecords: 2,
+	expectedPagination := &pkg.Pagination[entities.Project]{
+		Items:        expectedProjects,
+		PageSize:     10,
+		TotalPages:   &totalPages,
+		TotalRecords: &totalRecords,
 		CurrentPage:  1,
 	}
 
-	mockRepo.On("GetList", ctx, page, pageSize).Return(expectedPagination, nil)
+	mockRepo.On("GetList", ctx, repositories.ProjectQuery{}, repositories.PageSpec{Page: 1, PageSize: 10, IncludeTotal: true}).Return(expectedPagination, nil)
 
-	result, err := service.GetProjectList(ctx, page, pageSize)
+	result, err := service.GetProjectList(ctx, use_cases.ProjectFilter{}, page)
 
 	assert.NoError(t, err)
 	assert.Equal(t, expectedPagination, result)
-	assert.Len(t, result.Projects, 2)
-	assert.Equal(t, 1, result.TotalPages)
-	assert.Equal(t, 2, result.TotalRecords)
+	assert.Len(t, result.Items, 2)
+	assert.Equal(t, 1, *result.TotalPages)
+	assert.Equal(t, 2, *result.TotalRecords)
 	assert.Equal(t, 1, result.CurrentPage)
+	assert.Empty(t, result.NextCursor)
 
 	mockRepo.AssertExpectations(t)
 }
 
 func TestProjectService_GetProjectList_Error(t *testing.T) {
 	mockRepo := new(MockProjectRepository)
-	service := use_cases.NewProjectService(mockRepo)
+	service := newProjectService(mockRepo, new(MockProjectMemberRepository))
 
 	ctx := context.Background()
-	page := 1
-	pageSize := 10
-
 	expectedError := errors.New("database error")
 
-	mockRepo.On("GetList", ctx, page, pageSize).Return((*pkg.Pagination)(nil), expectedError)
+	mockRepo.On("GetList", ctx, repositories.ProjectQuery{}, repositories.PageSpec{Page: 1, PageSize: 10}).Return(nil, expectedError)
 
-	result, err := service.GetProjectList(ctx, page, pageSize)
+	result, err := service.GetProjectList(ctx, use_cases.ProjectFilter{}, pkg.PageRequest{Page: 1, PageSize: 10})
 
 	assert.Error(t, err)
 	assert.Nil(t, result)
@@ -179,3 +319,210 @@ func TestProjectService_GetProjectList_Error(t *testing.T) {
 
 	mockRepo.AssertExpectations(t)
 }
+
+// TestProjectService_GetProjectList_CursorRoundTrip next_cursor của trang đầu được giải mã thành keyset
+// gồm giá trị các cột sort của project cuối cùng, kèm id
+func TestProjectService_GetProjectList_CursorRoundTrip(t *testing.T) {
+	mockRepo := new(MockProjectRepository)
+	service := newProjectService(mockRepo, new(MockProjectMemberRepository))
+	ctx := context.Background()
+	filter := use_cases.ProjectFilter{Sort: "-project_started_at"}
+	started := time.Date(2024, 3, 1, 8, 30, 0, 0, time.UTC)
+	query := repositories.ProjectQuery{Sort: []repositories.SortColumn{{Column: "project_started_at", Desc: true}}}
+
+	mockRepo.On("GetList", ctx, query, repositories.PageSpec{Page: 1, PageSize: 2}).Return(&pkg.Pagination[entities.Project]{
+		Items:   []entities.Project{{ID: 9, ProjectStartedAt: started.AddDate(0, 1, 0)}, {ID: 4, ProjectStartedAt: started}},
+		HasNext: true,
+	}, nil)
+	first, err := service.GetProjectList(ctx, filter, pkg.PageRequest{Page: 1, PageSize: 2})
+	assert.NoError(t, err)
+	assert.NotEmpty(t, first.NextCursor)
+	assert.Empty(t, first.PrevCursor)
+
+	nextSpec := repositories.PageSpec{Page: 1, PageSize: 2, Keyset: &repositories.Keyset{Values: []interface{}{started, int64(4)}}}
+	mockRepo.On("GetList", ctx, query, nextSpec).Return(&pkg.Pagination[entities.Project]{HasPrev: true}, nil)
+	_, err = service.GetProjectList(ctx, filter, pkg.PageRequest{PageSize: 2, Cursor: first.NextCursor})
+	assert.NoError(t, err)
+	mockRepo.AssertExpectations(t)
+
+	// cursor của thứ tự sort khác hoặc bị sửa đều bị từ chối
+	_, err = service.GetProjectList(ctx, use_cases.ProjectFilter{Sort: "name"}, pkg.PageRequest{PageSize: 2, Cursor: first.NextCursor})
+	assert.ErrorIs(t, err, use_cases.ErrInvalidCursor)
+	_, err = service.GetProjectList(ctx, filter, pkg.PageRequest{PageSize: 2, Cursor: "x" + first.NextCursor})
+	assert.ErrorIs(t, err, use_cases.ErrInvalidCursor)
+}
+
+func TestProjectService_GetProjectList_CursorRejectedForNullableSort(t *testing.T) {
+	service := newProjectService(new(MockProjectRepository), new(MockProjectMemberRepository))
+
+	_, err := service.GetProjectList(context.Background(), use_cases.ProjectFilter{Sort: "project_ended_at"},
+		pkg.PageRequest{PageSize: 10, Cursor: "anything"})
+
+	assert.ErrorIs(t, err, use_cases.ErrInvalidProjectFilter)
+}
+
+func TestProjectService_Create_AddsCallerAsOwner(t *testing.T) {
+	mockRepo := new(MockProjectRepository)
+	service := newProjectService(mockRepo, new(MockProjectMemberRepository))
+
+	ctx := pkg.WithUserID(context.Background(), 7)
+	mockRepo.On("Create", ctx, mock.MatchedBy(func(pj *entities.Project) bool {
+		return len(pj.Members) == 1 && pj.Members[0].UserID == 7 && pj.Members[0].Role == entities.ProjectRoleOwner
+	})).Return(&entities.Project{ID: 1}, nil)
+
+	_, err := service.Create(ctx, request.CreateProjectRequest{Name: "Owned Project"})
+
+	assert.NoError(t, err)
+	mockRepo.AssertExpectations(t)
+}
+
+func TestProjectService_AddMember(t *testing.T) {
+	mockRepo := new(MockProjectRepository)
+	memberRepo := new(MockProjectMemberRepository)
+	service := newProjectService(mockRepo, memberRepo)
+
+	ctx := pkg.WithUserID(context.Background(), 1)
+	req := request.AddProjectMemberRequest{UserID: 2, Role: entities.ProjectRoleContributor}
+	added := &entities.UserProject{ProjectID: 10, UserID: 2, Role: entities.ProjectRoleContributor}
+
+	mockRepo.On("GetById", ctx, 10).Return(&entities.Project{ID: 10}, nil)
+	memberRepo.On("GetMember", ctx, 10, 1).Return(&entities.UserProject{ProjectID: 10, UserID: 1, Role: entities.ProjectRoleManager}, nil)
+	memberRepo.On("AddMember", ctx, mock.AnythingOfType("*entities.UserProject")).Return(added, nil)
+
+	result, err := service.AddMember(ctx, 10, req)
+
+	assert.NoError(t, err)
+	assert.Equal(t, added, result)
+	memberRepo.AssertExpectations(t)
+}
+
+func TestProjectService_AddMember_ForbiddenForContributor(t *testing.T) {
+	mockRepo := new(MockProjectRepository)
+	memberRepo := new(MockProjectMemberRepository)
+	service := newProjectService(mockRepo, memberRepo)
+
+	ctx := pkg.WithUserID(context.Background(), 1)
+	req := request.AddProjectMemberRequest{UserID: 2, Role: entities.ProjectRoleViewer}
+
+	mockRepo.On("GetById", ctx, 10).Return(&entities.Project{ID: 10}, nil)
+	memberRepo.On("GetMember", ctx, 10, 1).Return(&entities.UserProject{ProjectID: 10, UserID: 1, Role: entities.ProjectRoleContributor}, nil)
+
+	_, err := service.AddMember(ctx, 10, req)
+
+	assert.ErrorIs(t, err, use_cases.ErrForbidden)
+	memberRepo.AssertNotCalled(t, "AddMember", mock.Anything, mock.Anything)
+}
+
+func TestProjectService_AddMember_ManagerCannotGrantOwner(t *testing.T) {
+	mockRepo := new(MockProjectRepository)
+	memberRepo := new(MockProjectMemberRepository)
+	service := newProjectService(mockRepo, memberRepo)
+
+	ctx := pkg.WithUserID(context.Background(), 1)
+	req := request.AddProjectMemberRequest{UserID: 2, Role: entities.ProjectRoleOwner}
+
+	mockRepo.On("GetById", ctx, 10).Return(&entities.Project{ID: 10}, nil)
+	memberRepo.On("GetMember", ctx, 10, 1).Return(&entities.UserProject{ProjectID: 10, UserID: 1, Role: entities.ProjectRoleManager}, nil)
+
+	_, err := service.AddMember(ctx, 10, req)
+
+	assert.ErrorIs(t, err, use_cases.ErrForbidden)
+}
+
+func TestProjectService_RemoveMember_LastOwner(t *testing.T) {
+	mockRepo := new(MockProjectRepository)
+	memberRepo := new(MockProjectMemberRepository)
+	service := newProjectService(mockRepo, memberRepo)
+
+	ctx := pkg.WithUserID(context.Background(), 1)
+	owner := &entities.UserProject{ProjectID: 10, UserID: 1, Role: entities.ProjectRoleOwner}
+
+	memberRepo.On("GetMember", ctx, 10, 1).Return(owner, nil)
+	memberRepo.On("CountByRole", ctx, 10, entities.ProjectRoleOwner).Return(int64(1), nil)
+
+	err := service.RemoveMember(ctx, 10, 1)
+
+	assert.ErrorIs(t, err, use_cases.ErrLastProjectOwner)
+	memberRepo.AssertNotCalled(t, "RemoveMember", mock.Anything, mock.Anything, mock.Anything)
+}
+
+func TestProjectService_GetProjectList_PassesFilter(t *testing.T) {
+	mockRepo := new(MockProjectRepository)
+	service := newProjectService(mockRepo, new(MockProjectMemberRepository))
+	ctx := context.Background()
+	spendMin := 1000
+
+	expectedQuery := repositories.ProjectQuery{
+		Category:     "client",
+		NameContains: "apollo",
+		SpendMin:     &spendMin,
+		Sort: []repositories.SortColumn{
+			{Column: "project_spend_amount", Desc: true},
+			{Column: "name"},
+		},
+	}
+	mockRepo.On("GetList", ctx, expectedQuery, repositories.PageSpec{Page: 1, PageSize: 10}).Return(&pkg.Pagination[entities.Project]{CurrentPage: 1}, nil)
+
+	_, err := service.GetProjectList(ctx, use_cases.ProjectFilter{
+		Category:     "client",
+		NameContains: "  apollo ",
+		SpendMin:     &spendMin,
+		Sort:         "-project_spend, name,-name",
+	}, pkg.PageRequest{Page: 1, PageSize: 10})
+
+	assert.NoError(t, err)
+	mockRepo.AssertExpectations(t)
+}
+
+func TestProjectService_GetProjectList_ConvertsCurrency(t *testing.T) {
+	mockRepo := new(MockProjectRepository)
+	service := newProjectService(mockRepo, new(MockProjectMemberRepository))
+	ctx := context.Background()
+
+	mockRepo.On("GetList", ctx, mock.Anything, mock.Anything).Return(&pkg.Pagination[entities.Project]{
+		Items: []entities.Project{{
+			ID:                1,
+			Budget:            money.New(2487500, "VND"),
+			ProjectSpend:      money.New(2500000, "VND"),
+			ProjectVariance:   money.New(-12500, "VND"),
+			RevenueRecognised: money.New(0, "VND"),
+		}, {
+			ID:                2,
+			Budget:            money.New(1999, "USD"),
+			ProjectSpend:      money.New(1999, "USD"),
+			ProjectVariance:   money.New(0, "USD"),
+			RevenueRecognised: money.New(100, "USD"),
+		}},
+	}, nil)
+
+	pagination, err := service.GetProjectList(ctx, use_cases.ProjectFilter{Currency: "USD"}, pkg.PageRequest{Page: 1, PageSize: 10})
+
+	assert.NoError(t, err)
+	assert.Equal(t, money.New(10000, "USD"), pagination.Items[0].ProjectSpend)
+	assert.Equal(t, money.New(-50, "USD"), pagination.Items[0].ProjectVariance)
+	assert.Equal(t, money.New(9950, "USD"), pagination.Items[0].Budget)
+	assert.Equal(t, money.New(1999, "USD"), pagination.Items[1].ProjectSpend)
+
+	_, err = service.GetProjectList(ctx, use_cases.ProjectFilter{Currency: "XYZ"}, pkg.PageRequest{Page: 1, PageSize: 10})
+	assert.ErrorIs(t, err, use_cases.ErrInvalidProjectFilter)
+}
+
+func TestProjectFilter_Query_RejectsInvalidInput(t *testing.T) {
+	min, max := 500, 100
+	from := time.Date(2024, 6, 1, 0, 0, 0, 0, time.UTC)
+	to := from.AddDate(0, -1, 0)
+
+	filters := map[string]use_cases.ProjectFilter{
+		"unknown sort field": {Sort: "password"},
+		"sql in sort":        {Sort: "name; DROP TABLE projects"},
+		"empty sort field":   {Sort: "name,,-id"},
+		"spend range":        {SpendMin: &min, SpendMax: &max},
+		"started range":      {StartedFrom: &from, StartedTo: &to},
+	}
+	for name, filter := range filters {
+		t.Run(name, func(t *testing.T) {
+			_, err := filter.Query()
+			assert.ErrorIs(t, err, use_cases.ErrInvalidProjectFilter)
+		})
+	}
+}
@@ -0,0 +1,119 @@
+package test
+
+import (
+	"Backend_golang_project/infrastructure/eventbus"
+	"Backend_golang_project/internal/domain/entities"
+	"Backend_golang_project/internal/domain/money"
+	"Backend_golang_project/internal/pkg"
+	"Backend_golang_project/internal/repositories"
+	"Backend_golang_project/internal/use_cases"
+	"context"
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+	"gorm.io/gorm"
+)
+
+func newVersionService(repo *MockProjectRepository) use_cases.IProjectService {
+	categories := newCategoryRepository()
+	categories.On("GetByName", mock.Anything, "research").
+		Return(nil, fmt.Errorf("project category research not found: %w", gorm.ErrRecordNotFound)).Maybe()
+	cfg := newTestConfig()
+	return use_cases.NewProjectService(repo, new(MockProjectMemberRepository), new(MockProjectTransactionRepository),
+		new(MockRevenueScheduleRepository), newWorkRepository(), categories, pkg.NewCursorSigner(cfg), newTestRates(), eventbus.NewBus(), cfg)
+}
+
+func TestProjectService_GetByIdAsOf(t *testing.T) {
+	repo := new(MockProjectRepository)
+	service := newVersionService(repo)
+	ctx := context.Background()
+	asOf := time.Date(2024, 3, 31, 23, 59, 59, 0, time.UTC)
+	recordedAt := time.Date(2024, 2, 1, 0, 0, 0, 0, time.UTC)
+	repo.On("GetVersionAt", ctx, 1, asOf).Return(&entities.ProjectVersion{ProjectID: 1, Version: 4, Name: "Q1",
+		Budget: money.New(1000, "VND"), CreatedAt: recordedAt}, nil)
+
+	project, err := service.GetByIdAsOf(ctx, 1, asOf)
+
+	require.NoError(t, err)
+	assert.Equal(t, 1, project.ID)
+	assert.Equal(t, "Q1", project.Name)
+	assert.Equal(t, money.New(1000, "VND"), project.Budget)
+	assert.True(t, project.UpdatedAt.Equal(recordedAt))
+	assert.Nil(t, project.Progress)
+	repo.AssertNotCalled(t, "GetById", mock.Anything, mock.Anything)
+}
+
+func TestProjectService_ListVersions_Cursor(t *testing.T) {
+	repo := new(MockProjectRepository)
+	service := newVersionService(repo)
+	ctx := context.Background()
+	repo.On("GetById", ctx, 1).Return(&entities.Project{ID: 1}, nil)
+	repo.On("ListVersions", ctx, 1, repositories.PageSpec{Page: 1, PageSize: 2}).Return(&pkg.Pagination[entities.ProjectVersion]{
+		PageSize: 2, HasNext: true,
+		Items: []entities.ProjectVersion{{ID: 12, ProjectID: 1, Version: 5}, {ID: 10, ProjectID: 1, Version: 4}},
+	}, nil)
+
+	page, err := service.ListVersions(ctx, 1, pkg.PageRequest{Page: 1, PageSize: 2})
+	require.NoError(t, err)
+	require.NotEmpty(t, page.NextCursor)
+
+	repo.On("ListVersions", ctx, 1, repositories.PageSpec{Page: 1, PageSize: 2,
+		Keyset: &repositories.Keyset{Values: []interface{}{int64(4), int64(10)}}}).
+		Return(&pkg.Pagination[entities.ProjectVersion]{PageSize: 2}, nil)
+	_, err = service.ListVersions(ctx, 1, pkg.PageRequest{Page: 1, PageSize: 2, Cursor: page.NextCursor})
+	require.NoError(t, err)
+	repo.AssertExpectations(t)
+}
+
+func TestProjectService_RestoreVersion(t *testing.T) {
+	ctx := context.Background()
+	current := &entities.Project{ID: 1, Status: entities.ProjectStatusActive, Category: "client", Budget: money.New(5000, "VND")}
+	version := &entities.ProjectVersion{ProjectID: 1, Version: 2, Name: "Before", Category: "system", Budget: money.New(1000, "VND")}
+
+	repo := new(MockProjectRepository)
+	service := newVersionService(repo)
+	repo.On("GetById", ctx, 1).Return(current, nil)
+	repo.On("GetVersion", ctx, 1, 2).Return(version, nil)
+	repo.On("RestoreVersion", ctx, 1, 2).Return(&entities.Project{ID: 1, Name: "Before", Category: "system"}, nil)
+
+	restored, err := service.RestoreVersion(ctx, 1, 2)
+
+	require.NoError(t, err)
+	assert.Equal(t, "Before", restored.Name)
+	repo.AssertExpectations(t)
+}
+
+func TestProjectService_RestoreVersion_Rejected(t *testing.T) {
+	ctx := context.Background()
+	cases := map[string]struct {
+		current  *entities.Project
+		version  *entities.ProjectVersion
+		expected error
+	}{
+		"budget of completed project": {
+			&entities.Project{ID: 1, Status: entities.ProjectStatusCompleted, Budget: money.New(5000, "VND")},
+			&entities.ProjectVersion{ProjectID: 1, Version: 2, Category: "client", Budget: money.New(1000, "VND")},
+			use_cases.ErrFinancialsLocked,
+		},
+		"deleted category": {
+			&entities.Project{ID: 1, Status: entities.ProjectStatusActive, Budget: money.New(5000, "VND")},
+			&entities.ProjectVersion{ProjectID: 1, Version: 2, Category: "research", Budget: money.New(5000, "VND")},
+			use_cases.ErrInvalidCategory,
+		},
+	}
+	for name, c := range cases {
+		repo := new(MockProjectRepository)
+		service := newVersionService(repo)
+		repo.On("GetById", ctx, 1).Return(c.current, nil)
+		repo.On("GetVersion", ctx, 1, 2).Return(c.version, nil)
+
+		_, err := service.RestoreVersion(ctx, 1, 2)
+
+		assert.ErrorIs(t, err, c.expected, name)
+		repo.AssertNotCalled(t, "RestoreVersion", mock.Anything, mock.Anything, mock.Anything)
+	}
+}
@@ -0,0 +1,197 @@
+package test
+
+import (
+	"Backend_golang_project/infrastructure/eventbus"
+	"Backend_golang_project/internal/domain/dto/request"
+	"Backend_golang_project/internal/domain/entities"
+	"Backend_golang_project/internal/pkg"
+	"Backend_golang_project/internal/use_cases"
+	"context"
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+	"gorm.io/gorm"
+)
+
+type MockProjectWorkRepository struct {
+	mock.Mock
+}
+
+func (m *MockProjectWorkRepository) ListMilestones(ctx context.Context, projectID int) ([]entities.Milestone, error) {
+	args := m.Called(ctx, projectID)
+	return args.Get(0).([]entities.Milestone), args.Error(1)
+}
+
+func (m *MockProjectWorkRepository) GetMilestone(ctx context.Context, projectID int, id int) (*entities.Milestone, error) {
+	args := m.Called(ctx, projectID, id)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*entities.Milestone), args.Error(1)
+}
+
+func (m *MockProjectWorkRepository) SaveMilestone(ctx context.Context, milestone *entities.Milestone) (*entities.Milestone, error) {
+	args := m.Called(ctx, milestone)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*entities.Milestone), args.Error(1)
+}
+
+func (m *MockProjectWorkRepository) DeleteMilestone(ctx context.Context, projectID int, id int) error {
+	args := m.Called(ctx, projectID, id)
+	return args.Error(0)
+}
+
+func (m *MockProjectWorkRepository) ListTasks(ctx context.Context, projectID int) ([]entities.Task, error) {
+	args := m.Called(ctx, projectID)
+	return args.Get(0).([]entities.Task), args.Error(1)
+}
+
+func (m *MockProjectWorkRepository) GetTask(ctx context.Context, projectID int, id int) (*entities.Task, error) {
+	args := m.Called(ctx, projectID, id)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*entities.Task), args.Error(1)
+}
+
+func (m *MockProjectWorkRepository) SaveTask(ctx context.Context, task *entities.Task) (*entities.Task, error) {
+	args := m.Called(ctx, task)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*entities.Task), args.Error(1)
+}
+
+func (m *MockProjectWorkRepository) DeleteTask(ctx context.Context, projectID int, id int) error {
+	args := m.Called(ctx, projectID, id)
+	return args.Error(0)
+}
+
+func (m *MockProjectWorkRepository) ProgressByProjects(ctx context.Context, projectIDs []int) (map[int]*entities.ProjectProgress, error) {
+	args := m.Called(ctx, projectIDs)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(map[int]*entities.ProjectProgress), args.Error(1)
+}
+
+// newWorkRepository repository mặc định cho các test không quan tâm tới milestone/task: project nào cũng chưa có tiến độ
+func newWorkRepository() *MockProjectWorkRepository {
+	work := new(MockProjectWorkRepository)
+	work.On("ProgressByProjects", mock.Anything, mock.Anything).Return(map[int]*entities.ProjectProgress{}, nil).Maybe()
+	return work
+}
+
+func newWorkService(repo *MockProjectRepository, memberRepo *MockProjectMemberRepository, work *MockProjectWorkRepository) use_cases.IProjectService {
+	return use_cases.NewProjectService(repo, memberRepo, new(MockProjectTransactionRepository), new(MockRevenueScheduleRepository), work, newCategoryRepository(),
+		pkg.NewCursorSigner(newTestConfig()), newTestRates(), eventbus.NewBus(), newTestConfig())
+}
+
+func TestProjectService_CreateTask_AssigneeMustBeMember(t *testing.T) {
+	mockRepo, memberRepo, work := new(MockProjectRepository), new(MockProjectMemberRepository), new(MockProjectWorkRepository)
+	service := newWorkService(mockRepo, memberRepo, work)
+	ctx := context.Background()
+	assignee := 9
+
+	mockRepo.On("GetById", ctx, 1).Return(&entities.Project{ID: 1}, nil)
+	memberRepo.On("GetMember", ctx, 1, assignee).Return(nil, fmt.Errorf("not a member: %w", gorm.ErrRecordNotFound))
+
+	_, err := service.CreateTask(ctx, 1, request.TaskRequest{Title: "Design", WorkItemRequest: request.WorkItemRequest{AssigneeID: &assignee}})
+
+	assert.ErrorIs(t, err, use_cases.ErrInvalidWorkItem)
+	work.AssertNotCalled(t, "SaveTask", mock.Anything, mock.Anything)
+}
+
+func TestProjectService_CreateTask_MilestoneOfAnotherProject(t *testing.T) {
+	mockRepo, work := new(MockProjectRepository), new(MockProjectWorkRepository)
+	service := newWorkService(mockRepo, new(MockProjectMemberRepository), work)
+	ctx := context.Background()
+	milestoneID := 4
+
+	mockRepo.On("GetById", ctx, 1).Return(&entities.Project{ID: 1}, nil)
+	work.On("GetMilestone", ctx, 1, milestoneID).Return(nil, fmt.Errorf("milestone not found: %w", gorm.ErrRecordNotFound))
+
+	_, err := service.CreateTask(ctx, 1, request.TaskRequest{Title: "Design", MilestoneID: &milestoneID})
+
+	assert.ErrorIs(t, err, use_cases.ErrInvalidWorkItem)
+}
+
+func TestProjectService_UpdateTask_TracksCompletion(t *testing.T) {
+	mockRepo, work := new(MockProjectRepository), new(MockProjectWorkRepository)
+	service := newWorkService(mockRepo, new(MockProjectMemberRepository), work)
+	ctx := context.Background()
+	completedAt := time.Date(2024, 3, 1, 10, 0, 0, 0, time.UTC)
+
+	mockRepo.On("GetById", ctx, 1).Return(&entities.Project{ID: 1}, nil)
+	task := &entities.Task{ID: 3, ProjectID: 1, Title: "Design", WorkItem: entities.WorkItem{Status: entities.WorkStatusInProgress}}
+	work.On("GetTask", ctx, 1, 3).Return(task, nil)
+	work.On("SaveTask", ctx, mock.AnythingOfType("*entities.Task")).Return(task, nil)
+
+	saved, err := service.UpdateTask(ctx, 1, 3, request.TaskRequest{Title: "Design", WorkItemRequest: request.WorkItemRequest{Status: entities.WorkStatusDone}})
+	require.NoError(t, err)
+	require.NotNil(t, saved.CompletedAt)
+
+	// lưu lại khi vẫn done không đổi thời điểm hoàn thành
+	saved.CompletedAt = &completedAt
+	saved, err = service.UpdateTask(ctx, 1, 3, request.TaskRequest{Title: "Design v2", WorkItemRequest: request.WorkItemRequest{Status: entities.WorkStatusDone}})
+	require.NoError(t, err)
+	assert.Equal(t, completedAt, *saved.CompletedAt)
+
+	saved, err = service.UpdateTask(ctx, 1, 3, request.TaskRequest{Title: "Design v2"})
+	require.NoError(t, err)
+	assert.Equal(t, entities.WorkStatusTodo, saved.Status)
+	assert.Nil(t, saved.CompletedAt)
+}
+
+func TestProjectService_WorkItems_TrashedProject(t *testing.T) {
+	mockRepo, work := new(MockProjectRepository), new(MockProjectWorkRepository)
+	service := newWorkService(mockRepo, new(MockProjectMemberRepository), work)
+	ctx := context.Background()
+
+	// project trong thùng rác không được GetById trả về
+	mockRepo.On("GetById", ctx, 1).Return((*entities.Project)(nil), fmt.Errorf("project not found: %w", gorm.ErrRecordNotFound))
+
+	_, err := service.GetMilestone(ctx, 1, 2)
+	assert.ErrorIs(t, err, gorm.ErrRecordNotFound)
+	_, err = service.UpdateTask(ctx, 1, 3, request.TaskRequest{Title: "Design"})
+	assert.ErrorIs(t, err, gorm.ErrRecordNotFound)
+	assert.ErrorIs(t, service.DeleteTask(ctx, 1, 3), gorm.ErrRecordNotFound)
+	work.AssertNotCalled(t, "GetMilestone", mock.Anything, mock.Anything, mock.Anything)
+	work.AssertNotCalled(t, "DeleteTask", mock.Anything, mock.Anything, mock.Anything)
+}
+
+func TestProjectService_GetById_AttachesProgress(t *testing.T) {
+	mockRepo, work := new(MockProjectRepository), new(MockProjectWorkRepository)
+	service := newWorkService(mockRepo, new(MockProjectMemberRepository), work)
+	ctx := context.Background()
+	progress := &entities.ProjectProgress{Tasks: entities.WorkCount{Total: 4, Done: 1}, Percent: 25}
+
+	mockRepo.On("GetById", ctx, 1).Return(&entities.Project{ID: 1}, nil)
+	work.On("ProgressByProjects", ctx, []int{1}).Return(map[int]*entities.ProjectProgress{1: progress}, nil)
+
+	project, err := service.GetById(ctx, 1)
+
+	require.NoError(t, err)
+	assert.Equal(t, progress, project.Progress)
+}
+
+func TestProjectProgress_ComputePercent(t *testing.T) {
+	var progress entities.ProjectProgress
+	progress.Milestones.Add(entities.WorkStatusDone, 1, 0)
+	progress.Milestones.Add(entities.WorkStatusTodo, 1, 0)
+	progress.ComputePercent()
+	assert.Equal(t, 50, progress.Percent, "no tasks: milestone count ratio")
+
+	progress.Tasks.Add(entities.WorkStatusDone, 1, 6)
+	progress.Tasks.Add(entities.WorkStatusInProgress, 2, 14)
+	progress.Tasks.Add(entities.WorkStatusCancelled, 5, 100)
+	progress.ComputePercent()
+	assert.Equal(t, int64(3), progress.Tasks.Total)
+	assert.Equal(t, 30, progress.Percent, "weighted by estimate, cancelled excluded")
+}
@@ -0,0 +1,127 @@
+package test
+
+import (
+	"Backend_golang_project/internal/domain/entities"
+	"Backend_golang_project/internal/repositories"
+	"Backend_golang_project/internal/use_cases"
+	"context"
+	"fmt"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"gorm.io/gorm"
+)
+
+// MockRoleRepository là một mock của IRoleRepository
+type MockRoleRepository struct {
+	mock.Mock
+}
+
+func (m *MockRoleRepository) ListRoles(ctx context.Context) ([]entities.Role, error) {
+	args := m.Called(ctx)
+	return args.Get(0).([]entities.Role), args.Error(1)
+}
+
+func (m *MockRoleRepository) GetRole(ctx context.Context, name string) (*entities.Role, error) {
+	args := m.Called(ctx, name)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*entities.Role), args.Error(1)
+}
+
+// seededRoles giống dữ liệu khởi tạo trong migration/005_roles_permissions.sql
+func seededRoles() []entities.Role {
+	permissions := func(names ...string) []entities.Permission {
+		result := make([]entities.Permission, 0, len(names))
+		for _, name := range names {
+			result = append(result, entities.Permission{Name: name})
+		}
+		return result
+	}
+	return []entities.Role{
+		{Name: entities.RoleAdmin, Permissions: permissions(
+			entities.PermissionProjectCreate, entities.PermissionProjectRead, entities.PermissionProjectUpdate,
+			entities.PermissionProjectDelete, entities.PermissionProjectReadAll, entities.PermissionProjectManageAll,
+			entities.PermissionUserRead, entities.PermissionUserExport, entities.PermissionRoleAssign,
+		)},
+		{Name: entities.RoleAuditor, Permissions: permissions(
+			entities.PermissionProjectRead, entities.PermissionProjectReadAll, entities.PermissionUserRead,
+		)},
+		{Name: entities.RoleMember, Permissions: permissions(
+			entities.PermissionProjectCreate, entities.PermissionProjectRead,
+			entities.PermissionProjectUpdate, entities.PermissionProjectDelete,
+		)},
+	}
+}
+
+func newSeededRoleRepository() *MockRoleRepository {
+	repo := new(MockRoleRepository)
+	repo.On("ListRoles", mock.Anything).Return(seededRoles(), nil)
+	for _, role := range seededRoles() {
+		role := role
+		repo.On("GetRole", mock.Anything, role.Name).Return(&role, nil)
+	}
+	return repo
+}
+
+func TestRoleService_HasPermission(t *testing.T) {
+	roleRepo := newSeededRoleRepository()
+	service := use_cases.NewRoleService(roleRepo, new(MockUserRepository))
+	ctx := context.Background()
+
+	granted, err := service.HasPermission(ctx, []string{entities.RoleAdmin}, entities.PermissionUserExport)
+	assert.NoError(t, err)
+	assert.True(t, granted)
+
+	granted, err = service.HasPermission(ctx, []string{entities.RoleMember}, entities.PermissionUserExport)
+	assert.NoError(t, err)
+	assert.False(t, granted)
+
+	granted, err = service.HasPermission(ctx, nil, entities.PermissionProjectRead)
+	assert.NoError(t, err)
+	assert.False(t, granted)
+
+	// lần gọi thứ hai đọc từ cache
+	roleRepo.AssertNumberOfCalls(t, "ListRoles", 1)
+}
+
+func TestRoleService_AssignRole(t *testing.T) {
+	userRepo := new(MockUserRepository)
+	service := use_cases.NewRoleService(newSeededRoleRepository(), userRepo)
+	ctx := context.Background()
+
+	userRepo.On("GetRole", ctx, 2).Return(entities.RoleMember, nil)
+	userRepo.On("UpdateRole", ctx, 2, entities.RoleAuditor).Return(nil)
+
+	err := service.AssignRole(ctx, 2, entities.RoleAuditor)
+
+	assert.NoError(t, err)
+	userRepo.AssertExpectations(t)
+}
+
+func TestRoleService_AssignRole_UnknownRole(t *testing.T) {
+	roleRepo := newSeededRoleRepository()
+	roleRepo.On("GetRole", mock.Anything, "superuser").Return(nil, fmt.Errorf("role superuser not found: %w", gorm.ErrRecordNotFound))
+	userRepo := new(MockUserRepository)
+	service := use_cases.NewRoleService(roleRepo, userRepo)
+
+	err := service.AssignRole(context.Background(), 2, "superuser")
+
+	assert.ErrorIs(t, err, use_cases.ErrInvalidRole)
+	userRepo.AssertNotCalled(t, "UpdateRole", mock.Anything, mock.Anything, mock.Anything)
+}
+
+func TestRoleService_AssignRole_LastAdmin(t *testing.T) {
+	userRepo := new(MockUserRepository)
+	service := use_cases.NewRoleService(newSeededRoleRepository(), userRepo)
+	ctx := context.Background()
+
+	userRepo.On("GetRole", ctx, 1).Return(entities.RoleAdmin, nil)
+	userRepo.On("UpdateRole", ctx, 1, entities.RoleMember).Return(repositories.ErrLastAdmin)
+
+	err := service.AssignRole(ctx, 1, entities.RoleMember)
+
+	assert.ErrorIs(t, err, use_cases.ErrLastAdmin)
+}
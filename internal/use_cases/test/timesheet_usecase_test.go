@@ -0,0 +1,264 @@
+package test
+
+import (
+	"Backend_golang_project/infrastructure/eventbus"
+	"Backend_golang_project/internal/domain/dto/request"
+	"Backend_golang_project/internal/domain/entities"
+	"Backend_golang_project/internal/domain/events"
+	"Backend_golang_project/internal/domain/money"
+	"Backend_golang_project/internal/pkg"
+	"Backend_golang_project/internal/repositories"
+	"Backend_golang_project/internal/use_cases"
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+)
+
+type MockTimesheetRepository struct {
+	mock.Mock
+}
+
+func (m *MockTimesheetRepository) SaveEntry(ctx context.Context, entry *entities.TimesheetEntry) (*entities.TimesheetEntry, error) {
+	args := m.Called(ctx, entry)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*entities.TimesheetEntry), args.Error(1)
+}
+
+func (m *MockTimesheetRepository) GetEntry(ctx context.Context, id int) (*entities.TimesheetEntry, error) {
+	args := m.Called(ctx, id)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*entities.TimesheetEntry), args.Error(1)
+}
+
+func (m *MockTimesheetRepository) DeleteEntry(ctx context.Context, id int) error {
+	args := m.Called(ctx, id)
+	return args.Error(0)
+}
+
+func (m *MockTimesheetRepository) Get(ctx context.Context, id int) (*entities.Timesheet, error) {
+	args := m.Called(ctx, id)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*entities.Timesheet), args.Error(1)
+}
+
+func (m *MockTimesheetRepository) List(ctx context.Context, query repositories.TimesheetQuery) ([]entities.Timesheet, error) {
+	args := m.Called(ctx, query)
+	return args.Get(0).([]entities.Timesheet), args.Error(1)
+}
+
+func (m *MockTimesheetRepository) Submit(ctx context.Context, id int, submittedAt time.Time) error {
+	args := m.Called(ctx, id, submittedAt)
+	return args.Error(0)
+}
+
+func (m *MockTimesheetRepository) Reject(ctx context.Context, id int, reviewerID int, note string, reviewedAt time.Time) error {
+	args := m.Called(ctx, id, reviewerID, note, reviewedAt)
+	return args.Error(0)
+}
+
+func (m *MockTimesheetRepository) Approve(ctx context.Context, id int, reviewerID int, rate money.Money, reviewedAt time.Time) (*entities.Timesheet, *entities.Project, error) {
+	args := m.Called(ctx, id, reviewerID, rate, reviewedAt)
+	if args.Get(0) == nil {
+		return nil, nil, args.Error(2)
+	}
+	return args.Get(0).(*entities.Timesheet), args.Get(1).(*entities.Project), args.Error(2)
+}
+
+func (m *MockTimesheetRepository) Summary(ctx context.Context, query repositories.TimesheetSummaryQuery) ([]entities.TimesheetSummary, error) {
+	args := m.Called(ctx, query)
+	return args.Get(0).([]entities.TimesheetSummary), args.Error(1)
+}
+
+func newTimesheetService(timesheets *MockTimesheetRepository, repo *MockProjectRepository, memberRepo *MockProjectMemberRepository, userRepo *MockUserRepository, bus eventbus.Bus) use_cases.ITimesheetService {
+	roleService := use_cases.NewRoleService(newSeededRoleRepository(), userRepo)
+	return use_cases.NewTimesheetService(timesheets, repo, memberRepo, userRepo, new(MockProjectWorkRepository),
+		newTestRates(), bus, roleService, newTestConfig())
+}
+
+func stubMember(ctx context.Context, userRepo *MockUserRepository, memberRepo *MockProjectMemberRepository, projectID, userID int, role string) {
+	userRepo.On("GetRole", ctx, userID).Return(entities.RoleMember, nil)
+	memberRepo.On("GetMember", ctx, projectID, userID).Return(&entities.UserProject{ProjectID: projectID, UserID: userID, Role: role}, nil)
+}
+
+func TestTimesheetService_LogTime_Validation(t *testing.T) {
+	timesheets := new(MockTimesheetRepository)
+	repo := new(MockProjectRepository)
+	memberRepo := new(MockProjectMemberRepository)
+	userRepo := new(MockUserRepository)
+	service := newTimesheetService(timesheets, repo, memberRepo, userRepo, eventbus.NewBus())
+	ctx := pkg.WithUserID(context.Background(), 5)
+	start := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+	end := time.Date(2024, 6, 30, 0, 0, 0, 0, time.UTC)
+	repo.On("GetById", ctx, 1).Return(&entities.Project{ID: 1, Status: entities.ProjectStatusActive, ProjectStartedAt: start, ProjectEndedAt: &end}, nil)
+	repo.On("GetById", ctx, 2).Return(&entities.Project{ID: 2, Status: entities.ProjectStatusDraft, ProjectStartedAt: start}, nil)
+	repo.On("GetById", ctx, 3).Return(&entities.Project{ID: 3, Status: entities.ProjectStatusActive, ProjectStartedAt: start}, nil)
+	stubMember(ctx, userRepo, memberRepo, 1, 5, entities.ProjectRoleContributor)
+	stubMember(ctx, userRepo, memberRepo, 2, 5, entities.ProjectRoleContributor)
+	stubMember(ctx, userRepo, memberRepo, 3, 5, entities.ProjectRoleViewer)
+
+	cases := map[string]struct {
+		projectID int
+		workDate  time.Time
+		expected  error
+	}{
+		"before start":  {1, start.AddDate(0, 0, -1), use_cases.ErrInvalidTimesheet},
+		"after end":     {1, end.AddDate(0, 0, 1), use_cases.ErrInvalidTimesheet},
+		"draft project": {2, start.AddDate(0, 0, 1), use_cases.ErrInvalidTimesheet},
+		"viewer":        {3, start.AddDate(0, 0, 1), use_cases.ErrProjectAccessDenied},
+		"future":        {3, time.Now().AddDate(0, 0, 2), use_cases.ErrProjectAccessDenied},
+	}
+	for name, c := range cases {
+		req := request.LogTimeRequest{ProjectID: c.projectID, TimesheetEntryRequest: request.TimesheetEntryRequest{WorkDate: c.workDate, Hours: 2}}
+
+		_, err := service.LogTime(ctx, req)
+
+		assert.ErrorIs(t, err, c.expected, name)
+	}
+	timesheets.AssertNotCalled(t, "SaveEntry", mock.Anything, mock.Anything)
+}
+
+func TestTimesheetService_LogTime_Saves(t *testing.T) {
+	timesheets := new(MockTimesheetRepository)
+	repo := new(MockProjectRepository)
+	memberRepo := new(MockProjectMemberRepository)
+	userRepo := new(MockUserRepository)
+	service := newTimesheetService(timesheets, repo, memberRepo, userRepo, eventbus.NewBus())
+	ctx := pkg.WithUserID(context.Background(), 5)
+	workDate := time.Date(2024, 3, 6, 14, 30, 0, 0, time.UTC)
+	repo.On("GetById", ctx, 1).Return(&entities.Project{ID: 1, Status: entities.ProjectStatusOnHold,
+		ProjectStartedAt: time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)}, nil)
+	stubMember(ctx, userRepo, memberRepo, 1, 5, entities.ProjectRoleContributor)
+	timesheets.On("SaveEntry", ctx, mock.MatchedBy(func(entry *entities.TimesheetEntry) bool {
+		return entry.UserID == 5 && entry.ProjectID == 1 && entry.WorkDate.Equal(entities.Day(workDate)) && entry.Hours == 1.5
+	})).Return(&entities.TimesheetEntry{ID: 9, UserID: 5, ProjectID: 1, WorkDate: entities.Day(workDate), Hours: 1.5}, nil)
+
+	entry, err := service.LogTime(ctx, request.LogTimeRequest{ProjectID: 1,
+		TimesheetEntryRequest: request.TimesheetEntryRequest{WorkDate: workDate, Hours: 1.5, Description: " Standup "}})
+
+	require.NoError(t, err)
+	assert.Equal(t, 9, entry.ID)
+	timesheets.AssertExpectations(t)
+}
+
+func TestTimesheetService_Approve_OwnTimesheetForbidden(t *testing.T) {
+	timesheets := new(MockTimesheetRepository)
+	memberRepo := new(MockProjectMemberRepository)
+	userRepo := new(MockUserRepository)
+	service := newTimesheetService(timesheets, new(MockProjectRepository), memberRepo, userRepo, eventbus.NewBus())
+	ctx := pkg.WithUserID(context.Background(), 5)
+	timesheets.On("Get", ctx, 3).Return(&entities.Timesheet{ID: 3, UserID: 5, ProjectID: 1, Status: entities.TimesheetStatusSubmitted}, nil)
+	stubMember(ctx, userRepo, memberRepo, 1, 5, entities.ProjectRoleManager)
+
+	_, err := service.Approve(ctx, 3)
+
+	assert.ErrorIs(t, err, use_cases.ErrForbidden)
+	timesheets.AssertNotCalled(t, "Approve", mock.Anything, mock.Anything, mock.Anything, mock.Anything, mock.Anything)
+}
+
+func TestTimesheetService_Approve_ContributorDenied(t *testing.T) {
+	timesheets := new(MockTimesheetRepository)
+	memberRepo := new(MockProjectMemberRepository)
+	userRepo := new(MockUserRepository)
+	service := newTimesheetService(timesheets, new(MockProjectRepository), memberRepo, userRepo, eventbus.NewBus())
+	ctx := pkg.WithUserID(context.Background(), 7)
+	timesheets.On("Get", ctx, 3).Return(&entities.Timesheet{ID: 3, UserID: 5, ProjectID: 1, Status: entities.TimesheetStatusSubmitted}, nil)
+	stubMember(ctx, userRepo, memberRepo, 1, 7, entities.ProjectRoleContributor)
+
+	_, err := service.Approve(ctx, 3)
+
+	assert.ErrorIs(t, err, use_cases.ErrProjectAccessDenied)
+}
+
+func TestTimesheetService_Approve_ConvertsRateAndAlertsBudget(t *testing.T) {
+	timesheets := new(MockTimesheetRepository)
+	repo := new(MockProjectRepository)
+	memberRepo := new(MockProjectMemberRepository)
+	userRepo := new(MockUserRepository)
+	bus := eventbus.NewBus()
+	service := newTimesheetService(timesheets, repo, memberRepo, userRepo, bus)
+	ctx := pkg.WithUserID(context.Background(), 7)
+	var published []events.ProjectOverBudget
+	bus.Subscribe(events.ProjectOverBudgetEvent, func(_ context.Context, event eventbus.Event) error {
+		published = append(published, event.(events.ProjectOverBudget))
+		return nil
+	})
+
+	timesheets.On("Get", ctx, 3).Return(&entities.Timesheet{ID: 3, UserID: 5, ProjectID: 1, Status: entities.TimesheetStatusSubmitted}, nil)
+	stubMember(ctx, userRepo, memberRepo, 1, 7, entities.ProjectRoleManager)
+	repo.On("GetById", ctx, 1).Return(&entities.Project{ID: 1, Budget: money.New(5000000, "VND"), ProjectSpend: money.New(0, "VND")}, nil)
+	// 40.00 USD/giờ = 1.000.000 VND/giờ
+	userRepo.On("GetHourlyRate", ctx, 5).Return(money.New(4000, "USD"), nil)
+	approved := &entities.Timesheet{ID: 3, UserID: 5, ProjectID: 1, Status: entities.TimesheetStatusApproved, Cost: money.New(5000000, "VND")}
+	after := &entities.Project{ID: 1, Budget: money.New(5000000, "VND"), ProjectSpend: money.New(5000000, "VND")}
+	timesheets.On("Approve", ctx, 3, 7, money.New(1000000, "VND"), mock.AnythingOfType("time.Time")).Return(approved, after, nil)
+
+	result, err := service.Approve(ctx, 3)
+
+	require.NoError(t, err)
+	assert.Equal(t, entities.TimesheetStatusApproved, result.Status)
+	require.Len(t, published, 1)
+	assert.Equal(t, 1, published[0].ProjectID)
+	timesheets.AssertExpectations(t)
+}
+
+func TestTimesheetService_Reject_AdminWithoutMembership(t *testing.T) {
+	timesheets := new(MockTimesheetRepository)
+	memberRepo := new(MockProjectMemberRepository)
+	userRepo := new(MockUserRepository)
+	service := newTimesheetService(timesheets, new(MockProjectRepository), memberRepo, userRepo, eventbus.NewBus())
+	ctx := pkg.WithUserID(context.Background(), 9)
+	userRepo.On("GetRole", ctx, 9).Return(entities.RoleAdmin, nil)
+	timesheets.On("Get", ctx, 3).Return(&entities.Timesheet{ID: 3, UserID: 5, ProjectID: 1, Status: entities.TimesheetStatusSubmitted}, nil).Once()
+	timesheets.On("Reject", ctx, 3, 9, "missing tasks", mock.AnythingOfType("time.Time")).Return(nil)
+	timesheets.On("Get", ctx, 3).Return(&entities.Timesheet{ID: 3, UserID: 5, ProjectID: 1, Status: entities.TimesheetStatusRejected}, nil)
+
+	result, err := service.Reject(ctx, 3, request.RejectTimesheetRequest{Note: "missing tasks"})
+
+	require.NoError(t, err)
+	assert.Equal(t, entities.TimesheetStatusRejected, result.Status)
+	memberRepo.AssertNotCalled(t, "GetMember", mock.Anything, mock.Anything, mock.Anything)
+}
+
+func TestTimesheetService_ProjectSummary_AuditorWithoutMembership(t *testing.T) {
+	timesheets := new(MockTimesheetRepository)
+	memberRepo := new(MockProjectMemberRepository)
+	userRepo := new(MockUserRepository)
+	service := newTimesheetService(timesheets, new(MockProjectRepository), memberRepo, userRepo, eventbus.NewBus())
+	ctx := pkg.WithUserID(context.Background(), 8)
+	userRepo.On("GetRole", ctx, 8).Return(entities.RoleAuditor, nil)
+	timesheets.On("Summary", ctx, mock.Anything).Return([]entities.TimesheetSummary{}, nil)
+
+	_, err := service.ProjectSummary(ctx, 1, request.TimesheetSummaryRequest{})
+
+	require.NoError(t, err)
+	memberRepo.AssertNotCalled(t, "GetMember", mock.Anything, mock.Anything, mock.Anything)
+}
+
+func TestTimesheetService_Submit_EmptyTimesheet(t *testing.T) {
+	timesheets := new(MockTimesheetRepository)
+	service := newTimesheetService(timesheets, new(MockProjectRepository), new(MockProjectMemberRepository), new(MockUserRepository), eventbus.NewBus())
+	ctx := pkg.WithUserID(context.Background(), 5)
+	timesheets.On("Get", ctx, 3).Return(&entities.Timesheet{ID: 3, UserID: 5, ProjectID: 1, Status: entities.TimesheetStatusDraft}, nil)
+
+	_, err := service.Submit(ctx, 3)
+
+	assert.ErrorIs(t, err, use_cases.ErrInvalidTimesheet)
+}
+
+func TestEntryCost(t *testing.T) {
+	assert.Equal(t, int64(10000), entities.EntryCost(2.5, 4000))
+	// giờ công được làm tròn tới 0.01 giờ trước khi nhân đơn giá
+	assert.Equal(t, int64(330), entities.EntryCost(1.0/3, 1000))
+	assert.Equal(t, int64(0), entities.EntryCost(8, 0))
+	assert.True(t, entities.WeekStart(time.Date(2024, 3, 10, 23, 0, 0, 0, time.UTC)).Equal(time.Date(2024, 3, 4, 0, 0, 0, 0, time.UTC)))
+}
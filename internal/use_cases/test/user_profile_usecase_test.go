@@ -0,0 +1,121 @@
+package test
+
+import (
+	dto "Backend_golang_project/internal/domain/dto/request"
+	"Backend_golang_project/internal/domain/entities"
+	"Backend_golang_project/internal/pkg"
+	"Backend_golang_project/internal/repositories"
+	"Backend_golang_project/internal/use_cases"
+	"context"
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+)
+
+func newProfileUserService(t *testing.T, userRepo *MockUserRepository, accounts *MockAccountService, tokenStore repositories.IRefreshTokenStore) use_cases.IUserService {
+	cfg := newTestConfig()
+	return use_cases.NewUserService(cfg, userRepo, nil, tokenStore, newTestKeySet(t, cfg),
+		repositories.NewInMemoryLoginAttemptStore(), accounts)
+}
+
+func newProfileUser(t *testing.T) *entities.User {
+	hash, err := pkg.NewPasswordHasher(pkg.PasswordAlgorithmBcrypt, 0, pkg.Argon2Params{}).Hash("Passw0rd1")
+	require.NoError(t, err)
+	verifiedAt := time.Now().Add(-time.Hour)
+	return &entities.User{ID: 5, Email: "erin@example.com", Username: "erin", Password: hash, EmailVerifiedAt: &verifiedAt}
+}
+
+func TestUserService_UpdateProfile_EmailChangeRequiresVerification(t *testing.T) {
+	userRepo := new(MockUserRepository)
+	accounts := new(MockAccountService)
+	service := newProfileUserService(t, userRepo, accounts, repositories.NewInMemoryRefreshTokenStore())
+	ctx := pkg.WithUserID(context.Background(), 5)
+	user := newProfileUser(t)
+	newEmail := "erin@new.example.com"
+	updated := &entities.User{ID: 5, Email: newEmail, Username: "erin"}
+
+	userRepo.On("GetUserById", ctx, 5).Return(user, nil).Once()
+	userRepo.On("UpdateProfile", ctx, 5, map[string]interface{}{
+		"email":             newEmail,
+		"email_verified_at": nil,
+	}).Return(nil)
+	userRepo.On("GetUserById", ctx, 5).Return(updated, nil).Once()
+	accounts.On("SendVerification", ctx, updated).Return(nil)
+
+	result, err := service.UpdateProfile(ctx, dto.UpdateProfileRequest{Email: &newEmail})
+
+	require.NoError(t, err)
+	assert.Nil(t, result.EmailVerifiedAt)
+	userRepo.AssertExpectations(t)
+	accounts.AssertExpectations(t)
+}
+
+func TestUserService_UpdateProfile_DuplicateEmail(t *testing.T) {
+	userRepo := new(MockUserRepository)
+	accounts := new(MockAccountService)
+	service := newProfileUserService(t, userRepo, accounts, repositories.NewInMemoryRefreshTokenStore())
+	ctx := pkg.WithUserID(context.Background(), 5)
+	user := newProfileUser(t)
+	taken := "taken@example.com"
+
+	userRepo.On("GetUserById", ctx, 5).Return(user, nil)
+	userRepo.On("UpdateProfile", ctx, 5, mock.Anything).Return(repositories.ErrEmailAlreadyExists)
+
+	_, err := service.UpdateProfile(ctx, dto.UpdateProfileRequest{Email: &taken})
+
+	assert.ErrorIs(t, err, repositories.ErrEmailAlreadyExists)
+	accounts.AssertNotCalled(t, "SendVerification", mock.Anything, mock.Anything)
+}
+
+func TestUserService_ChangePassword_RequiresCurrentPassword(t *testing.T) {
+	userRepo := new(MockUserRepository)
+	service := newProfileUserService(t, userRepo, new(MockAccountService), repositories.NewInMemoryRefreshTokenStore())
+	ctx := pkg.WithUserID(context.Background(), 5)
+	user := newProfileUser(t)
+	userRepo.On("GetUserById", ctx, 5).Return(user, nil)
+
+	err := service.ChangePassword(ctx, dto.ChangePasswordRequest{CurrentPassword: "wrong", NewPassword: "NewPassw0rd"})
+
+	assert.ErrorIs(t, err, use_cases.ErrInvalidCredentials)
+	userRepo.AssertNotCalled(t, "UpdatePassword", mock.Anything, mock.Anything, mock.Anything)
+}
+
+func TestUserService_DeleteAccount_SoftDeletesAndRevokesSessions(t *testing.T) {
+	userRepo := new(MockUserRepository)
+	tokenStore := repositories.NewInMemoryRefreshTokenStore()
+	service := newProfileUserService(t, userRepo, new(MockAccountService), tokenStore)
+	ctx := pkg.WithUserID(context.Background(), 5)
+	user := newProfileUser(t)
+	require.NoError(t, tokenStore.Save(ctx, &entities.RefreshToken{JTI: "jti-5", FamilyID: "family-5", UserID: 5, ExpiresAt: time.Now().Add(time.Hour)}))
+	userRepo.On("GetUserById", ctx, 5).Return(user, nil)
+	userRepo.On("DeleteUser", ctx, 5).Return(nil)
+
+	err := service.DeleteAccount(ctx, dto.DeleteAccountRequest{Password: "Passw0rd1"})
+
+	require.NoError(t, err)
+	stored, err := tokenStore.GetByJTI(ctx, "jti-5")
+	require.NoError(t, err)
+	assert.True(t, stored.IsRevoked())
+}
+
+func TestUserService_DeleteAccount_SoleProjectOwnerKeepsSessions(t *testing.T) {
+	userRepo := new(MockUserRepository)
+	tokenStore := repositories.NewInMemoryRefreshTokenStore()
+	service := newProfileUserService(t, userRepo, new(MockAccountService), tokenStore)
+	ctx := pkg.WithUserID(context.Background(), 5)
+	user := newProfileUser(t)
+	require.NoError(t, tokenStore.Save(ctx, &entities.RefreshToken{JTI: "jti-6", FamilyID: "family-6", UserID: 5, ExpiresAt: time.Now().Add(time.Hour)}))
+	userRepo.On("GetUserById", ctx, 5).Return(user, nil)
+	userRepo.On("DeleteUser", ctx, 5).Return(fmt.Errorf("%w: [7]", repositories.ErrUserOwnsProjects))
+
+	err := service.DeleteAccount(ctx, dto.DeleteAccountRequest{Password: "Passw0rd1"})
+
+	assert.ErrorIs(t, err, use_cases.ErrUserOwnsProjects)
+	stored, err := tokenStore.GetByJTI(ctx, "jti-6")
+	require.NoError(t, err)
+	assert.False(t, stored.IsRevoked())
+}
@@ -0,0 +1,137 @@
+package test
+
+import (
+	"Backend_golang_project/infrastructure/config"
+	"Backend_golang_project/infrastructure/middleware/jwt"
+	dto "Backend_golang_project/internal/domain/dto/request"
+	"Backend_golang_project/internal/domain/entities"
+	"Backend_golang_project/internal/repositories"
+	"Backend_golang_project/internal/use_cases"
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+)
+
+func newTestConfig() *config.Config {
+	cfg := &config.Config{}
+	cfg.JwtConfig.SecretKey = "test-secret-key"
+	cfg.JwtConfig.Issuer = "golang-web"
+	cfg.JwtConfig.Audience = []string{"golang-web-api"}
+	cfg.JwtConfig.AccessTokenExp = 10
+	cfg.JwtConfig.RefreshTokenExp = 60
+	return cfg
+}
+
+func newTestKeySet(t *testing.T, cfg *config.Config) *jwt.KeySet {
+	keySet, err := jwt.NewKeySet(cfg)
+	require.NoError(t, err)
+	return keySet
+}
+
+// seedRefreshToken giả lập một lần đăng nhập: lưu token vào store và trả về refresh token đã mã hóa
+func seedRefreshToken(t *testing.T, cfg *config.Config, keySet *jwt.KeySet, store repositories.IRefreshTokenStore, userID int, jti string, familyID string) string {
+	err := store.Save(context.Background(), &entities.RefreshToken{
+		JTI:       jti,
+		FamilyID:  familyID,
+		UserID:    userID,
+		ExpiresAt: time.Now().Add(time.Hour),
+	})
+	require.NoError(t, err)
+
+	_, refreshToken, err := jwt.GenerateJwtToken(cfg, keySet, jwt.TokenSubject{ID: userID}, jti)
+	require.NoError(t, err)
+	return refreshToken
+}
+
+func TestUserService_RefreshToken_Rotates(t *testing.T) {
+	cfg := newTestConfig()
+	store := repositories.NewInMemoryRefreshTokenStore()
+	keySet := newTestKeySet(t, cfg)
+	userRepo := new(MockUserRepository)
+	userRepo.On("GetRole", mock.Anything, 1).Return(entities.UserRoleMember, nil)
+	service := use_cases.NewUserService(cfg, userRepo, nil, store, keySet, repositories.NewInMemoryLoginAttemptStore(), nil)
+	ctx := context.Background()
+
+	oldToken := seedRefreshToken(t, cfg, keySet, store, 1, "jti-1", "family-1")
+
+	accessToken, newToken, err := service.RefreshToken(ctx, &dto.RefreshTokenRequest{RefreshToken: oldToken})
+
+	require.NoError(t, err)
+	assert.NotEmpty(t, accessToken)
+	assert.NotEqual(t, oldToken, newToken)
+
+	old, err := store.GetByJTI(ctx, "jti-1")
+	require.NoError(t, err)
+	assert.True(t, old.IsRevoked())
+	assert.NotEmpty(t, old.ReplacedBy)
+
+	next, err := store.GetByJTI(ctx, old.ReplacedBy)
+	require.NoError(t, err)
+	assert.Equal(t, "family-1", next.FamilyID)
+	assert.False(t, next.IsRevoked())
+}
+
+func TestUserService_RefreshToken_ReuseRevokesFamily(t *testing.T) {
+	cfg := newTestConfig()
+	store := repositories.NewInMemoryRefreshTokenStore()
+	keySet := newTestKeySet(t, cfg)
+	userRepo := new(MockUserRepository)
+	userRepo.On("GetRole", mock.Anything, 1).Return(entities.UserRoleMember, nil)
+	service := use_cases.NewUserService(cfg, userRepo, nil, store, keySet, repositories.NewInMemoryLoginAttemptStore(), nil)
+	ctx := context.Background()
+
+	oldToken := seedRefreshToken(t, cfg, keySet, store, 1, "jti-1", "family-1")
+	_, _, err := service.RefreshToken(ctx, &dto.RefreshTokenRequest{RefreshToken: oldToken})
+	require.NoError(t, err)
+
+	// gửi lại token đã bị rotate
+	_, _, err = service.RefreshToken(ctx, &dto.RefreshTokenRequest{RefreshToken: oldToken})
+	assert.ErrorIs(t, err, use_cases.ErrRefreshTokenReused)
+
+	old, err := store.GetByJTI(ctx, "jti-1")
+	require.NoError(t, err)
+	next, err := store.GetByJTI(ctx, old.ReplacedBy)
+	require.NoError(t, err)
+	assert.True(t, next.IsRevoked(), "the successor token must be revoked together with its family")
+}
+
+func TestUserService_RefreshToken_UnknownToken(t *testing.T) {
+	cfg := newTestConfig()
+	store := repositories.NewInMemoryRefreshTokenStore()
+	keySet := newTestKeySet(t, cfg)
+	userRepo := new(MockUserRepository)
+	userRepo.On("GetRole", mock.Anything, 1).Return(entities.UserRoleMember, nil)
+	service := use_cases.NewUserService(cfg, userRepo, nil, store, keySet, repositories.NewInMemoryLoginAttemptStore(), nil)
+
+	_, refreshToken, err := jwt.GenerateJwtToken(cfg, keySet, jwt.TokenSubject{ID: 1}, "not-stored")
+	require.NoError(t, err)
+
+	_, _, err = service.RefreshToken(context.Background(), &dto.RefreshTokenRequest{RefreshToken: refreshToken})
+
+	assert.ErrorIs(t, err, use_cases.ErrInvalidRefreshToken)
+}
+
+func TestUserService_Logout_RevokesFamily(t *testing.T) {
+	cfg := newTestConfig()
+	store := repositories.NewInMemoryRefreshTokenStore()
+	keySet := newTestKeySet(t, cfg)
+	userRepo := new(MockUserRepository)
+	userRepo.On("GetRole", mock.Anything, 1).Return(entities.UserRoleMember, nil)
+	service := use_cases.NewUserService(cfg, userRepo, nil, store, keySet, repositories.NewInMemoryLoginAttemptStore(), nil)
+	ctx := context.Background()
+
+	token := seedRefreshToken(t, cfg, keySet, store, 1, "jti-1", "family-1")
+	seedRefreshToken(t, cfg, keySet, store, 1, "jti-2", "family-2")
+
+	err := service.Logout(ctx, &dto.RefreshTokenRequest{RefreshToken: token})
+	require.NoError(t, err)
+
+	loggedOut, _ := store.GetByJTI(ctx, "jti-1")
+	otherSession, _ := store.GetByJTI(ctx, "jti-2")
+	assert.True(t, loggedOut.IsRevoked())
+	assert.False(t, otherSession.IsRevoked())
+}
@@ -0,0 +1,395 @@
+package use_cases
+
+import (
+	"Backend_golang_project/infrastructure/config"
+	"Backend_golang_project/infrastructure/eventbus"
+	"Backend_golang_project/infrastructure/exchange"
+	"Backend_golang_project/internal/domain/dto/request"
+	"Backend_golang_project/internal/domain/entities"
+	"Backend_golang_project/internal/domain/money"
+	"Backend_golang_project/internal/pkg"
+	"Backend_golang_project/internal/repositories"
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	log "github.com/sirupsen/logrus"
+	"gorm.io/gorm"
+)
+
+type ITimesheetService interface {
+	SetHourlyRate(ctx context.Context, userID int, request request.HourlyRateRequest) (money.Money, error)
+	LogTime(ctx context.Context, request request.LogTimeRequest) (*entities.TimesheetEntry, error)
+	UpdateEntry(ctx context.Context, id int, request request.TimesheetEntryRequest) (*entities.TimesheetEntry, error)
+	DeleteEntry(ctx context.Context, id int) error
+	ListMyTimesheets(ctx context.Context, request request.ListTimesheetsRequest) ([]entities.Timesheet, error)
+	Submit(ctx context.Context, id int) (*entities.Timesheet, error)
+	ListProjectTimesheets(ctx context.Context, projectID int, request request.ListTimesheetsRequest) ([]entities.Timesheet, error)
+	Approve(ctx context.Context, id int) (*entities.Timesheet, error)
+	Reject(ctx context.Context, id int, request request.RejectTimesheetRequest) (*entities.Timesheet, error)
+	ProjectSummary(ctx context.Context, projectID int, request request.TimesheetSummaryRequest) ([]entities.TimesheetSummary, error)
+	UserSummary(ctx context.Context, userID int, request request.TimesheetSummaryRequest) ([]entities.TimesheetSummary, error)
+}
+
+// TimesheetService ghi nhận giờ công của thành viên project theo tuần. Owner/manager/contributor được ghi giờ
+// cho chính mình, owner/manager (hoặc role có project:manage_all) duyệt timesheet của người khác; chi phí của timesheet đã duyệt được ghi vào sổ cái
+type TimesheetService struct {
+	timesheets        repositories.ITimesheetRepository
+	projectRepository repositories.IProjectRepository
+	memberRepository  repositories.IProjectMemberRepository
+	userRepository    repositories.IUserRepository
+	work              repositories.IProjectWorkRepository
+	rates             exchange.RateProvider
+	events            eventbus.Bus
+	// policy dùng chung kiểm tra membership và quyền bypass (project:read_all, project:manage_all) với ProjectPolicy
+	policy ProjectPolicy
+
+	budgetAlertThreshold int
+}
+
+// SetHourlyRate đổi đơn giá của user, chỉ áp dụng cho các timesheet được duyệt sau đó
+func (s TimesheetService) SetHourlyRate(ctx context.Context, userID int, req request.HourlyRateRequest) (money.Money, error) {
+	rate := money.New(req.Amount, req.Currency)
+	if !money.IsSupported(rate.Currency) {
+		return money.Money{}, fmt.Errorf("%w: %q", money.ErrUnsupportedCurrency, rate.Currency)
+	}
+	if _, err := s.userRepository.GetRole(ctx, userID); err != nil {
+		return money.Money{}, err
+	}
+	if err := s.userRepository.UpdateHourlyRate(ctx, userID, rate); err != nil {
+		return money.Money{}, err
+	}
+
+	log.WithFields(log.Fields{
+		"user_id": userID,
+		"rate":    rate.String(),
+	}).Info("User hourly rate updated")
+	return rate, nil
+}
+
+func (s TimesheetService) LogTime(ctx context.Context, req request.LogTimeRequest) (*entities.TimesheetEntry, error) {
+	callerID, ok := pkg.UserIDFromContext(ctx)
+	if !ok {
+		return nil, ErrForbidden
+	}
+	entry := req.ToTimesheetEntryEntity(callerID)
+	if err := s.validateEntry(ctx, entry); err != nil {
+		return nil, err
+	}
+	return s.saveEntry(ctx, entry)
+}
+
+func (s TimesheetService) UpdateEntry(ctx context.Context, id int, req request.TimesheetEntryRequest) (*entities.TimesheetEntry, error) {
+	entry, err := s.ownEntry(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	req.ApplyTo(entry)
+	if err := s.validateEntry(ctx, entry); err != nil {
+		return nil, err
+	}
+	return s.saveEntry(ctx, entry)
+}
+
+func (s TimesheetService) DeleteEntry(ctx context.Context, id int) error {
+	if _, err := s.ownEntry(ctx, id); err != nil {
+		return err
+	}
+	if err := s.timesheets.DeleteEntry(ctx, id); err != nil {
+		return timesheetError(err)
+	}
+	return nil
+}
+
+// ListMyTimesheets timesheet của người gọi trên mọi project trong tuần, bỏ trống week thì là tuần hiện tại
+func (s TimesheetService) ListMyTimesheets(ctx context.Context, req request.ListTimesheetsRequest) ([]entities.Timesheet, error) {
+	callerID, ok := pkg.UserIDFromContext(ctx)
+	if !ok {
+		return nil, ErrForbidden
+	}
+	week := entities.WeekStart(time.Now())
+	if req.Week != nil {
+		week = entities.WeekStart(*req.Week)
+	}
+	return s.timesheets.List(ctx, repositories.TimesheetQuery{UserID: callerID, WeekStart: &week, Status: req.Status})
+}
+
+// Submit nộp timesheet của chính người gọi để manager duyệt, timesheet bị từ chối được sửa rồi nộp lại
+func (s TimesheetService) Submit(ctx context.Context, id int) (*entities.Timesheet, error) {
+	callerID, ok := pkg.UserIDFromContext(ctx)
+	if !ok {
+		return nil, ErrForbidden
+	}
+	timesheet, err := s.timesheets.Get(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	if timesheet.UserID != callerID {
+		return nil, ErrForbidden
+	}
+	if len(timesheet.Entries) == 0 {
+		return nil, fmt.Errorf("%w: timesheet %d has no entries", ErrInvalidTimesheet, id)
+	}
+
+	if err := s.timesheets.Submit(ctx, id, time.Now()); err != nil {
+		return nil, timesheetError(err)
+	}
+	log.WithFields(log.Fields{
+		"timesheet_id": id,
+		"project_id":   timesheet.ProjectID,
+		"week_start":   timesheet.WeekStart.Format(time.DateOnly),
+		"hours":        timesheet.TotalHours(),
+	}).Info("Timesheet submitted")
+	return s.timesheets.Get(ctx, id)
+}
+
+// ListProjectTimesheets danh sách timesheet của project cho owner/manager hoặc role có project:read_all,
+// ví dụ ?status=submitted là hàng chờ duyệt
+func (s TimesheetService) ListProjectTimesheets(ctx context.Context, projectID int, req request.ListTimesheetsRequest) ([]entities.Timesheet, error) {
+	if err := s.policy.authorize(ctx, projectID, updateRoles, entities.PermissionProjectReadAll); err != nil {
+		return nil, err
+	}
+	query := repositories.TimesheetQuery{ProjectID: projectID, Status: req.Status}
+	if req.Week != nil {
+		week := entities.WeekStart(*req.Week)
+		query.WeekStart = &week
+	}
+	return s.timesheets.List(ctx, query)
+}
+
+// Approve duyệt timesheet theo đơn giá hiện tại của user (quy đổi sang đơn vị tiền của project),
+// chi phí được cộng vào ProjectSpend. Người duyệt không được duyệt timesheet của chính mình
+func (s TimesheetService) Approve(ctx context.Context, id int) (*entities.Timesheet, error) {
+	timesheet, reviewerID, err := s.reviewable(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	project, err := s.projectRepository.GetById(ctx, timesheet.ProjectID)
+	if err != nil {
+		return nil, err
+	}
+	rate, err := s.userRepository.GetHourlyRate(ctx, timesheet.UserID)
+	if err != nil {
+		return nil, err
+	}
+	if rate.Currency == "" {
+		rate.Currency = money.DefaultCurrency
+	}
+	rate, err = convertMoney(ctx, s.rates, rate, project.Currency())
+	if err != nil {
+		return nil, err
+	}
+
+	approved, project, err := s.timesheets.Approve(ctx, id, reviewerID, rate, time.Now())
+	if err != nil {
+		return nil, timesheetError(err)
+	}
+	log.WithFields(log.Fields{
+		"timesheet_id":  id,
+		"project_id":    project.ID,
+		"cost":          approved.Cost.String(),
+		"project_spend": project.ProjectSpend.String(),
+	}).Info("Timesheet approved")
+
+	before := *project
+	before.ProjectSpend.Amount -= approved.Cost.Amount
+	publishOverBudget(ctx, s.events, s.budgetAlertThreshold, before.OverBudget(s.budgetAlertThreshold), project)
+	return approved, nil
+}
+
+func (s TimesheetService) Reject(ctx context.Context, id int, req request.RejectTimesheetRequest) (*entities.Timesheet, error) {
+	_, reviewerID, err := s.reviewable(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	if err := s.timesheets.Reject(ctx, id, reviewerID, req.Note, time.Now()); err != nil {
+		return nil, timesheetError(err)
+	}
+	log.WithFields(log.Fields{
+		"timesheet_id": id,
+		"reviewed_by":  reviewerID,
+	}).Info("Timesheet rejected")
+	return s.timesheets.Get(ctx, id)
+}
+
+// ProjectSummary tổng giờ công của project theo user (mặc định) hoặc theo tuần, chỉ owner/manager
+// hoặc role có project:read_all xem được chi phí
+func (s TimesheetService) ProjectSummary(ctx context.Context, projectID int, req request.TimesheetSummaryRequest) ([]entities.TimesheetSummary, error) {
+	if err := s.policy.authorize(ctx, projectID, updateRoles, entities.PermissionProjectReadAll); err != nil {
+		return nil, err
+	}
+	query := repositories.TimesheetSummaryQuery{ProjectID: projectID, From: req.From, To: req.To}
+	switch req.Group {
+	case "", "user":
+		query.ByUser = true
+	case "week":
+		query.ByWeek = true
+	default:
+		return nil, fmt.Errorf("%w: cannot group a project summary by %s", ErrInvalidTimesheet, req.Group)
+	}
+	return s.timesheets.Summary(ctx, query)
+}
+
+// UserSummary tổng giờ công của user theo project (mặc định) hoặc theo tuần của từng project,
+// quyền xem (chính user hoặc user:read) được kiểm tra ở router
+func (s TimesheetService) UserSummary(ctx context.Context, userID int, req request.TimesheetSummaryRequest) ([]entities.TimesheetSummary, error) {
+	query := repositories.TimesheetSummaryQuery{UserID: userID, From: req.From, To: req.To}
+	switch req.Group {
+	case "", "project":
+	case "week":
+		query.ByWeek = true
+	default:
+		return nil, fmt.Errorf("%w: cannot group a user summary by %s", ErrInvalidTimesheet, req.Group)
+	}
+	return s.timesheets.Summary(ctx, query)
+}
+
+// validateEntry người ghi giờ phải là owner/manager/contributor của project, project phải đang nhận giờ công
+// và ngày làm việc nằm trong thời gian của project, không ở tương lai
+func (s TimesheetService) validateEntry(ctx context.Context, entry *entities.TimesheetEntry) error {
+	project, err := s.projectRepository.GetById(ctx, entry.ProjectID)
+	if err != nil {
+		return err
+	}
+	if _, err := s.requireProjectRole(ctx, entry.ProjectID, taskRoles); err != nil {
+		return err
+	}
+	if !project.AcceptsTime() {
+		return fmt.Errorf("%w: project is %s", ErrInvalidTimesheet, project.Status)
+	}
+
+	workDate := entities.Day(entry.WorkDate)
+	if workDate.Before(entities.Day(project.ProjectStartedAt)) {
+		return fmt.Errorf("%w: project starts on %s", ErrInvalidTimesheet, project.ProjectStartedAt.Format(time.DateOnly))
+	}
+	if project.ProjectEndedAt != nil && workDate.After(entities.Day(*project.ProjectEndedAt)) {
+		return fmt.Errorf("%w: project ended on %s", ErrInvalidTimesheet, project.ProjectEndedAt.Format(time.DateOnly))
+	}
+	if workDate.After(entities.Day(time.Now())) {
+		return fmt.Errorf("%w: cannot log time in the future", ErrInvalidTimesheet)
+	}
+
+	if entry.TaskID != nil {
+		if _, err := s.work.GetTask(ctx, entry.ProjectID, *entry.TaskID); err != nil {
+			if errors.Is(err, gorm.ErrRecordNotFound) {
+				return fmt.Errorf("%w: task %d does not belong to project %d", ErrInvalidTimesheet, *entry.TaskID, entry.ProjectID)
+			}
+			return err
+		}
+	}
+	return nil
+}
+
+func (s TimesheetService) saveEntry(ctx context.Context, entry *entities.TimesheetEntry) (*entities.TimesheetEntry, error) {
+	saved, err := s.timesheets.SaveEntry(ctx, entry)
+	if err != nil {
+		return nil, timesheetError(err)
+	}
+	log.WithFields(log.Fields{
+		"entry_id":   saved.ID,
+		"project_id": saved.ProjectID,
+		"user_id":    saved.UserID,
+		"work_date":  saved.WorkDate.Format(time.DateOnly),
+		"hours":      saved.Hours,
+	}).Info("Time logged")
+	return saved, nil
+}
+
+// ownEntry dòng giờ công của chính người gọi
+func (s TimesheetService) ownEntry(ctx context.Context, id int) (*entities.TimesheetEntry, error) {
+	callerID, ok := pkg.UserIDFromContext(ctx)
+	if !ok {
+		return nil, ErrForbidden
+	}
+	entry, err := s.timesheets.GetEntry(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	if entry.UserID != callerID {
+		return nil, ErrForbidden
+	}
+	return entry, nil
+}
+
+// reviewable timesheet mà người gọi được duyệt/từ chối: người gọi là owner/manager của project
+// hoặc có project:manage_all, và không phải người nộp
+func (s TimesheetService) reviewable(ctx context.Context, id int) (*entities.Timesheet, int, error) {
+	timesheet, err := s.timesheets.Get(ctx, id)
+	if err != nil {
+		return nil, 0, err
+	}
+	if err := s.policy.authorize(ctx, timesheet.ProjectID, updateRoles, entities.PermissionProjectManageAll); err != nil {
+		return nil, 0, err
+	}
+	reviewerID, _ := pkg.UserIDFromContext(ctx)
+	if reviewerID == timesheet.UserID {
+		return nil, 0, ErrForbidden
+	}
+	return timesheet, reviewerID, nil
+}
+
+// requireProjectRole membership của người gọi nếu họ có một trong các vai trò trong project,
+// không có bypass vì chỉ thành viên mới ghi giờ công vào project
+func (s TimesheetService) requireProjectRole(ctx context.Context, projectID int, roles []string) (*entities.UserProject, error) {
+	callerID, ok := pkg.UserIDFromContext(ctx)
+	if !ok {
+		return nil, ErrForbidden
+	}
+	member, err := s.memberRepository.GetMember(ctx, projectID, callerID)
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, ErrProjectAccessDenied
+		}
+		return nil, err
+	}
+	for _, role := range roles {
+		if member.Role == role {
+			return member, nil
+		}
+	}
+	return nil, ErrProjectAccessDenied
+}
+
+// timesheetError chuyển lỗi của repository thành lỗi nghiệp vụ
+func timesheetError(err error) error {
+	switch {
+	case errors.Is(err, repositories.ErrTimesheetLocked):
+		return fmt.Errorf("%w: %v", ErrTimesheetLocked, err)
+	case errors.Is(err, repositories.ErrProjectClosed):
+		return fmt.Errorf("%w: %v", ErrFinancialsLocked, err)
+	case errors.Is(err, money.ErrCurrencyMismatch):
+		return fmt.Errorf("%w: %v", ErrInvalidTimesheet, err)
+	}
+	return err
+}
+
+func NewTimesheetService(
+	timesheets repositories.ITimesheetRepository,
+	projectRepository repositories.IProjectRepository,
+	memberRepository repositories.IProjectMemberRepository,
+	userRepository repositories.IUserRepository,
+	work repositories.IProjectWorkRepository,
+	rates exchange.RateProvider,
+	events eventbus.Bus,
+	roleService IRoleService,
+	config *config.Config,
+) ITimesheetService {
+	policy := ProjectPolicy{
+		projectRepository: projectRepository,
+		memberRepository:  memberRepository,
+		userRepository:    userRepository,
+		roleService:       roleService,
+	}
+	return &TimesheetService{
+		timesheets:           timesheets,
+		projectRepository:    projectRepository,
+		memberRepository:     memberRepository,
+		userRepository:       userRepository,
+		work:                 work,
+		rates:                rates,
+		events:               events,
+		policy:               policy,
+		budgetAlertThreshold: budgetAlertThreshold(config),
+	}
+}
@@ -0,0 +1,108 @@
+package use_cases
+
+import (
+	dto "Backend_golang_project/internal/domain/dto/request"
+	"Backend_golang_project/internal/domain/entities"
+	"Backend_golang_project/internal/pkg"
+	"context"
+	"strings"
+
+	log "github.com/sirupsen/logrus"
+)
+
+// GetProfile trả về user đang đăng nhập
+func (u *UserService) GetProfile(ctx context.Context) (*entities.User, error) {
+	callerID, ok := pkg.UserIDFromContext(ctx)
+	if !ok {
+		return nil, ErrForbidden
+	}
+	return u.userRepository.GetUserById(ctx, callerID)
+}
+
+// UpdateProfile đổi email thì email mới phải được xác thực lại, link xác thực được gửi tới email mới
+func (u *UserService) UpdateProfile(ctx context.Context, req dto.UpdateProfileRequest) (*entities.User, error) {
+	user, err := u.GetProfile(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	changes := map[string]interface{}{}
+	if req.Username != nil && *req.Username != user.Username {
+		changes["username"] = *req.Username
+	}
+	emailChanged := false
+	if req.Email != nil && !strings.EqualFold(*req.Email, user.Email) {
+		changes["email"] = *req.Email
+		changes["email_verified_at"] = nil
+		emailChanged = true
+	}
+	if len(changes) == 0 {
+		return user, nil
+	}
+
+	if err := u.userRepository.UpdateProfile(ctx, user.ID, changes); err != nil {
+		return nil, err
+	}
+
+	updated, err := u.userRepository.GetUserById(ctx, user.ID)
+	if err != nil {
+		return nil, err
+	}
+	if emailChanged {
+		if err := u.accounts.SendVerification(ctx, updated); err != nil {
+			log.Error("Cannot send verification email ", err)
+		}
+	}
+
+	log.WithField("user_id", user.ID).Info("User profile updated")
+	return updated, nil
+}
+
+// ChangePassword đăng xuất mọi phiên sau khi đổi mật khẩu, client phải đăng nhập lại bằng mật khẩu mới
+func (u *UserService) ChangePassword(ctx context.Context, req dto.ChangePasswordRequest) error {
+	user, err := u.GetProfile(ctx)
+	if err != nil {
+		return err
+	}
+	if !u.hasher.Verify(req.CurrentPassword, user.Password) {
+		return ErrInvalidCredentials
+	}
+
+	hash, err := u.hasher.Hash(req.NewPassword)
+	if err != nil {
+		return err
+	}
+	if err := u.userRepository.UpdatePassword(ctx, user.ID, hash); err != nil {
+		return err
+	}
+	if err := u.tokenStore.RevokeAllForUser(ctx, user.ID); err != nil {
+		log.Error("Cannot revoke sessions after password change ", err)
+	}
+
+	log.WithField("user_id", user.ID).Info("User password changed")
+	return nil
+}
+
+// DeleteAccount xóa mềm tài khoản và thu hồi mọi refresh token. Admin cuối cùng hoặc owner duy nhất của project
+// không xóa được tài khoản. Access token đã phát hành không bị thu hồi: các route đọc user từ DB (profile, project)
+// từ chối ngay, còn route chỉ kiểm tra RequirePermission vẫn nhận token theo claim roles cho tới khi token hết hạn
+func (u *UserService) DeleteAccount(ctx context.Context, req dto.DeleteAccountRequest) error {
+	user, err := u.GetProfile(ctx)
+	if err != nil {
+		return err
+	}
+	if !u.hasher.Verify(req.Password, user.Password) {
+		return ErrInvalidCredentials
+	}
+
+	if err := u.userRepository.DeleteUser(ctx, user.ID); err != nil {
+		return err
+	}
+	if err := u.tokenStore.RevokeAllForUser(ctx, user.ID); err != nil {
+		log.Error("Cannot revoke sessions after account deletion ", err)
+		return err
+	}
+
+	log.WithField("user_id", user.ID).Info("User account deleted")
+	return nil
+}
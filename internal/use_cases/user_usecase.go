@@ -8,8 +8,11 @@ import (
 	"Backend_golang_project/internal/pkg"
 	"Backend_golang_project/internal/repositories"
 	"context"
+	"errors"
 	"fmt"
 	"github.com/go-playground/validator/v10"
+	"github.com/google/uuid"
+	"gorm.io/gorm"
 	"io"
 	"time"
 
@@ -20,14 +23,27 @@ type IUserService interface {
 	Create(ctx context.Context, request *dto.CreateUserRequest) (*entities.User, error)
 	GetUserByID(ctx context.Context, ID int) (*entities.User, error)
 	Login(cxt context.Context, req dto.LoginRequest) (string, string, error)
-	RefreshToken(req *dto.RefreshTokenRequest) (string, error)
+	RefreshToken(ctx context.Context, req *dto.RefreshTokenRequest) (string, string, error)
+	Logout(ctx context.Context, req *dto.RefreshTokenRequest) error
+	LogoutAll(ctx context.Context) error
 	ExportToS3(ctx context.Context, filename string) error
+	ListUsers(ctx context.Context, page pkg.PageRequest) (*pkg.Pagination[entities.User], error)
+	GetProfile(ctx context.Context) (*entities.User, error)
+	UpdateProfile(ctx context.Context, req dto.UpdateProfileRequest) (*entities.User, error)
+	ChangePassword(ctx context.Context, req dto.ChangePasswordRequest) error
+	DeleteAccount(ctx context.Context, req dto.DeleteAccountRequest) error
 }
 
 type UserService struct {
 	config         *config.Config
 	userRepository repositories.IUserRepository
 	s3repository   repositories.S3RepositoryInterface
+	tokenStore     repositories.IRefreshTokenStore
+	keySet         *jwt.KeySet
+	throttle       *loginThrottle
+	accounts       IAccountService
+	hasher         *pkg.PasswordHasher
+	cursorSigner   *pkg.CursorSigner
 }
 
 // ExportToS3
@@ -45,11 +61,11 @@ func (u *UserService) ExportToS3(ctx context.Context, filename string) error {
 	go func() {
 		defer pipeWriter.Close()
 
-		offset := 0
+		afterID := 0
 		limit := 1000
 		for {
-			// Query dữ liệu từ database theo batch (1000)
-			users, err := u.userRepository.GetUsersBatch(ctx, offset, limit)
+			// Query dữ liệu từ database theo batch (1000), batch sau bắt đầu từ id cuối của batch trước
+			users, err := u.userRepository.GetUsersBatch(ctx, afterID, limit)
 			if err != nil {
 				pipeWriter.CloseWithError(err)
 				return
@@ -64,13 +80,11 @@ func (u *UserService) ExportToS3(ctx context.Context, filename string) error {
 				}
 			}
 
-			// Tăng offset để lấy batch tiếp theo
-			offset += limit
-
 			// Nếu không còn dữ liệu thì thoát
 			if len(users) < limit {
 				return
 			}
+			afterID = users[len(users)-1].ID
 		}
 	}()
 
@@ -85,19 +99,124 @@ func (u *UserService) ExportToS3(ctx context.Context, filename string) error {
 	return nil
 }
 
-func (u UserService) RefreshToken(req *dto.RefreshTokenRequest) (string, error) {
-	claims, err := jwt.ClaimRefreshToken(req.RefreshToken, u.config)
+// RefreshToken đổi refresh token lấy một cặp token mới (rotation).
+// Refresh token cũ bị thu hồi ngay, nếu một token đã bị thu hồi được gửi lại thì coi như bị đánh cắp
+// và toàn bộ family của nó bị thu hồi theo
+func (u UserService) RefreshToken(ctx context.Context, req *dto.RefreshTokenRequest) (string, string, error) {
+	claims, err := jwt.ClaimRefreshToken(req.RefreshToken, u.config, u.keySet)
 	if err != nil {
 		log.Error("Invalid refresh token")
-		return "", err
+		return "", "", ErrInvalidRefreshToken
+	}
+
+	stored, err := u.tokenStore.GetByJTI(ctx, claims.RegisteredClaims.ID)
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			log.Error("Refresh token is not in the token store")
+			return "", "", ErrInvalidRefreshToken
+		}
+		return "", "", err
+	}
+	if stored.IsRevoked() {
+		return "", "", u.revokeReusedFamily(ctx, stored)
+	}
+
+	// đọc lại role để access token mới phản ánh quyền hiện tại của user
+	role, err := u.userRepository.GetRole(ctx, stored.UserID)
+	if err != nil {
+		return "", "", err
+	}
+
+	next := u.newRefreshToken(stored.UserID, stored.FamilyID)
+	subject := jwt.TokenSubject{ID: stored.UserID, Roles: []string{role}}
+	accessToken, refreshToken, err := jwt.GenerateJwtToken(u.config, u.keySet, subject, next.JTI)
+	if err != nil {
+		log.Error("Failed to generate new token pair")
+		return "", "", err
+	}
+
+	if err := u.tokenStore.Rotate(ctx, stored.JTI, next); err != nil {
+		if errors.Is(err, repositories.ErrRefreshTokenRevoked) {
+			// một request khác đã dùng token này trước đó
+			return "", "", u.revokeReusedFamily(ctx, stored)
+		}
+		return "", "", err
+	}
+	return accessToken, refreshToken, nil
+}
+
+// Logout thu hồi phiên đăng nhập ứng với refresh token (cả family của nó)
+func (u UserService) Logout(ctx context.Context, req *dto.RefreshTokenRequest) error {
+	claims, err := jwt.ClaimRefreshToken(req.RefreshToken, u.config, u.keySet)
+	if err != nil {
+		return ErrInvalidRefreshToken
+	}
+	if callerID, ok := pkg.UserIDFromContext(ctx); ok && callerID != claims.ID {
+		return ErrForbidden
+	}
+
+	stored, err := u.tokenStore.GetByJTI(ctx, claims.RegisteredClaims.ID)
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return ErrInvalidRefreshToken
+		}
+		return err
+	}
+	if err := u.tokenStore.RevokeFamily(ctx, stored.FamilyID); err != nil {
+		return fmt.Errorf("failed to revoke refresh tokens: %w", err)
+	}
+
+	log.WithField("user_id", stored.UserID).Info("User logged out")
+	return nil
+}
+
+// LogoutAll thu hồi mọi refresh token của user đang đăng nhập, trên tất cả thiết bị
+func (u UserService) LogoutAll(ctx context.Context) error {
+	callerID, ok := pkg.UserIDFromContext(ctx)
+	if !ok {
+		return ErrForbidden
+	}
+	if err := u.tokenStore.RevokeAllForUser(ctx, callerID); err != nil {
+		return fmt.Errorf("failed to revoke refresh tokens: %w", err)
+	}
+
+	log.WithField("user_id", callerID).Info("User logged out from all sessions")
+	return nil
+}
+
+func (u UserService) revokeReusedFamily(ctx context.Context, token *entities.RefreshToken) error {
+	log.WithFields(log.Fields{
+		"user_id":   token.UserID,
+		"family_id": token.FamilyID,
+	}).Warn("Refresh token reuse detected, revoking token family")
+
+	if err := u.tokenStore.RevokeFamily(ctx, token.FamilyID); err != nil {
+		return fmt.Errorf("failed to revoke refresh token family: %w", err)
 	}
+	return ErrRefreshTokenReused
+}
 
-	newAccessToken, _, err := jwt.GenerateJwtToken(u.config, claims.ID)
+// issueTokenPair phát hành cặp token mới cho một lần đăng nhập, mở ra một family mới
+func (u UserService) issueTokenPair(ctx context.Context, user *entities.User) (string, string, error) {
+	token := u.newRefreshToken(user.ID, uuid.NewString())
+	subject := jwt.TokenSubject{ID: user.ID, Roles: []string{user.Role}}
+	accessToken, refreshToken, err := jwt.GenerateJwtToken(u.config, u.keySet, subject, token.JTI)
 	if err != nil {
-		log.Error("Failed to generate new access token")
-		return "", err
+		return "", "", err
+	}
+	if err := u.tokenStore.Save(ctx, token); err != nil {
+		return "", "", err
+	}
+	return accessToken, refreshToken, nil
+}
+
+func (u UserService) newRefreshToken(userID int, familyID string) *entities.RefreshToken {
+	return &entities.RefreshToken{
+		JTI:       uuid.NewString(),
+		FamilyID:  familyID,
+		UserID:    userID,
+		ExpiresAt: jwt.RefreshTokenExpiry(u.config, time.Now()),
 	}
-	return newAccessToken, nil
 }
 
 func (u UserService) Create(ctx context.Context, request *dto.CreateUserRequest) (*entities.User, error) {
@@ -107,7 +226,7 @@ func (u UserService) Create(ctx context.Context, request *dto.CreateUserRequest)
 		log.Error(err)
 	}
 
-	hashPassword, err := pkg.HashPassword(request.Password)
+	hashPassword, err := u.hasher.Hash(request.Password)
 	if err != nil {
 		log.Error("Hash password fail")
 		return nil, err
@@ -138,19 +257,42 @@ func (u UserService) GetUserByID(ctx context.Context, ID int) (*entities.User, e
 	return user, nil
 }
 
+// Login trả về ErrInvalidCredentials cho cả email không tồn tại lẫn sai mật khẩu để không lộ email nào đã đăng ký,
+// mỗi lần sai được đếm theo email và IP, vượt ngưỡng thì trả về *LockedError.
+// Lỗi đọc DB không phải là đăng nhập sai nên được trả nguyên về và không được đếm
 func (u *UserService) Login(ctx context.Context, req dto.LoginRequest) (string, string, error) {
-	user, err := u.userRepository.GetUserByEmail(ctx, req.Email)
-	if err != nil || user == nil {
-		log.Error("Invalid login")
+	ip := pkg.ClientIPFromContext(ctx)
+	if err := u.throttle.check(ctx, req.Email, ip); err != nil {
 		return "", "", err
 	}
-	ok := pkg.CheckPasswordHash(req.Password, user.Password)
-	if !ok {
-		log.Error("Invalid password")
+
+	user, err := u.userRepository.GetUserByEmail(ctx, req.Email)
+	if err != nil && !errors.Is(err, gorm.ErrRecordNotFound) {
+		log.Error("Cannot load user for login ", err)
 		return "", "", err
 	}
+	if user == nil || !u.hasher.Verify(req.Password, user.Password) {
+		log.WithFields(log.Fields{"email": req.Email, "ip": ip}).Warn("Invalid login")
+		if err := u.throttle.registerFailure(ctx, req.Email, ip); err != nil {
+			log.Error("Cannot register login failure ", err)
+		}
+		return "", "", ErrInvalidCredentials
+	}
+
+	if err := u.throttle.reset(ctx, req.Email); err != nil {
+		log.Error("Cannot reset login attempts ", err)
+	}
+	u.upgradePasswordHash(ctx, user, req.Password)
 
-	accessToken, refreshToken, err := jwt.GenerateJwtToken(u.config, user.ID)
+	// lần đăng nhập đầu tiên (và các lần sau nếu vẫn chưa xác thực) gửi link xác thực thay vì cấp token
+	if user.EmailVerifiedAt == nil {
+		if err := u.accounts.SendVerification(ctx, user); err != nil {
+			log.Error("Cannot send verification email ", err)
+		}
+		return "", "", ErrEmailNotVerified
+	}
+
+	accessToken, refreshToken, err := u.issueTokenPair(ctx, user)
 	if err != nil {
 		log.Error("Cannot generate token ", err)
 		return "", "", err
@@ -159,10 +301,72 @@ func (u *UserService) Login(ctx context.Context, req dto.LoginRequest) (string,
 	return accessToken, refreshToken, nil
 }
 
-func NewUserService(config *config.Config, userRepository repositories.IUserRepository, s3repository repositories.S3RepositoryInterface) IUserService {
+// upgradePasswordHash băm lại mật khẩu khi hash đang lưu dùng thuật toán hoặc cost cũ,
+// chỉ làm được lúc đăng nhập vì đây là lúc duy nhất có mật khẩu gốc. Lỗi chỉ được ghi log, không chặn đăng nhập
+func (u *UserService) upgradePasswordHash(ctx context.Context, user *entities.User, password string) {
+	if !u.hasher.NeedsRehash(user.Password) {
+		return
+	}
+	hash, err := u.hasher.Hash(password)
+	if err != nil {
+		log.Error("Cannot rehash password ", err)
+		return
+	}
+	if err := u.userRepository.UpdatePassword(ctx, user.ID, hash); err != nil {
+		log.Error("Cannot store upgraded password hash ", err)
+		return
+	}
+	user.Password = hash
+	log.WithField("user_id", user.ID).Info("Upgraded password hash")
+}
+
+// ListUsers danh sách user theo thứ tự id, cursor chỉ chứa id của user biên
+func (u *UserService) ListUsers(ctx context.Context, page pkg.PageRequest) (*pkg.Pagination[entities.User], error) {
+	const sort = "id"
+	spec, err := pageSpec(u.cursorSigner, page, sort, []cursorKind{cursorInt})
+	if err != nil {
+		return nil, err
+	}
+	users, err := u.userRepository.ListUsers(ctx, spec)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list users: %w", err)
+	}
+	err = setPageCursors(u.cursorSigner, users, sort, func(user entities.User) []interface{} {
+		return []interface{}{user.ID}
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to encode page cursor: %w", err)
+	}
+	return users, nil
+}
+
+func NewUserService(
+	config *config.Config,
+	userRepository repositories.IUserRepository,
+	s3repository repositories.S3RepositoryInterface,
+	tokenStore repositories.IRefreshTokenStore,
+	keySet *jwt.KeySet,
+	attemptStore repositories.ILoginAttemptStore,
+	accounts IAccountService,
+) IUserService {
 	return &UserService{
 		config:         config,
 		userRepository: userRepository,
 		s3repository:   s3repository,
+		tokenStore:     tokenStore,
+		keySet:         keySet,
+		throttle:       newLoginThrottle(attemptStore, config.JwtConfig.LoginThrottle),
+		accounts:       accounts,
+		hasher:         newPasswordHasher(config.Password),
+		cursorSigner:   pkg.NewCursorSigner(config),
 	}
 }
+
+// newPasswordHasher tạo PasswordHasher từ mục password trong config, dùng chung cho UserService và AccountService
+func newPasswordHasher(cfg config.PasswordConfig) *pkg.PasswordHasher {
+	return pkg.NewPasswordHasher(cfg.Algorithm, cfg.BcryptCost, pkg.Argon2Params{
+		MemoryKiB:   cfg.Argon2.MemoryKiB,
+		Iterations:  cfg.Argon2.Iterations,
+		Parallelism: cfg.Argon2.Parallelism,
+	})
+}
@@ -0,0 +1,10 @@
+-- vai trò của user trong từng project, lưu trên bảng nối user_projects
+alter table user_projects
+    add column role varchar(32) not null default 'contributor' after user_id;
+
+-- những liên kết tạo ra trước đây đều đến từ việc user tự tạo project lồng trong CreateUserRequest
+update user_projects
+set role = 'owner';
+
+create unique index uni_user_projects_project_user
+    on user_projects (project_id, user_id);
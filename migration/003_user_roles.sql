@@ -0,0 +1,3 @@
+-- vai trò toàn hệ thống của user, admin được xem và thao tác trên mọi project
+alter table users
+    add column role varchar(32) not null default 'member' after username;
@@ -0,0 +1,22 @@
+create table refresh_tokens
+(
+    id          bigint auto_increment
+        primary key,
+    jti         varchar(64) not null,
+    family_id   varchar(64) not null,
+    user_id     bigint      not null,
+    expires_at  datetime(3) not null,
+    revoked_at  datetime(3) null,
+    replaced_by varchar(64) null,
+    created_at  datetime(3) null,
+    constraint uni_refresh_tokens_jti
+        unique (jti),
+    constraint refresh_tokens_users_id_fk
+        foreign key (user_id) references users (id)
+);
+
+create index idx_refresh_tokens_family_id
+    on refresh_tokens (family_id);
+
+create index idx_refresh_tokens_user_id
+    on refresh_tokens (user_id);
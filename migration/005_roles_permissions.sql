@@ -0,0 +1,62 @@
+create table roles
+(
+    name        varchar(32)  not null
+        primary key,
+    description varchar(255) null
+);
+
+create table permissions
+(
+    name        varchar(64)  not null
+        primary key,
+    description varchar(255) null
+);
+
+create table role_permissions
+(
+    role_name       varchar(32) not null,
+    permission_name varchar(64) not null,
+    primary key (role_name, permission_name),
+    constraint role_permissions_roles_name_fk
+        foreign key (role_name) references roles (name),
+    constraint role_permissions_permissions_name_fk
+        foreign key (permission_name) references permissions (name)
+);
+
+insert into roles (name, description)
+values ('admin', 'Full access to every project, user and role'),
+       ('member', 'Regular user, works on the projects they are a member of'),
+       ('auditor', 'Read-only access to every project and user');
+
+insert into permissions (name, description)
+values ('project:create', 'Create projects'),
+       ('project:read', 'Read projects the user is a member of'),
+       ('project:update', 'Update projects the user manages'),
+       ('project:delete', 'Delete projects the user owns'),
+       ('project:read_all', 'Read every project regardless of membership'),
+       ('project:manage_all', 'Update and delete every project regardless of membership'),
+       ('user:read', 'Read other users and their projects'),
+       ('user:export', 'Export users in bulk'),
+       ('role:assign', 'Assign roles to users');
+
+insert into role_permissions (role_name, permission_name)
+values ('admin', 'project:create'),
+       ('admin', 'project:read'),
+       ('admin', 'project:update'),
+       ('admin', 'project:delete'),
+       ('admin', 'project:read_all'),
+       ('admin', 'project:manage_all'),
+       ('admin', 'user:read'),
+       ('admin', 'user:export'),
+       ('admin', 'role:assign'),
+       ('member', 'project:create'),
+       ('member', 'project:read'),
+       ('member', 'project:update'),
+       ('member', 'project:delete'),
+       ('auditor', 'project:read'),
+       ('auditor', 'project:read_all'),
+       ('auditor', 'user:read');
+
+alter table users
+    add constraint users_roles_name_fk
+        foreign key (role) references roles (name);
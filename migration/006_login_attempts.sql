@@ -0,0 +1,12 @@
+create table login_attempts
+(
+    id             int auto_increment
+        primary key,
+    attempt_key    varchar(320) not null,
+    failures       int          not null default 0,
+    last_failed_at datetime(3)  not null,
+    locked_until   datetime(3)  null,
+    updated_at     datetime(3)  null,
+    constraint login_attempts_attempt_key_uindex
+        unique (attempt_key)
+);
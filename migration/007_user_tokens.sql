@@ -0,0 +1,26 @@
+alter table users
+    add email_verified_at datetime(3) null after role;
+
+-- các tài khoản tạo trước khi có bước xác thực email được coi như đã xác thực
+update users
+set email_verified_at = coalesce(created_at, now(3))
+where email_verified_at is null;
+
+create table user_tokens
+(
+    id         int auto_increment
+        primary key,
+    user_id    bigint      not null,
+    purpose    varchar(32) not null,
+    token_hash varchar(64) not null,
+    expires_at datetime(3) not null,
+    used_at    datetime(3) null,
+    created_at datetime(3) null,
+    constraint user_tokens_token_hash_uindex
+        unique (token_hash),
+    constraint user_tokens_users_id_fk
+        foreign key (user_id) references users (id)
+);
+
+create index user_tokens_user_id_index
+    on user_tokens (user_id);
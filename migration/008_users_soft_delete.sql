@@ -0,0 +1,5 @@
+alter table users
+    add deleted_at datetime(3) null;
+
+create index idx_users_deleted_at
+    on users (deleted_at);
@@ -0,0 +1,29 @@
+-- trạng thái vòng đời của project, project mới tạo ở trạng thái draft
+alter table projects
+    add column status varchar(20) not null default 'draft' after category;
+
+-- project đã có trước đây: đã kết thúc thì completed, đã bắt đầu thì active
+update projects
+set status = case
+                 when project_ended_at is not null and project_ended_at <= now() then 'completed'
+                 when project_started_at <= now() then 'active'
+                 else 'draft'
+    end;
+
+create index idx_projects_status
+    on projects (status);
+
+create table project_status_history
+(
+    id          bigint auto_increment
+        primary key,
+    project_id  bigint       not null,
+    from_status varchar(20)  not null,
+    to_status   varchar(20)  not null,
+    reason      varchar(500) not null,
+    changed_by  bigint       null,
+    created_at  datetime(3)  null
+);
+
+create index idx_project_status_history_project_id
+    on project_status_history (project_id);
@@ -0,0 +1,30 @@
+-- sổ cái spend/revenue của project, project_spend và revenue_recognised trên bảng projects
+-- là tổng của sổ cái và chỉ được cập nhật cùng transaction với việc ghi bút toán
+create table project_transactions
+(
+    id             bigint auto_increment
+        primary key,
+    project_id     bigint       not null,
+    type           varchar(20)  not null,
+    target         varchar(20)  not null,
+    amount         bigint       not null,
+    currency       char(3)      not null,
+    effective_date datetime(3)  not null,
+    description    varchar(500) null,
+    created_by     bigint       null,
+    created_at     datetime(3)  null
+);
+
+create index idx_project_transactions_project_id
+    on project_transactions (project_id, effective_date);
+
+-- số liệu cũ được chuyển thành bút toán số dư đầu kỳ để tổng sổ cái khớp với bảng projects
+insert into project_transactions (project_id, type, target, amount, currency, effective_date, description, created_at)
+select id, 'adjustment', 'spend', project_spend, 'VND', coalesce(created_at, project_started_at), 'Opening balance', now(3)
+from projects
+where coalesce(project_spend, 0) <> 0;
+
+insert into project_transactions (project_id, type, target, amount, currency, effective_date, description, created_at)
+select id, 'adjustment', 'revenue', revenue_recognised, 'VND', coalesce(created_at, project_started_at), 'Opening balance', now(3)
+from projects
+where coalesce(revenue_recognised, 0) <> 0;
@@ -0,0 +1,14 @@
+-- các khoản tiền của project lưu theo đơn vị nhỏ nhất kèm mã tiền tệ ISO 4217,
+-- project có từ trước đều tính bằng VND
+update projects
+set project_spend      = coalesce(project_spend, 0),
+    project_variance   = coalesce(project_variance, 0),
+    revenue_recognised = coalesce(revenue_recognised, 0);
+
+alter table projects
+    change column project_spend project_spend_amount bigint not null default 0,
+    add column project_spend_currency char(3) not null default 'VND' after project_spend_amount,
+    change column project_variance project_variance_amount bigint not null default 0,
+    add column project_variance_currency char(3) not null default 'VND' after project_variance_amount,
+    change column revenue_recognised revenue_recognised_amount bigint not null default 0,
+    add column revenue_recognised_currency char(3) not null default 'VND' after revenue_recognised_amount;
@@ -0,0 +1,9 @@
+-- ngân sách của project, project_variance_amount từ nay luôn bằng budget_amount - project_spend_amount
+alter table projects
+    add column budget_amount bigint not null default 0 after status,
+    add column budget_currency char(3) not null default 'VND' after budget_amount;
+
+-- variance trước đây nhập tay, ngân sách của project cũ được suy ra để variance giữ nguyên
+update projects
+set budget_amount   = project_variance_amount + project_spend_amount,
+    budget_currency = project_spend_currency;
@@ -0,0 +1,32 @@
+-- lịch ghi nhận doanh thu của project client, mỗi project tối đa một lịch
+create table project_revenue_schedules
+(
+    id                      bigint auto_increment
+        primary key,
+    project_id              bigint      not null,
+    method                  varchar(20) not null,
+    contract_value_amount   bigint      not null default 0,
+    contract_value_currency char(3)     not null default 'VND',
+    percent_complete        int         not null default 0,
+    recognised_amount       bigint      not null default 0,
+    recognised_through      date        null,
+    created_by              bigint      null,
+    created_at              datetime(3) null,
+    updated_at              datetime(3) null,
+    constraint idx_project_revenue_schedules_project_id
+        unique (project_id)
+);
+
+create table project_revenue_milestones
+(
+    id           bigint auto_increment
+        primary key,
+    schedule_id  bigint       not null,
+    name         varchar(255) not null,
+    amount       bigint       not null,
+    due_date     date         not null,
+    completed_at datetime(3)  null
+);
+
+create index idx_project_revenue_milestones_schedule_id
+    on project_revenue_milestones (schedule_id);
@@ -0,0 +1,51 @@
+-- milestone và task của project, assignee là thành viên project trong user_projects
+create table project_milestones
+(
+    id             bigint auto_increment
+        primary key,
+    project_id     bigint        not null,
+    name           varchar(255)  not null,
+    description    text          null,
+    assignee_id    bigint        null,
+    due_date       date          null,
+    status         varchar(20)   not null default 'todo',
+    estimate_hours decimal(8, 2) not null default 0,
+    completed_at   datetime(3)   null,
+    created_by     bigint        null,
+    created_at     datetime(3)   null,
+    updated_at     datetime(3)   null
+);
+
+create index idx_project_milestones_project_id
+    on project_milestones (project_id);
+create index idx_project_milestones_assignee_id
+    on project_milestones (assignee_id);
+create index idx_project_milestones_status
+    on project_milestones (status);
+
+create table project_tasks
+(
+    id             bigint auto_increment
+        primary key,
+    project_id     bigint        not null,
+    milestone_id   bigint        null,
+    title          varchar(255)  not null,
+    description    text          null,
+    assignee_id    bigint        null,
+    due_date       date          null,
+    status         varchar(20)   not null default 'todo',
+    estimate_hours decimal(8, 2) not null default 0,
+    completed_at   datetime(3)   null,
+    created_by     bigint        null,
+    created_at     datetime(3)   null,
+    updated_at     datetime(3)   null
+);
+
+create index idx_project_tasks_project_id
+    on project_tasks (project_id);
+create index idx_project_tasks_milestone_id
+    on project_tasks (milestone_id);
+create index idx_project_tasks_assignee_id
+    on project_tasks (assignee_id);
+create index idx_project_tasks_status
+    on project_tasks (status);
@@ -0,0 +1,62 @@
+-- đơn giá giờ công của user, chi phí của timesheet được tính theo đơn giá tại thời điểm duyệt
+alter table users
+    add column hourly_rate_amount   bigint  not null default 0,
+    add column hourly_rate_currency char(3) not null default 'VND';
+
+insert into permissions (name, description)
+values ('user:manage_rates', 'Set the hourly rate of users');
+
+insert into role_permissions (role_name, permission_name)
+values ('admin', 'user:manage_rates');
+
+-- timesheet của một user trên một project trong một tuần (week_start là thứ Hai)
+create table timesheets
+(
+    id                   bigint auto_increment
+        primary key,
+    user_id              bigint       not null,
+    project_id           bigint       not null,
+    week_start           date         not null,
+    status               varchar(20)  not null default 'draft',
+    submitted_at         datetime(3)  null,
+    reviewed_by          bigint       null,
+    reviewed_at          datetime(3)  null,
+    review_note          varchar(500) not null default '',
+    hourly_rate_amount   bigint       not null default 0,
+    hourly_rate_currency char(3)      not null default 'VND',
+    cost_amount          bigint       not null default 0,
+    cost_currency        char(3)      not null default 'VND',
+    transaction_id       bigint       null,
+    created_at           datetime(3)  null,
+    updated_at           datetime(3)  null,
+    constraint idx_timesheets_user_project_week
+        unique (user_id, project_id, week_start)
+);
+
+create index idx_timesheets_project_id
+    on timesheets (project_id);
+create index idx_timesheets_status
+    on timesheets (status);
+
+create table timesheet_entries
+(
+    id           bigint auto_increment
+        primary key,
+    timesheet_id bigint        not null,
+    user_id      bigint        not null,
+    project_id   bigint        not null,
+    task_id      bigint        null,
+    work_date    date          not null,
+    hours        decimal(5, 2) not null,
+    description  varchar(500)  not null default '',
+    cost         bigint        not null default 0,
+    created_at   datetime(3)   null,
+    updated_at   datetime(3)   null
+);
+
+create index idx_timesheet_entries_timesheet_id
+    on timesheet_entries (timesheet_id);
+create index idx_timesheet_entries_user_id
+    on timesheet_entries (user_id);
+create index idx_timesheet_entries_project_id
+    on timesheet_entries (project_id);
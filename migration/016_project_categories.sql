@@ -0,0 +1,37 @@
+-- category của project do admin quản lý thay cho danh sách cố định trong code,
+-- projects.category tham chiếu tới project_categories.name
+create table project_categories
+(
+    id                     bigint auto_increment
+        primary key,
+    name                   varchar(50)  not null,
+    description            varchar(255) not null default '',
+    billable               tinyint(1)   not null default 0,
+    default_revenue_method varchar(20)  not null default '',
+    created_at             datetime(3)  null,
+    updated_at             datetime(3)  null,
+    constraint idx_project_categories_name
+        unique (name)
+);
+
+insert into project_categories (name, description, billable, created_at, updated_at)
+values ('client', 'Client work, revenue is recognised', 1, now(3), now(3)),
+       ('non-billable', 'Internal work without revenue', 0, now(3), now(3)),
+       ('system', 'Platform and infrastructure work', 0, now(3), now(3));
+
+-- project cũ có category ngoài danh sách trên (trước đây không được validator chấp nhận) được tạo category tương ứng
+insert into project_categories (name, billable, created_at, updated_at)
+select distinct p.category, 0, now(3), now(3)
+from projects p
+where p.category not in (select name from project_categories);
+
+alter table projects
+    modify column category varchar(50) not null,
+    add constraint fk_projects_category
+        foreign key (category) references project_categories (name);
+
+insert into permissions (name, description)
+values ('category:manage', 'Create, update and delete project categories');
+
+insert into role_permissions (role_name, permission_name)
+values ('admin', 'category:manage');
@@ -0,0 +1,29 @@
+-- audit log của mọi lần create/update/delete trên users và projects, chỉ thêm mới (xem infrastructure/audit)
+create table audit_events
+(
+    id          bigint auto_increment
+        primary key,
+    actor_id    bigint      null,
+    action      varchar(10) not null,
+    entity_type varchar(20) not null,
+    entity_id   bigint      not null,
+    `before`    json        null,
+    `after`     json        null,
+    request_id  varchar(64) not null default '',
+    client_ip   varchar(45) not null default '',
+    created_at  datetime(3) null
+);
+
+create index idx_audit_events_entity
+    on audit_events (entity_type, entity_id);
+create index idx_audit_events_actor_id
+    on audit_events (actor_id);
+create index idx_audit_events_created_at
+    on audit_events (created_at);
+
+insert into permissions (name, description)
+values ('audit:read', 'Query the audit log');
+
+insert into role_permissions (role_name, permission_name)
+values ('admin', 'audit:read'),
+       ('auditor', 'audit:read');
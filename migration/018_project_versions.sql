@@ -0,0 +1,44 @@
+-- ảnh chụp của projects sau mỗi lần ghi (tạo, sửa, đổi trạng thái, bút toán, khôi phục),
+-- các cột tiền giữ nguyên dạng của projects để báo cáo quý truy vấn lịch sử budget/spend/revenue trực tiếp
+create table project_versions
+(
+    id                          bigint auto_increment
+        primary key,
+    project_id                  bigint       not null,
+    version                     bigint       not null,
+    change_type                 varchar(20)  not null,
+    restored_from               bigint       null,
+    changed_by                  bigint       null,
+    name                        longtext     not null,
+    category                    varchar(50)  not null,
+    status                      varchar(20)  not null,
+    budget_amount               bigint       not null default 0,
+    budget_currency             char(3)      not null default 'VND',
+    project_spend_amount        bigint       not null default 0,
+    project_spend_currency      char(3)      not null default 'VND',
+    project_variance_amount     bigint       not null default 0,
+    project_variance_currency   char(3)      not null default 'VND',
+    revenue_recognised_amount   bigint       not null default 0,
+    revenue_recognised_currency char(3)      not null default 'VND',
+    project_started_at          datetime(3)  not null,
+    project_ended_at            datetime     null,
+    created_at                  datetime(3)  null,
+    constraint idx_project_versions_project_version
+        unique (project_id, version)
+);
+
+create index idx_project_versions_created_at
+    on project_versions (created_at);
+
+-- project đã có trước đây bắt đầu lịch sử bằng trạng thái hiện tại, đọc asOf trước thời điểm migration trả về not found
+insert into project_versions (project_id, version, change_type, name, category, status,
+                              budget_amount, budget_currency, project_spend_amount, project_spend_currency,
+                              project_variance_amount, project_variance_currency,
+                              revenue_recognised_amount, revenue_recognised_currency,
+                              project_started_at, project_ended_at, created_at)
+select id, 1, 'create', name, category, status,
+       budget_amount, budget_currency, project_spend_amount, project_spend_currency,
+       project_variance_amount, project_variance_currency,
+       revenue_recognised_amount, revenue_recognised_currency,
+       project_started_at, project_ended_at, now(3)
+from projects;
@@ -0,0 +1,5 @@
+-- projects.deleted_at đã có từ init_database.sql nhưng trước đây project bị xóa hẳn,
+-- dọn các dòng user_projects còn trỏ tới project không còn tồn tại
+delete
+from user_projects
+where project_id not in (select id from projects);
@@ -0,0 +1,15 @@
+-- project được xóa/truy cập theo id thay vì theo name (name không duy nhất),
+-- slug là khóa dễ đọc tùy chọn, duy nhất trong các project của cùng người tạo (owner_id)
+alter table projects
+    add column owner_id bigint      null after project_ended_at,
+    add column slug     varchar(64) null after owner_id;
+
+-- project đã có trước đây: owner_id là owner có id nhỏ nhất trong user_projects, slug để trống
+update projects p
+set p.owner_id = (select min(up.user_id)
+                  from user_projects up
+                  where up.project_id = p.id
+                    and up.role = 'owner');
+
+create unique index idx_projects_owner_slug
+    on projects (owner_id, slug);
@@ -0,0 +1,8 @@
+-- version hiện tại của project dùng cho ETag/If-Match, tăng cùng với mỗi dòng project_versions được ghi
+alter table projects
+    add column version int not null default 0 after slug;
+
+update projects p
+set p.version = (select coalesce(max(v.version), 0)
+                 from project_versions v
+                 where v.project_id = p.id);